@@ -1,14 +1,15 @@
 package main
 
 import (
-	"bufio"
 	"context"
 	"encoding/json"
 	"fmt"
 	"log"
-	"os"
+	"math"
 
-	"github.com/sipeed/picoclaw/pkg/mcp"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	picomcp "github.com/sipeed/picoclaw/pkg/mcp"
 	"github.com/sipeed/picoclaw/pkg/orchestrator/family"
 	"github.com/sipeed/picoclaw/pkg/orchestrator/mailbox"
 )
@@ -19,145 +20,413 @@ var (
 )
 
 func main() {
-	scanner := bufio.NewScanner(os.Stdin)
-	for scanner.Scan() {
-		line := scanner.Bytes()
-		var req mcp.JSONRPCRequest
-		if err := json.Unmarshal(line, &req); err != nil {
-			log.Printf("Failed to unmarshal request: %v", err)
-			continue
-		}
+	wireChoreNotifications()
 
-		ctx := context.Background()
-		var resp *mcp.JSONRPCResponse
-
-		switch req.Method {
-		case "initialize":
-			resp = handleInitialize(req)
-		case "tools/list":
-			resp = handleToolsList(req)
-		case "tools/call":
-			resp = handleToolsCall(ctx, req)
-		default:
-			resp = &mcp.JSONRPCResponse{
-				JSONRPC: "2.0",
-				ID:      req.ID,
-				Error: &mcp.JSONRPCError{
-					Code:    -32601,
-					Message: "Method not found",
-				},
-			}
-		}
-
-		if resp != nil {
-			out, _ := json.Marshal(resp)
-			fmt.Println(string(out))
-		}
+	if err := newServer().Run(context.Background(), &mcp.StdioTransport{}); err != nil {
+		log.Fatalf("Failed to serve: %v", err)
 	}
 }
 
-func handleInitialize(req mcp.JSONRPCRequest) *mcp.JSONRPCResponse {
-	return &mcp.JSONRPCResponse{
-		JSONRPC: "2.0",
-		ID:      req.ID,
-		Result: mcp.InitializeResult{
-			ProtocolVersion: "2024-11-05", // Example standard version
-			Capabilities: map[string]interface{}{
-				"tools": map[string]interface{}{},
+// wireChoreNotifications drops a mailbox message for the assignee whenever a
+// chore is assigned to them or reviewed.
+func wireChoreNotifications() {
+	familyStore.SetNotifier(family.Notifier{
+		OnChoreAssigned: func(ctx context.Context, chore family.Chore) {
+			content := fmt.Sprintf("New chore assigned to you: %s", chore.Title)
+			if _, err := mailboxStore.SendMessage(ctx, chore.Assigner, chore.Assignee, content); err != nil {
+				log.Printf("Failed to notify %s of chore %s: %v", chore.Assignee, chore.ID, err)
+			}
+		},
+		OnChoreVerified: func(ctx context.Context, chore family.Chore, approved bool) {
+			content := fmt.Sprintf("Your chore %q was approved. Nice work!", chore.Title)
+			if !approved {
+				content = fmt.Sprintf("Your chore %q needs another try.", chore.Title)
+			}
+			if _, err := mailboxStore.SendMessage(ctx, chore.Assigner, chore.Assignee, content); err != nil {
+				log.Printf("Failed to notify %s of chore %s: %v", chore.Assignee, chore.ID, err)
+			}
+		},
+	})
+}
+
+// newServer returns the MCP server with every orchestrator tool registered.
+func newServer() *mcp.Server {
+	s := picomcp.NewServer("picoclaw-orchestrator", "1.0.0")
+
+	picomcp.AddTool(s, &mcp.Tool{
+		Name:        "send_message",
+		Description: "Send a message to another family member's mailbox, optionally referencing files such as photos.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"from":    map[string]interface{}{"type": "string", "description": "Who is sending it"},
+				"to":      map[string]interface{}{"type": "string", "description": "Who it is going to"},
+				"content": map[string]interface{}{"type": "string", "description": "The message body"},
+				"attachments": map[string]interface{}{
+					"type":        "array",
+					"items":       attachmentSchema,
+					"description": "Files that go with the message, each by url or workspace-relative path",
+				},
+			},
+			"required": []string{"from", "to", "content"},
+		},
+	}, sendMessage)
+
+	picomcp.AddTool(s, &mcp.Tool{
+		Name:        "broadcast_message",
+		Description: "Send the same message to several family members at once. Each recipient gets their own copy, linked by a shared group ID.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"from": map[string]interface{}{"type": "string", "description": "Who is sending it"},
+				"to": map[string]interface{}{
+					"type":        "array",
+					"items":       map[string]interface{}{"type": "string"},
+					"description": "Everyone it is going to",
+				},
+				"content": map[string]interface{}{"type": "string", "description": "The message body"},
 			},
-			ServerInfo: mcp.ServerInfo{
-				Name:    "picoclaw-orchestrator",
-				Version: "1.0.0",
+			"required": []string{"from", "to", "content"},
+		},
+	}, broadcastMessage)
+
+	picomcp.AddTool(s, &mcp.Tool{
+		Name: "list_messages",
+		Description: "List messages in your mailbox, newest first. Pass limit and offset to page through a long " +
+			`inbox. The result's structuredContent is {"messages": [...]}` + " and its text block is the messages as a JSON array.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"user":   map[string]interface{}{"type": "string", "description": "User whose inbox to list"},
+				"limit":  map[string]interface{}{"type": "integer", "minimum": 0, "description": "Most messages to return; 0 or omitted returns all"},
+				"offset": map[string]interface{}{"type": "integer", "minimum": 0, "description": "Number of newest messages to skip"},
 			},
+			"required": []string{"user"},
 		},
-	}
+		OutputSchema: listMessagesOutputSchema,
+	}, listMessages)
+
+	picomcp.AddTool(s, &mcp.Tool{
+		Name: "list_unread",
+		Description: "List only the unread messages in your mailbox, oldest first. The result's " +
+			`structuredContent is {"unread": n, "messages": [...]}` + " and its text block is the messages as a JSON array.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"user": map[string]interface{}{"type": "string", "description": "User whose unread messages to list"},
+			},
+			"required": []string{"user"},
+		},
+		OutputSchema: listUnreadOutputSchema,
+	}, listUnread)
+
+	picomcp.AddTool(s, &mcp.Tool{
+		Name: "search_messages",
+		Description: "Search the messages you sent or received for text, ignoring case. Matches are returned newest " +
+			`first; the result's structuredContent is {"messages": [...]}` + " and its text block is the messages as a JSON array.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"user":  map[string]interface{}{"type": "string", "description": "User whose messages to search"},
+				"query": map[string]interface{}{"type": "string", "description": "Text to look for in message content"},
+			},
+			"required": []string{"user", "query"},
+		},
+		OutputSchema: listMessagesOutputSchema,
+	}, searchMessages)
+
+	picomcp.AddTool(s, &mcp.Tool{
+		Name:        "mark_all_read",
+		Description: "Mark every message in your mailbox as read.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"user": map[string]interface{}{"type": "string", "description": "User whose inbox to mark read"},
+			},
+			"required": []string{"user"},
+		},
+	}, markAllRead)
+
+	picomcp.AddTool(s, &mcp.Tool{
+		Name:        "purge_read",
+		Description: "Delete the messages in your mailbox that you have already read. Unread messages and messages you sent are kept.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"user": map[string]interface{}{"type": "string", "description": "User whose read messages to delete"},
+			},
+			"required": []string{"user"},
+		},
+	}, purgeRead)
+
+	// Add chores, lists, etc. missing later if needed
+	return s
 }
 
-func handleToolsList(req mcp.JSONRPCRequest) *mcp.JSONRPCResponse {
-	return &mcp.JSONRPCResponse{
-		JSONRPC: "2.0",
-		ID:      req.ID,
-		Result: mcp.ToolsListResult{
-			Tools: []mcp.MCPToolDef{
-				{
-					Name:        "send_message",
-					Description: "Send a message to another family member's mailbox.",
-					InputSchema: map[string]interface{}{
-						"type": "object",
-						"properties": map[string]interface{}{
-							"from":    map[string]interface{}{"type": "string", "description": "Who is sending it"},
-							"to":      map[string]interface{}{"type": "string", "description": "Who it is going to"},
-							"content": map[string]interface{}{"type": "string", "description": "The message body"},
-						},
-						"required": []string{"from", "to", "content"},
-					},
-				},
-				{
-					Name:        "list_messages",
-					Description: "List messages in your mailbox.",
-					InputSchema: map[string]interface{}{
-						"type": "object",
-						"properties": map[string]interface{}{
-							"user": map[string]interface{}{"type": "string", "description": "User whose inbox to list"},
-						},
-						"required": []string{"user"},
+// listMessagesOutputSchema documents the structuredContent of list_messages.
+var listMessagesOutputSchema = map[string]interface{}{
+	"type": "object",
+	"properties": map[string]interface{}{
+		"messages": map[string]interface{}{
+			"type": "array",
+			"items": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"id":         map[string]interface{}{"type": "string"},
+					"from":       map[string]interface{}{"type": "string"},
+					"to":         map[string]interface{}{"type": "string"},
+					"content":    map[string]interface{}{"type": "string"},
+					"read":       map[string]interface{}{"type": "boolean"},
+					"timestamp":  map[string]interface{}{"type": "string", "format": "date-time"},
+					"group_id":   map[string]interface{}{"type": "string"},
+					"expires_at": map[string]interface{}{"type": "string", "format": "date-time"},
+					"attachments": map[string]interface{}{
+						"type":  "array",
+						"items": attachmentSchema,
 					},
 				},
-				// Add chores, lists, etc. missing later if needed
 			},
 		},
+	},
+	"required": []string{"messages"},
+}
+
+// attachmentSchema describes a mailbox.Attachment.
+var attachmentSchema = map[string]interface{}{
+	"type": "object",
+	"properties": map[string]interface{}{
+		"name":      map[string]interface{}{"type": "string"},
+		"mime_type": map[string]interface{}{"type": "string"},
+		"url":       map[string]interface{}{"type": "string"},
+		"path":      map[string]interface{}{"type": "string"},
+	},
+	"required": []string{"name"},
+}
+
+// listUnreadOutputSchema documents the structuredContent of list_unread.
+var listUnreadOutputSchema = map[string]interface{}{
+	"type": "object",
+	"properties": map[string]interface{}{
+		"unread":   map[string]interface{}{"type": "integer", "description": "Number of unread messages"},
+		"messages": listMessagesOutputSchema["properties"].(map[string]interface{})["messages"],
+	},
+	"required": []string{"unread", "messages"},
+}
+
+func sendMessage(ctx context.Context, args map[string]any) (*mcp.CallToolResult, error) {
+	a, err := stringArgs(args, "from", "to", "content")
+	if err != nil {
+		return nil, picomcp.InvalidParams(err)
+	}
+	attachments, err := attachmentsArg(args, "attachments")
+	if err != nil {
+		return nil, picomcp.InvalidParams(err)
+	}
+	id, err := mailboxStore.SendMessageWithAttachments(ctx, a[0], a[1], a[2], attachments)
+	if err != nil {
+		return nil, err
 	}
+	return picomcp.TextResult(fmt.Sprintf("Message sent with ID: %s", id)), nil
 }
 
-func handleToolsCall(ctx context.Context, req mcp.JSONRPCRequest) *mcp.JSONRPCResponse {
-	// Parse params
-	var params mcp.CallToolParams
-	paramsBytes, _ := json.Marshal(req.Params)
-	json.Unmarshal(paramsBytes, &params)
+func broadcastMessage(ctx context.Context, args map[string]any) (*mcp.CallToolResult, error) {
+	a, err := stringArgs(args, "from", "content")
+	if err != nil {
+		return nil, picomcp.InvalidParams(err)
+	}
+	to, err := stringListArg(args, "to")
+	if err != nil {
+		return nil, picomcp.InvalidParams(err)
+	}
+	groupID, err := mailboxStore.BroadcastMessage(ctx, a[0], to, a[1])
+	if err != nil {
+		return nil, err
+	}
+	return picomcp.TextResult(fmt.Sprintf("Message sent to %d recipients with group ID: %s", len(to), groupID)), nil
+}
+
+func listMessages(ctx context.Context, args map[string]any) (*mcp.CallToolResult, error) {
+	a, err := stringArgs(args, "user")
+	if err != nil {
+		return nil, picomcp.InvalidParams(err)
+	}
+	limit, err := intArg(args, "limit")
+	if err != nil {
+		return nil, picomcp.InvalidParams(err)
+	}
+	offset, err := intArg(args, "offset")
+	if err != nil {
+		return nil, picomcp.InvalidParams(err)
+	}
+	msgs, err := mailboxStore.ListMessagesPage(ctx, a[0], limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	return messagesResult(msgs)
+}
+
+func listUnread(ctx context.Context, args map[string]any) (*mcp.CallToolResult, error) {
+	a, err := stringArgs(args, "user")
+	if err != nil {
+		return nil, picomcp.InvalidParams(err)
+	}
+	msgs, err := mailboxStore.ListUnread(ctx, a[0])
+	if err != nil {
+		return nil, err
+	}
+	result, err := messagesResult(msgs)
+	if err != nil {
+		return nil, err
+	}
+	result.StructuredContent.(map[string]any)["unread"] = len(msgs)
+	return result, nil
+}
+
+func searchMessages(ctx context.Context, args map[string]any) (*mcp.CallToolResult, error) {
+	a, err := stringArgs(args, "user", "query")
+	if err != nil {
+		return nil, picomcp.InvalidParams(err)
+	}
+	msgs, err := mailboxStore.SearchMessages(ctx, a[0], a[1])
+	if err != nil {
+		return nil, err
+	}
+	return messagesResult(msgs)
+}
+
+func markAllRead(ctx context.Context, args map[string]any) (*mcp.CallToolResult, error) {
+	a, err := stringArgs(args, "user")
+	if err != nil {
+		return nil, picomcp.InvalidParams(err)
+	}
+	n, err := mailboxStore.MarkAllRead(ctx, a[0])
+	if err != nil {
+		return nil, err
+	}
+	return picomcp.TextResult(fmt.Sprintf("Marked %d messages as read", n)), nil
+}
+
+func purgeRead(ctx context.Context, args map[string]any) (*mcp.CallToolResult, error) {
+	a, err := stringArgs(args, "user")
+	if err != nil {
+		return nil, picomcp.InvalidParams(err)
+	}
+	n, err := mailboxStore.PurgeRead(ctx, a[0])
+	if err != nil {
+		return nil, err
+	}
+	return picomcp.TextResult(fmt.Sprintf("Deleted %d read messages", n)), nil
+}
 
-	var result string
-	var isError bool
+// messagesResult returns msgs as a JSON array text block with matching
+// {"messages": [...]} structured content.
+func messagesResult(msgs []mailbox.Message) (*mcp.CallToolResult, error) {
+	if msgs == nil {
+		msgs = []mailbox.Message{}
+	}
+	b, err := json.Marshal(msgs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode messages: %w", err)
+	}
+	result := picomcp.TextResult(string(b))
+	result.StructuredContent = map[string]any{"messages": msgs}
+	return result, nil
+}
 
-	switch params.Name {
-	case "send_message":
-		from, _ := params.Arguments["from"].(string)
-		to, _ := params.Arguments["to"].(string)
-		content, _ := params.Arguments["content"].(string)
-		id, err := mailboxStore.SendMessage(ctx, from, to, content)
-		if err != nil {
-			result = err.Error()
-			isError = true
-		} else {
-			result = fmt.Sprintf("Message sent with ID: %s", id)
+// stringArgs returns the named tool arguments in order. Each must be present
+// and a non-empty string.
+func stringArgs(args map[string]any, names ...string) ([]string, error) {
+	values := make([]string, len(names))
+	for i, name := range names {
+		raw, ok := args[name]
+		if !ok || raw == nil {
+			return nil, fmt.Errorf("missing required argument %q", name)
 		}
+		v, ok := raw.(string)
+		if !ok {
+			return nil, fmt.Errorf("argument %q must be a string, got %T", name, raw)
+		}
+		if v == "" {
+			return nil, fmt.Errorf("argument %q must not be empty", name)
+		}
+		values[i] = v
+	}
+	return values, nil
+}
 
-	case "list_messages":
-		user, _ := params.Arguments["user"].(string)
-		msgs, err := mailboxStore.ListMessages(ctx, user)
-		if err != nil {
-			result = err.Error()
-			isError = true
-		} else {
-			b, _ := json.Marshal(msgs)
-			result = string(b)
+// attachmentsArg returns the named optional tool argument as attachments.
+// It must be an array of objects whose fields are strings.
+func attachmentsArg(args map[string]any, name string) ([]mailbox.Attachment, error) {
+	raw, ok := args[name]
+	if !ok || raw == nil {
+		return nil, nil
+	}
+	items, ok := raw.([]any)
+	if !ok {
+		return nil, fmt.Errorf("argument %q must be an array of objects, got %T", name, raw)
+	}
+	attachments := make([]mailbox.Attachment, len(items))
+	for i, item := range items {
+		obj, ok := item.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("argument %q must contain only objects", name)
+		}
+		fields := make(map[string]string, len(obj))
+		for k, v := range obj {
+			s, ok := v.(string)
+			if !ok {
+				return nil, fmt.Errorf("attachment field %q must be a string, got %T", k, v)
+			}
+			fields[k] = s
+		}
+		attachments[i] = mailbox.Attachment{
+			Name:     fields["name"],
+			MimeType: fields["mime_type"],
+			URL:      fields["url"],
+			Path:     fields["path"],
 		}
+	}
+	return attachments, nil
+}
 
-	default:
-		result = fmt.Sprintf("Unknown tool %s", params.Name)
-		isError = true
+// intArg returns the named optional tool argument, which must be a
+// non-negative whole number. A missing argument is 0.
+func intArg(args map[string]any, name string) (int, error) {
+	raw, ok := args[name]
+	if !ok || raw == nil {
+		return 0, nil
+	}
+	v, ok := raw.(float64)
+	if !ok || v != math.Trunc(v) {
+		return 0, fmt.Errorf("argument %q must be an integer, got %v", name, raw)
 	}
+	if v < 0 {
+		return 0, fmt.Errorf("argument %q must not be negative", name)
+	}
+	return int(v), nil
+}
 
-	return &mcp.JSONRPCResponse{
-		JSONRPC: "2.0",
-		ID:      req.ID,
-		Result: mcp.CallToolResult{
-			Content: []mcp.ToolContent{
-				{
-					Type: "text",
-					Text: result,
-				},
-			},
-			IsError: isError,
-		},
+// stringListArg returns the named tool argument, which must be a non-empty
+// array of non-empty strings.
+func stringListArg(args map[string]any, name string) ([]string, error) {
+	raw, ok := args[name]
+	if !ok || raw == nil {
+		return nil, fmt.Errorf("missing required argument %q", name)
+	}
+	items, ok := raw.([]any)
+	if !ok {
+		return nil, fmt.Errorf("argument %q must be an array of strings, got %T", name, raw)
+	}
+	if len(items) == 0 {
+		return nil, fmt.Errorf("argument %q must not be empty", name)
+	}
+	values := make([]string, len(items))
+	for i, item := range items {
+		v, ok := item.(string)
+		if !ok || v == "" {
+			return nil, fmt.Errorf("argument %q must contain only non-empty strings", name)
+		}
+		values[i] = v
 	}
+	return values, nil
 }
@@ -0,0 +1,372 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/sipeed/picoclaw/pkg/orchestrator/mailbox"
+)
+
+// callToolErr calls a tool on a fresh orchestrator server through an
+// in-memory MCP client session.
+func callToolErr(t *testing.T, name string, args any) (*mcp.CallToolResult, error) {
+	t.Helper()
+	ctx := context.Background()
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	if _, err := newServer().Connect(ctx, serverTransport, nil); err != nil {
+		t.Fatalf("server connect: %v", err)
+	}
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "1.0.0"}, nil)
+	session, err := client.Connect(ctx, clientTransport, nil)
+	if err != nil {
+		t.Fatalf("client connect: %v", err)
+	}
+	defer session.Close()
+	return session.CallTool(ctx, &mcp.CallToolParams{Name: name, Arguments: args})
+}
+
+func callTool(t *testing.T, name string, args map[string]any) *mcp.CallToolResult {
+	t.Helper()
+	result, err := callToolErr(t, name, args)
+	if err != nil {
+		t.Fatalf("%s: JSON-RPC error %v", name, err)
+	}
+	return result
+}
+
+// invalidParams returns the Invalid params error a tool call failed with,
+// or nil if it did not fail that way.
+func invalidParams(t *testing.T, name string, args any) *jsonrpc.Error {
+	t.Helper()
+	_, err := callToolErr(t, name, args)
+	var wire *jsonrpc.Error
+	if errors.As(err, &wire) && wire.Code == jsonrpc.CodeInvalidParams {
+		return wire
+	}
+	return nil
+}
+
+// textOf returns the text of a result's first content block.
+func textOf(result *mcp.CallToolResult) string {
+	if len(result.Content) == 0 {
+		return ""
+	}
+	if text, ok := result.Content[0].(*mcp.TextContent); ok {
+		return text.Text
+	}
+	return ""
+}
+
+func TestListMessagesReturnsStructuredJSON(t *testing.T) {
+	mailboxStore = mailbox.NewMemoryStore()
+
+	callTool(t, "send_message", map[string]any{"from": "mom", "to": "kid", "content": "dinner"})
+	callTool(t, "send_message", map[string]any{"from": "dad", "to": "kid", "content": "homework"})
+
+	result := callTool(t, "list_messages", map[string]any{"user": "kid"})
+	if result.IsError {
+		t.Fatalf("list_messages returned an error: %+v", result.Content)
+	}
+	if _, ok := result.Content[0].(*mcp.TextContent); len(result.Content) != 1 || !ok {
+		t.Fatalf("content = %+v, want one text block", result.Content)
+	}
+
+	var fromText []mailbox.Message
+	if err := json.Unmarshal([]byte(textOf(result)), &fromText); err != nil {
+		t.Fatalf("text block is not a JSON message array: %v", err)
+	}
+	if len(fromText) != 2 {
+		t.Errorf("text block has %d messages, want 2", len(fromText))
+	}
+
+	// structuredContent round-trips through the wire format as {"messages": [...]}.
+	raw, err := json.Marshal(result.StructuredContent)
+	if err != nil {
+		t.Fatalf("marshal structuredContent: %v", err)
+	}
+	var structured struct {
+		Messages []mailbox.Message `json:"messages"`
+	}
+	if err := json.Unmarshal(raw, &structured); err != nil {
+		t.Fatalf("structuredContent does not parse: %v", err)
+	}
+	if len(structured.Messages) != 2 {
+		t.Fatalf("structuredContent has %d messages, want 2", len(structured.Messages))
+	}
+	for _, m := range structured.Messages {
+		if m.To != "kid" || m.ID == "" {
+			t.Errorf("unexpected message %+v", m)
+		}
+	}
+}
+
+func TestListMessagesEmptyInbox(t *testing.T) {
+	mailboxStore = mailbox.NewMemoryStore()
+
+	result := callTool(t, "list_messages", map[string]any{"user": "nobody"})
+	if result.IsError {
+		t.Fatalf("list_messages returned an error: %+v", result.Content)
+	}
+	if got := textOf(result); got != "[]" {
+		t.Errorf("text = %q, want []", got)
+	}
+}
+
+func TestUnknownToolIsInvalidParams(t *testing.T) {
+	if invalidParams(t, "no_such_tool", nil) == nil {
+		t.Error("unknown tool should be rejected with Invalid params")
+	}
+}
+
+func TestToolCallArgumentValidation(t *testing.T) {
+	mailboxStore = mailbox.NewMemoryStore()
+
+	tests := []struct {
+		name string
+		tool string
+		args map[string]any
+		want string
+	}{
+		{"missing from", "send_message", map[string]any{"to": "kid", "content": "hi"}, `"from"`},
+		{"missing content", "send_message", map[string]any{"from": "mom", "to": "kid"}, `"content"`},
+		{"numeric to", "send_message", map[string]any{"from": "mom", "to": 42, "content": "hi"}, `"to" must be a string`},
+		{"null content", "send_message", map[string]any{"from": "mom", "to": "kid", "content": nil}, `"content"`},
+		{"empty from", "send_message", map[string]any{"from": "", "to": "kid", "content": "hi"}, `"from" must not be empty`},
+		{"list without user", "list_messages", nil, `"user"`},
+		{"list with object user", "list_messages", map[string]any{"user": map[string]any{}}, `"user" must be a string`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wire := invalidParams(t, tt.tool, tt.args)
+			if wire == nil {
+				t.Fatal("expected an Invalid params error")
+			}
+			if !strings.Contains(wire.Message, tt.want) {
+				t.Errorf("message = %q, want it to mention %s", wire.Message, tt.want)
+			}
+		})
+	}
+
+	if msgs, _ := mailboxStore.ListMessages(context.Background(), "kid"); len(msgs) != 0 {
+		t.Errorf("invalid calls delivered %d messages", len(msgs))
+	}
+}
+
+func TestToolCallMalformedParams(t *testing.T) {
+	if invalidParams(t, "send_message", "oops") == nil {
+		t.Error("non-object arguments should be rejected with Invalid params")
+	}
+}
+
+func TestBroadcastMessageTool(t *testing.T) {
+	mailboxStore = mailbox.NewMemoryStore()
+
+	result := callTool(t, "broadcast_message", map[string]any{
+		"from":    "mom",
+		"to":      []any{"dad", "kid"},
+		"content": "Movie night!",
+	})
+	if result.IsError {
+		t.Fatalf("broadcast_message returned an error: %+v", result.Content)
+	}
+
+	var groupID string
+	for _, user := range []string{"dad", "kid"} {
+		msgs, _ := mailboxStore.ListMessages(context.Background(), user)
+		if len(msgs) != 1 || msgs[0].Content != "Movie night!" {
+			t.Fatalf("%s inbox = %+v, want the broadcast", user, msgs)
+		}
+		if groupID == "" {
+			groupID = msgs[0].GroupID
+		} else if msgs[0].GroupID != groupID {
+			t.Errorf("group IDs differ: %q vs %q", groupID, msgs[0].GroupID)
+		}
+	}
+	if !strings.Contains(textOf(result), groupID) {
+		t.Errorf("result %q does not report group ID %q", textOf(result), groupID)
+	}
+
+	if invalidParams(t, "broadcast_message", map[string]any{"from": "mom", "to": "kid", "content": "hi"}) == nil {
+		t.Error("string recipient should be invalid params")
+	}
+}
+
+func TestListUnreadReturnsOnlyUnread(t *testing.T) {
+	mailboxStore = mailbox.NewMemoryStore()
+	ctx := context.Background()
+
+	readID, _ := mailboxStore.SendMessage(ctx, "mom", "kid", "dinner")
+	mailboxStore.SendMessage(ctx, "dad", "kid", "homework")
+	mailboxStore.SendMessage(ctx, "grandma", "kid", "call me")
+	mailboxStore.SendMessage(ctx, "kid", "mom", "ok")
+	if _, err := mailboxStore.ReadMessage(ctx, "kid", readID); err != nil {
+		t.Fatalf("ReadMessage: %v", err)
+	}
+
+	result := callTool(t, "list_unread", map[string]any{"user": "kid"})
+	if result.IsError {
+		t.Fatalf("list_unread returned an error: %+v", result.Content)
+	}
+
+	raw, err := json.Marshal(result.StructuredContent)
+	if err != nil {
+		t.Fatalf("marshal structuredContent: %v", err)
+	}
+	var structured struct {
+		Unread   int               `json:"unread"`
+		Messages []mailbox.Message `json:"messages"`
+	}
+	if err := json.Unmarshal(raw, &structured); err != nil {
+		t.Fatalf("structuredContent does not parse: %v", err)
+	}
+	if structured.Unread != 2 || len(structured.Messages) != 2 {
+		t.Fatalf("unread = %d with %d messages, want 2 and 2", structured.Unread, len(structured.Messages))
+	}
+	for _, m := range structured.Messages {
+		if m.Read || m.To != "kid" || m.ID == readID {
+			t.Errorf("unexpected message %+v", m)
+		}
+	}
+
+	var fromText []mailbox.Message
+	if err := json.Unmarshal([]byte(textOf(result)), &fromText); err != nil || len(fromText) != 2 {
+		t.Errorf("text block = %q, want a JSON array of 2 messages", textOf(result))
+	}
+}
+
+func TestListUnreadEmpty(t *testing.T) {
+	mailboxStore = mailbox.NewMemoryStore()
+
+	result := callTool(t, "list_unread", map[string]any{"user": "kid"})
+	if result.IsError {
+		t.Fatalf("list_unread returned an error: %+v", result.Content)
+	}
+	if got := textOf(result); got != "[]" {
+		t.Errorf("text = %q, want []", got)
+	}
+	if invalidParams(t, "list_unread", map[string]any{}) == nil {
+		t.Error("missing user should be invalid params")
+	}
+}
+
+func TestMarkAllReadAndPurgeReadTools(t *testing.T) {
+	mailboxStore = mailbox.NewMemoryStore()
+	ctx := context.Background()
+
+	mailboxStore.SendMessage(ctx, "mom", "kid", "dinner")
+	mailboxStore.SendMessage(ctx, "dad", "kid", "homework")
+	mailboxStore.SendMessage(ctx, "kid", "mom", "ok")
+
+	if got := textOf(callTool(t, "mark_all_read", map[string]any{"user": "kid"})); got != "Marked 2 messages as read" {
+		t.Errorf("mark_all_read = %q", got)
+	}
+	if n, _ := mailboxStore.UnreadCount(ctx, "kid"); n != 0 {
+		t.Errorf("kid has %d unread after mark_all_read, want 0", n)
+	}
+
+	mailboxStore.SendMessage(ctx, "grandma", "kid", "call me")
+	if got := textOf(callTool(t, "purge_read", map[string]any{"user": "kid"})); got != "Deleted 2 read messages" {
+		t.Errorf("purge_read = %q", got)
+	}
+	inbox, _ := mailboxStore.ListMessages(ctx, "kid")
+	if len(inbox) != 1 || inbox[0].Content != "call me" {
+		t.Errorf("kid's inbox after purge = %+v, want only the unread message", inbox)
+	}
+	if n, _ := mailboxStore.UnreadCount(ctx, "mom"); n != 1 {
+		t.Errorf("mom has %d unread, want the kid's reply kept", n)
+	}
+}
+
+func TestSearchMessagesTool(t *testing.T) {
+	mailboxStore = mailbox.NewMemoryStore()
+
+	callTool(t, "send_message", map[string]any{"from": "mom", "to": "kid", "content": "Library books are due"})
+	callTool(t, "send_message", map[string]any{"from": "mom", "to": "dad", "content": "library run tonight?"})
+	callTool(t, "send_message", map[string]any{"from": "dad", "to": "kid", "content": "Dinner at six"})
+
+	result := callTool(t, "search_messages", map[string]any{"user": "kid", "query": "library"})
+	if result.IsError {
+		t.Fatalf("search_messages returned an error: %+v", result.Content)
+	}
+	var found []mailbox.Message
+	if err := json.Unmarshal([]byte(textOf(result)), &found); err != nil {
+		t.Fatalf("text block is not a JSON message array: %v", err)
+	}
+	if len(found) != 1 || found[0].Content != "Library books are due" {
+		t.Errorf("search results = %+v, want only the kid's library message", found)
+	}
+
+	if invalidParams(t, "search_messages", map[string]any{"user": "kid"}) == nil {
+		t.Error("missing query should be invalid params")
+	}
+}
+
+func TestListMessagesPaging(t *testing.T) {
+	mailboxStore = mailbox.NewMemoryStore()
+	for _, content := range []string{"one", "two", "three"} {
+		callTool(t, "send_message", map[string]any{"from": "mom", "to": "kid", "content": content})
+		time.Sleep(time.Millisecond)
+	}
+
+	result := callTool(t, "list_messages", map[string]any{"user": "kid", "limit": 2.0, "offset": 1.0})
+	var page []mailbox.Message
+	if err := json.Unmarshal([]byte(textOf(result)), &page); err != nil {
+		t.Fatalf("text block is not a JSON message array: %v", err)
+	}
+	if len(page) != 2 || page[0].Content != "two" || page[1].Content != "one" {
+		t.Errorf("page = %+v, want two then one", page)
+	}
+
+	for _, args := range []map[string]any{
+		{"user": "kid", "limit": -1.0},
+		{"user": "kid", "offset": 1.5},
+		{"user": "kid", "limit": "2"},
+	} {
+		if invalidParams(t, "list_messages", args) == nil {
+			t.Errorf("args %v: want invalid params", args)
+		}
+	}
+}
+
+func TestSendMessageWithAttachmentsTool(t *testing.T) {
+	mailboxStore = mailbox.NewMemoryStore()
+
+	result := callTool(t, "send_message", map[string]any{
+		"from":    "kid",
+		"to":      "mom",
+		"content": "Dishes done",
+		"attachments": []any{
+			map[string]any{"name": "sink.jpg", "mime_type": "image/jpeg", "path": "chores/sink.jpg"},
+		},
+	})
+	if result.IsError {
+		t.Fatalf("send_message returned an error: %+v", result.Content)
+	}
+
+	var inbox []mailbox.Message
+	if err := json.Unmarshal([]byte(textOf(callTool(t, "list_messages", map[string]any{"user": "mom"}))), &inbox); err != nil {
+		t.Fatalf("list_messages text is not JSON: %v", err)
+	}
+	want := mailbox.Attachment{Name: "sink.jpg", MimeType: "image/jpeg", Path: "chores/sink.jpg"}
+	if len(inbox) != 1 || len(inbox[0].Attachments) != 1 || inbox[0].Attachments[0] != want {
+		t.Errorf("inbox = %+v, want one message with %+v", inbox, want)
+	}
+
+	if invalidParams(t, "send_message", map[string]any{
+		"from": "kid", "to": "mom", "content": "x", "attachments": []any{"sink.jpg"},
+	}) == nil {
+		t.Error("malformed attachments should be invalid params")
+	}
+	if result := callTool(t, "send_message", map[string]any{
+		"from": "kid", "to": "mom", "content": "x", "attachments": []any{map[string]any{"name": "a", "path": "../a"}},
+	}); !result.IsError {
+		t.Error("an attachment outside the workspace should be rejected")
+	}
+}
@@ -27,6 +27,7 @@ func agentCmd(message, sessionKey, model string, debug bool) error {
 	if err != nil {
 		return fmt.Errorf("error loading config: %w", err)
 	}
+	defer internal.PushMetrics(cfg)
 
 	if debug {
 		logger.SetLevel(logger.DEBUG)
@@ -15,6 +15,7 @@ func NewGatewayCommand() *cobra.Command {
 	var debug bool
 	var noTruncate bool
 	var allowEmpty bool
+	var check bool
 
 	cmd := &cobra.Command{
 		Use:     "gateway",
@@ -34,6 +35,13 @@ func NewGatewayCommand() *cobra.Command {
 			return nil
 		},
 		RunE: func(_ *cobra.Command, _ []string) error {
+			if check {
+				if err := gateway.Check(internal.GetConfigPath(), allowEmpty); err != nil {
+					return err
+				}
+				fmt.Println("✓ Config check passed")
+				return nil
+			}
 			return gateway.Run(debug, internal.GetPicoclawHome(), internal.GetConfigPath(), allowEmpty)
 		},
 	}
@@ -47,6 +55,12 @@ func NewGatewayCommand() *cobra.Command {
 		false,
 		"Continue starting even when no default model is configured",
 	)
+	cmd.Flags().BoolVar(
+		&check,
+		"check",
+		false,
+		"Load the config and build all services, then exit without serving",
+	)
 
 	return cmd
 }
@@ -1,10 +1,14 @@
 package gateway
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/sipeed/picoclaw/pkg/config"
 )
 
 func TestNewGatewayCommand(t *testing.T) {
@@ -29,4 +33,56 @@ func TestNewGatewayCommand(t *testing.T) {
 	assert.True(t, cmd.HasFlags())
 	assert.NotNil(t, cmd.Flags().Lookup("debug"))
 	assert.NotNil(t, cmd.Flags().Lookup("allow-empty"))
+	assert.NotNil(t, cmd.Flags().Lookup("check"))
+}
+
+func TestGatewayCommand_Check(t *testing.T) {
+	dir := t.TempDir()
+	workspace := filepath.Join(dir, "workspace")
+
+	tests := []struct {
+		name    string
+		config  string
+		wantErr string
+	}{
+		{
+			name:   "valid config",
+			config: `{"version": 1, "agents": {"defaults": {"workspace": "` + workspace + `"}}}`,
+		},
+		{
+			name:    "malformed config",
+			config:  `{"version": 1, "agents": `,
+			wantErr: "error loading config",
+		},
+		{
+			name: "unknown default model",
+			config: `{"version": 1, "agents": {"defaults": {"workspace": "` + workspace +
+				`", "model_name": "no-such-model"}}}`,
+			wantErr: "error creating provider",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configPath := filepath.Join(dir, "config.json")
+			require.NoError(t, os.WriteFile(configPath, []byte(tt.config), 0o644))
+			t.Setenv(config.EnvConfig, configPath)
+
+			cmd := NewGatewayCommand()
+			args := []string{"--check"}
+			if tt.wantErr == "" {
+				args = append(args, "--allow-empty")
+			}
+			cmd.SetArgs(args)
+			cmd.SilenceUsage = true
+			cmd.SilenceErrors = true
+
+			err := cmd.Execute()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
 }
@@ -1,12 +1,15 @@
 package internal
 
 import (
+	"context"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/sipeed/picoclaw/pkg"
 	"github.com/sipeed/picoclaw/pkg/config"
 	"github.com/sipeed/picoclaw/pkg/logger"
+	"github.com/sipeed/picoclaw/pkg/metrics"
 )
 
 const Logo = pkg.Logo
@@ -34,9 +37,23 @@ func LoadConfig() (*config.Config, error) {
 		return nil, err
 	}
 	logger.SetLevelFromString(cfg.Gateway.LogLevel)
+	logger.SetFormatFromString(cfg.Gateway.LogFormat)
 	return cfg, nil
 }
 
+// PushMetrics pushes collected metrics to the configured Pushgateway, if any.
+// Short-lived commands defer it so their metrics survive process exit.
+func PushMetrics(cfg *config.Config) {
+	if cfg == nil || cfg.Metrics.PushgatewayURL == "" {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := metrics.PushMetrics(ctx, cfg.Metrics.PushgatewayURL, cfg.Metrics.PushJob); err != nil {
+		logger.WarnCF("metrics", "Failed to push metrics", map[string]any{"error": err.Error()})
+	}
+}
+
 // FormatVersion returns the version string with optional git commit
 // Deprecated: Use pkg/config.FormatVersion instead
 func FormatVersion() string {
@@ -7,26 +7,15 @@ package agent
 
 import (
 	"encoding/json"
-	"unicode/utf8"
 
 	"github.com/sipeed/picoclaw/pkg/providers"
+	"github.com/sipeed/picoclaw/pkg/tokens"
 )
 
-// parseTurnBoundaries returns the starting index of each Turn in the history.
-// A Turn is a complete "user input → LLM iterations → final response" cycle
-// (as defined in #1316). Each Turn begins at a user message and extends
-// through all subsequent assistant/tool messages until the next user message.
-//
-// Cutting at a Turn boundary guarantees that no tool-call sequence
-// (assistant+ToolCalls → tool results) is split across the cut.
+// parseTurnBoundaries returns the starting index of each Turn in the history
+// (as defined in #1316); see providers.TurnBoundaries.
 func parseTurnBoundaries(history []providers.Message) []int {
-	var starts []int
-	for i, msg := range history {
-		if msg.Role == "user" {
-			starts = append(starts, i)
-		}
-	}
-	return starts
+	return providers.TurnBoundaries(history)
 }
 
 // isSafeBoundary reports whether index is a valid Turn boundary — i.e.,
@@ -86,48 +75,9 @@ func findSafeBoundary(history []providers.Message, targetIndex int) int {
 	return 0
 }
 
-// estimateMessageTokens estimates the token count for a single message,
-// including Content, ReasoningContent, ToolCalls arguments, ToolCallID
-// metadata, and Media items. Uses a heuristic of 2.5 characters per token.
+// estimateMessageTokens estimates the token count for a single message.
 func estimateMessageTokens(msg providers.Message) int {
-	chars := utf8.RuneCountInString(msg.Content)
-
-	// ReasoningContent (extended thinking / chain-of-thought) can be
-	// substantial and is stored in session history via AddFullMessage.
-	if msg.ReasoningContent != "" {
-		chars += utf8.RuneCountInString(msg.ReasoningContent)
-	}
-
-	for _, tc := range msg.ToolCalls {
-		chars += len(tc.ID) + len(tc.Type)
-		if tc.Function != nil {
-			// Count function name + arguments (the wire format for most providers).
-			// tc.Name mirrors tc.Function.Name — count only once to avoid double-counting.
-			chars += len(tc.Function.Name) + len(tc.Function.Arguments)
-		} else {
-			// Fallback: some provider formats use top-level Name without Function.
-			chars += len(tc.Name)
-		}
-	}
-
-	if msg.ToolCallID != "" {
-		chars += len(msg.ToolCallID)
-	}
-
-	// Per-message overhead for role label, JSON structure, separators.
-	const messageOverhead = 12
-	chars += messageOverhead
-
-	tokens := chars * 2 / 5
-
-	// Media items (images, files) are serialized by provider adapters into
-	// multipart or image_url payloads. Add a fixed per-item token estimate
-	// directly (not through the chars heuristic) since actual cost depends
-	// on resolution and provider-specific image tokenization.
-	const mediaTokensPerItem = 256
-	tokens += len(msg.Media) * mediaTokensPerItem
-
-	return tokens
+	return providers.EstimateMessageTokens(msg)
 }
 
 // estimateToolDefsTokens estimates the total token cost of tool definitions
@@ -152,7 +102,7 @@ func estimateToolDefsTokens(defs []providers.ToolDefinition) int {
 		totalChars += 20
 	}
 
-	return totalChars * 2 / 5
+	return tokens.FromChars(totalChars)
 }
 
 // isOverContextBudget checks whether the assembled messages plus tool definitions
@@ -793,6 +793,9 @@ func cloneToolResult(result *tools.ToolResult) *tools.ToolResult {
 	if len(result.Media) > 0 {
 		cloned.Media = append([]string(nil), result.Media...)
 	}
+	if len(result.Images) > 0 {
+		cloned.Images = append([]tools.Image(nil), result.Images...)
+	}
 	return &cloned
 }
 
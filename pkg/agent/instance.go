@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/sipeed/picoclaw/pkg/config"
 	"github.com/sipeed/picoclaw/pkg/logger"
@@ -42,6 +43,9 @@ type AgentInstance struct {
 	SkillsFilter              []string
 	Candidates                []providers.FallbackCandidate
 	Filter                    *safety.Filter
+	// Approvals holds responses flagged for parent review until an exempt
+	// user resolves them.
+	Approvals *safety.ApprovalStore
 
 	// Router is non-nil when model routing is configured and the light model
 	// was successfully resolved. It scores each incoming message and decides
@@ -73,6 +77,16 @@ func NewAgentInstance(
 	allowWritePaths := compilePatterns(cfg.Tools.AllowWritePaths)
 
 	toolsRegistry := tools.NewToolRegistry()
+	if agentCfg != nil {
+		toolsRegistry.SetPolicy(tools.ToolPolicy{Allow: agentCfg.AllowTools, Deny: agentCfg.DenyTools})
+	}
+	if cb := cfg.Tools.CircuitBreaker; cb != nil && cb.FailureThreshold > 0 {
+		cooldown := time.Duration(cb.CooldownSeconds) * time.Second
+		if cooldown <= 0 {
+			cooldown = time.Minute
+		}
+		toolsRegistry.SetCircuitBreaker(tools.NewCircuitBreaker(cb.FailureThreshold, cooldown))
+	}
 
 	if cfg.Tools.IsToolEnabled("read_file") {
 		maxReadFileSize := cfg.Tools.ReadFile.MaxReadFileSize
@@ -176,6 +190,40 @@ func NewAgentInstance(
 			filter = safety.NewFilter(defaults.SafetyLevel, agentCfg.BirthYear)
 		}
 	}
+	exemptUsers := defaults.SafetyExemptUsers
+	if agentCfg != nil && len(agentCfg.SafetyExemptUsers) > 0 {
+		exemptUsers = agentCfg.SafetyExemptUsers
+	}
+	filter.SetAgeThresholds(defaults.YoungUnder, defaults.TeenUnder)
+	if agentCfg != nil {
+		filter.SetAgeThresholds(agentCfg.YoungUnder, agentCfg.TeenUnder)
+	}
+	filter.SetExemptUsers(exemptUsers)
+	safetyLanguage := defaults.SafetyLanguage
+	if agentCfg != nil && agentCfg.SafetyLanguage != "" {
+		safetyLanguage = agentCfg.SafetyLanguage
+	}
+	filter.SetLanguage(safetyLanguage)
+	quietHours := defaults.QuietHours
+	if agentCfg != nil && agentCfg.QuietHours != nil {
+		quietHours = agentCfg.QuietHours
+	}
+	if quietHours != nil {
+		q, err := safety.ParseQuietHours(quietHours.Start, quietHours.End, quietHours.Days, quietHours.Timezone)
+		if err != nil {
+			logger.WarnCF("agent", "Invalid quiet hours; quiet hours disabled",
+				map[string]any{"agent_id": agentID, "error": err.Error()})
+		} else {
+			filter.SetQuietHours(q)
+		}
+	}
+	blockedMessages := defaults.SafetyBlockedMessages
+	if agentCfg != nil && agentCfg.SafetyBlockedMessages != nil {
+		blockedMessages = agentCfg.SafetyBlockedMessages
+	}
+	if blockedMessages != nil {
+		filter.SetBlockedMessages(blockedMessages.Young, blockedMessages.General)
+	}
 	contextBuilder.SetSafetyFilter(filter)
 
 	// Model routing setup: pre-resolve light model candidates at creation time
@@ -217,6 +265,7 @@ func NewAgentInstance(
 		SkillsFilter:              skillsFilter,
 		Candidates:                candidates,
 		Filter:                    filter,
+		Approvals:                 safety.NewApprovalStore(filter.IsExempt),
 		Router:                    router,
 		LightCandidates:           lightCandidates,
 	}
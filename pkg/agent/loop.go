@@ -18,6 +18,8 @@ import (
 	"sync/atomic"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+
 	"github.com/sipeed/picoclaw/pkg/bus"
 	"github.com/sipeed/picoclaw/pkg/channels"
 	"github.com/sipeed/picoclaw/pkg/commands"
@@ -25,11 +27,13 @@ import (
 	"github.com/sipeed/picoclaw/pkg/constants"
 	"github.com/sipeed/picoclaw/pkg/logger"
 	"github.com/sipeed/picoclaw/pkg/media"
+	"github.com/sipeed/picoclaw/pkg/metrics"
 	"github.com/sipeed/picoclaw/pkg/providers"
 	"github.com/sipeed/picoclaw/pkg/routing"
 	"github.com/sipeed/picoclaw/pkg/skills"
 	"github.com/sipeed/picoclaw/pkg/state"
 	"github.com/sipeed/picoclaw/pkg/tools"
+	"github.com/sipeed/picoclaw/pkg/tracing"
 	"github.com/sipeed/picoclaw/pkg/utils"
 	"github.com/sipeed/picoclaw/pkg/voice"
 )
@@ -56,6 +60,7 @@ type AgentLoop struct {
 	mcp            mcpRuntime
 	hookRuntime    hookRuntime
 	steering       *steeringQueue
+	rateLimiter    *userRateLimiter
 	pendingSkills  sync.Map
 	mu             sync.RWMutex
 
@@ -99,6 +104,7 @@ const (
 	defaultResponse            = "The model returned an empty response. This may indicate a provider error or token limit."
 	toolLimitResponse          = "I've reached `max_tool_iterations` without a final response. Increase `max_tool_iterations` in config.json if this task needs more tool steps."
 	handledToolResponseSummary = "Requested output delivered via tool attachment."
+	approvalHoldMessage        = "I've asked a grown-up to check my answer first. I'll send it once they say it's okay!"
 	sessionKeyAgentPrefix      = "agent:"
 	metadataKeyAccountID       = "account_id"
 	metadataKeyGuildID         = "guild_id"
@@ -136,6 +142,7 @@ func NewAgentLoop(
 		fallback:    fallbackChain,
 		cmdRegistry: commands.NewRegistry(commands.BuiltinDefinitions()),
 		steering:    newSteeringQueue(parseSteeringMode(cfg.Agents.Defaults.SteeringMode)),
+		rateLimiter: newUserRateLimiter(cfg.Agents.Defaults.RateLimit),
 	}
 	al.hooks = NewHookManager(eventBus)
 	configureHookManagerFromConfig(al.hooks, cfg)
@@ -441,7 +448,7 @@ func (al *AgentLoop) Run(ctx context.Context) error {
 				}
 				defer cancelDrain()
 
-				response, err := al.processMessage(ctx, msg)
+				response, err := al.processInbound(ctx, msg)
 				if err != nil {
 					response = fmt.Sprintf("Error processing message: %v", err)
 				}
@@ -981,6 +988,7 @@ func (al *AgentLoop) ReloadProviderAndConfig(
 
 	// Also update fallback chain with new config
 	al.fallback = providers.NewFallbackChain(providers.NewCooldownTracker())
+	al.rateLimiter = newUserRateLimiter(cfg.Agents.Defaults.RateLimit)
 
 	al.mu.Unlock()
 
@@ -1071,7 +1079,7 @@ func (al *AgentLoop) transcribeAudioInMessage(ctx context.Context, msg bus.Inbou
 		if !utils.IsAudioFile(meta.Filename, meta.ContentType) {
 			continue
 		}
-		result, err := al.transcriber.Transcribe(ctx, path)
+		result, err := al.transcriber.Transcribe(ctx, path, al.transcribeOptions(msg.Channel))
 		if err != nil {
 			logger.WarnCF("voice", "Transcription failed", map[string]any{"ref": ref, "error": err})
 			transcriptions = append(transcriptions, "")
@@ -1106,6 +1114,20 @@ func (al *AgentLoop) transcribeAudioInMessage(ctx context.Context, msg bus.Inbou
 	return msg, true
 }
 
+// transcribeOptions returns the language hint and model for voice messages
+// arriving on channel, preferring the channel's overrides.
+func (al *AgentLoop) transcribeOptions(channel string) voice.TranscribeOptions {
+	voiceCfg := al.GetConfig().Voice
+	opts := voice.TranscribeOptions{Language: voiceCfg.Language}
+	if ch, ok := voiceCfg.Channels[channel]; ok {
+		if ch.Language != "" {
+			opts.Language = ch.Language
+		}
+		opts.Model = ch.Model
+	}
+	return opts
+}
+
 // sendTranscriptionFeedback sends feedback to the user with the result of
 // audio transcription if the option is enabled. It uses Manager.SendMessage
 // which executes synchronously (rate limiting, splitting, retry) so that
@@ -1253,7 +1275,31 @@ func (al *AgentLoop) ProcessHeartbeat(
 	})
 }
 
+// processInbound handles a message that arrived on the bus. Senders are
+// throttled here, before anything is spent on their message, so internal
+// callers such as cron (ProcessDirectWithChannel) are never rate limited.
+func (al *AgentLoop) processInbound(ctx context.Context, msg bus.InboundMessage) (string, error) {
+	al.mu.RLock()
+	limiter := al.rateLimiter
+	al.mu.RUnlock()
+	if msg.Channel != "system" && !limiter.Allow(msg.SenderID) {
+		logger.WarnCtxCF(ctx, "agent", "Message rate limited",
+			map[string]any{
+				"channel":   msg.Channel,
+				"sender_id": msg.SenderID,
+			})
+		metrics.DefaultRecorder().RecordConcurrencyRejection(rateLimitMetricLabel)
+		return rateLimitedResponse, nil
+	}
+	return al.processMessage(ctx, msg)
+}
+
 func (al *AgentLoop) processMessage(ctx context.Context, msg bus.InboundMessage) (string, error) {
+	// Tag everything logged for this message with one request ID.
+	if logger.RequestIDFromContext(ctx) == "" {
+		ctx = logger.WithRequestID(ctx, logger.NewRequestID())
+	}
+
 	// Add message preview to log (show full content for error messages)
 	var logContent string
 	if strings.Contains(msg.Content, "Error:") || strings.Contains(msg.Content, "error") {
@@ -1261,7 +1307,8 @@ func (al *AgentLoop) processMessage(ctx context.Context, msg bus.InboundMessage)
 	} else {
 		logContent = utils.Truncate(msg.Content, 80)
 	}
-	logger.InfoCF(
+	logger.InfoCtxCF(
+		ctx,
 		"agent",
 		fmt.Sprintf("Processing message from %s:%s: %s", msg.Channel, msg.SenderID, logContent),
 		map[string]any{
@@ -1311,6 +1358,7 @@ func (al *AgentLoop) processMessage(ctx context.Context, msg bus.InboundMessage)
 			"route_agent":   route.AgentID,
 			"route_channel": route.Channel,
 		})
+	metrics.DefaultRecorder().RecordUserRequest(msg.SenderID, msg.Channel, agent.Workspace, agent.ID)
 
 	opts := processOptions{
 		SessionKey:        sessionKey,
@@ -1474,7 +1522,8 @@ func (al *AgentLoop) runAgentLoop(
 	if opts.Channel != "" && opts.ChatID != "" && !constants.IsInternalChannel(opts.Channel) {
 		channelKey := fmt.Sprintf("%s:%s", opts.Channel, opts.ChatID)
 		if err := al.RecordLastChannel(channelKey); err != nil {
-			logger.WarnCF(
+			logger.WarnCtxCF(
+				ctx,
 				"agent",
 				"Failed to record last channel",
 				map[string]any{"error": err.Error()},
@@ -1493,7 +1542,7 @@ func (al *AgentLoop) runAgentLoop(
 
 	for _, followUp := range result.followUps {
 		if pubErr := al.bus.PublishInbound(ctx, followUp); pubErr != nil {
-			logger.WarnCF("agent", "Failed to publish follow-up after turn",
+			logger.WarnCtxCF(ctx, "agent", "Failed to publish follow-up after turn",
 				map[string]any{
 					"turn_id": ts.turnID,
 					"error":   pubErr.Error(),
@@ -1501,6 +1550,31 @@ func (al *AgentLoop) runAgentLoop(
 		}
 	}
 
+	if agent.Filter != nil && result.finalContent != "" {
+		check := agent.Filter.CheckResponse(opts.SenderID, result.finalContent)
+		if check.Blocked {
+			logger.WarnCtxCF(ctx, "agent", "Response blocked by safety filter",
+				map[string]any{
+					"agent_id":  agent.ID,
+					"sender_id": opts.SenderID,
+					"reason":    check.Reason,
+				})
+			result.finalContent = check.BlockedMessage
+		} else if check.NeedsApproval && agent.Filter.RequiresApproval() &&
+			agent.Filter.HasExemptUsers() && agent.Approvals != nil {
+			held := agent.Approvals.EnqueueFor(opts.Channel, opts.ChatID,
+				result.finalContent, opts.SenderID, check.Reason)
+			logger.InfoCtxCF(ctx, "agent", "Response held for parent approval",
+				map[string]any{
+					"agent_id":    agent.ID,
+					"sender_id":   opts.SenderID,
+					"approval_id": held.ID,
+					"reason":      check.Reason,
+				})
+			result.finalContent = approvalHoldMessage
+		}
+	}
+
 	if opts.SendResponse && result.finalContent != "" {
 		al.bus.PublishOutbound(ctx, bus.OutboundMessage{
 			Channel: opts.Channel,
@@ -1511,7 +1585,7 @@ func (al *AgentLoop) runAgentLoop(
 
 	if result.finalContent != "" {
 		responsePreview := utils.Truncate(result.finalContent, 120)
-		logger.InfoCF("agent", fmt.Sprintf("Response: %s", responsePreview),
+		logger.InfoCtxCF(ctx, "agent", fmt.Sprintf("Response: %s", responsePreview),
 			map[string]any{
 				"agent_id":     agent.ID,
 				"session_key":  opts.SessionKey,
@@ -1588,11 +1662,22 @@ func (al *AgentLoop) runTurn(ctx context.Context, ts *turnState) (turnResult, er
 	turnCtx = withTurnState(turnCtx, ts)
 	turnCtx = WithAgentLoop(turnCtx, al)
 
+	turnCtx, span := tracing.Start(turnCtx, "agent.turn",
+		attribute.String("agent.id", ts.agentID),
+		attribute.String("agent.model", ts.agent.Model),
+		attribute.String("channel", ts.channel),
+	)
+
 	al.registerActiveTurn(ts)
 	defer al.clearActiveTurn(ts)
 
 	turnStatus := TurnEndStatusCompleted
 	defer func() {
+		span.SetAttributes(
+			attribute.String("turn.status", string(turnStatus)),
+			attribute.Int("turn.iterations", ts.currentIteration()),
+		)
+		span.End()
 		al.emitEvent(
 			EventKindTurnEnd,
 			ts.eventMeta("runTurn", "turn.end"),
@@ -1773,7 +1858,7 @@ turnLoop:
 			pendingMessages = nil
 		}
 
-		logger.DebugCF("agent", "LLM iteration",
+		logger.DebugCtxCF(ctx, "agent", "LLM iteration",
 			map[string]any{
 				"agent_id":  ts.agent.ID,
 				"iteration": iteration,
@@ -1893,6 +1978,11 @@ turnLoop:
 		callLLM := func(messagesForCall []providers.Message, toolDefsForCall []providers.ToolDefinition) (*providers.LLMResponse, error) {
 			providerCtx, providerCancel := context.WithCancel(turnCtx)
 			ts.setProviderCancel(providerCancel)
+			contextSize := estimateToolDefsTokens(toolDefsForCall)
+			for _, m := range messagesForCall {
+				contextSize += estimateMessageTokens(m)
+			}
+			providerCtx = providers.WithContextSize(providerCtx, contextSize)
 			defer func() {
 				providerCancel()
 				ts.clearProviderCancel(providerCancel)
@@ -2051,7 +2141,7 @@ turnLoop:
 					Message: err.Error(),
 				},
 			)
-			logger.ErrorCF("agent", "LLM call failed",
+			logger.ErrorCtxCF(ctx, "agent", "LLM call failed",
 				map[string]any{
 					"agent_id":  ts.agent.ID,
 					"iteration": iteration,
@@ -2113,7 +2203,7 @@ turnLoop:
 			},
 		)
 
-		logger.DebugCF("agent", "LLM response",
+		logger.DebugCtxCF(ctx, "agent", "LLM response",
 			map[string]any{
 				"agent_id":       ts.agent.ID,
 				"iteration":      iteration,
@@ -2140,7 +2230,7 @@ turnLoop:
 				continue
 			}
 			finalContent = responseContent
-			logger.InfoCF("agent", "LLM response without tool calls (direct answer)",
+			logger.InfoCtxCF(ctx, "agent", "LLM response without tool calls (direct answer)",
 				map[string]any{
 					"agent_id":      ts.agent.ID,
 					"iteration":     iteration,
@@ -2158,7 +2248,7 @@ turnLoop:
 		for _, tc := range normalizedToolCalls {
 			toolNames = append(toolNames, tc.Name)
 		}
-		logger.InfoCF("agent", "LLM requested tool calls",
+		logger.InfoCtxCF(ctx, "agent", "LLM requested tool calls",
 			map[string]any{
 				"agent_id":  ts.agent.ID,
 				"tools":     toolNames,
@@ -2199,6 +2289,7 @@ turnLoop:
 		}
 
 		ts.setPhase(TurnPhaseTools)
+		var toolImages []string
 		for i, tc := range normalizedToolCalls {
 			if ts.hardAbortRequested() {
 				turnStatus = TurnEndStatusAborted
@@ -2289,7 +2380,7 @@ turnLoop:
 
 			argsJSON, _ := json.Marshal(toolArgs)
 			argsPreview := utils.Truncate(string(argsJSON), 200)
-			logger.InfoCF("agent", fmt.Sprintf("Tool call: %s(%s)", toolName, argsPreview),
+			logger.InfoCtxCF(ctx, "agent", fmt.Sprintf("Tool call: %s(%s)", toolName, argsPreview),
 				map[string]any{
 					"agent_id":  ts.agent.ID,
 					"tool":      toolName,
@@ -2372,6 +2463,7 @@ turnLoop:
 				})
 			}
 
+			ts.firstTool.ToolDispatched()
 			toolStart := time.Now()
 			toolResult := ts.agent.Tools.ExecuteWithContext(
 				turnCtx,
@@ -2508,6 +2600,9 @@ turnLoop:
 				ts.agent.Sessions.AddFullMessage(ts.sessionKey, toolResultMsg)
 				ts.recordPersistedMessage(toolResultMsg)
 			}
+			for _, img := range toolResult.Images {
+				toolImages = append(toolImages, img.DataURL())
+			}
 
 			if steerMsgs := al.dequeueSteeringMessagesForScope(ts.sessionKey); len(steerMsgs) > 0 {
 				pendingMessages = append(pendingMessages, steerMsgs...)
@@ -2574,6 +2669,13 @@ turnLoop:
 			}
 		}
 
+		if len(toolImages) > 0 {
+			// Tool messages cannot carry images on most providers, so hand
+			// them to the model in a follow-up user message. It is kept out
+			// of session history to avoid persisting the image data.
+			messages = append(messages, toolImagesMessage(toolImages))
+		}
+
 		if allResponsesHandled {
 			if len(pendingMessages) > 0 {
 				logger.InfoCF("agent", "Pending steering exists after handled tool delivery; continuing turn before finalizing",
@@ -3336,6 +3438,44 @@ func (al *AgentLoop) applyExplicitSkillCommand(
 	return true, false, ""
 }
 
+// resolveApproval approves or denies a response held for parent review and
+// tells the original chat the outcome. Only trusted users may resolve.
+func (al *AgentLoop) resolveApproval(
+	ctx context.Context,
+	agent *AgentInstance,
+	parentID, id string,
+	approved bool,
+) error {
+	if agent.Filter == nil || !agent.Filter.IsExempt(parentID) {
+		return fmt.Errorf("only a parent can resolve approvals")
+	}
+	a, err := agent.Approvals.Resolve(id, approved)
+	if err != nil {
+		return fmt.Errorf("approval #%s: %w", id, err)
+	}
+
+	logger.InfoCF("agent", "Held response resolved",
+		map[string]any{
+			"agent_id":    agent.ID,
+			"approval_id": a.ID,
+			"status":      a.Status,
+			"parent_id":   parentID,
+		})
+
+	if a.Channel == "" || a.ChatID == "" {
+		return nil
+	}
+	content := a.Content
+	if !approved {
+		content = agent.Filter.BlockedMessage(a.Reason)
+	}
+	return al.bus.PublishOutbound(ctx, bus.OutboundMessage{
+		Channel: a.Channel,
+		ChatID:  a.ChatID,
+		Content: content,
+	})
+}
+
 func (al *AgentLoop) buildCommandsRuntime(agent *AgentInstance, opts *processOptions) *commands.Runtime {
 	registry := al.GetRegistry()
 	cfg := al.GetConfig()
@@ -3375,6 +3515,12 @@ func (al *AgentLoop) buildCommandsRuntime(agent *AgentInstance, opts *processOpt
 	if agent != nil && agent.ContextBuilder != nil {
 		rt.ListSkillNames = agent.ContextBuilder.ListSkillNames
 	}
+	if agent != nil && agent.Approvals != nil {
+		rt.ListApprovals = agent.Approvals.ListPending
+		rt.ResolveApproval = func(ctx context.Context, parentID, id string, approved bool) error {
+			return al.resolveApproval(ctx, agent, parentID, id, approved)
+		}
+	}
 	rt.ReloadConfig = func() error {
 		if al.reloadFunc == nil {
 			return fmt.Errorf("reload not configured")
@@ -3395,7 +3541,7 @@ func (al *AgentLoop) buildCommandsRuntime(agent *AgentInstance, opts *processOpt
 				return "", err
 			}
 
-			nextProvider, _, err := providers.CreateProviderFromConfig(modelCfg)
+			nextProvider, _, err := providers.CreateProviderForModel(modelCfg)
 			if err != nil {
 				return "", fmt.Errorf("failed to initialize model %q: %w", value, err)
 			}
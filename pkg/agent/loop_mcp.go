@@ -110,7 +110,12 @@ func (al *AgentLoop) ensureMCPInitialized(ctx context.Context) error {
 		agentIDs := al.registry.ListAgentIDs()
 		agentCount := len(agentIDs)
 
-		for serverName, conn := range servers {
+		// Iterate in name order so tool registration is deterministic.
+		for _, serverName := range mcpManager.ServerNames() {
+			conn, ok := servers[serverName]
+			if !ok {
+				continue
+			}
 			uniqueTools += len(conn.Tools)
 
 			// Determine whether this server's tools should be deferred (hidden).
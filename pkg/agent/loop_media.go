@@ -87,6 +87,16 @@ func resolveMediaRefs(messages []providers.Message, store media.MediaStore, maxS
 	return result
 }
 
+// toolImagesMessage wraps image data URLs returned by tools in a user message
+// so multimodal models can see them on the next call.
+func toolImagesMessage(dataURLs []string) providers.Message {
+	return providers.Message{
+		Role:    "user",
+		Content: "[Images returned by the tool calls above]",
+		Media:   dataURLs,
+	}
+}
+
 func buildArtifactTags(store media.MediaStore, refs []string) []string {
 	if store == nil || len(refs) == 0 {
 		return nil
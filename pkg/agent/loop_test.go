@@ -20,6 +20,7 @@ import (
 	"github.com/sipeed/picoclaw/pkg/providers"
 	"github.com/sipeed/picoclaw/pkg/routing"
 	"github.com/sipeed/picoclaw/pkg/tools"
+	"github.com/sipeed/picoclaw/pkg/voice"
 )
 
 type fakeChannel struct{ id string }
@@ -1684,6 +1685,44 @@ func TestProcessMessage_SwitchModelRoutesSubsequentRequestsToSelectedProvider(t
 	}
 }
 
+func TestProcessMessage_SwitchModelAppliesProviderWrappers(t *testing.T) {
+	cfg := &config.Config{
+		Agents: config.AgentsConfig{
+			Defaults: config.AgentDefaults{
+				Workspace:         t.TempDir(),
+				ModelName:         "plain",
+				MaxTokens:         4096,
+				MaxToolIterations: 10,
+			},
+		},
+		ModelList: []*config.ModelConfig{
+			{ModelName: "plain", Model: "echo/plain"},
+			{ModelName: "cached", Model: "echo/cached", CacheTTL: 60},
+		},
+	}
+	provider, _, err := providers.CreateProvider(cfg)
+	if err != nil {
+		t.Fatalf("CreateProvider() error = %v", err)
+	}
+	al := NewAgentLoop(cfg, bus.NewMessageBus(), provider)
+	helper := testHelper{al: al}
+
+	resp := helper.executeAndGetResponse(t, context.Background(), bus.InboundMessage{
+		Channel:  "telegram",
+		SenderID: "user1",
+		ChatID:   "chat1",
+		Content:  "/switch model to cached",
+		Peer:     bus.Peer{Kind: "direct", ID: "user1"},
+	})
+	if !strings.Contains(resp, "Switched model from plain to cached") {
+		t.Fatalf("unexpected /switch reply: %q", resp)
+	}
+	p := al.GetRegistry().GetDefaultAgent().Provider
+	if _, ok := p.(*providers.CachingProvider); !ok {
+		t.Errorf("provider after switch = %T, want it wrapped with the response cache", p)
+	}
+}
+
 // TestToolResult_SilentToolDoesNotSendUserMessage verifies silent tools don't trigger outbound
 func TestToolResult_SilentToolDoesNotSendUserMessage(t *testing.T) {
 	tmpDir, err := os.MkdirTemp("", "agent-test-*")
@@ -2677,3 +2716,153 @@ func TestFilterClientWebSearch_EmptyInput(t *testing.T) {
 		t.Fatalf("len(result) = %d, want 0", len(result))
 	}
 }
+
+type fakeTranscriber struct {
+	text  string
+	paths []string
+	opts  []voice.TranscribeOptions
+}
+
+func (f *fakeTranscriber) Transcribe(
+	ctx context.Context,
+	audioFilePath string,
+	opts voice.TranscribeOptions,
+) (*voice.TranscriptionResponse, error) {
+	f.paths = append(f.paths, audioFilePath)
+	f.opts = append(f.opts, opts)
+	return &voice.TranscriptionResponse{Text: f.text}, nil
+}
+
+func (f *fakeTranscriber) Name() string { return "fake" }
+
+func TestTranscribeAudioInMessage_ReplacesVoiceAnnotation(t *testing.T) {
+	al, _, _, _, cleanup := newTestAgentLoop(t)
+	defer cleanup()
+
+	store := media.NewFileMediaStore()
+	al.SetMediaStore(store)
+	tr := &fakeTranscriber{text: "buy milk"}
+	al.SetTranscriber(tr)
+
+	audioPath := filepath.Join(t.TempDir(), "voice.ogg")
+	if err := os.WriteFile(audioPath, []byte("fake-audio"), 0o644); err != nil {
+		t.Fatalf("WriteFile(audioPath) error = %v", err)
+	}
+	ref, err := store.Store(audioPath, media.MediaMeta{Filename: "voice.ogg", ContentType: "audio/ogg"}, "test")
+	if err != nil {
+		t.Fatalf("Store() error = %v", err)
+	}
+
+	msg, ok := al.transcribeAudioInMessage(context.Background(), bus.InboundMessage{
+		Channel: "telegram",
+		ChatID:  "chat1",
+		Content: "remind me [voice]",
+		Media:   []string{ref},
+	})
+	if !ok {
+		t.Fatal("transcribeAudioInMessage() reported no transcription")
+	}
+	if msg.Content != "remind me [voice: buy milk]" {
+		t.Errorf("Content = %q, want %q", msg.Content, "remind me [voice: buy milk]")
+	}
+	if len(tr.paths) != 1 || tr.paths[0] != audioPath {
+		t.Errorf("transcribed paths = %v, want [%s]", tr.paths, audioPath)
+	}
+}
+
+func TestTranscribeOptions_ChannelOverrides(t *testing.T) {
+	al, cfg, _, _, cleanup := newTestAgentLoop(t)
+	defer cleanup()
+	cfg.Voice.Language = "en"
+	cfg.Voice.Channels = map[string]config.VoiceChannelConfig{
+		"telegram": {Language: "es", Model: "whisper-large-v3"},
+		"discord":  {Model: "whisper-1"},
+	}
+
+	tests := []struct {
+		channel string
+		want    voice.TranscribeOptions
+	}{
+		{"telegram", voice.TranscribeOptions{Language: "es", Model: "whisper-large-v3"}},
+		{"discord", voice.TranscribeOptions{Language: "en", Model: "whisper-1"}},
+		{"slack", voice.TranscribeOptions{Language: "en"}},
+	}
+	for _, tt := range tests {
+		if got := al.transcribeOptions(tt.channel); got != tt.want {
+			t.Errorf("transcribeOptions(%q) = %+v, want %+v", tt.channel, got, tt.want)
+		}
+	}
+}
+
+func TestProcessMessage_HoldsFlaggedResponseForParentApproval(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "agent-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	cfg := &config.Config{
+		Agents: config.AgentsConfig{
+			Defaults: config.AgentDefaults{
+				Workspace:         tmpDir,
+				ModelName:         "test-model",
+				MaxTokens:         4096,
+				MaxToolIterations: 10,
+				SafetyLevel:       "high",
+				BirthYear:         time.Now().Year() - 7,
+				SafetyExemptUsers: []string{"parent"},
+			},
+		},
+	}
+
+	msgBus := bus.NewMessageBus()
+	const answer = "Grief is a feeling people have when someone they love dies."
+	al := NewAgentLoop(cfg, msgBus, &simpleMockProvider{response: answer})
+
+	resp, err := al.processMessage(context.Background(), bus.InboundMessage{
+		Channel:  "telegram",
+		SenderID: "kid",
+		ChatID:   "kid-chat",
+		Content:  "what is grief?",
+	})
+	if err != nil {
+		t.Fatalf("processMessage() error = %v", err)
+	}
+	if resp != approvalHoldMessage {
+		t.Fatalf("response = %q, want the holding message", resp)
+	}
+
+	parentMsg := func(content string) string {
+		t.Helper()
+		reply, err := al.processMessage(context.Background(), bus.InboundMessage{
+			Channel:  "telegram",
+			SenderID: "parent",
+			ChatID:   "parent-chat",
+			Content:  content,
+		})
+		if err != nil {
+			t.Fatalf("processMessage(%q) error = %v", content, err)
+		}
+		return reply
+	}
+
+	if list := parentMsg("/approval list"); !strings.Contains(list, "#1 for kid") {
+		t.Fatalf("/approval list = %q, want the held response", list)
+	}
+	if reply := parentMsg("/approval approve 1"); !strings.Contains(reply, "Approved #1") {
+		t.Fatalf("/approval approve = %q", reply)
+	}
+
+	select {
+	case out := <-msgBus.OutboundChan():
+		if out.Channel != "telegram" || out.ChatID != "kid-chat" || out.Content != answer {
+			t.Fatalf("delivered message = %+v, want the held answer in the child's chat", out)
+		}
+	default:
+		t.Fatal("approved response was not delivered")
+	}
+
+	if reply := parentMsg("/approval deny 1"); !strings.Contains(reply, "not found") {
+		t.Fatalf("resolving twice = %q, want not found", reply)
+	}
+}
@@ -0,0 +1,83 @@
+package agent
+
+import (
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/sipeed/picoclaw/pkg/config"
+)
+
+const (
+	rateLimitedResponse = "You're sending messages too quickly. Please slow down and try again in a moment."
+	// rateLimitMetricLabel is the concurrency-rejection label used for
+	// messages refused at ingress.
+	rateLimitMetricLabel   = "agent_ingress"
+	defaultRateLimitWindow = time.Minute
+)
+
+// userRateLimiter is a per-sender token bucket. Each sender may send up to
+// burst messages at once; the bucket refills completely over one window.
+type userRateLimiter struct {
+	limit  rate.Limit
+	burst  int
+	window time.Duration
+	now    func() time.Time
+
+	mu        sync.Mutex
+	limiters  map[string]*rate.Limiter
+	lastPrune time.Time
+}
+
+// newUserRateLimiter returns nil when cfg does not enable rate limiting.
+func newUserRateLimiter(cfg *config.RateLimitConfig) *userRateLimiter {
+	if cfg == nil || cfg.Messages <= 0 {
+		return nil
+	}
+	window := time.Duration(cfg.WindowSeconds) * time.Second
+	if window <= 0 {
+		window = defaultRateLimitWindow
+	}
+	return &userRateLimiter{
+		limit:    rate.Limit(float64(cfg.Messages) / window.Seconds()),
+		burst:    cfg.Messages,
+		window:   window,
+		now:      time.Now,
+		limiters: make(map[string]*rate.Limiter),
+	}
+}
+
+// Allow reports whether senderID may send another message now. A nil
+// limiter and an empty sender ID are never throttled.
+func (l *userRateLimiter) Allow(senderID string) bool {
+	if l == nil || senderID == "" {
+		return true
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.pruneLocked(now)
+	lim, ok := l.limiters[senderID]
+	if !ok {
+		lim = rate.NewLimiter(l.limit, l.burst)
+		l.limiters[senderID] = lim
+	}
+	return lim.AllowN(now, 1)
+}
+
+// pruneLocked drops senders whose bucket has refilled, at most once per
+// window, so idle senders do not accumulate.
+func (l *userRateLimiter) pruneLocked(now time.Time) {
+	if now.Sub(l.lastPrune) < l.window {
+		return
+	}
+	l.lastPrune = now
+	for id, lim := range l.limiters {
+		if lim.TokensAt(now) >= float64(l.burst) {
+			delete(l.limiters, id)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/sipeed/picoclaw/pkg/bus"
+	"github.com/sipeed/picoclaw/pkg/config"
+)
+
+func TestUserRateLimiter_ThrottlesWithinWindow(t *testing.T) {
+	l := newUserRateLimiter(&config.RateLimitConfig{Messages: 3, WindowSeconds: 60})
+	now := time.Unix(1_700_000_000, 0)
+	l.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		if !l.Allow("alice") {
+			t.Fatalf("message %d was throttled, want allowed", i+1)
+		}
+	}
+	if l.Allow("alice") {
+		t.Fatal("message 4 within the window was allowed, want throttled")
+	}
+	if !l.Allow("bob") {
+		t.Error("another sender was throttled by alice's bucket")
+	}
+
+	// One message's worth of tokens refills after window/messages.
+	now = now.Add(20 * time.Second)
+	if !l.Allow("alice") {
+		t.Error("message after refill was throttled")
+	}
+	if l.Allow("alice") {
+		t.Error("second message after a single refill was allowed")
+	}
+}
+
+func TestUserRateLimiter_Disabled(t *testing.T) {
+	for _, cfg := range []*config.RateLimitConfig{nil, {Messages: 0}} {
+		l := newUserRateLimiter(cfg)
+		if l != nil {
+			t.Fatalf("newUserRateLimiter(%+v) = %+v, want nil", cfg, l)
+		}
+		for i := 0; i < 100; i++ {
+			if !l.Allow("alice") {
+				t.Fatal("nil limiter throttled a message")
+			}
+		}
+	}
+}
+
+func TestUserRateLimiter_PrunesRefilledSenders(t *testing.T) {
+	l := newUserRateLimiter(&config.RateLimitConfig{Messages: 2, WindowSeconds: 10})
+	now := time.Unix(1_700_000_000, 0)
+	l.now = func() time.Time { return now }
+
+	l.Allow("alice")
+	l.Allow("bob")
+	now = now.Add(time.Minute)
+	l.Allow("carol")
+
+	if _, ok := l.limiters["alice"]; ok {
+		t.Error("idle sender alice was not pruned")
+	}
+	if len(l.limiters) != 1 {
+		t.Errorf("tracked senders = %d, want 1", len(l.limiters))
+	}
+}
+
+func TestProcessMessage_RateLimitsSender(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "agent-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	cfg := &config.Config{
+		Agents: config.AgentsConfig{
+			Defaults: config.AgentDefaults{
+				Workspace:         tmpDir,
+				ModelName:         "test-model",
+				MaxTokens:         4096,
+				MaxToolIterations: 10,
+				RateLimit:         &config.RateLimitConfig{Messages: 2, WindowSeconds: 3600},
+			},
+		},
+	}
+	provider := &countingMockProvider{response: "LLM reply"}
+	al := NewAgentLoop(cfg, bus.NewMessageBus(), provider)
+
+	send := func(sender string) string {
+		t.Helper()
+		resp, err := al.processInbound(context.Background(), bus.InboundMessage{
+			Channel:  "telegram",
+			SenderID: sender,
+			ChatID:   "chat-" + sender,
+			Content:  "hello",
+		})
+		if err != nil {
+			t.Fatalf("processInbound() error = %v", err)
+		}
+		return resp
+	}
+
+	for i := 0; i < 2; i++ {
+		if resp := send("kid"); resp != "LLM reply" {
+			t.Fatalf("message %d response = %q, want the LLM reply", i+1, resp)
+		}
+	}
+	if resp := send("kid"); resp != rateLimitedResponse {
+		t.Fatalf("third message response = %q, want the rate-limit notice", resp)
+	}
+	if provider.calls != 2 {
+		t.Errorf("LLM calls = %d, want 2 (throttled message must not reach the model)", provider.calls)
+	}
+	if resp := send("parent"); resp != "LLM reply" {
+		t.Errorf("other sender response = %q, want the LLM reply", resp)
+	}
+}
+
+func TestProcessDirectWithChannel_CronIsNotRateLimited(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "agent-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	cfg := &config.Config{
+		Agents: config.AgentsConfig{
+			Defaults: config.AgentDefaults{
+				Workspace:         tmpDir,
+				ModelName:         "test-model",
+				MaxTokens:         4096,
+				MaxToolIterations: 10,
+				RateLimit:         &config.RateLimitConfig{Messages: 2, WindowSeconds: 3600},
+			},
+		},
+	}
+	provider := &countingMockProvider{response: "LLM reply"}
+	al := NewAgentLoop(cfg, bus.NewMessageBus(), provider)
+
+	// More cron jobs fire in one window than a user may send.
+	for i := 0; i < 5; i++ {
+		resp, err := al.ProcessDirectWithChannel(
+			context.Background(), "run the job", fmt.Sprintf("cron-job-%d", i), "cli", "direct")
+		if err != nil {
+			t.Fatalf("job %d: ProcessDirectWithChannel() error = %v", i+1, err)
+		}
+		if resp != "LLM reply" {
+			t.Fatalf("job %d response = %q, want the LLM reply", i+1, resp)
+		}
+	}
+	if provider.calls != 5 {
+		t.Errorf("LLM calls = %d, want 5", provider.calls)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"testing"
 
+	"github.com/sipeed/picoclaw/pkg/bus"
 	"github.com/sipeed/picoclaw/pkg/config"
 	"github.com/sipeed/picoclaw/pkg/providers"
 )
@@ -203,3 +204,37 @@ func TestAgentInstance_FallbackExplicitEmpty(t *testing.T) {
 		t.Errorf("expected 0 fallbacks (explicit empty), got %d: %v", len(agent.Fallbacks), agent.Fallbacks)
 	}
 }
+
+func TestAgentLoop_PerAgentToolPolicy(t *testing.T) {
+	cfg := testCfg([]config.AgentConfig{
+		{ID: "parent", Default: true},
+		{ID: "kid", DenyTools: []string{"cron", "write_file"}},
+		{ID: "reader", AllowTools: []string{"read_*"}},
+	})
+	cfg.Agents.Defaults.Workspace = t.TempDir()
+	cfg.Tools.ReadFile.Enabled = true
+	cfg.Tools.WriteFile.Enabled = true
+
+	al := NewAgentLoop(cfg, bus.NewMessageBus(), &mockRegistryProvider{})
+	al.RegisterTool(&slowTool{name: "cron"})
+
+	tests := []struct {
+		agentID string
+		want    map[string]bool
+	}{
+		{"parent", map[string]bool{"cron": true, "write_file": true, "read_file": true}},
+		{"kid", map[string]bool{"cron": false, "write_file": false, "read_file": true}},
+		{"reader", map[string]bool{"cron": false, "write_file": false, "read_file": true}},
+	}
+	for _, tt := range tests {
+		agent, ok := al.GetRegistry().GetAgent(tt.agentID)
+		if !ok {
+			t.Fatalf("agent %q not found", tt.agentID)
+		}
+		for name, want := range tt.want {
+			if _, got := agent.Tools.Get(name); got != want {
+				t.Errorf("agent %q has %s = %v, want %v", tt.agentID, name, got, want)
+			}
+		}
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/sipeed/picoclaw/pkg/bus"
+	"github.com/sipeed/picoclaw/pkg/metrics"
 	"github.com/sipeed/picoclaw/pkg/providers"
 	"github.com/sipeed/picoclaw/pkg/session"
 	"github.com/sipeed/picoclaw/pkg/tools"
@@ -65,6 +66,7 @@ type turnState struct {
 	iteration    int
 	startedAt    time.Time
 	finalContent string
+	firstTool    *metrics.FirstToolTimer
 
 	followUps []bus.InboundMessage
 
@@ -122,6 +124,7 @@ func newTurnState(agent *AgentInstance, opts processOptions, scope turnEventScop
 		phase:       TurnPhaseSetup,
 		startedAt:   time.Now(),
 	}
+	ts.firstTool = metrics.NewFirstToolTimer(metrics.DefaultRecorder(), agent.Model, ts.startedAt)
 
 	// Bind session store and capture initial history length for rollback logic
 	if agent != nil && agent.Sessions != nil {
@@ -17,5 +17,6 @@ func BuiltinDefinitions() []Definition {
 		clearCommand(),
 		subagentsCommand(),
 		reloadCommand(),
+		approvalCommand(),
 	}
 }
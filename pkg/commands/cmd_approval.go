@@ -0,0 +1,79 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// approvalPreviewRunes bounds how much of each held response /approval list
+// shows.
+const approvalPreviewRunes = 200
+
+func approvalCommand() Definition {
+	return Definition{
+		Name:        "approval",
+		Description: "Review responses held for parent approval",
+		SubCommands: []SubCommand{
+			{
+				Name:        "list",
+				Description: "Responses waiting for review",
+				Handler: func(_ context.Context, req Request, rt *Runtime) error {
+					if rt == nil || rt.ListApprovals == nil {
+						return req.Reply(unavailableMsg)
+					}
+					pending := rt.ListApprovals(req.SenderID)
+					if len(pending) == 0 {
+						return req.Reply("No responses waiting for approval")
+					}
+					var sb strings.Builder
+					sb.WriteString("Waiting for approval:")
+					for _, a := range pending {
+						fmt.Fprintf(&sb, "\n\n#%s for %s (%s)\n%s", a.ID, a.UserID, a.Reason, previewApproval(a.Content))
+					}
+					sb.WriteString("\n\nUse /approval approve <id> or /approval deny <id>.")
+					return req.Reply(sb.String())
+				},
+			},
+			{
+				Name:        "approve",
+				Description: "Send a held response",
+				ArgsUsage:   "<id>",
+				Handler:     resolveApprovalHandler(true),
+			},
+			{
+				Name:        "deny",
+				Description: "Discard a held response",
+				ArgsUsage:   "<id>",
+				Handler:     resolveApprovalHandler(false),
+			},
+		},
+	}
+}
+
+func resolveApprovalHandler(approved bool) Handler {
+	return func(ctx context.Context, req Request, rt *Runtime) error {
+		if rt == nil || rt.ResolveApproval == nil {
+			return req.Reply(unavailableMsg)
+		}
+		id := strings.TrimPrefix(nthToken(req.Text, 2), "#")
+		if id == "" {
+			return req.Reply(fmt.Sprintf("Usage: /approval %s <id>", nthToken(req.Text, 1)))
+		}
+		if err := rt.ResolveApproval(ctx, req.SenderID, id, approved); err != nil {
+			return req.Reply(err.Error())
+		}
+		if approved {
+			return req.Reply(fmt.Sprintf("Approved #%s; the response has been sent", id))
+		}
+		return req.Reply(fmt.Sprintf("Denied #%s", id))
+	}
+}
+
+func previewApproval(content string) string {
+	runes := []rune(content)
+	if len(runes) <= approvalPreviewRunes {
+		return content
+	}
+	return string(runes[:approvalPreviewRunes]) + "..."
+}
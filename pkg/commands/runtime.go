@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/sipeed/picoclaw/pkg/config"
+	"github.com/sipeed/picoclaw/pkg/safety"
 )
 
 // Runtime provides runtime dependencies to command handlers. It is constructed
@@ -22,4 +23,8 @@ type Runtime struct {
 	RotateSession      func(ctx context.Context, sessionKey string) error
 	ClearHistory       func() error
 	ReloadConfig       func() error
+	// ListApprovals and ResolveApproval manage responses held for parent
+	// review. Both are scoped to parentID, which must be a trusted user.
+	ListApprovals   func(parentID string) []safety.Approval
+	ResolveApproval func(ctx context.Context, parentID, id string, approved bool) error
 }
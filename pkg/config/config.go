@@ -95,6 +95,9 @@ type Config struct {
 	Heartbeat HeartbeatConfig `json:"heartbeat"`
 	Devices   DevicesConfig   `json:"devices"`
 	Voice     VoiceConfig     `json:"voice"`
+	Memory    MemoryConfig    `json:"memory"`
+	Metrics   MetricsConfig   `json:"metrics,omitempty"`
+	Tracing   TracingConfig   `json:"tracing,omitempty"`
 	// BuildInfo contains build-time version information
 	BuildInfo BuildInfo `json:"build_info,omitempty"`
 
@@ -243,6 +246,52 @@ type AgentConfig struct {
 	Model     *AgentModelConfig `json:"model,omitempty"`
 	Skills    []string          `json:"skills,omitempty"`
 	Subagents *SubagentsConfig  `json:"subagents,omitempty"`
+	// SafetyLevel overrides agents.defaults.safety_level for this agent.
+	SafetyLevel string `json:"safety_level,omitempty" enum:"off,low,medium,high"`
+	// BirthYear overrides agents.defaults.birth_year for this agent.
+	BirthYear int `json:"birth_year,omitempty"`
+	// SafetyExemptUsers overrides agents.defaults.safety_exempt_users for this agent.
+	SafetyExemptUsers []string `json:"safety_exempt_users,omitempty"`
+	// QuietHours overrides agents.defaults.quiet_hours for this agent.
+	QuietHours *QuietHoursConfig `json:"quiet_hours,omitempty"`
+	// SafetyBlockedMessages overrides agents.defaults.safety_blocked_messages for this agent.
+	SafetyBlockedMessages *SafetyBlockedMessagesConfig `json:"safety_blocked_messages,omitempty"`
+	// YoungUnder overrides agents.defaults.young_under for this agent.
+	YoungUnder int `json:"young_under,omitempty"`
+	// TeenUnder overrides agents.defaults.teen_under for this agent.
+	TeenUnder int `json:"teen_under,omitempty"`
+	// SafetyLanguage overrides agents.defaults.safety_language for this agent.
+	SafetyLanguage string `json:"safety_language,omitempty"`
+	// AllowTools, when non-empty, limits this agent to the listed tools.
+	// DenyTools removes tools it would otherwise get. Entries are tool
+	// names such as "cron" or "write_file", or globs like "mcp_*".
+	AllowTools []string `json:"allow_tools,omitempty"`
+	DenyTools  []string `json:"deny_tools,omitempty"`
+}
+
+// SafetyBlockedMessagesConfig customizes the text shown in place of content
+// blocked by the safety filter. "{reason}" is replaced with the block reason;
+// empty fields keep the built-in English messages.
+type SafetyBlockedMessagesConfig struct {
+	Young   string `json:"young,omitempty"`
+	General string `json:"general,omitempty"`
+}
+
+// QuietHoursConfig is a daily window during which the assistant declines to
+// talk to young users. An end before the start spans midnight.
+type QuietHoursConfig struct {
+	Start    string   `json:"start"`              // "HH:MM"
+	End      string   `json:"end"`                // "HH:MM"
+	Days     []string `json:"days,omitempty"`     // weekdays the window starts on; empty means every day
+	Timezone string   `json:"timezone,omitempty"` // IANA name; empty means local time
+}
+
+// RateLimitConfig is a per-sender token bucket: up to Messages messages at
+// once, refilling fully over WindowSeconds (default 60). Zero Messages
+// disables the limit.
+type RateLimitConfig struct {
+	Messages      int `json:"messages"`
+	WindowSeconds int `json:"window_seconds,omitempty"`
 }
 
 type SubagentsConfig struct {
@@ -316,9 +365,26 @@ type AgentDefaults struct {
 	SummarizeTokenPercent     int                `json:"summarize_token_percent"         env:"PICOCLAW_AGENTS_DEFAULTS_SUMMARIZE_TOKEN_PERCENT"`
 	MaxMediaSize              int                `json:"max_media_size,omitempty"        env:"PICOCLAW_AGENTS_DEFAULTS_MAX_MEDIA_SIZE"`
 	Routing                   *RoutingConfig     `json:"routing,omitempty"`
-	SteeringMode              string             `json:"steering_mode,omitempty"         env:"PICOCLAW_AGENTS_DEFAULTS_STEERING_MODE"` // "one-at-a-time" (default) or "all"
+	SteeringMode              string             `json:"steering_mode,omitempty"         env:"PICOCLAW_AGENTS_DEFAULTS_STEERING_MODE"                enum:"one-at-a-time,all"` // "one-at-a-time" (default) or "all"
 	SubTurn                   SubTurnConfig      `json:"subturn"                                                                                     envPrefix:"PICOCLAW_AGENTS_DEFAULTS_SUBTURN_"`
 	ToolFeedback              ToolFeedbackConfig `json:"tool_feedback,omitempty"`
+	SafetyLevel               string             `json:"safety_level,omitempty"          env:"PICOCLAW_AGENTS_DEFAULTS_SAFETY_LEVEL"                 enum:"off,low,medium,high"`
+	BirthYear                 int                `json:"birth_year,omitempty"            env:"PICOCLAW_AGENTS_DEFAULTS_BIRTH_YEAR"`
+	// SafetyExemptUsers lists trusted sender IDs (e.g. parents) that bypass the safety filter.
+	SafetyExemptUsers []string `json:"safety_exempt_users,omitempty"`
+	// QuietHours restricts interaction with young users during bedtime or school hours.
+	QuietHours *QuietHoursConfig `json:"quiet_hours,omitempty"`
+	// SafetyBlockedMessages customizes the text shown in place of blocked content.
+	SafetyBlockedMessages *SafetyBlockedMessagesConfig `json:"safety_blocked_messages,omitempty"`
+	// YoungUnder and TeenUnder are the ages below which the safety filter treats
+	// the user as a young child or a teenager (defaults 13 and 18).
+	YoungUnder int `json:"young_under,omitempty" env:"PICOCLAW_AGENTS_DEFAULTS_YOUNG_UNDER"`
+	TeenUnder  int `json:"teen_under,omitempty"  env:"PICOCLAW_AGENTS_DEFAULTS_TEEN_UNDER"`
+	// SafetyLanguage adds a non-English keyword set to the safety filter
+	// ("es", "fr", or "auto" to detect per message). English is always checked.
+	SafetyLanguage string `json:"safety_language,omitempty" env:"PICOCLAW_AGENTS_DEFAULTS_SAFETY_LANGUAGE"`
+	// RateLimit throttles how often a single sender may message the agent.
+	RateLimit *RateLimitConfig `json:"rate_limit,omitempty"`
 }
 
 const DefaultMaxMediaSize = 20 * 1024 * 1024 // 20 MB
@@ -811,6 +877,33 @@ func (c *IRCConfig) SetSASLPassword(password string) {
 type HeartbeatConfig struct {
 	Enabled  bool `json:"enabled"  env:"PICOCLAW_HEARTBEAT_ENABLED"`
 	Interval int  `json:"interval" env:"PICOCLAW_HEARTBEAT_INTERVAL"` // minutes, min 5
+	// Channel and ChatID, when both set, send heartbeats and their results
+	// to that chat instead of the last channel a user was active on.
+	Channel string `json:"channel,omitempty" env:"PICOCLAW_HEARTBEAT_CHANNEL"`
+	ChatID  string `json:"chat_id,omitempty" env:"PICOCLAW_HEARTBEAT_CHAT_ID"`
+}
+
+// MetricsConfig configures Prometheus metrics export.
+type MetricsConfig struct {
+	// PushgatewayURL, when set, makes short-lived processes push their metrics
+	// to a Prometheus Pushgateway on exit.
+	PushgatewayURL string `json:"pushgateway_url,omitempty" env:"PICOCLAW_METRICS_PUSHGATEWAY_URL"`
+	PushJob        string `json:"push_job,omitempty"        env:"PICOCLAW_METRICS_PUSH_JOB"`
+	// MaxUserIDs caps distinct user_id label values; further users are
+	// recorded as "other". Defaults to 1000.
+	MaxUserIDs int `json:"max_user_ids,omitempty" env:"PICOCLAW_METRICS_MAX_USER_IDS"`
+	// LLMDurationBuckets overrides the picoclaw_llm_request_duration_seconds
+	// histogram buckets, in seconds and strictly increasing.
+	LLMDurationBuckets []float64 `json:"llm_duration_buckets,omitempty"`
+}
+
+// TracingConfig configures span export for agent turns, LLM calls and tool
+// calls. Tracing is off by default.
+type TracingConfig struct {
+	Enabled bool `json:"enabled"                 env:"PICOCLAW_TRACING_ENABLED"`
+	// OTLPEndpoint is the base URL of an OTLP/HTTP collector, e.g. http://localhost:4318.
+	OTLPEndpoint string `json:"otlp_endpoint,omitempty" env:"PICOCLAW_TRACING_OTLP_ENDPOINT"`
+	ServiceName  string `json:"service_name,omitempty"  env:"PICOCLAW_TRACING_SERVICE_NAME"`
 }
 
 type DevicesConfig struct {
@@ -822,19 +915,90 @@ type VoiceConfig struct {
 	ModelName         string `json:"model_name,omitempty"         env:"PICOCLAW_VOICE_MODEL_NAME"`
 	EchoTranscription bool   `json:"echo_transcription"           env:"PICOCLAW_VOICE_ECHO_TRANSCRIPTION"`
 	ElevenLabsAPIKey  string `json:"elevenlabs_api_key,omitempty" env:"PICOCLAW_VOICE_ELEVENLABS_API_KEY"`
+	// WhisperAPIBase selects an OpenAI-compatible transcription endpoint,
+	// e.g. "https://api.openai.com/v1" or a local Whisper server. The key is
+	// optional for local servers; the model defaults to "whisper-1".
+	WhisperAPIBase string `json:"whisper_api_base,omitempty" env:"PICOCLAW_VOICE_WHISPER_API_BASE"`
+	WhisperAPIKey  string `json:"whisper_api_key,omitempty"  env:"PICOCLAW_VOICE_WHISPER_API_KEY"`
+	WhisperModel   string `json:"whisper_model,omitempty"    env:"PICOCLAW_VOICE_WHISPER_MODEL"`
+	// Language is the default ISO-639-1 hint sent with each transcription.
+	Language string `json:"language,omitempty" env:"PICOCLAW_VOICE_LANGUAGE"`
+	// Channels overrides the language hint and transcription model per
+	// channel name, e.g. "telegram".
+	Channels map[string]VoiceChannelConfig `json:"channels,omitempty"`
+}
+
+// VoiceChannelConfig tunes transcription for one channel. Empty fields fall
+// back to the voice defaults.
+type VoiceChannelConfig struct {
+	Language string `json:"language,omitempty"`
+	Model    string `json:"model,omitempty"`
+}
+
+// MemoryConfig configures long-term vector memory (session archiving and search).
+type MemoryConfig struct {
+	Enabled   bool            `json:"enabled"           env:"PICOCLAW_MEMORY_ENABLED"`
+	Backend   string          `json:"backend,omitempty" env:"PICOCLAW_MEMORY_BACKEND" enum:"qdrant,weaviate,memory"` // "qdrant" (default), "weaviate" or "memory"
+	Qdrant    QdrantConfig    `json:"qdrant"`
+	Weaviate  WeaviateConfig  `json:"weaviate,omitempty"`
+	Embedding EmbeddingConfig `json:"embedding"`
+	// ArchiveSummary has archived sessions summarized before embedding:
+	// "alongside" stores the summary with the raw chunks, "instead" stores
+	// only the summary. Empty stores raw chunks only.
+	ArchiveSummary string `json:"archive_summary,omitempty" env:"PICOCLAW_MEMORY_ARCHIVE_SUMMARY" enum:"alongside,instead"`
+	// CollectionPerWorkspace stores each workspace in its own collection,
+	// "<collection_name>_<workspace>", instead of sharing one collection
+	// filtered by workspace_id.
+	CollectionPerWorkspace bool `json:"collection_per_workspace,omitempty" env:"PICOCLAW_MEMORY_COLLECTION_PER_WORKSPACE"`
+}
+
+// QdrantConfig holds the connection settings for the Qdrant vector database.
+type QdrantConfig struct {
+	Address        string `json:"address"           env:"PICOCLAW_MEMORY_QDRANT_ADDRESS"`
+	APIKey         string `json:"api_key,omitempty" env:"PICOCLAW_MEMORY_QDRANT_API_KEY"`
+	CollectionName string `json:"collection_name"   env:"PICOCLAW_MEMORY_QDRANT_COLLECTION_NAME"`
+	// ConnectRetries and ConnectBackoffMS control the startup health check;
+	// zero values use the client defaults.
+	ConnectRetries   int `json:"connect_retries,omitempty"    env:"PICOCLAW_MEMORY_QDRANT_CONNECT_RETRIES"`
+	ConnectBackoffMS int `json:"connect_backoff_ms,omitempty" env:"PICOCLAW_MEMORY_QDRANT_CONNECT_BACKOFF_MS"`
+}
+
+// WeaviateConfig holds the connection settings for the Weaviate vector database.
+// The collection name is shared with QdrantConfig.CollectionName.
+type WeaviateConfig struct {
+	Address string `json:"address"           env:"PICOCLAW_MEMORY_WEAVIATE_ADDRESS"`
+	APIKey  string `json:"api_key,omitempty" env:"PICOCLAW_MEMORY_WEAVIATE_API_KEY"`
+}
+
+// EmbeddingConfig holds the settings for the embedding API used by memory.
+type EmbeddingConfig struct {
+	Provider  string `json:"provider"             env:"PICOCLAW_MEMORY_EMBEDDING_PROVIDER"` // "openai" or "ollama"
+	Model     string `json:"model"                env:"PICOCLAW_MEMORY_EMBEDDING_MODEL"`
+	APIKey    string `json:"api_key,omitempty"    env:"PICOCLAW_MEMORY_EMBEDDING_API_KEY"`
+	BaseURL   string `json:"base_url,omitempty"   env:"PICOCLAW_MEMORY_EMBEDDING_BASE_URL"`
+	ChunkSize int    `json:"chunk_size"           env:"PICOCLAW_MEMORY_EMBEDDING_CHUNK_SIZE"` // runes per chunk
+	Timeout   int    `json:"timeout"              env:"PICOCLAW_MEMORY_EMBEDDING_TIMEOUT"`    // seconds
+	KeepAlive string `json:"keep_alive,omitempty" env:"PICOCLAW_MEMORY_EMBEDDING_KEEP_ALIVE"` // Ollama only
+	NumCtx    int    `json:"num_ctx,omitempty"    env:"PICOCLAW_MEMORY_EMBEDDING_NUM_CTX"`    // Ollama only
+	// Normalize scales every embedding to unit length, for models whose
+	// vectors are not normalized and so rank poorly under cosine distance.
+	Normalize bool `json:"normalize,omitempty" env:"PICOCLAW_MEMORY_EMBEDDING_NORMALIZE"`
+	// ChunkTokens, when set, sizes chunks by tokens, counted for Model,
+	// instead of by ChunkSize runes.
+	ChunkTokens int `json:"chunk_tokens,omitempty" env:"PICOCLAW_MEMORY_EMBEDDING_CHUNK_TOKENS"`
 }
 
 // ModelConfig represents a model-centric provider configuration.
 // It allows adding new providers (especially OpenAI-compatible ones) via configuration only.
 // The model field uses protocol prefix format: [protocol/]model-identifier
 // Supported protocols include openai, anthropic, antigravity, claude-cli,
-// codex-cli, github-copilot, and named OpenAI-compatible protocols such as
-// groq, deepseek, modelscope, and novita.
+// codex-cli, github-copilot, echo (an offline test provider), and named
+// OpenAI-compatible protocols such as groq, deepseek, modelscope, and novita.
 // Default protocol is "openai" if no prefix is specified.
 type ModelConfig struct {
 	// Required fields
-	ModelName string `json:"model_name"` // User-facing alias for the model
-	Model     string `json:"model"`      // Protocol/model-identifier (e.g., "openai/gpt-4o", "anthropic/claude-sonnet-4.6")
+	ModelName string `json:"model_name" required:"true"` // User-facing alias for the model
+	Model     string `json:"model"      required:"true"` // Protocol/model-identifier (e.g., "openai/gpt-4o", "anthropic/claude-sonnet-4.6")
 
 	// HTTP-based providers
 	APIBase   string   `json:"api_base,omitempty"`  // API endpoint URL
@@ -842,16 +1006,39 @@ type ModelConfig struct {
 	Fallbacks []string `json:"fallbacks,omitempty"` // Fallback model names for failover
 
 	// Special providers (CLI-based, OAuth, etc.)
-	AuthMethod  string `json:"auth_method,omitempty"`  // Authentication method: oauth, token
-	ConnectMode string `json:"connect_mode,omitempty"` // Connection mode: stdio, grpc
-	Workspace   string `json:"workspace,omitempty"`    // Workspace path for CLI-based providers
+	AuthMethod  string `json:"auth_method,omitempty"`                    // Authentication method: oauth, token
+	ConnectMode string `json:"connect_mode,omitempty" enum:"stdio,grpc"` // Connection mode: stdio, grpc
+	Workspace   string `json:"workspace,omitempty"`                      // Workspace path for CLI-based providers
 
 	// Optional optimizations
 	RPM            int            `json:"rpm,omitempty"`              // Requests per minute limit
 	MaxTokensField string         `json:"max_tokens_field,omitempty"` // Field name for max tokens (e.g., "max_completion_tokens")
 	RequestTimeout int            `json:"request_timeout,omitempty"`
-	ThinkingLevel  string         `json:"thinking_level,omitempty"` // Extended thinking: off|low|medium|high|xhigh|adaptive
-	ExtraBody      map[string]any `json:"extra_body,omitempty"`     // Additional fields to inject into request body
+	ThinkingLevel  string         `json:"thinking_level,omitempty" enum:"off,low,medium,high,xhigh,adaptive"` // Extended thinking: off|low|medium|high|xhigh|adaptive
+	ExtraBody      map[string]any `json:"extra_body,omitempty"`                                               // Additional fields to inject into request body
+
+	// Pricing in USD per 1K tokens, used for the picoclaw_llm_cost_usd_total metric
+	InputPricePer1K  float64 `json:"input_price_per_1k,omitempty"`
+	OutputPricePer1K float64 `json:"output_price_per_1k,omitempty"`
+
+	// MaxConcurrent caps in-flight requests to this model's provider; further
+	// requests wait in a queue of up to MaxQueue and are rejected beyond it.
+	// Zero MaxConcurrent means unlimited.
+	MaxConcurrent int `json:"max_concurrent,omitempty"`
+	MaxQueue      int `json:"max_queue,omitempty"`
+	// MaxRetries retries timeouts, rate limits and 5xx errors on the same
+	// model with jittered exponential backoff before giving up.
+	MaxRetries int `json:"max_retries,omitempty"`
+	// DedupeRequests coalesces concurrent identical requests to this model
+	// into one upstream call whose response every caller shares.
+	DedupeRequests bool `json:"dedupe_requests,omitempty"`
+	// CacheTTL caches responses to identical tool-free prompts for this many
+	// seconds, keeping up to CacheSize entries (default 256). Zero disables it.
+	CacheTTL  int `json:"cache_ttl,omitempty"`
+	CacheSize int `json:"cache_size,omitempty"`
+	// ValidateMessages rejects malformed histories (bad roles, misplaced
+	// system prompts, orphaned tool results) before they are sent.
+	ValidateMessages bool `json:"validate_messages,omitempty"`
 
 	// from security
 	secModelName string
@@ -900,7 +1087,16 @@ type GatewayConfig struct {
 	Host      string `json:"host"                env:"PICOCLAW_GATEWAY_HOST"`
 	Port      int    `json:"port"                env:"PICOCLAW_GATEWAY_PORT"`
 	HotReload bool   `json:"hot_reload"          env:"PICOCLAW_GATEWAY_HOT_RELOAD"`
-	LogLevel  string `json:"log_level,omitempty" env:"PICOCLAW_LOG_LEVEL"`
+	LogLevel  string `json:"log_level,omitempty" env:"PICOCLAW_LOG_LEVEL"          enum:"debug,info,warn,warning,error,fatal"`
+	// LogFormat selects console log output: "text" (default) or "json",
+	// one JSON object per line for log collectors.
+	LogFormat string `json:"log_format,omitempty" env:"PICOCLAW_LOG_FORMAT" enum:"text,json"`
+	// ShutdownTimeout bounds a graceful shutdown, in seconds (default 15).
+	// Services still running at the deadline are logged and abandoned.
+	ShutdownTimeout int `json:"shutdown_timeout,omitempty" env:"PICOCLAW_GATEWAY_SHUTDOWN_TIMEOUT"`
+	// DashboardToken is the bearer token required by the dashboard's config,
+	// restart, MCP and cron-run endpoints. They are disabled while it is empty.
+	DashboardToken string `json:"dashboard_token,omitempty" env:"PICOCLAW_GATEWAY_DASHBOARD_TOKEN"`
 }
 
 type ToolDiscoveryConfig struct {
@@ -1090,6 +1286,10 @@ type CronToolsConfig struct {
 	ToolConfig         `     envPrefix:"PICOCLAW_TOOLS_CRON_"`
 	ExecTimeoutMinutes int  `                                 env:"PICOCLAW_TOOLS_CRON_EXEC_TIMEOUT_MINUTES" json:"exec_timeout_minutes"` // 0 means no timeout
 	AllowCommand       bool `                                 env:"PICOCLAW_TOOLS_CRON_ALLOW_COMMAND"        json:"allow_command"`
+	// MissedPolicy decides what happens to runs missed while the gateway was
+	// down: "skip" (default) waits for the next scheduled time, "run_once"
+	// runs each affected job once at startup.
+	MissedPolicy string `json:"missed_policy,omitempty" env:"PICOCLAW_TOOLS_CRON_MISSED_POLICY" enum:"skip,run_once"`
 }
 
 type ExecConfig struct {
@@ -1152,6 +1352,19 @@ type ToolsConfig struct {
 	Subagent        ToolConfig         `json:"subagent"                                                 envPrefix:"PICOCLAW_TOOLS_SUBAGENT_"`
 	WebFetch        ToolConfig         `json:"web_fetch"                                                envPrefix:"PICOCLAW_TOOLS_WEB_FETCH_"`
 	WriteFile       ToolConfig         `json:"write_file"                                               envPrefix:"PICOCLAW_TOOLS_WRITE_FILE_"`
+	MemorySearch    ToolConfig         `json:"memory_search"                                            envPrefix:"PICOCLAW_TOOLS_MEMORY_SEARCH_"`
+	MemoryBrowse    ToolConfig         `json:"memory_browse"                                            envPrefix:"PICOCLAW_TOOLS_MEMORY_BROWSE_"`
+	MemoryList      ToolConfig         `json:"memory_list"                                              envPrefix:"PICOCLAW_TOOLS_MEMORY_LIST_"`
+	// CircuitBreaker stops calling a tool that keeps failing.
+	CircuitBreaker *CircuitBreakerConfig `json:"circuit_breaker,omitempty"`
+}
+
+// CircuitBreakerConfig short-circuits a tool for CooldownSeconds (default 60)
+// after FailureThreshold consecutive failures. Zero FailureThreshold disables
+// the breaker.
+type CircuitBreakerConfig struct {
+	FailureThreshold int `json:"failure_threshold"`
+	CooldownSeconds  int `json:"cooldown_seconds,omitempty"`
 }
 
 // IsFilterSensitiveDataEnabled returns true if sensitive data filtering is enabled
@@ -1239,6 +1452,9 @@ type MCPServerConfig struct {
 	URL string `json:"url,omitempty"`
 	// Headers are HTTP headers to send with requests (sse/http only)
 	Headers map[string]string `json:"headers,omitempty"`
+	// StrictEnv makes undefined $VAR/${VAR} references in Command, Args and
+	// Env an error instead of expanding them to an empty string
+	StrictEnv bool `json:"strict_env,omitempty"`
 }
 
 // MCPConfig defines configuration for all MCP servers
@@ -2027,20 +2243,27 @@ func expandMultiKeyModels(models []*ModelConfig) []*ModelConfig {
 
 			// Create a copy for the additional key
 			additionalEntry := &ModelConfig{
-				ModelName:      expandedName,
-				Model:          m.Model,
-				APIBase:        m.APIBase,
-				apiKeys:        []string{keys[i]},
-				Proxy:          m.Proxy,
-				AuthMethod:     m.AuthMethod,
-				ConnectMode:    m.ConnectMode,
-				Workspace:      m.Workspace,
-				RPM:            m.RPM,
-				MaxTokensField: m.MaxTokensField,
-				RequestTimeout: m.RequestTimeout,
-				ThinkingLevel:  m.ThinkingLevel,
-				ExtraBody:      m.ExtraBody,
-				isVirtual:      true,
+				ModelName:        expandedName,
+				Model:            m.Model,
+				APIBase:          m.APIBase,
+				apiKeys:          []string{keys[i]},
+				Proxy:            m.Proxy,
+				AuthMethod:       m.AuthMethod,
+				ConnectMode:      m.ConnectMode,
+				Workspace:        m.Workspace,
+				RPM:              m.RPM,
+				MaxTokensField:   m.MaxTokensField,
+				RequestTimeout:   m.RequestTimeout,
+				ThinkingLevel:    m.ThinkingLevel,
+				ExtraBody:        m.ExtraBody,
+				MaxConcurrent:    m.MaxConcurrent,
+				MaxQueue:         m.MaxQueue,
+				MaxRetries:       m.MaxRetries,
+				DedupeRequests:   m.DedupeRequests,
+				CacheTTL:         m.CacheTTL,
+				CacheSize:        m.CacheSize,
+				ValidateMessages: m.ValidateMessages,
+				isVirtual:        true,
 			}
 			expanded = append(expanded, additionalEntry)
 			fallbackNames = append(fallbackNames, expandedName)
@@ -2048,19 +2271,26 @@ func expandMultiKeyModels(models []*ModelConfig) []*ModelConfig {
 
 		// Create the primary entry with first key and fallbacks
 		primaryEntry := &ModelConfig{
-			ModelName:      originalName,
-			Model:          m.Model,
-			APIBase:        m.APIBase,
-			Proxy:          m.Proxy,
-			AuthMethod:     m.AuthMethod,
-			ConnectMode:    m.ConnectMode,
-			Workspace:      m.Workspace,
-			RPM:            m.RPM,
-			MaxTokensField: m.MaxTokensField,
-			RequestTimeout: m.RequestTimeout,
-			ThinkingLevel:  m.ThinkingLevel,
-			ExtraBody:      m.ExtraBody,
-			apiKeys:        []string{keys[0]},
+			ModelName:        originalName,
+			Model:            m.Model,
+			APIBase:          m.APIBase,
+			Proxy:            m.Proxy,
+			AuthMethod:       m.AuthMethod,
+			ConnectMode:      m.ConnectMode,
+			Workspace:        m.Workspace,
+			RPM:              m.RPM,
+			MaxTokensField:   m.MaxTokensField,
+			RequestTimeout:   m.RequestTimeout,
+			ThinkingLevel:    m.ThinkingLevel,
+			ExtraBody:        m.ExtraBody,
+			MaxConcurrent:    m.MaxConcurrent,
+			MaxQueue:         m.MaxQueue,
+			MaxRetries:       m.MaxRetries,
+			DedupeRequests:   m.DedupeRequests,
+			CacheTTL:         m.CacheTTL,
+			CacheSize:        m.CacheSize,
+			ValidateMessages: m.ValidateMessages,
+			apiKeys:          []string{keys[0]},
 		}
 
 		// Prepend new fallbacks to existing ones
@@ -2120,6 +2350,12 @@ func (t *ToolsConfig) IsToolEnabled(name string) bool {
 		return t.WriteFile.Enabled
 	case "mcp":
 		return t.MCP.Enabled
+	case "memory_search":
+		return t.MemorySearch.Enabled
+	case "memory_browse":
+		return t.MemoryBrowse.Enabled
+	case "memory_list":
+		return t.MemoryList.Enabled
 	default:
 		return true
 	}
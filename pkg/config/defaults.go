@@ -42,7 +42,7 @@ func DefaultConfig() *Config {
 					Enabled:       true,
 					MaxArgsLength: 300,
 				},
-				SafetyLevel: "relaxed",
+				SafetyLevel: "off",
 				BirthYear:   0,
 			},
 		},
@@ -507,6 +507,9 @@ func DefaultConfig() *Config {
 			MemoryBrowse: ToolConfig{
 				Enabled: true,
 			},
+			MemoryList: ToolConfig{
+				Enabled: true,
+			},
 		},
 		Heartbeat: HeartbeatConfig{
 			Enabled:  true,
@@ -522,6 +525,7 @@ func DefaultConfig() *Config {
 		},
 		Memory: MemoryConfig{
 			Enabled: false,
+			Backend: "qdrant",
 			Qdrant: QdrantConfig{
 				Address:        "http://localhost:6334",
 				CollectionName: "picoclaw",
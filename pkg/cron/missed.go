@@ -0,0 +1,104 @@
+package cron
+
+import (
+	"log"
+	"time"
+
+	"github.com/adhocore/gronx"
+
+	"github.com/sipeed/picoclaw/pkg/metrics"
+)
+
+// Missed-run policies, applied by Start to jobs whose scheduled time passed
+// while the service was stopped.
+const (
+	// MissedPolicySkip drops missed runs; the job waits for its next
+	// scheduled time.
+	MissedPolicySkip = "skip"
+	// MissedPolicyRunOnce runs each job with missed runs once at start,
+	// however many runs were missed.
+	MissedPolicyRunOnce = "run_once"
+)
+
+// maxMissedScan bounds how many cron expression ticks are counted for one
+// job, so a long outage of a frequent job stays cheap to evaluate.
+const maxMissedScan = 10000
+
+// SetMissedPolicy sets how Start handles missed runs. Unknown policies,
+// including the empty string, behave like MissedPolicySkip.
+func (cs *CronService) SetMissedPolicy(policy string) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	cs.missedPolicy = policy
+}
+
+// detectMissedRuns counts the runs each enabled job missed before nowMS,
+// going by the next run time saved when the service last ran. It records
+// them in the cron missed metric and returns the jobs to catch up on under
+// the current policy.
+func (cs *CronService) detectMissedRuns(nowMS int64) map[string]bool {
+	catchUp := make(map[string]bool)
+	total := 0
+	for i := range cs.store.Jobs {
+		job := &cs.store.Jobs[i]
+		if !job.Enabled {
+			continue
+		}
+		missed := missedRuns(job, nowMS)
+		if missed == 0 {
+			continue
+		}
+		total += missed
+		log.Printf("[cron] job '%s' (id: %s) missed %d run(s) while stopped, policy: %s",
+			job.Name, job.ID, missed, cs.missedPolicyOrDefault())
+		if cs.missedPolicy == MissedPolicyRunOnce {
+			catchUp[job.ID] = true
+		}
+	}
+	if total > 0 {
+		metrics.DefaultRecorder().RecordCronMissed(total)
+	}
+	return catchUp
+}
+
+func (cs *CronService) missedPolicyOrDefault() string {
+	if cs.missedPolicy == MissedPolicyRunOnce {
+		return MissedPolicyRunOnce
+	}
+	return MissedPolicySkip
+}
+
+// missedRuns returns how many of job's scheduled times fall between its
+// saved next run and nowMS, inclusive. A run that already happened at or
+// after the expected time does not count.
+func missedRuns(job *CronJob, nowMS int64) int {
+	next := job.State.NextRunAtMS
+	if next == nil || *next > nowMS {
+		return 0
+	}
+	if last := job.State.LastRunAtMS; last != nil && *last >= *next {
+		return 0
+	}
+
+	switch job.Schedule.Kind {
+	case "every":
+		if job.Schedule.EveryMS == nil || *job.Schedule.EveryMS <= 0 {
+			return 1
+		}
+		return int((nowMS-*next) / *job.Schedule.EveryMS) + 1
+	case "cron":
+		count := 1
+		t := time.UnixMilli(*next)
+		for count < maxMissedScan {
+			tick, err := gronx.NextTickAfter(job.Schedule.Expr, t, false)
+			if err != nil || tick.UnixMilli() > nowMS {
+				break
+			}
+			count++
+			t = tick
+		}
+		return count
+	default:
+		return 1
+	}
+}
@@ -0,0 +1,137 @@
+package cron
+
+import (
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func cronMissedTotal(t *testing.T) float64 {
+	t.Helper()
+	families, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatalf("Gather: %v", err)
+	}
+	for _, mf := range families {
+		if mf.GetName() == "picoclaw_cron_missed_total" {
+			return mf.GetMetric()[0].GetCounter().GetValue()
+		}
+	}
+	return 0
+}
+
+func TestMissedRuns(t *testing.T) {
+	base := time.Date(2026, 3, 1, 10, 0, 0, 0, time.Local).UnixMilli()
+	hour := time.Hour.Milliseconds()
+	ms := func(v int64) *int64 { return &v }
+
+	tests := []struct {
+		name string
+		job  CronJob
+		now  int64
+		want int
+	}{
+		{
+			name: "not yet due",
+			job:  CronJob{Schedule: CronSchedule{Kind: "every", EveryMS: ms(hour)}, State: CronJobState{NextRunAtMS: ms(base)}},
+			now:  base - 1,
+			want: 0,
+		},
+		{
+			name: "every across a gap",
+			job:  CronJob{Schedule: CronSchedule{Kind: "every", EveryMS: ms(hour)}, State: CronJobState{NextRunAtMS: ms(base)}},
+			now:  base + 2*hour + hour/2,
+			want: 3,
+		},
+		{
+			name: "cron across a gap",
+			job:  CronJob{Schedule: CronSchedule{Kind: "cron", Expr: "0 * * * *"}, State: CronJobState{NextRunAtMS: ms(base)}},
+			now:  base + 2*hour + hour/2,
+			want: 3,
+		},
+		{
+			name: "one-shot in the past",
+			job:  CronJob{Schedule: CronSchedule{Kind: "at", AtMS: ms(base)}, State: CronJobState{NextRunAtMS: ms(base)}},
+			now:  base + hour,
+			want: 1,
+		},
+		{
+			name: "already ran",
+			job: CronJob{
+				Schedule: CronSchedule{Kind: "every", EveryMS: ms(hour)},
+				State:    CronJobState{NextRunAtMS: ms(base), LastRunAtMS: ms(base + 5)},
+			},
+			now:  base + hour/2,
+			want: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := missedRuns(&tt.job, tt.now); got != tt.want {
+				t.Errorf("missedRuns() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCronService_StartDetectsMissedRuns(t *testing.T) {
+	tests := []struct {
+		policy   string
+		wantRuns int32
+	}{
+		{MissedPolicySkip, 0},
+		{MissedPolicyRunOnce, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.policy, func(t *testing.T) {
+			storePath := filepath.Join(t.TempDir(), "jobs.json")
+
+			// Save a job whose next run fell 2.5 intervals before "restart".
+			every := time.Hour.Milliseconds()
+			before := NewCronService(storePath, nil)
+			job, err := before.AddJob("hourly", CronSchedule{Kind: "every", EveryMS: &every}, "check in", false, "cli", "direct")
+			if err != nil {
+				t.Fatalf("AddJob: %v", err)
+			}
+			missedAt := time.Now().Add(-150 * time.Minute).UnixMilli()
+			before.store.Jobs[0].State.NextRunAtMS = &missedAt
+			if err := before.saveStoreUnsafe(); err != nil {
+				t.Fatalf("saveStoreUnsafe: %v", err)
+			}
+
+			var runs atomic.Int32
+			cs := NewCronService(storePath, func(*CronJob) (string, error) {
+				runs.Add(1)
+				return "ok", nil
+			})
+			cs.SetMissedPolicy(tt.policy)
+
+			missedBefore := cronMissedTotal(t)
+			if err := cs.Start(); err != nil {
+				t.Fatalf("Start: %v", err)
+			}
+			defer cs.Stop()
+
+			if got := cronMissedTotal(t) - missedBefore; got != 3 {
+				t.Errorf("cron missed delta = %v, want 3", got)
+			}
+
+			deadline := time.Now().Add(2 * time.Second)
+			for runs.Load() < tt.wantRuns && time.Now().Before(deadline) {
+				time.Sleep(10 * time.Millisecond)
+			}
+			time.Sleep(50 * time.Millisecond)
+			if got := runs.Load(); got != tt.wantRuns {
+				t.Errorf("job ran %d times, want %d", got, tt.wantRuns)
+			}
+
+			got, _ := cs.FindJob(job.ID)
+			if next := got.State.NextRunAtMS; next == nil || *next <= time.Now().UnixMilli() {
+				t.Errorf("next run = %v, want a future time", next)
+			}
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"crypto/rand"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"os"
@@ -23,6 +24,14 @@ type CronSchedule struct {
 	TZ      string `json:"tz,omitempty"`
 }
 
+// Payload kinds say what a job runs. Jobs saved before kinds were tracked
+// carry "agent_turn", which PayloadKind reports as a prompt.
+const (
+	PayloadKindPrompt   = "prompt"   // a message delivered or handled by the agent
+	PayloadKindSubagent = "subagent" // a task handed to a subagent
+	PayloadKindShell    = "shell"    // a shell command
+)
+
 type CronPayload struct {
 	Kind    string `json:"kind"`
 	Message string `json:"message"`
@@ -51,6 +60,19 @@ type CronJob struct {
 	DeleteAfterRun bool         `json:"deleteAfterRun"`
 }
 
+// PayloadKind returns the job's payload kind. A job with a command is always
+// a shell job; unknown and legacy kinds are prompts.
+func (j *CronJob) PayloadKind() string {
+	switch {
+	case j.Payload.Command != "":
+		return PayloadKindShell
+	case j.Payload.Kind == PayloadKindSubagent:
+		return PayloadKindSubagent
+	default:
+		return PayloadKindPrompt
+	}
+}
+
 type CronStore struct {
 	Version int       `json:"version"`
 	Jobs    []CronJob `json:"jobs"`
@@ -58,6 +80,9 @@ type CronStore struct {
 
 type JobHandler func(job *CronJob) (string, error)
 
+// ErrJobNotFound is returned when a job ID or name does not match any job.
+var ErrJobNotFound = errors.New("cron job not found")
+
 type CronService struct {
 	storePath string
 	store     *CronStore
@@ -67,6 +92,9 @@ type CronService struct {
 	stopChan  chan struct{}
 	wakeChan  chan struct{}
 	gronx     *gronx.Gronx
+	// missedPolicy decides what Start does with runs missed while the
+	// service was stopped.
+	missedPolicy string
 }
 
 func NewCronService(storePath string, onJob JobHandler) *CronService {
@@ -93,7 +121,14 @@ func (cs *CronService) Start() error {
 		return fmt.Errorf("failed to load store: %w", err)
 	}
 
+	now := time.Now().UnixMilli()
+	catchUp := cs.detectMissedRuns(now)
 	cs.recomputeNextRuns()
+	for i := range cs.store.Jobs {
+		if catchUp[cs.store.Jobs[i].ID] {
+			cs.store.Jobs[i].State.NextRunAtMS = &now
+		}
+	}
 	if err := cs.saveStoreUnsafe(); err != nil {
 		return fmt.Errorf("failed to save store: %w", err)
 	}
@@ -212,7 +247,37 @@ func (cs *CronService) checkJobs() {
 	}
 }
 
-func (cs *CronService) executeJobByID(jobID string) {
+// RunJob runs the job with the given ID or name immediately, outside its
+// schedule, and records the outcome like a scheduled run. It blocks until
+// the job handler returns and reports the handler's error.
+func (cs *CronService) RunJob(idOrName string) error {
+	job, ok := cs.FindJob(idOrName)
+	if !ok {
+		return ErrJobNotFound
+	}
+	return cs.executeJobByID(job.ID)
+}
+
+// FindJob returns a copy of the job with the given ID or, failing that, the
+// first job with the given name.
+func (cs *CronService) FindJob(idOrName string) (CronJob, bool) {
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+
+	for _, job := range cs.store.Jobs {
+		if job.ID == idOrName {
+			return job, true
+		}
+	}
+	for _, job := range cs.store.Jobs {
+		if job.Name == idOrName {
+			return job, true
+		}
+	}
+	return CronJob{}, false
+}
+
+func (cs *CronService) executeJobByID(jobID string) error {
 	startTime := time.Now().UnixMilli()
 
 	cs.mu.RLock()
@@ -229,7 +294,7 @@ func (cs *CronService) executeJobByID(jobID string) {
 
 	if callbackJob == nil {
 		log.Printf("[cron] job %s not found, skipping", jobID)
-		return
+		return ErrJobNotFound
 	}
 
 	// Log job execution start
@@ -256,7 +321,7 @@ func (cs *CronService) executeJobByID(jobID string) {
 	}
 	if job == nil {
 		log.Printf("[cron] job %s disappeared before state update", jobID)
-		return
+		return err
 	}
 
 	job.State.LastRunAtMS = &startTime
@@ -299,6 +364,7 @@ func (cs *CronService) executeJobByID(jobID string) {
 	if err := cs.saveStoreUnsafe(); err != nil {
 		log.Printf("[cron] failed to save store: %v", err)
 	}
+	return err
 }
 
 func (cs *CronService) computeNextRun(schedule *CronSchedule, nowMS int64) *int64 {
@@ -426,7 +492,7 @@ func (cs *CronService) AddJob(
 		Enabled:  true,
 		Schedule: schedule,
 		Payload: CronPayload{
-			Kind:    "agent_turn",
+			Kind:    PayloadKindPrompt,
 			Message: message,
 			Deliver: deliver,
 			Channel: channel,
@@ -530,7 +596,7 @@ func (cs *CronService) ListJobs(includeDisabled bool) []CronJob {
 	defer cs.mu.RUnlock()
 
 	if includeDisabled {
-		return cs.store.Jobs
+		return append([]CronJob(nil), cs.store.Jobs...)
 	}
 
 	var enabled []CronJob
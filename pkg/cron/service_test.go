@@ -235,3 +235,46 @@ func TestCronService_ConcurrentAccess(t *testing.T) {
 
 	wg.Wait()
 }
+
+func TestRunJob(t *testing.T) {
+	cs := NewCronService(filepath.Join(t.TempDir(), "jobs.json"), func(job *CronJob) (string, error) {
+		return "", fmt.Errorf("boom")
+	})
+	job, err := cs.AddJob("nightly", CronSchedule{Kind: "every", EveryMS: int64Ptr(60000)}, "hi", false, "cli", "direct")
+	if err != nil {
+		t.Fatalf("AddJob failed: %v", err)
+	}
+
+	if err := cs.RunJob("nightly"); err == nil || err.Error() != "boom" {
+		t.Errorf("RunJob by name: err = %v, want boom", err)
+	}
+	got, ok := cs.FindJob(job.ID)
+	if !ok || got.State.LastStatus != "error" || got.State.LastError != "boom" {
+		t.Errorf("job state after run = %+v", got.State)
+	}
+
+	if err := cs.RunJob("missing"); err != ErrJobNotFound {
+		t.Errorf("RunJob(missing): err = %v, want ErrJobNotFound", err)
+	}
+}
+
+func TestCronJob_PayloadKind(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload CronPayload
+		want    string
+	}{
+		{"prompt", CronPayload{Kind: PayloadKindPrompt, Message: "hi"}, PayloadKindPrompt},
+		{"legacy agent turn", CronPayload{Kind: "agent_turn", Message: "hi"}, PayloadKindPrompt},
+		{"subagent", CronPayload{Kind: PayloadKindSubagent, Message: "research"}, PayloadKindSubagent},
+		{"command wins", CronPayload{Kind: "agent_turn", Command: "uptime"}, PayloadKindShell},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job := &CronJob{Payload: tt.payload}
+			if got := job.PayloadKind(); got != tt.want {
+				t.Errorf("PayloadKind() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
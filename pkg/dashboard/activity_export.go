@@ -0,0 +1,79 @@
+package dashboard
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// activityExportColumns are the event keys written by the CSV export, in
+// column order.
+var activityExportColumns = []string{"time", "type", "channel", "sender", "chat_id", "direction", "content"}
+
+// handleActivityExport serves the retained activity as a downloadable CSV
+// or JSON file, selected by the format query parameter (default json). It
+// exposes chat history, so it is registered behind requireAuth.
+func (s *Server) handleActivityExport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "json"
+	}
+	if format != "csv" && format != "json" {
+		http.Error(w, fmt.Sprintf("Unsupported format %q, use csv or json", format), http.StatusBadRequest)
+		return
+	}
+
+	events, err := s.activity.History()
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to read activity: %v", err), http.StatusInternalServerError)
+		return
+	}
+	filename := fmt.Sprintf("activity-%s.%s", time.Now().Format("20060102-150405"), format)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+
+	if format == "json" {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(events)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	cw := csv.NewWriter(w)
+	cw.Write(activityExportColumns)
+	row := make([]string, len(activityExportColumns))
+	for _, ev := range events {
+		for i, col := range activityExportColumns {
+			row[i] = formatActivityField(ev[col])
+		}
+		cw.Write(row)
+	}
+	cw.Flush()
+}
+
+// formatActivityField renders an event value as a CSV cell. Cells that a
+// spreadsheet would evaluate as a formula are prefixed with a quote.
+func formatActivityField(v any) string {
+	var cell string
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		cell = val
+	case time.Time:
+		return val.Format(time.RFC3339)
+	default:
+		cell = fmt.Sprint(val)
+	}
+	if cell != "" && strings.ContainsRune("=+-@", rune(cell[0])) {
+		cell = "'" + cell
+	}
+	return cell
+}
@@ -0,0 +1,117 @@
+package dashboard
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newExportTestServer() *Server {
+	s := NewServer("127.0.0.1", 0, nil, "", nil)
+	s.SetAuthToken(testAuthToken)
+	at := time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC)
+	s.activity.Add(map[string]interface{}{
+		"time": at, "type": "message", "channel": "telegram",
+		"sender": "alice", "chat_id": "42", "direction": "inbound", "content": "hi, bot",
+	})
+	s.activity.Add(map[string]interface{}{
+		"time": at.Add(time.Second), "type": "message", "channel": "telegram",
+		"chat_id": "42", "direction": "outbound", "content": "hello\nalice",
+	})
+	s.activity.Add(map[string]interface{}{"type": "tool", "channel": "cli", "content": "read_file"})
+	return s
+}
+
+func getExport(s *Server, format, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/api/activity/export?format="+format, nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, req)
+	return rec
+}
+
+func TestActivityExport_CSV(t *testing.T) {
+	s := newExportTestServer()
+	rec := getExport(s, "csv", testAuthToken)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rec.Code)
+	}
+	if cd := rec.Header().Get("Content-Disposition"); !strings.HasPrefix(cd, "attachment;") || !strings.Contains(cd, ".csv") {
+		t.Errorf("unexpected Content-Disposition %q", cd)
+	}
+
+	records, err := csv.NewReader(rec.Body).ReadAll()
+	if err != nil {
+		t.Fatalf("invalid CSV: %v", err)
+	}
+	want := "time,type,channel,sender,chat_id,direction,content"
+	if got := strings.Join(records[0], ","); got != want {
+		t.Errorf("header = %q, want %q", got, want)
+	}
+	if got := len(records) - 1; got != 3 {
+		t.Fatalf("got %d data rows, want 3", got)
+	}
+	if records[1][0] != "2026-03-01T09:30:00Z" || records[1][3] != "alice" || records[1][6] != "hi, bot" {
+		t.Errorf("unexpected first row %q", records[1])
+	}
+	if records[2][6] != "hello\nalice" {
+		t.Errorf("multi-line content not preserved: %q", records[2][6])
+	}
+}
+
+func TestActivityExport_JSON(t *testing.T) {
+	s := newExportTestServer()
+	rec := getExport(s, "json", testAuthToken)
+
+	if cd := rec.Header().Get("Content-Disposition"); !strings.Contains(cd, ".json") {
+		t.Errorf("unexpected Content-Disposition %q", cd)
+	}
+	var events []map[string]any
+	if err := json.NewDecoder(rec.Body).Decode(&events); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(events) != 3 {
+		t.Errorf("got %d events, want 3", len(events))
+	}
+
+	rec = getExport(s, "xml", testAuthToken)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("format=xml: expected 400, got %d", rec.Code)
+	}
+}
+
+func TestActivityExport_RequiresAuth(t *testing.T) {
+	s := newExportTestServer()
+	if rec := getExport(s, "json", ""); rec.Code != http.StatusUnauthorized {
+		t.Errorf("without token: expected 401, got %d", rec.Code)
+	}
+	if rec := getExport(s, "json", "wrong"); rec.Code != http.StatusUnauthorized {
+		t.Errorf("wrong token: expected 401, got %d", rec.Code)
+	}
+}
+
+func TestFormatActivityField_NeutralizesFormulas(t *testing.T) {
+	tests := map[string]string{
+		"=HYPERLINK(\"http://x\")": "'=HYPERLINK(\"http://x\")",
+		"+1":                       "'+1",
+		"-2":                       "'-2",
+		"@SUM(A1)":                 "'@SUM(A1)",
+		"hi, bot":                  "hi, bot",
+		"":                         "",
+	}
+	for in, want := range tests {
+		if got := formatActivityField(in); got != want {
+			t.Errorf("formatActivityField(%q) = %q, want %q", in, got, want)
+		}
+	}
+	if got := formatActivityField(-3); got != "'-3" {
+		t.Errorf("formatActivityField(-3) = %q, want %q", got, "'-3")
+	}
+}
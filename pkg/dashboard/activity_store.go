@@ -0,0 +1,150 @@
+package dashboard
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/sipeed/picoclaw/pkg/fileutil"
+	"github.com/sipeed/picoclaw/pkg/logger"
+)
+
+// activityCompactInterval is how often a persistent buffer rewrites its file
+// to drop expired events while running.
+const activityCompactInterval = time.Hour
+
+// NewPersistentActivityBuffer returns an ActivityBuffer that also appends
+// every event to the JSONL file at path. Events younger than retention are
+// loaded on startup, so recent history survives a restart; older events are
+// dropped from the file on startup and periodically as events are appended.
+// A non-positive retention keeps everything.
+func NewPersistentActivityBuffer(size int, path string, retention time.Duration) (*ActivityBuffer, error) {
+	ab := NewActivityBuffer(size)
+	ab.path = path
+	ab.retention = retention
+
+	events, err := ab.readRetained()
+	if err != nil {
+		return nil, err
+	}
+	if err := ab.compactLocked(events); err != nil {
+		return nil, err
+	}
+
+	if len(events) > size {
+		events = events[len(events)-size:]
+	}
+	ab.events = append(ab.events, events...)
+	return ab, nil
+}
+
+// History returns every retained event, oldest first. For a buffer without
+// a backing file this is the same as GetEvents.
+func (ab *ActivityBuffer) History() ([]map[string]interface{}, error) {
+	if ab.path == "" {
+		return ab.GetEvents(), nil
+	}
+	ab.mu.RLock()
+	defer ab.mu.RUnlock()
+	return ab.readRetained()
+}
+
+// appendLocked writes event to the backing file. Callers must hold ab.mu.
+func (ab *ActivityBuffer) appendLocked(event map[string]interface{}) {
+	data, err := json.Marshal(event)
+	if err == nil {
+		var f *os.File
+		f, err = os.OpenFile(ab.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+		if err == nil {
+			_, err = f.Write(append(data, '\n'))
+			if closeErr := f.Close(); err == nil {
+				err = closeErr
+			}
+		}
+	}
+	if err != nil {
+		logger.WarnCF("dashboard", "Failed to persist activity event", map[string]any{"error": err.Error()})
+		return
+	}
+
+	if ab.retention <= 0 || time.Since(ab.compactedAt) < min(activityCompactInterval, ab.retention) {
+		return
+	}
+	events, err := ab.readRetained()
+	if err == nil {
+		err = ab.compactLocked(events)
+	}
+	if err != nil {
+		logger.WarnCF("dashboard", "Failed to compact activity log", map[string]any{"error": err.Error()})
+	}
+}
+
+// compactLocked rewrites the backing file so it holds only events. Callers
+// must hold ab.mu or own ab exclusively.
+func (ab *ActivityBuffer) compactLocked(events []map[string]interface{}) error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, ev := range events {
+		if err := enc.Encode(ev); err != nil {
+			return fmt.Errorf("failed to encode activity event: %w", err)
+		}
+	}
+	if err := fileutil.WriteFileAtomic(ab.path, buf.Bytes(), 0o600); err != nil {
+		return fmt.Errorf("failed to compact activity log: %w", err)
+	}
+	ab.compactedAt = time.Now()
+	return nil
+}
+
+// readRetained reads the backing file and drops events older than the
+// retention window. Lines that fail to parse are skipped.
+func (ab *ActivityBuffer) readRetained() ([]map[string]interface{}, error) {
+	f, err := os.Open(ab.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open activity log: %w", err)
+	}
+	defer f.Close()
+
+	var cutoff time.Time
+	if ab.retention > 0 {
+		cutoff = time.Now().Add(-ab.retention)
+	}
+
+	var events []map[string]interface{}
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		var ev map[string]interface{}
+		if err := json.Unmarshal(scanner.Bytes(), &ev); err != nil {
+			continue
+		}
+		if at, ok := eventTime(ev); ok && at.Before(cutoff) {
+			continue
+		}
+		events = append(events, ev)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read activity log %s: %w", ab.path, err)
+	}
+	return events, nil
+}
+
+// eventTime extracts the "time" of an event as stored in memory (time.Time)
+// or decoded from JSON (an RFC 3339 string).
+func eventTime(ev map[string]interface{}) (time.Time, bool) {
+	switch v := ev["time"].(type) {
+	case time.Time:
+		return v, true
+	case string:
+		t, err := time.Parse(time.RFC3339Nano, v)
+		return t, err == nil
+	}
+	return time.Time{}, false
+}
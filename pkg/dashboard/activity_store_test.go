@@ -0,0 +1,106 @@
+package dashboard
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPersistentActivityBuffer_Reload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "activity.jsonl")
+
+	ab, err := NewPersistentActivityBuffer(2, path, time.Hour)
+	if err != nil {
+		t.Fatalf("NewPersistentActivityBuffer: %v", err)
+	}
+	for _, content := range []string{"one", "two", "three"} {
+		ab.Add(map[string]interface{}{"type": "message", "content": content})
+	}
+
+	reloaded, err := NewPersistentActivityBuffer(2, path, time.Hour)
+	if err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+
+	// The live ring keeps its size; the file keeps the full history.
+	events := reloaded.GetEvents()
+	if len(events) != 2 || events[0]["content"] != "two" || events[1]["content"] != "three" {
+		t.Errorf("ring after reload = %v, want [two three]", events)
+	}
+	history, err := reloaded.History()
+	if err != nil {
+		t.Fatalf("History: %v", err)
+	}
+	if len(history) != 3 {
+		t.Errorf("history has %d events, want 3", len(history))
+	}
+	if _, ok := history[0]["time"]; !ok {
+		t.Error("persisted events should be stamped with a time")
+	}
+}
+
+func TestPersistentActivityBuffer_Retention(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "activity.jsonl")
+	old := time.Now().Add(-48 * time.Hour).Format(time.RFC3339Nano)
+	recent := time.Now().Add(-time.Hour).Format(time.RFC3339Nano)
+	lines := []string{
+		`{"time":"` + old + `","content":"stale"}`,
+		`not json`,
+		`{"time":"` + recent + `","content":"fresh"}`,
+	}
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	ab, err := NewPersistentActivityBuffer(10, path, 24*time.Hour)
+	if err != nil {
+		t.Fatalf("NewPersistentActivityBuffer: %v", err)
+	}
+	events := ab.GetEvents()
+	if len(events) != 1 || events[0]["content"] != "fresh" {
+		t.Errorf("events = %v, want only the fresh event", events)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "stale") {
+		t.Error("expired events should be compacted out of the file")
+	}
+}
+
+func TestPersistentActivityBuffer_CompactsWhileRunning(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "activity.jsonl")
+	ab, err := NewPersistentActivityBuffer(10, path, 24*time.Hour)
+	if err != nil {
+		t.Fatalf("NewPersistentActivityBuffer: %v", err)
+	}
+
+	// An event that expired after startup, as if the gateway had been up for days.
+	old := time.Now().Add(-48 * time.Hour).Format(time.RFC3339Nano)
+	if err := os.WriteFile(path, []byte(`{"time":"`+old+`","content":"stale"}`+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	ab.Add(map[string]interface{}{"content": "soon"})
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "stale") {
+		t.Error("compaction should not run on every append")
+	}
+
+	ab.compactedAt = time.Now().Add(-activityCompactInterval)
+	ab.Add(map[string]interface{}{"content": "later"})
+	data, err = os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "stale") || !strings.Contains(string(data), "later") {
+		t.Errorf("file after compaction = %q, want expired events dropped and new ones kept", data)
+	}
+}
@@ -1,40 +1,172 @@
 package dashboard
 
 import (
+	"context"
+	"crypto/subtle"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
 	"path/filepath"
 	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/sipeed/picoclaw/pkg/config"
 	"github.com/sipeed/picoclaw/pkg/logger"
 )
 
+// Reloadable is implemented by services that can rebuild themselves from a
+// freshly loaded config without restarting the process. Implementations must
+// swap their internal state atomically so in-flight work is not interrupted.
+type Reloadable interface {
+	Reload(ctx context.Context, cfg *config.Config) error
+}
+
+// ReloadableFunc adapts an ordinary function to the Reloadable interface.
+type ReloadableFunc func(ctx context.Context, cfg *config.Config) error
+
+// Reload calls f(ctx, cfg).
+func (f ReloadableFunc) Reload(ctx context.Context, cfg *config.Config) error {
+	return f(ctx, cfg)
+}
+
 // ConfigAPI handles configuration management endpoints.
 type ConfigAPI struct {
 	configPath string
-	appConfig  *config.Config
+	appConfig  atomic.Pointer[config.Config]
+
+	reloadMu    sync.Mutex
+	reloadables []Reloadable
+
+	authToken atomic.Pointer[string]
+
+	maxBodyBytes atomic.Int64
+
+	// exit ends the process for a restart; tests replace it.
+	exit func()
 }
 
+// defaultMaxConfigBodySize caps config bodies accepted by the API. Real
+// configs are a few kilobytes; the cap only guards against runaway uploads.
+const defaultMaxConfigBodySize = 4 << 20 // 4 MiB
+
 // NewConfigAPI creates a new ConfigAPI.
 func NewConfigAPI(configPath string, cfg *config.Config) *ConfigAPI {
-	return &ConfigAPI{
+	api := &ConfigAPI{
 		configPath: configPath,
-		appConfig:  cfg,
+		exit: func() {
+			time.Sleep(1 * time.Second)
+			os.Exit(0) // Rely on Docker/Systemd restart policy
+		},
+	}
+	api.appConfig.Store(cfg)
+	api.maxBodyBytes.Store(defaultMaxConfigBodySize)
+	return api
+}
+
+// Config returns the currently active configuration.
+func (api *ConfigAPI) Config() *config.Config {
+	return api.appConfig.Load()
+}
+
+// AddReloadable registers a service to be rebuilt on in-process reload.
+// Services are reloaded in registration order.
+func (api *ConfigAPI) AddReloadable(r Reloadable) {
+	api.reloadMu.Lock()
+	defer api.reloadMu.Unlock()
+	api.reloadables = append(api.reloadables, r)
+}
+
+// canReload reports whether any service can be rebuilt in-process.
+func (api *ConfigAPI) canReload() bool {
+	api.reloadMu.Lock()
+	defer api.reloadMu.Unlock()
+	return len(api.reloadables) > 0
+}
+
+// Reload re-reads the config file and rebuilds every registered service.
+// The active config is only swapped once all services reloaded successfully.
+func (api *ConfigAPI) Reload(ctx context.Context) error {
+	api.reloadMu.Lock()
+	defer api.reloadMu.Unlock()
+
+	cfg, err := config.LoadConfig(api.configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	for _, r := range api.reloadables {
+		if err := r.Reload(ctx, cfg); err != nil {
+			return fmt.Errorf("failed to reload service: %w", err)
+		}
+	}
+
+	api.appConfig.Store(cfg)
+	return nil
+}
+
+// SetAuthToken overrides the bearer token that requests to the config API,
+// and other privileged dashboard endpoints, must carry as
+// "Authorization: Bearer <token>". Without an override the token comes from
+// gateway.dashboard_token in the active config. While no token is set, those
+// endpoints reject every request.
+func (api *ConfigAPI) SetAuthToken(token string) {
+	api.authToken.Store(&token)
+}
+
+// token returns the bearer token privileged endpoints require, or "" if none
+// is configured.
+func (api *ConfigAPI) token() string {
+	if token := api.authToken.Load(); token != nil {
+		return *token
+	}
+	if cfg := api.Config(); cfg != nil {
+		return cfg.Gateway.DashboardToken
+	}
+	return ""
+}
+
+// SetMaxBodySize limits the size of config bodies sent to the API; larger
+// requests are rejected with 413. A non-positive size restores the default.
+func (api *ConfigAPI) SetMaxBodySize(n int64) {
+	if n <= 0 {
+		n = defaultMaxConfigBodySize
+	}
+	api.maxBodyBytes.Store(n)
+}
+
+// requireAuth wraps h with the bearer token check. Requests are denied
+// outright when no token is configured.
+func (api *ConfigAPI) requireAuth(h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := api.token()
+		if token == "" {
+			http.Error(w, "Forbidden: set gateway.dashboard_token to enable this endpoint", http.StatusForbidden)
+			return
+		}
+		got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		h(w, r)
 	}
 }
 
 // RegisterRoutes registers configuration API routes.
 func (api *ConfigAPI) RegisterRoutes(mux *http.ServeMux) {
-	mux.HandleFunc("/api/config", api.handleConfig)
-	mux.HandleFunc("/api/config/schema", api.handleSchema)
-	mux.HandleFunc("/api/config/backups", api.handleBackups)
-	mux.HandleFunc("/api/config/rollback", api.handleRollback)
-	mux.HandleFunc("/api/restart", api.handleRestart)
+	mux.HandleFunc("/api/config", api.requireAuth(api.handleConfig))
+	mux.HandleFunc("/api/config/schema", api.requireAuth(api.handleSchema))
+	mux.HandleFunc("/api/config/diff", api.requireAuth(api.handleDiff))
+	mux.HandleFunc("/api/config/validate", api.requireAuth(api.handleValidate))
+	mux.HandleFunc("/api/config/backups", api.requireAuth(api.handleBackups))
+	mux.HandleFunc("/api/config/rollback", api.requireAuth(api.handleRollback))
+	mux.HandleFunc("/api/restart", api.requireAuth(api.handleRestart))
 }
 
 func (api *ConfigAPI) handleConfig(w http.ResponseWriter, r *http.Request) {
@@ -49,16 +181,15 @@ func (api *ConfigAPI) handleConfig(w http.ResponseWriter, r *http.Request) {
 		w.Write(data)
 
 	case http.MethodPut:
-		body, err := io.ReadAll(r.Body)
-		if err != nil {
-			http.Error(w, "Failed to read body", http.StatusBadRequest)
+		// 1. Validate JSON
+		body, ok := api.readCandidateConfig(w, r)
+		if !ok {
 			return
 		}
-
-		// 1. Validate JSON
-		var testCfg config.Config
-		if err := json.Unmarshal(body, &testCfg); err != nil {
-			http.Error(w, fmt.Sprintf("Invalid JSON: %v", err), http.StatusBadRequest)
+		if violations, err := ValidateConfig(body); err == nil && len(violations) > 0 {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			json.NewEncoder(w).Encode(map[string]any{"valid": false, "violations": violations})
 			return
 		}
 
@@ -81,6 +212,28 @@ func (api *ConfigAPI) handleConfig(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// readCandidateConfig reads a config body from r and checks that it decodes
+// into config.Config. On failure it writes a 400 response, or 413 when the
+// body exceeds the configured size, and returns false.
+func (api *ConfigAPI) readCandidateConfig(w http.ResponseWriter, r *http.Request) ([]byte, bool) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, api.maxBodyBytes.Load()))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			http.Error(w, "Request entity too large", http.StatusRequestEntityTooLarge)
+			return nil, false
+		}
+		http.Error(w, "Failed to read body", http.StatusBadRequest)
+		return nil, false
+	}
+	var testCfg config.Config
+	if err := json.Unmarshal(body, &testCfg); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid JSON: %v", err), http.StatusBadRequest)
+		return nil, false
+	}
+	return body, true
+}
+
 func (api *ConfigAPI) handleSchema(w http.ResponseWriter, r *http.Request) {
 	schema := GenerateSchema()
 	w.Header().Set("Content-Type", "application/json")
@@ -127,20 +280,31 @@ func (api *ConfigAPI) handleRollback(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(map[string]string{"status": "rolled back"})
 }
 
+// handleRestart restarts the gateway process so the saved config takes
+// effect. Only when services have registered a Reloadable is the config
+// reloaded in-process instead; ?hard=true always restarts.
 func (api *ConfigAPI) handleRestart(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(map[string]string{"status": "restarting"})
+	if r.URL.Query().Get("hard") == "true" || !api.canReload() {
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(map[string]string{"status": "restarting"})
 
-	// Trigger graceful restart
-	go func() {
-		time.Sleep(1 * time.Second)
-		os.Exit(0) // Rely on Docker/Systemd restart policy
-	}()
+		go api.exit()
+		return
+	}
+
+	if err := api.Reload(r.Context()); err != nil {
+		logger.ErrorCF("dashboard", "Config reload failed", map[string]interface{}{"error": err.Error()})
+		http.Error(w, fmt.Sprintf("Reload failed: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"status": "reloaded"})
 }
 
 func (api *ConfigAPI) createBackup() error {
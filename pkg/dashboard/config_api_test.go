@@ -0,0 +1,340 @@
+package dashboard
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sipeed/picoclaw/pkg/config"
+)
+
+func writeTestConfig(t *testing.T, path, modelName string) {
+	t.Helper()
+	data := `{"version": 1, "agents": {"defaults": {"model_name": "` + modelName + `"}}}`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+}
+
+// testAuthToken is the dashboard token used by tests that exercise
+// privileged endpoints.
+const testAuthToken = "test-token"
+
+func newTestConfigAPI(configPath string, cfg *config.Config) *ConfigAPI {
+	api := NewConfigAPI(configPath, cfg)
+	api.SetAuthToken(testAuthToken)
+	return api
+}
+
+// newAuthedRequest is httptest.NewRequest carrying the test bearer token.
+func newAuthedRequest(method, target string, body io.Reader) *http.Request {
+	req := httptest.NewRequest(method, target, body)
+	req.Header.Set("Authorization", "Bearer "+testAuthToken)
+	return req
+}
+
+func TestConfigAPI_ReloadInProcess(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	writeTestConfig(t, configPath, "first")
+
+	initial, err := config.LoadConfig(configPath)
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+
+	api := newTestConfigAPI(configPath, initial)
+	var reloadedModel string
+	api.AddReloadable(ReloadableFunc(func(ctx context.Context, cfg *config.Config) error {
+		reloadedModel = cfg.Agents.Defaults.ModelName
+		return nil
+	}))
+
+	writeTestConfig(t, configPath, "second")
+
+	mux := http.NewServeMux()
+	api.RegisterRoutes(mux)
+	req := newAuthedRequest(http.MethodPost, "/api/restart", nil)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if reloadedModel != "second" {
+		t.Errorf("expected reloadable to see model 'second', got %q", reloadedModel)
+	}
+	if got := api.Config().Agents.Defaults.ModelName; got != "second" {
+		t.Errorf("expected active config model 'second', got %q", got)
+	}
+}
+
+func TestConfigAPI_RestartExitsWithoutReloadables(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	writeTestConfig(t, configPath, "first")
+
+	initial, err := config.LoadConfig(configPath)
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+
+	api := newTestConfigAPI(configPath, initial)
+	exited := make(chan struct{})
+	api.exit = func() { close(exited) }
+
+	mux := http.NewServeMux()
+	api.RegisterRoutes(mux)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, newAuthedRequest(http.MethodPost, "/api/restart", nil))
+
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "restarting") {
+		t.Fatalf("expected a restarting response, got %d: %s", rec.Code, rec.Body.String())
+	}
+	select {
+	case <-exited:
+	case <-time.After(time.Second):
+		t.Fatal("restart did not exit the process")
+	}
+}
+
+func TestConfigAPI_ReloadFailureKeepsConfig(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	writeTestConfig(t, configPath, "first")
+
+	initial, err := config.LoadConfig(configPath)
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+
+	api := newTestConfigAPI(configPath, initial)
+	api.AddReloadable(ReloadableFunc(func(ctx context.Context, cfg *config.Config) error {
+		return errors.New("boom")
+	}))
+
+	writeTestConfig(t, configPath, "second")
+
+	if err := api.Reload(context.Background()); err == nil {
+		t.Fatal("expected reload error")
+	}
+	if got := api.Config().Agents.Defaults.ModelName; got != "first" {
+		t.Errorf("expected active config to stay 'first', got %q", got)
+	}
+}
+
+func postConfigDiff(t *testing.T, api *ConfigAPI, body string) []ConfigChange {
+	t.Helper()
+	mux := http.NewServeMux()
+	api.RegisterRoutes(mux)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, newAuthedRequest(http.MethodPost, "/api/config/diff", strings.NewReader(body)))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp struct {
+		Changes []ConfigChange `json:"changes"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.Changes == nil {
+		t.Fatal("changes should be an empty list, not null")
+	}
+	return resp.Changes
+}
+
+func TestConfigAPI_Diff(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	writeTestConfig(t, configPath, "first")
+	before, _ := os.ReadFile(configPath)
+	api := newTestConfigAPI(configPath, nil)
+
+	changes := postConfigDiff(t, api,
+		`{"version": 1, "agents": {"defaults": {"model_name": "second"}}, "heartbeat": {"enabled": true}}`)
+	if len(changes) != 2 {
+		t.Fatalf("got %d changes, want 2: %+v", len(changes), changes)
+	}
+	if c := changes[0]; c.Path != "agents.defaults.model_name" || c.Old != "first" || c.New != "second" {
+		t.Errorf("unexpected change %+v", c)
+	}
+	if c := changes[1]; c.Path != "heartbeat.enabled" || c.Old != nil || c.New != true {
+		t.Errorf("unexpected change %+v", c)
+	}
+
+	after, _ := os.ReadFile(configPath)
+	if string(before) != string(after) {
+		t.Error("diff must not modify the config file")
+	}
+}
+
+func TestConfigAPI_DiffIdentical(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	writeTestConfig(t, configPath, "first")
+	api := newTestConfigAPI(configPath, nil)
+
+	// Same content, different key order and whitespace.
+	changes := postConfigDiff(t, api, `{"agents":{"defaults":{"model_name":"first"}},"version":1}`)
+	if len(changes) != 0 {
+		t.Errorf("expected no changes, got %+v", changes)
+	}
+}
+
+func postConfigValidate(t *testing.T, body string) (bool, []ConfigViolation) {
+	t.Helper()
+	mux := http.NewServeMux()
+	newTestConfigAPI(filepath.Join(t.TempDir(), "config.json"), nil).RegisterRoutes(mux)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, newAuthedRequest(http.MethodPost, "/api/config/validate", strings.NewReader(body)))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp struct {
+		Valid      bool              `json:"valid"`
+		Violations []ConfigViolation `json:"violations"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	return resp.Valid, resp.Violations
+}
+
+func TestConfigAPI_ValidateMissingRequiredField(t *testing.T) {
+	valid, violations := postConfigValidate(t,
+		`{"version": 1, "model_list": [{"model_name": "gpt", "model": "openai/gpt-4o"}, {"model_name": "broken"}]}`)
+	if valid {
+		t.Fatal("expected config to be invalid")
+	}
+	if len(violations) != 1 || violations[0].Path != "model_list[1].model" {
+		t.Errorf("violations = %+v, want missing model_list[1].model", violations)
+	}
+}
+
+func TestConfigAPI_ValidateBadEnum(t *testing.T) {
+	valid, violations := postConfigValidate(t,
+		`{"agents": {"defaults": {"safety_level": "extreme", "model_name": "gpt"}}, "gateway": {"log_level": "info"}}`)
+	if valid {
+		t.Fatal("expected config to be invalid")
+	}
+	if len(violations) != 1 || violations[0].Path != "agents.defaults.safety_level" ||
+		!strings.Contains(violations[0].Message, "extreme") {
+		t.Errorf("violations = %+v, want bad agents.defaults.safety_level", violations)
+	}
+}
+
+func TestConfigAPI_ValidateEnumIgnoresCase(t *testing.T) {
+	valid, violations := postConfigValidate(t,
+		`{"agents": {"defaults": {"safety_level": "High", "model_name": "gpt"}}, "gateway": {"log_level": "DEBUG"}}`)
+	if !valid || len(violations) != 0 {
+		t.Errorf("expected valid config, got %+v", violations)
+	}
+
+	valid, violations = postConfigValidate(t, `{"agents": {"defaults": {"safety_level": "relaxed"}}}`)
+	if valid || len(violations) != 1 {
+		t.Errorf("relaxed is not a safety level; violations = %+v", violations)
+	}
+}
+
+func TestConfigAPI_ValidateAcceptsValidConfig(t *testing.T) {
+	valid, violations := postConfigValidate(t,
+		`{"version": 1, "agents": {"defaults": {"model": "gpt", "safety_level": "high"}},
+		  "channels": {"telegram": {"allow_from": ["123", 456]}},
+		  "model_list": [{"model_name": "gpt", "model": "openai/gpt-4o", "extra_body": {"seed": 1}}]}`)
+	if !valid || len(violations) != 0 {
+		t.Errorf("expected valid config, got %+v", violations)
+	}
+}
+
+func TestConfigAPI_PutRejectsInvalidConfig(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	writeTestConfig(t, configPath, "first")
+	mux := http.NewServeMux()
+	newTestConfigAPI(configPath, nil).RegisterRoutes(mux)
+
+	rec := httptest.NewRecorder()
+	body := `{"version": 1, "gateway": {"log_level": "loud"}}`
+	mux.ServeHTTP(rec, newAuthedRequest(http.MethodPut, "/api/config", strings.NewReader(body)))
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected status 422, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if data, _ := os.ReadFile(configPath); strings.Contains(string(data), "loud") {
+		t.Error("invalid config must not be saved")
+	}
+}
+
+func TestConfigAPI_PutRejectsOversizedBody(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	writeTestConfig(t, configPath, "first")
+	api := newTestConfigAPI(configPath, nil)
+	api.SetMaxBodySize(64)
+	mux := http.NewServeMux()
+	api.RegisterRoutes(mux)
+
+	body := `{"version": 1, "agents": {"defaults": {"model_name": "` + strings.Repeat("x", 100) + `"}}}`
+	for _, tc := range []struct{ method, path string }{
+		{http.MethodPut, "/api/config"},
+		{http.MethodPost, "/api/config/validate"},
+		{http.MethodPost, "/api/config/diff"},
+	} {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, newAuthedRequest(tc.method, tc.path, strings.NewReader(body)))
+		if rec.Code != http.StatusRequestEntityTooLarge {
+			t.Errorf("%s %s: status %d, want 413", tc.method, tc.path, rec.Code)
+		}
+	}
+	if data, _ := os.ReadFile(configPath); strings.Contains(string(data), "xxx") {
+		t.Error("oversized config must not be saved")
+	}
+}
+
+func TestValidateConfig_DefaultConfigIsValid(t *testing.T) {
+	data, err := json.Marshal(config.DefaultConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	violations, err := ValidateConfig(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(violations) != 0 {
+		t.Errorf("default config has violations: %+v", violations)
+	}
+}
+
+func TestConfigAPI_DeniesWithoutConfiguredToken(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	writeTestConfig(t, configPath, "first")
+	mux := http.NewServeMux()
+	NewConfigAPI(configPath, nil).RegisterRoutes(mux)
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/config", nil))
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("expected status 403 with no token configured, got %d", rec.Code)
+	}
+}
+
+func TestConfigAPI_TokenFromConfig(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	writeTestConfig(t, configPath, "first")
+	cfg := config.DefaultConfig()
+	cfg.Gateway.DashboardToken = testAuthToken
+	mux := http.NewServeMux()
+	NewConfigAPI(configPath, cfg).RegisterRoutes(mux)
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/config", nil))
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("missing token: expected status 401, got %d", rec.Code)
+	}
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, newAuthedRequest(http.MethodGet, "/api/config", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("configured token: expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
@@ -0,0 +1,93 @@
+package dashboard
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"reflect"
+	"sort"
+)
+
+// ConfigChange is one differing key between two configs. Path is the
+// dot-separated JSON key; Old or New is null when the key is absent on that
+// side. Arrays are compared as a whole.
+type ConfigChange struct {
+	Path string `json:"path"`
+	Old  any    `json:"old"`
+	New  any    `json:"new"`
+}
+
+// handleDiff compares a candidate config body with the config file on disk
+// without writing anything.
+func (api *ConfigAPI) handleDiff(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, ok := api.readCandidateConfig(w, r)
+	if !ok {
+		return
+	}
+	current, err := os.ReadFile(api.configPath)
+	if err != nil {
+		http.Error(w, "Failed to read config", http.StatusInternalServerError)
+		return
+	}
+
+	changes, err := diffConfigJSON(current, body)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to diff config: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{"changes": changes})
+}
+
+// diffConfigJSON returns the changes from oldData to newData, sorted by path.
+func diffConfigJSON(oldData, newData []byte) ([]ConfigChange, error) {
+	var oldVal, newVal any
+	if err := json.Unmarshal(oldData, &oldVal); err != nil {
+		return nil, fmt.Errorf("current config: %w", err)
+	}
+	if err := json.Unmarshal(newData, &newVal); err != nil {
+		return nil, fmt.Errorf("candidate config: %w", err)
+	}
+
+	changes := []ConfigChange{}
+	diffValues("", oldVal, newVal, &changes)
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes, nil
+}
+
+func diffValues(path string, oldVal, newVal any, changes *[]ConfigChange) {
+	oldObj, oldIsObj := oldVal.(map[string]any)
+	newObj, newIsObj := newVal.(map[string]any)
+	// An added or removed section is reported key by key.
+	if oldIsObj && newVal == nil || newIsObj && oldVal == nil {
+		oldIsObj, newIsObj = true, true
+	}
+	if oldIsObj && newIsObj {
+		for key, ov := range oldObj {
+			diffValues(joinPath(path, key), ov, newObj[key], changes)
+		}
+		for key, nv := range newObj {
+			if _, ok := oldObj[key]; !ok {
+				diffValues(joinPath(path, key), nil, nv, changes)
+			}
+		}
+		return
+	}
+	if !reflect.DeepEqual(oldVal, newVal) {
+		*changes = append(*changes, ConfigChange{Path: path, Old: oldVal, New: newVal})
+	}
+}
+
+func joinPath(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
@@ -0,0 +1,117 @@
+package dashboard
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/sipeed/picoclaw/pkg/cron"
+	"github.com/sipeed/picoclaw/pkg/logger"
+)
+
+// cronJobView is the dashboard representation of a cron job.
+type cronJobView struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Schedule   string     `json:"schedule"`
+	Enabled    bool       `json:"enabled"`
+	NextRun    *time.Time `json:"next_run,omitempty"`
+	LastRun    *time.Time `json:"last_run,omitempty"`
+	LastStatus string     `json:"last_status,omitempty"`
+	LastError  string     `json:"last_error,omitempty"`
+}
+
+// SetCronService sets the cron service behind the /api/cron endpoints.
+func (s *Server) SetCronService(cs *cron.CronService) {
+	s.cronMu.Lock()
+	defer s.cronMu.Unlock()
+	s.cron = cs
+}
+
+func (s *Server) cronService() *cron.CronService {
+	s.cronMu.RLock()
+	defer s.cronMu.RUnlock()
+	return s.cron
+}
+
+func (s *Server) handleCronJobs(w http.ResponseWriter, r *http.Request) {
+	cs := s.cronService()
+	if cs == nil {
+		http.Error(w, "Cron is not enabled", http.StatusServiceUnavailable)
+		return
+	}
+
+	jobs := cs.ListJobs(true)
+	views := make([]cronJobView, 0, len(jobs))
+	for _, job := range jobs {
+		views = append(views, cronJobView{
+			ID:         job.ID,
+			Name:       job.Name,
+			Schedule:   describeSchedule(job.Schedule),
+			Enabled:    job.Enabled,
+			NextRun:    msToTime(job.State.NextRunAtMS),
+			LastRun:    msToTime(job.State.LastRunAtMS),
+			LastStatus: job.State.LastStatus,
+			LastError:  job.State.LastError,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(views)
+}
+
+// handleCronRun starts a job immediately. The job runs in the background,
+// since it may take as long as a full agent turn; its outcome shows up in
+// last_status.
+func (s *Server) handleCronRun(w http.ResponseWriter, r *http.Request) {
+	cs := s.cronService()
+	if cs == nil {
+		http.Error(w, "Cron is not enabled", http.StatusServiceUnavailable)
+		return
+	}
+
+	job, ok := cs.FindJob(r.PathValue("name"))
+	if !ok {
+		http.Error(w, fmt.Sprintf("Cron job %q not found", r.PathValue("name")), http.StatusNotFound)
+		return
+	}
+
+	go func() {
+		if err := cs.RunJob(job.ID); err != nil {
+			logger.WarnCF("dashboard", "Manually triggered cron job failed",
+				map[string]any{"job": job.Name, "error": err.Error()})
+		}
+	}()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(map[string]string{"status": "triggered", "id": job.ID})
+}
+
+func describeSchedule(s cron.CronSchedule) string {
+	switch s.Kind {
+	case "every":
+		if s.EveryMS != nil {
+			return "every " + (time.Duration(*s.EveryMS) * time.Millisecond).String()
+		}
+	case "cron":
+		if s.TZ != "" {
+			return s.Expr + " (" + s.TZ + ")"
+		}
+		return s.Expr
+	case "at":
+		if s.AtMS != nil {
+			return "at " + time.UnixMilli(*s.AtMS).Format(time.RFC3339)
+		}
+	}
+	return s.Kind
+}
+
+func msToTime(ms *int64) *time.Time {
+	if ms == nil {
+		return nil
+	}
+	t := time.UnixMilli(*ms)
+	return &t
+}
@@ -0,0 +1,95 @@
+package dashboard
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sipeed/picoclaw/pkg/cron"
+)
+
+func newCronTestServer(t *testing.T, onJob cron.JobHandler) (*Server, *cron.CronService) {
+	t.Helper()
+	cs := cron.NewCronService(filepath.Join(t.TempDir(), "jobs.json"), onJob)
+	every := int64(5 * time.Minute / time.Millisecond)
+	if _, err := cs.AddJob("water-plants", cron.CronSchedule{Kind: "every", EveryMS: &every}, "water", false, "cli", "direct"); err != nil {
+		t.Fatalf("AddJob: %v", err)
+	}
+	if _, err := cs.AddJob("report", cron.CronSchedule{Kind: "cron", Expr: "0 9 * * *"}, "report", false, "cli", "direct"); err != nil {
+		t.Fatalf("AddJob: %v", err)
+	}
+
+	s := NewServer("127.0.0.1", 0, nil, "", nil)
+	s.SetCronService(cs)
+	s.SetAuthToken(testAuthToken)
+	return s, cs
+}
+
+func TestCronAPI_ListJobs(t *testing.T) {
+	s, _ := newCronTestServer(t, nil)
+
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/cron/jobs", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var jobs []cronJobView
+	if err := json.NewDecoder(rec.Body).Decode(&jobs); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(jobs) != 2 {
+		t.Fatalf("got %d jobs, want 2", len(jobs))
+	}
+	if jobs[0].Name != "water-plants" || jobs[0].Schedule != "every 5m0s" || !jobs[0].Enabled || jobs[0].NextRun == nil {
+		t.Errorf("unexpected first job %+v", jobs[0])
+	}
+	if jobs[1].Schedule != "0 9 * * *" {
+		t.Errorf("schedule = %q, want cron expression", jobs[1].Schedule)
+	}
+}
+
+func TestCronAPI_RunJob(t *testing.T) {
+	ran := make(chan string, 1)
+	s, cs := newCronTestServer(t, func(job *cron.CronJob) (string, error) {
+		ran <- job.Name
+		return "ok", nil
+	})
+
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, newAuthedRequest(http.MethodPost, "/api/cron/jobs/report/run", nil))
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("expected status 202, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	select {
+	case name := <-ran:
+		if name != "report" {
+			t.Errorf("ran job %q, want report", name)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("job was not triggered")
+	}
+
+	// The run is recorded once the handler returns.
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		job, _ := cs.FindJob("report")
+		if job.State.LastStatus == "ok" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("last status = %q, want ok", job.State.LastStatus)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	rec = httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, newAuthedRequest(http.MethodPost, "/api/cron/jobs/missing/run", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("unknown job: expected 404, got %d", rec.Code)
+	}
+}
@@ -1,6 +1,12 @@
 package dashboard
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 )
 
@@ -46,3 +52,105 @@ func TestGenerateSchema(t *testing.T) {
 		t.Error("expected 'agents' property in schema")
 	}
 }
+
+func TestServerServesSPA(t *testing.T) {
+	s := NewServer("127.0.0.1", 0, nil, "", nil)
+	handler := s.Handler()
+
+	for _, path := range []string{"/", "/dashboard/", "/some/client/route"} {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusOK {
+			t.Errorf("GET %s: expected status 200, got %d", path, rec.Code)
+			continue
+		}
+		if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/html") {
+			t.Errorf("GET %s: expected HTML content type, got %q", path, ct)
+		}
+		if !strings.Contains(rec.Body.String(), "<title>PicoClaw Dashboard</title>") {
+			t.Errorf("GET %s: expected dashboard index.html in body", path)
+		}
+	}
+}
+
+func TestServerServesStaticAssets(t *testing.T) {
+	s := NewServer("127.0.0.1", 0, nil, "", nil)
+	handler := s.Handler()
+
+	for _, path := range []string{"/dashboard.css", "/dashboard/dashboard.js"} {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusOK {
+			t.Errorf("GET %s: expected status 200, got %d", path, rec.Code)
+		}
+		if strings.Contains(rec.Body.String(), "<title>PicoClaw Dashboard</title>") {
+			t.Errorf("GET %s: expected asset, got index.html fallback", path)
+		}
+	}
+}
+
+func TestServerReadyReportsFailingProbes(t *testing.T) {
+	s := NewServer("127.0.0.1", 0, nil, "", nil)
+	handler := s.Handler()
+
+	s.Checker().Register("provider", func(ctx context.Context) error { return nil })
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ready", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 with passing probe, got %d", rec.Code)
+	}
+
+	s.Checker().Register("channels", func(ctx context.Context) error { return errors.New("not started") })
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ready", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 with failing probe, got %d", rec.Code)
+	}
+	if !strings.Contains(rec.Body.String(), `"not_ready":["channels"]`) {
+		t.Errorf("expected body to list channels as not ready, got %s", rec.Body.String())
+	}
+}
+
+func TestServerHealthContentNegotiation(t *testing.T) {
+	s := NewServer("127.0.0.1", 0, nil, "", nil)
+	s.Checker().Register("provider", func(ctx context.Context) error { return nil })
+	handler := s.Handler()
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+	if rec.Code != http.StatusOK || strings.TrimSpace(rec.Body.String()) != "OK" {
+		t.Errorf("expected plain OK, got %d %q", rec.Code, rec.Body.String())
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/health", nil)
+	req.Header.Set("Accept", "application/json")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Fatalf("expected application/json, got %q", ct)
+	}
+	var resp map[string]any
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp["status"] != "ok" {
+		t.Errorf("status = %v, want ok", resp["status"])
+	}
+	for _, key := range []string{"uptime", "version", "checks"} {
+		if _, ok := resp[key]; !ok {
+			t.Errorf("expected %q in JSON health response, got %v", key, resp)
+		}
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/ready", nil)
+	req.Header.Set("Accept", "text/html, application/json;q=0.9")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if !strings.Contains(rec.Body.String(), `"status":"ready"`) {
+		t.Errorf("expected JSON ready response, got %q", rec.Body.String())
+	}
+}
@@ -0,0 +1,90 @@
+package dashboard
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/sipeed/picoclaw/pkg/logger"
+)
+
+// mcpCallTimeout bounds a tool call made from the dashboard.
+const mcpCallTimeout = 30 * time.Second
+
+// maxMCPCallBodySize caps the body of POST /api/mcp/call.
+const maxMCPCallBodySize = 1 << 20 // 1 MiB
+
+// MCPCaller invokes a tool on a connected MCP server. *mcp.Manager from
+// pkg/mcp implements it.
+type MCPCaller interface {
+	CallTool(ctx context.Context, serverName, toolName string, arguments map[string]any) (*mcp.CallToolResult, error)
+}
+
+// mcpCallRequest is the body of POST /api/mcp/call.
+type mcpCallRequest struct {
+	Server    string         `json:"server"`
+	Tool      string         `json:"tool"`
+	Arguments map[string]any `json:"arguments"`
+}
+
+// SetMCPCaller sets the MCP manager used by POST /api/mcp/call.
+func (s *Server) SetMCPCaller(c MCPCaller) {
+	s.mcpMu.Lock()
+	defer s.mcpMu.Unlock()
+	s.mcp = c
+}
+
+func (s *Server) mcpCaller() MCPCaller {
+	s.mcpMu.RLock()
+	defer s.mcpMu.RUnlock()
+	return s.mcp
+}
+
+// handleMCPCall calls a single MCP tool and returns the raw CallToolResult,
+// so operators can debug a server from the dashboard.
+func (s *Server) handleMCPCall(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req mcpCallRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxMCPCallBodySize)).Decode(&req); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			http.Error(w, "Request entity too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, fmt.Sprintf("Invalid JSON: %v", err), http.StatusBadRequest)
+		return
+	}
+	if req.Server == "" || req.Tool == "" {
+		http.Error(w, "server and tool are required", http.StatusBadRequest)
+		return
+	}
+
+	caller := s.mcpCaller()
+	if caller == nil {
+		http.Error(w, "MCP is not enabled", http.StatusServiceUnavailable)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), mcpCallTimeout)
+	defer cancel()
+
+	result, err := caller.CallTool(ctx, req.Server, req.Tool, req.Arguments)
+	if err != nil {
+		logger.WarnCF("dashboard", "MCP tool call failed",
+			map[string]any{"server": req.Server, "tool": req.Tool, "error": err.Error()})
+		http.Error(w, fmt.Sprintf("MCP call failed: %v", err), http.StatusBadGateway)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
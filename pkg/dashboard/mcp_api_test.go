@@ -0,0 +1,156 @@
+package dashboard
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	picomcp "github.com/sipeed/picoclaw/pkg/mcp"
+)
+
+var _ MCPCaller = (*picomcp.Manager)(nil)
+
+type fakeMCPCaller struct {
+	server, tool string
+	args         map[string]any
+	result       *mcp.CallToolResult
+	err          error
+}
+
+func (f *fakeMCPCaller) CallTool(
+	ctx context.Context,
+	serverName, toolName string,
+	arguments map[string]any,
+) (*mcp.CallToolResult, error) {
+	f.server, f.tool, f.args = serverName, toolName, arguments
+	return f.result, f.err
+}
+
+func postMCPCall(t *testing.T, s *Server, body, token string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "/api/mcp/call", strings.NewReader(body))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, req)
+	return rec
+}
+
+func TestMCPCall_Success(t *testing.T) {
+	caller := &fakeMCPCaller{result: &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: "42 files"}},
+	}}
+	s := NewServer("127.0.0.1", 0, nil, "", nil)
+	s.SetMCPCaller(caller)
+	s.SetAuthToken(testAuthToken)
+
+	rec := postMCPCall(t, s, `{"server":"fs","tool":"count","arguments":{"dir":"/tmp"}}`, testAuthToken)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if caller.server != "fs" || caller.tool != "count" || caller.args["dir"] != "/tmp" {
+		t.Errorf("CallTool got server=%q tool=%q args=%v", caller.server, caller.tool, caller.args)
+	}
+
+	var resp struct {
+		Content []struct {
+			Type string `json:"type"`
+			Text string `json:"text"`
+		} `json:"content"`
+		IsError bool `json:"isError"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.IsError || len(resp.Content) != 1 || resp.Content[0].Text != "42 files" {
+		t.Errorf("unexpected result: %+v", resp)
+	}
+}
+
+func TestMCPCall_ErrorResult(t *testing.T) {
+	caller := &fakeMCPCaller{result: &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: "permission denied"}},
+		IsError: true,
+	}}
+	s := NewServer("127.0.0.1", 0, nil, "", nil)
+	s.SetMCPCaller(caller)
+	s.SetAuthToken(testAuthToken)
+
+	rec := postMCPCall(t, s, `{"server":"fs","tool":"read"}`, testAuthToken)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if !strings.Contains(rec.Body.String(), `"isError":true`) {
+		t.Errorf("expected isError in body, got %s", rec.Body.String())
+	}
+}
+
+func TestMCPCall_Failures(t *testing.T) {
+	s := NewServer("127.0.0.1", 0, nil, "", nil)
+	s.SetAuthToken(testAuthToken)
+
+	if rec := postMCPCall(t, s, `{"server":"fs","tool":"read"}`, testAuthToken); rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("without a caller: expected 503, got %d", rec.Code)
+	}
+
+	s.SetMCPCaller(&fakeMCPCaller{err: errors.New("server fs not found")})
+	if rec := postMCPCall(t, s, `{"tool":"read"}`, testAuthToken); rec.Code != http.StatusBadRequest {
+		t.Errorf("missing server: expected 400, got %d", rec.Code)
+	}
+	rec := postMCPCall(t, s, `{"server":"fs","tool":"read"}`, testAuthToken)
+	if rec.Code != http.StatusBadGateway || !strings.Contains(rec.Body.String(), "server fs not found") {
+		t.Errorf("call error: expected 502 with message, got %d %q", rec.Code, rec.Body.String())
+	}
+}
+
+func TestMCPCall_RequiresAuthToken(t *testing.T) {
+	s := NewServer("127.0.0.1", 0, nil, "", nil)
+	s.SetMCPCaller(&fakeMCPCaller{result: &mcp.CallToolResult{}})
+	s.SetAuthToken("secret")
+
+	body := `{"server":"fs","tool":"read"}`
+	if rec := postMCPCall(t, s, body, ""); rec.Code != http.StatusUnauthorized {
+		t.Errorf("no token: expected 401, got %d", rec.Code)
+	}
+	if rec := postMCPCall(t, s, body, "wrong"); rec.Code != http.StatusUnauthorized {
+		t.Errorf("wrong token: expected 401, got %d", rec.Code)
+	}
+	if rec := postMCPCall(t, s, body, "secret"); rec.Code != http.StatusOK {
+		t.Errorf("valid token: expected 200, got %d", rec.Code)
+	}
+}
+
+func TestMCPCall_DeniedWithoutConfiguredToken(t *testing.T) {
+	caller := &fakeMCPCaller{result: &mcp.CallToolResult{}}
+	s := NewServer("127.0.0.1", 0, nil, "", nil)
+	s.SetMCPCaller(caller)
+
+	if rec := postMCPCall(t, s, `{"server":"fs","tool":"read"}`, "anything"); rec.Code != http.StatusForbidden {
+		t.Errorf("expected 403 with no token configured, got %d", rec.Code)
+	}
+	if caller.tool != "" {
+		t.Error("tool was called without authentication")
+	}
+}
+
+func TestMCPCall_RejectsOversizedBody(t *testing.T) {
+	caller := &fakeMCPCaller{result: &mcp.CallToolResult{}}
+	s := NewServer("127.0.0.1", 0, nil, "", nil)
+	s.SetMCPCaller(caller)
+	s.SetAuthToken(testAuthToken)
+
+	body := `{"server":"fs","tool":"write","arguments":{"data":"` + strings.Repeat("x", maxMCPCallBodySize) + `"}}`
+	if rec := postMCPCall(t, s, body, testAuthToken); rec.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("expected 413, got %d", rec.Code)
+	}
+	if caller.tool != "" {
+		t.Error("oversized request reached the MCP server")
+	}
+}
@@ -1,13 +1,18 @@
 package dashboard
 
 import (
+	"encoding/json"
 	"reflect"
 	"strings"
 
 	"github.com/sipeed/picoclaw/pkg/config"
 )
 
-// GenerateSchema creates a simple JSON schema for the Config struct.
+var jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// GenerateSchema creates a simple JSON schema for the Config struct. Fields
+// tagged required:"true" are listed under "required", and enum:"a,b" tags
+// become "enum" constraints.
 func GenerateSchema() map[string]interface{} {
 	return reflectTypeToSchema(reflect.TypeOf(config.Config{}))
 }
@@ -17,6 +22,12 @@ func reflectTypeToSchema(t reflect.Type) map[string]interface{} {
 		t = t.Elem()
 	}
 
+	// Interfaces and types with custom decoding accept several JSON shapes,
+	// so leave their type unconstrained.
+	if t.Kind() == reflect.Interface || reflect.PointerTo(t).Implements(jsonUnmarshalerType) {
+		return map[string]interface{}{}
+	}
+
 	schema := map[string]interface{}{
 		"type": stringKind(t.Kind()),
 	}
@@ -24,6 +35,7 @@ func reflectTypeToSchema(t reflect.Type) map[string]interface{} {
 	switch t.Kind() {
 	case reflect.Struct:
 		properties := make(map[string]interface{})
+		var required []string
 		for i := 0; i < t.NumField(); i++ {
 			field := t.Field(i)
 			jsonTag := field.Tag.Get("json")
@@ -38,10 +50,20 @@ func reflectTypeToSchema(t reflect.Type) map[string]interface{} {
 				prop["description"] = "Environment variable: " + envTag
 			}
 
+			if enum := field.Tag.Get("enum"); enum != "" {
+				prop["enum"] = strings.Split(enum, ",")
+			}
+			if field.Tag.Get("required") == "true" {
+				required = append(required, name)
+			}
+
 			properties[name] = prop
 		}
 		schema["properties"] = properties
 		schema["type"] = "object"
+		if len(required) > 0 {
+			schema["required"] = required
+		}
 
 	case reflect.Slice, reflect.Array:
 		schema["type"] = "array"
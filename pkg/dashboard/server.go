@@ -5,13 +5,20 @@ import (
 	"embed"
 	"encoding/json"
 	"fmt"
+	"io/fs"
+	"maps"
 	"net/http"
+	"os"
+	"path"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/sipeed/picoclaw/pkg/bus"
 	"github.com/sipeed/picoclaw/pkg/config"
+	"github.com/sipeed/picoclaw/pkg/cron"
+	"github.com/sipeed/picoclaw/pkg/health"
 )
 
 //go:embed static/*
@@ -24,6 +31,14 @@ type Server struct {
 	server   *http.Server
 	activity *ActivityBuffer
 	config   *ConfigAPI
+	checker  *health.Checker
+	started  time.Time
+
+	mcpMu sync.RWMutex
+	mcp   MCPCaller
+
+	cronMu sync.RWMutex
+	cron   *cron.CronService
 }
 
 // NewServer creates a new dashboard server.
@@ -33,6 +48,8 @@ func NewServer(host string, port int, msgBus *bus.MessageBus, configPath string,
 		port:     port,
 		activity: NewActivityBuffer(100),
 		config:   NewConfigAPI(configPath, cfg),
+		checker:  health.NewChecker(),
+		started:  time.Now(),
 	}
 
 	if msgBus != nil {
@@ -42,8 +59,48 @@ func NewServer(host string, port int, msgBus *bus.MessageBus, configPath string,
 	return s
 }
 
+// AddReloadable registers a service to be rebuilt when the config is
+// reloaded through the dashboard.
+func (s *Server) AddReloadable(r Reloadable) {
+	s.config.AddReloadable(r)
+}
+
+// SetAuthToken overrides the bearer token protecting the config, MCP and
+// cron-run endpoints. See ConfigAPI.SetAuthToken.
+func (s *Server) SetAuthToken(token string) {
+	s.config.SetAuthToken(token)
+}
+
+// PersistActivity backs the activity buffer with the JSONL file at path,
+// loading events younger than retention. Call it before Start. Only events
+// passed to ActivityBuffer.Add are persisted; Subscribe does not feed the
+// buffer yet.
+func (s *Server) PersistActivity(path string, retention time.Duration) error {
+	ab, err := NewPersistentActivityBuffer(s.activity.size, path, retention)
+	if err != nil {
+		return err
+	}
+	s.activity = ab
+	return nil
+}
+
+// Checker returns the registry of readiness probes evaluated by /ready.
+func (s *Server) Checker() *health.Checker {
+	return s.checker
+}
+
 // Start starts the dashboard server.
 func (s *Server) Start() error {
+	s.server = &http.Server{
+		Addr:    fmt.Sprintf("%s:%d", s.host, s.port),
+		Handler: s.Handler(),
+	}
+
+	return s.server.ListenAndServe()
+}
+
+// Handler builds the HTTP handler serving the dashboard API and the SPA.
+func (s *Server) Handler() http.Handler {
 	mux := http.NewServeMux()
 
 	// Health endpoints (legacy)
@@ -56,33 +113,42 @@ func (s *Server) Start() error {
 	// Dashboard API
 	mux.HandleFunc("/api/status", s.handleStatus)
 	mux.HandleFunc("/api/activity", s.handleActivity)
+	mux.HandleFunc("/api/activity/export", s.config.requireAuth(s.handleActivityExport))
 
 	// Config API
 	s.config.RegisterRoutes(mux)
+	mux.HandleFunc("/api/mcp/call", s.config.requireAuth(s.handleMCPCall))
+	mux.HandleFunc("GET /api/cron/jobs", s.handleCronJobs)
+	mux.HandleFunc("POST /api/cron/jobs/{name}/run", s.config.requireAuth(s.handleCronRun))
+
+	// Static files (SPA). The embedded FS is rooted at "static/", so serve
+	// from the sub-filesystem to make index.html available at "/".
+	staticRoot, err := fs.Sub(staticFS, "static")
+	if err != nil {
+		// Only possible if the embed directive changes; fail loudly.
+		panic(fmt.Sprintf("dashboard: invalid embedded static dir: %v", err))
+	}
+	spa := spaHandler(staticRoot)
+	mux.Handle("/dashboard/", http.StripPrefix("/dashboard", spa))
+	mux.Handle("/", spa)
+
+	return mux
+}
 
-	// Static files (SPA)
-	mux.Handle("/", http.FileServer(http.FS(staticFS)))
-	// Note: We need a sub-filesystem or to handle the prefix if embedded folder is not root
-	// For "embed static/*", the path is static/index.html
-	// So we use a strip prefix or a sub filesystem
-
-	fileServer := http.FileServer(http.FS(staticFS))
-	mux.Handle("/dashboard/", http.StripPrefix("/dashboard/", fileServer))
-	// Redirect root to dashboard
-	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
-		if r.URL.Path == "/" {
-			http.Redirect(w, r, "/dashboard/static/", http.StatusFound)
-			return
+// spaHandler serves files from root and falls back to index.html for any
+// path that does not exist, so client-side routes survive a page reload.
+func spaHandler(root fs.FS) http.Handler {
+	fileServer := http.FileServer(http.FS(root))
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := strings.TrimPrefix(path.Clean("/"+r.URL.Path), "/")
+		if name != "" {
+			if _, err := fs.Stat(root, name); err != nil {
+				http.ServeFileFS(w, r, root, "index.html")
+				return
+			}
 		}
 		fileServer.ServeHTTP(w, r)
 	})
-
-	s.server = &http.Server{
-		Addr:    fmt.Sprintf("%s:%d", s.host, s.port),
-		Handler: mux,
-	}
-
-	return s.server.ListenAndServe()
 }
 
 // Stop stops the server.
@@ -94,15 +160,43 @@ func (s *Server) Stop(ctx context.Context) error {
 }
 
 func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
-	w.WriteHeader(http.StatusOK)
-	fmt.Fprintln(w, "OK")
+	if !health.WantsJSON(r) {
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintln(w, "OK")
+		return
+	}
+	checks, _ := s.checker.Run(r.Context())
+	s.writeStatusJSON(w, http.StatusOK, health.StatusResponse{Status: "ok", Checks: checks})
 }
 
 func (s *Server) handleReady(w http.ResponseWriter, r *http.Request) {
+	checks, notReady := s.checker.Run(r.Context())
+	if len(notReady) > 0 {
+		s.writeStatusJSON(w, http.StatusServiceUnavailable, health.StatusResponse{
+			Status:   "not ready",
+			Checks:   checks,
+			NotReady: notReady,
+		})
+		return
+	}
+	if health.WantsJSON(r) {
+		s.writeStatusJSON(w, http.StatusOK, health.StatusResponse{Status: "ready", Checks: checks})
+		return
+	}
 	w.WriteHeader(http.StatusOK)
 	fmt.Fprintln(w, "READY")
 }
 
+// writeStatusJSON fills in uptime and version and writes resp as JSON.
+func (s *Server) writeStatusJSON(w http.ResponseWriter, code int, resp health.StatusResponse) {
+	resp.Uptime = time.Since(s.started).Round(time.Second).String()
+	resp.Version = config.FormatVersion()
+	resp.Pid = os.Getpid()
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(resp)
+}
+
 func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
 	status := map[string]interface{}{
 		"uptime":    time.Since(time.Now()).String(), // Placeholder
@@ -124,6 +218,13 @@ type ActivityBuffer struct {
 	mu     sync.RWMutex
 	events []map[string]interface{}
 	size   int
+
+	// path and retention are set for buffers backed by a JSONL file; see
+	// NewPersistentActivityBuffer.
+	path      string
+	retention time.Duration
+	// compactedAt is when expired events were last dropped from the file.
+	compactedAt time.Time
 }
 
 // NewActivityBuffer creates a new ring buffer for activity.
@@ -142,11 +243,22 @@ func (ab *ActivityBuffer) Subscribe(msgBus *bus.MessageBus) {
 	// TODO: Re-implement using a bus middleware or tap pattern.
 }
 
-// Add adds an event to the buffer.
+// Add adds an event to the buffer. Events added to a persistent buffer
+// without a "time" are stamped with the current time.
 func (ab *ActivityBuffer) Add(event map[string]interface{}) {
 	ab.mu.Lock()
 	defer ab.mu.Unlock()
 
+	if ab.path != "" {
+		if _, ok := event["time"]; !ok {
+			stamped := make(map[string]interface{}, len(event)+1)
+			maps.Copy(stamped, event)
+			stamped["time"] = time.Now()
+			event = stamped
+		}
+		ab.appendLocked(event)
+	}
+
 	if len(ab.events) >= ab.size {
 		ab.events = ab.events[1:]
 	}
@@ -137,9 +137,13 @@ document.addEventListener('DOMContentLoaded', () => {
     document.getElementById('restart-btn').addEventListener('click', async () => {
         if (confirm('Are you sure you want to restart the gateway? All active connections will be dropped.')) {
             try {
-                await fetch('/api/restart', { method: 'POST' });
-                alert('Restarting... Please refresh in a few seconds.');
-            } catch (e) { alert('Restart failed'); }
+                const res = await fetch('/api/restart', { method: 'POST' });
+                if (!res.ok) throw new Error(await res.text());
+                const data = await res.json();
+                alert(data.status === 'reloaded'
+                    ? 'Configuration reloaded.'
+                    : 'Restarting... Please refresh in a few seconds.');
+            } catch (e) { alert('Restart failed: ' + e.message); }
         }
     });
 });
@@ -0,0 +1,124 @@
+package dashboard
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ConfigViolation is a single schema violation in a candidate config.
+type ConfigViolation struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+// ValidateConfig checks a config document against GenerateSchema: JSON
+// types, required fields and enum values. Unknown keys are ignored. The
+// result is sorted by path and empty when the config is valid.
+func ValidateConfig(data []byte) ([]ConfigViolation, error) {
+	var doc any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	violations := []ConfigViolation{}
+	validateValue("", doc, GenerateSchema(), &violations)
+	sort.SliceStable(violations, func(i, j int) bool { return violations[i].Path < violations[j].Path })
+	return violations, nil
+}
+
+func validateValue(path string, value any, schema map[string]interface{}, out *[]ConfigViolation) {
+	if value == nil {
+		return
+	}
+	fail := func(format string, args ...any) {
+		*out = append(*out, ConfigViolation{Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	switch schema["type"] {
+	case "object":
+		obj, ok := value.(map[string]any)
+		if !ok {
+			fail("expected an object")
+			return
+		}
+		if required, ok := schema["required"].([]string); ok {
+			for _, name := range required {
+				if v, present := obj[name]; !present || v == nil || v == "" {
+					*out = append(*out, ConfigViolation{Path: joinPath(path, name), Message: "required field is missing"})
+				}
+			}
+		}
+		if props, ok := schema["properties"].(map[string]interface{}); ok {
+			for name, v := range obj {
+				if prop, ok := props[name].(map[string]interface{}); ok {
+					validateValue(joinPath(path, name), v, prop, out)
+				}
+			}
+		} else if items, ok := schema["additionalProperties"].(map[string]interface{}); ok {
+			for name, v := range obj {
+				validateValue(joinPath(path, name), v, items, out)
+			}
+		}
+
+	case "array":
+		arr, ok := value.([]any)
+		if !ok {
+			fail("expected an array")
+			return
+		}
+		items, _ := schema["items"].(map[string]interface{})
+		for i, v := range arr {
+			validateValue(path+"["+strconv.Itoa(i)+"]", v, items, out)
+		}
+
+	case "string":
+		str, ok := value.(string)
+		if !ok {
+			fail("expected a string")
+			return
+		}
+		// Enum values are matched case-insensitively, as the consumers of
+		// these fields (e.g. logger.ParseLevel) normalize case.
+		if enum, ok := schema["enum"].([]string); ok && str != "" &&
+			!slices.ContainsFunc(enum, func(e string) bool { return strings.EqualFold(e, str) }) {
+			fail("invalid value %q, expected one of %v", str, enum)
+		}
+
+	case "number":
+		if _, ok := value.(float64); !ok {
+			fail("expected a number")
+		}
+
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			fail("expected a boolean")
+		}
+	}
+}
+
+// handleValidate reports schema violations in a candidate config without
+// saving it.
+func (api *ConfigAPI) handleValidate(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	body, ok := api.readCandidateConfig(w, r)
+	if !ok {
+		return
+	}
+	violations, err := ValidateConfig(body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{
+		"valid":      len(violations) == 0,
+		"violations": violations,
+	})
+}
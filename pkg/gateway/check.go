@@ -0,0 +1,71 @@
+package gateway
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/sipeed/picoclaw/pkg/agent"
+	"github.com/sipeed/picoclaw/pkg/bus"
+	"github.com/sipeed/picoclaw/pkg/channels"
+	"github.com/sipeed/picoclaw/pkg/config"
+	"github.com/sipeed/picoclaw/pkg/heartbeat"
+	"github.com/sipeed/picoclaw/pkg/media"
+	"github.com/sipeed/picoclaw/pkg/providers"
+)
+
+// Check loads the config at configPath and builds every service the gateway
+// would start, without starting listeners, channels or background loops.
+// It returns the first error, so operators can validate a config before
+// deploying it.
+func Check(configPath string, allowEmptyStartup bool) error {
+	cfg, err := config.LoadConfig(configPath)
+	if err != nil {
+		return fmt.Errorf("error loading config: %w", err)
+	}
+	if err = cfg.ValidateModelList(); err != nil {
+		return fmt.Errorf("invalid model list: %w", err)
+	}
+	fmt.Println("✓ Config loaded")
+
+	provider, modelID, err := createStartupProvider(cfg, allowEmptyStartup)
+	if err != nil {
+		return fmt.Errorf("error creating provider: %w", err)
+	}
+	if cp, ok := provider.(providers.StatefulProvider); ok {
+		defer cp.Close()
+	}
+	if modelID != "" {
+		cfg.Agents.Defaults.ModelName = modelID
+	}
+	fmt.Println("✓ Provider created")
+
+	msgBus := bus.NewMessageBus()
+	defer msgBus.Close()
+	agentLoop := agent.NewAgentLoop(cfg, msgBus, provider)
+	defer agentLoop.Close()
+
+	if _, err = setupCronTool(
+		agentLoop,
+		msgBus,
+		cfg.WorkspacePath(),
+		cfg.Agents.Defaults.RestrictToWorkspace,
+		time.Duration(cfg.Tools.Cron.ExecTimeoutMinutes)*time.Minute,
+		cfg,
+	); err != nil {
+		return fmt.Errorf("error setting up cron service: %w", err)
+	}
+	fmt.Println("✓ Cron service configured")
+
+	hs := heartbeat.NewHeartbeatService(cfg.WorkspacePath(), cfg.Heartbeat.Interval, cfg.Heartbeat.Enabled)
+	hs.SetHandler(createHeartbeatHandler(agentLoop))
+	hs.SetTarget(cfg.Heartbeat.Channel, cfg.Heartbeat.ChatID)
+	fmt.Println("✓ Heartbeat service configured")
+
+	channelManager, err := channels.NewManager(cfg, msgBus, media.NewFileMediaStore())
+	if err != nil {
+		return fmt.Errorf("error creating channel manager: %w", err)
+	}
+	fmt.Printf("✓ Channels configured: %v\n", channelManager.GetEnabledChannels())
+
+	return nil
+}
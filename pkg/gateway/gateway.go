@@ -6,6 +6,8 @@ import (
 	"os"
 	"os/signal"
 	"path/filepath"
+	"sort"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"syscall"
@@ -36,9 +38,11 @@ import (
 	"github.com/sipeed/picoclaw/pkg/heartbeat"
 	"github.com/sipeed/picoclaw/pkg/logger"
 	"github.com/sipeed/picoclaw/pkg/media"
+	"github.com/sipeed/picoclaw/pkg/metrics"
 	"github.com/sipeed/picoclaw/pkg/providers"
 	"github.com/sipeed/picoclaw/pkg/state"
 	"github.com/sipeed/picoclaw/pkg/tools"
+	"github.com/sipeed/picoclaw/pkg/tracing"
 	"github.com/sipeed/picoclaw/pkg/voice"
 )
 
@@ -101,6 +105,9 @@ func Run(debug bool, homePath, configPath string, allowEmptyStartup bool) error
 	}
 
 	logger.SetLevelFromString(cfg.Gateway.LogLevel)
+	logger.SetFormatFromString(cfg.Gateway.LogFormat)
+	applyMetricsConfig(cfg)
+	defer setupTracing(cfg)()
 
 	if debug {
 		logger.SetLevel(logger.DEBUG)
@@ -180,7 +187,7 @@ func Run(debug bool, homePath, configPath string, allowEmptyStartup bool) error
 		select {
 		case <-sigChan:
 			logger.Info("Shutting down...")
-			shutdownGateway(runningServices, agentLoop, provider, true)
+			shutdownGateway(runningServices, agentLoop, provider, true, shutdownTimeout(cfg))
 			return nil
 		case newCfg := <-configReloadChan:
 			if !runningServices.reloading.CompareAndSwap(false, true) {
@@ -276,6 +283,7 @@ func setupAndStartServices(
 	)
 	runningServices.HeartbeatService.SetBus(msgBus)
 	runningServices.HeartbeatService.SetHandler(createHeartbeatHandler(agentLoop))
+	runningServices.HeartbeatService.SetTarget(cfg.Heartbeat.Channel, cfg.Heartbeat.ChatID)
 	if err = runningServices.HeartbeatService.Start(); err != nil {
 		return nil, fmt.Errorf("error starting heartbeat service: %w", err)
 	}
@@ -316,6 +324,7 @@ func setupAndStartServices(
 	addr := fmt.Sprintf("%s:%d", cfg.Gateway.Host, cfg.Gateway.Port)
 	runningServices.HealthServer = health.NewServer(cfg.Gateway.Host, cfg.Gateway.Port)
 	runningServices.ChannelManager.SetupHTTPServer(addr, runningServices.HealthServer)
+	registerChannelProbe(runningServices.HealthServer, runningServices.ChannelManager)
 
 	if err = runningServices.ChannelManager.StartAll(context.Background()); err != nil {
 		return nil, fmt.Errorf("error starting channels: %w", err)
@@ -342,48 +351,6 @@ func setupAndStartServices(
 	return runningServices, nil
 }
 
-func stopAndCleanupServices(runningServices *services, shutdownTimeout time.Duration, isReload bool) {
-	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), shutdownTimeout)
-	defer shutdownCancel()
-
-	// reload should not stop channel manager
-	if !isReload && runningServices.ChannelManager != nil {
-		runningServices.ChannelManager.StopAll(shutdownCtx)
-	}
-	if runningServices.DeviceService != nil {
-		runningServices.DeviceService.Stop()
-	}
-	if runningServices.HeartbeatService != nil {
-		runningServices.HeartbeatService.Stop()
-	}
-	if runningServices.CronService != nil {
-		runningServices.CronService.Stop()
-	}
-	if runningServices.MediaStore != nil {
-		if fms, ok := runningServices.MediaStore.(*media.FileMediaStore); ok {
-			fms.Stop()
-		}
-	}
-}
-
-func shutdownGateway(
-	runningServices *services,
-	agentLoop *agent.AgentLoop,
-	provider providers.LLMProvider,
-	fullShutdown bool,
-) {
-	if cp, ok := provider.(providers.StatefulProvider); ok && fullShutdown {
-		cp.Close()
-	}
-
-	stopAndCleanupServices(runningServices, gracefulShutdownTimeout, false)
-
-	agentLoop.Stop()
-	agentLoop.Close()
-
-	logger.Info("✓ Gateway stopped")
-}
-
 func handleConfigReload(
 	ctx context.Context,
 	al *agent.AgentLoop,
@@ -396,6 +363,7 @@ func handleConfigReload(
 	logger.Info("🔄 Config file changed, reloading...")
 
 	newModel := newCfg.Agents.Defaults.ModelName
+	applyMetricsConfig(newCfg)
 
 	logger.Infof(" New model is '%s', recreating provider...", newModel)
 
@@ -443,6 +411,77 @@ func handleConfigReload(
 	return nil
 }
 
+// registerChannelProbe makes /ready fail while any enabled channel is not
+// running.
+func registerChannelProbe(hs *health.Server, cm *channels.Manager) {
+	hs.Checker().Register("channels", func(ctx context.Context) error {
+		var down []string
+		for name, st := range cm.GetStatus() {
+			if info, ok := st.(map[string]any); ok && info["running"] != true {
+				down = append(down, name)
+			}
+		}
+		if len(down) > 0 {
+			sort.Strings(down)
+			return fmt.Errorf("channels not running: %s", strings.Join(down, ", "))
+		}
+		return nil
+	})
+}
+
+// applyMetricsConfig applies the metrics settings that can change at runtime.
+func applyMetricsConfig(cfg *config.Config) {
+	metrics.DefaultRecorder().SetMaxUserIDs(cfg.Metrics.MaxUserIDs)
+	if err := metrics.SetLLMDurationBuckets(cfg.Metrics.LLMDurationBuckets); err != nil {
+		logger.WarnCF("metrics", "Ignoring metrics.llm_duration_buckets", map[string]any{"error": err.Error()})
+	}
+	applyModelPricing(cfg)
+}
+
+// applyModelPricing registers configured per-model prices with the LLM cost
+// metric, under both the model alias and the bare model ID.
+func applyModelPricing(cfg *config.Config) {
+	for _, mc := range cfg.ModelList {
+		if mc == nil || (mc.InputPricePer1K == 0 && mc.OutputPricePer1K == 0) {
+			continue
+		}
+		price := metrics.ModelPrice{InputPer1K: mc.InputPricePer1K, OutputPer1K: mc.OutputPricePer1K}
+		_, modelID := providers.ExtractProtocol(mc.Model)
+		metrics.SetModelPrice(modelID, price)
+		if mc.ModelName != "" {
+			metrics.SetModelPrice(mc.ModelName, price)
+		}
+	}
+}
+
+// setupTracing installs an OTLP tracer when tracing is enabled and returns a
+// function that flushes and removes it.
+func setupTracing(cfg *config.Config) func() {
+	tc := cfg.Tracing
+	if !tc.Enabled {
+		return func() {}
+	}
+	if tc.OTLPEndpoint == "" {
+		logger.Warn("Tracing is enabled but tracing.otlp_endpoint is not set; spans will not be exported")
+		return func() {}
+	}
+
+	shutdown, err := tracing.Setup(context.Background(), tc.OTLPEndpoint, tc.ServiceName)
+	if err != nil {
+		logger.WarnCF("tracing", "Failed to set up OTLP tracing", map[string]any{"error": err.Error()})
+		return func() {}
+	}
+	logger.InfoCF("tracing", "OTLP tracing enabled", map[string]any{"endpoint": tc.OTLPEndpoint})
+
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdown(ctx); err != nil {
+			logger.WarnCF("tracing", "Failed to flush spans", map[string]any{"error": err.Error()})
+		}
+	}
+}
+
 func restartServices(
 	al *agent.AgentLoop,
 	runningServices *services,
@@ -475,6 +514,7 @@ func restartServices(
 	)
 	runningServices.HeartbeatService.SetBus(msgBus)
 	runningServices.HeartbeatService.SetHandler(createHeartbeatHandler(al))
+	runningServices.HeartbeatService.SetTarget(cfg.Heartbeat.Channel, cfg.Heartbeat.ChatID)
 	if err = runningServices.HeartbeatService.Start(); err != nil {
 		return fmt.Errorf("error restarting heartbeat service: %w", err)
 	}
@@ -509,6 +549,7 @@ func restartServices(
 		runningServices.HealthServer = health.NewServer(cfg.Gateway.Host, cfg.Gateway.Port)
 	}
 	runningServices.ChannelManager.SetupHTTPServer(addr, runningServices.HealthServer)
+	registerChannelProbe(runningServices.HealthServer, runningServices.ChannelManager)
 
 	if err = runningServices.ChannelManager.Reload(context.Background(), cfg); err != nil {
 		return fmt.Errorf("error reload channels: %w", err)
@@ -631,6 +672,7 @@ func setupCronTool(
 	cronStorePath := filepath.Join(workspace, "cron", "jobs.json")
 
 	cronService := cron.NewCronService(cronStorePath, nil)
+	cronService.SetMissedPolicy(cfg.Tools.Cron.MissedPolicy)
 
 	var cronTool *tools.CronTool
 	if cfg.Tools.IsToolEnabled("cron") {
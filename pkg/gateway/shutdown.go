@@ -0,0 +1,133 @@
+package gateway
+
+import (
+	"context"
+	"time"
+
+	"github.com/sipeed/picoclaw/pkg/agent"
+	"github.com/sipeed/picoclaw/pkg/config"
+	"github.com/sipeed/picoclaw/pkg/logger"
+	"github.com/sipeed/picoclaw/pkg/media"
+	"github.com/sipeed/picoclaw/pkg/providers"
+)
+
+// shutdownStep stops one service. stop should return once the service has
+// stopped; ctx carries the deadline shared by the whole shutdown.
+type shutdownStep struct {
+	name string
+	stop func(ctx context.Context)
+}
+
+// lateStepGrace is how long a step started after the shutdown deadline may
+// take, so quick cleanups behind a hung service still finish.
+const lateStepGrace = 100 * time.Millisecond
+
+// runShutdown runs steps in order under ctx's deadline. A step still running
+// at the deadline is abandoned and logged; the remaining steps are still
+// started, each with lateStepGrace to finish, so stores get a chance to
+// flush. It returns the names of the steps that did not stop in time.
+func runShutdown(ctx context.Context, steps []shutdownStep) []string {
+	var timedOut []string
+	for _, step := range steps {
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			step.stop(ctx)
+		}()
+
+		waitCtx, cancel := ctx, context.CancelFunc(func() {})
+		if ctx.Err() != nil {
+			waitCtx, cancel = context.WithTimeout(context.Background(), lateStepGrace)
+		}
+
+		select {
+		case <-done:
+		case <-waitCtx.Done():
+			timedOut = append(timedOut, step.name)
+			logger.WarnCF("gateway", "Service did not stop before the shutdown deadline",
+				map[string]any{"service": step.name})
+		}
+		cancel()
+	}
+	return timedOut
+}
+
+// ingressSteps stop everything that feeds work to the agent loop: channels
+// (kept running across reloads), devices, heartbeat and cron.
+func ingressSteps(runningServices *services, isReload bool) []shutdownStep {
+	var steps []shutdownStep
+	if !isReload && runningServices.ChannelManager != nil {
+		steps = append(steps, shutdownStep{"channels", func(ctx context.Context) {
+			runningServices.ChannelManager.StopAll(ctx)
+		}})
+	}
+	if runningServices.DeviceService != nil {
+		steps = append(steps, shutdownStep{"devices", func(context.Context) {
+			runningServices.DeviceService.Stop()
+		}})
+	}
+	if runningServices.HeartbeatService != nil {
+		steps = append(steps, shutdownStep{"heartbeat", func(context.Context) {
+			runningServices.HeartbeatService.Stop()
+		}})
+	}
+	if runningServices.CronService != nil {
+		steps = append(steps, shutdownStep{"cron", func(context.Context) {
+			runningServices.CronService.Stop()
+		}})
+	}
+	return steps
+}
+
+// storeSteps stop the stores services write to; they go last.
+func storeSteps(runningServices *services) []shutdownStep {
+	if fms, ok := runningServices.MediaStore.(*media.FileMediaStore); ok {
+		return []shutdownStep{{"media store", func(context.Context) { fms.Stop() }}}
+	}
+	return nil
+}
+
+// shutdownTimeout is the configured gateway shutdown deadline.
+func shutdownTimeout(cfg *config.Config) time.Duration {
+	if cfg != nil && cfg.Gateway.ShutdownTimeout > 0 {
+		return time.Duration(cfg.Gateway.ShutdownTimeout) * time.Second
+	}
+	return gracefulShutdownTimeout
+}
+
+func stopAndCleanupServices(runningServices *services, timeout time.Duration, isReload bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	runShutdown(ctx, append(ingressSteps(runningServices, isReload), storeSteps(runningServices)...))
+}
+
+// shutdownGateway stops the gateway in dependency order: ingress first so no
+// new work arrives, then the agent loop, then stores and the provider.
+func shutdownGateway(
+	runningServices *services,
+	agentLoop *agent.AgentLoop,
+	provider providers.LLMProvider,
+	fullShutdown bool,
+	timeout time.Duration,
+) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	steps := ingressSteps(runningServices, false)
+	steps = append(steps, shutdownStep{"agent loop", func(context.Context) {
+		agentLoop.Stop()
+		agentLoop.Close()
+	}})
+	steps = append(steps, storeSteps(runningServices)...)
+	if cp, ok := provider.(providers.StatefulProvider); ok && fullShutdown {
+		steps = append(steps, shutdownStep{"provider", func(context.Context) { cp.Close() }})
+	}
+
+	if timedOut := runShutdown(ctx, steps); len(timedOut) > 0 {
+		logger.WarnCF("gateway", "Gateway stopped with services still running",
+			map[string]any{"services": timedOut, "timeout": timeout.String()})
+		return
+	}
+	logger.Info("✓ Gateway stopped")
+}
@@ -0,0 +1,87 @@
+package gateway
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sipeed/picoclaw/pkg/config"
+)
+
+func TestRunShutdown_HungServiceDoesNotBlock(t *testing.T) {
+	hang := make(chan struct{})
+	defer close(hang)
+
+	var mu sync.Mutex
+	var stopped []string
+	record := func(name string) func(context.Context) {
+		return func(context.Context) {
+			mu.Lock()
+			stopped = append(stopped, name)
+			mu.Unlock()
+		}
+	}
+
+	steps := []shutdownStep{
+		{"channels", record("channels")},
+		{"hung", func(context.Context) { <-hang }},
+		{"agent loop", record("agent loop")},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	timedOut := runShutdown(ctx, steps)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("shutdown took %v, want it bounded by the deadline", elapsed)
+	}
+
+	if len(timedOut) != 1 || timedOut[0] != "hung" {
+		t.Errorf("timed out = %v, want [hung]", timedOut)
+	}
+
+	// Steps after the hung one still get started.
+	deadline := time.Now().Add(time.Second)
+	for {
+		mu.Lock()
+		n := len(stopped)
+		mu.Unlock()
+		if n == 2 || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(stopped) != 2 || stopped[0] != "channels" || stopped[1] != "agent loop" {
+		t.Errorf("stopped = %v, want [channels agent loop]", stopped)
+	}
+}
+
+func TestRunShutdown_Order(t *testing.T) {
+	var order []string
+	step := func(name string) shutdownStep {
+		return shutdownStep{name, func(context.Context) { order = append(order, name) }}
+	}
+
+	timedOut := runShutdown(context.Background(), []shutdownStep{step("a"), step("b"), step("c")})
+	if len(timedOut) != 0 {
+		t.Errorf("timed out = %v, want none", timedOut)
+	}
+	if len(order) != 3 || order[0] != "a" || order[1] != "b" || order[2] != "c" {
+		t.Errorf("order = %v, want [a b c]", order)
+	}
+}
+
+func TestShutdownTimeout(t *testing.T) {
+	cfg := &config.Config{}
+	if got := shutdownTimeout(cfg); got != gracefulShutdownTimeout {
+		t.Errorf("default = %v, want %v", got, gracefulShutdownTimeout)
+	}
+	cfg.Gateway.ShutdownTimeout = 3
+	if got := shutdownTimeout(cfg); got != 3*time.Second {
+		t.Errorf("configured = %v, want 3s", got)
+	}
+}
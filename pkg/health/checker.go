@@ -0,0 +1,62 @@
+package health
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Probe reports whether a subsystem is ready. A nil error means ready; the
+// error message is surfaced in the readiness response otherwise.
+type Probe func(ctx context.Context) error
+
+// Checker is a registry of readiness probes. Services register a probe when
+// they start, and /ready evaluates every probe on each request.
+type Checker struct {
+	mu     sync.RWMutex
+	probes map[string]Probe
+}
+
+// NewChecker returns an empty Checker.
+func NewChecker() *Checker {
+	return &Checker{probes: make(map[string]Probe)}
+}
+
+// Register adds or replaces the probe for a subsystem.
+func (c *Checker) Register(name string, probe Probe) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.probes[name] = probe
+}
+
+// Unregister removes the probe for a subsystem.
+func (c *Checker) Unregister(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.probes, name)
+}
+
+// Run evaluates every probe. It returns the result of each probe keyed by
+// subsystem name, and the sorted names of subsystems that are not ready.
+func (c *Checker) Run(ctx context.Context) (checks map[string]Check, notReady []string) {
+	c.mu.RLock()
+	probes := make(map[string]Probe, len(c.probes))
+	for name, p := range c.probes {
+		probes[name] = p
+	}
+	c.mu.RUnlock()
+
+	checks = make(map[string]Check, len(probes))
+	for name, probe := range probes {
+		check := Check{Name: name, Status: statusString(true), Timestamp: time.Now()}
+		if err := probe(ctx); err != nil {
+			check.Status = statusString(false)
+			check.Message = err.Error()
+			notReady = append(notReady, name)
+		}
+		checks[name] = check
+	}
+	sort.Strings(notReady)
+	return checks, notReady
+}
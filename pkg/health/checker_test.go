@@ -0,0 +1,67 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestChecker_Run(t *testing.T) {
+	c := NewChecker()
+	c.Register("provider", func(ctx context.Context) error { return nil })
+	c.Register("channels", func(ctx context.Context) error { return errors.New("telegram not running") })
+
+	checks, notReady := c.Run(context.Background())
+	if len(checks) != 2 {
+		t.Fatalf("expected 2 checks, got %d", len(checks))
+	}
+	if checks["provider"].Status != "ok" {
+		t.Errorf("provider status = %q, want ok", checks["provider"].Status)
+	}
+	if checks["channels"].Status != "fail" || checks["channels"].Message != "telegram not running" {
+		t.Errorf("unexpected channels check: %+v", checks["channels"])
+	}
+	if len(notReady) != 1 || notReady[0] != "channels" {
+		t.Errorf("notReady = %v, want [channels]", notReady)
+	}
+
+	c.Unregister("channels")
+	if _, notReady = c.Run(context.Background()); len(notReady) != 0 {
+		t.Errorf("expected all ready after unregistering failing probe, got %v", notReady)
+	}
+}
+
+func TestServer_ReadyReflectsProbes(t *testing.T) {
+	s := NewServer("127.0.0.1", 0)
+	s.SetReady(true)
+
+	ready := func() *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		s.readyHandler(rec, httptest.NewRequest(http.MethodGet, "/ready", nil))
+		return rec
+	}
+
+	s.Checker().Register("provider", func(ctx context.Context) error { return nil })
+	if rec := ready(); rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 with passing probe, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	s.Checker().Register("channels", func(ctx context.Context) error { return errors.New("no channels running") })
+	rec := ready()
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 with failing probe, got %d", rec.Code)
+	}
+	var resp StatusResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(resp.NotReady) != 1 || resp.NotReady[0] != "channels" {
+		t.Errorf("not_ready = %v, want [channels]", resp.NotReady)
+	}
+	if resp.Checks["channels"].Message != "no channels running" {
+		t.Errorf("unexpected channels check: %+v", resp.Checks["channels"])
+	}
+}
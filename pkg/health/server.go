@@ -7,8 +7,12 @@ import (
 	"maps"
 	"net/http"
 	"os"
+	"sort"
+	"strings"
 	"sync"
 	"time"
+
+	"github.com/sipeed/picoclaw/pkg/config"
 )
 
 type Server struct {
@@ -16,6 +20,7 @@ type Server struct {
 	mu         sync.RWMutex
 	ready      bool
 	checks     map[string]Check
+	checker    *Checker
 	startTime  time.Time
 	reloadFunc func() error
 }
@@ -28,10 +33,12 @@ type Check struct {
 }
 
 type StatusResponse struct {
-	Status string           `json:"status"`
-	Uptime string           `json:"uptime"`
-	Checks map[string]Check `json:"checks,omitempty"`
-	Pid    int              `json:"pid"`
+	Status   string           `json:"status"`
+	Uptime   string           `json:"uptime"`
+	Version  string           `json:"version,omitempty"`
+	Checks   map[string]Check `json:"checks,omitempty"`
+	NotReady []string         `json:"not_ready,omitempty"`
+	Pid      int              `json:"pid"`
 }
 
 func NewServer(host string, port int) *Server {
@@ -39,6 +46,7 @@ func NewServer(host string, port int) *Server {
 	s := &Server{
 		ready:     false,
 		checks:    make(map[string]Check),
+		checker:   NewChecker(),
 		startTime: time.Now(),
 	}
 
@@ -108,6 +116,11 @@ func (s *Server) RegisterCheck(name string, checkFn func() (bool, string)) {
 	}
 }
 
+// Checker returns the registry of live readiness probes evaluated by /ready.
+func (s *Server) Checker() *Checker {
+	return s.checker
+}
+
 // SetReloadFunc sets the callback function for config reload.
 func (s *Server) SetReloadFunc(fn func() error) {
 	s.mu.Lock()
@@ -152,9 +165,10 @@ func (s *Server) healthHandler(w http.ResponseWriter, r *http.Request) {
 
 	uptime := time.Since(s.startTime)
 	resp := StatusResponse{
-		Status: "ok",
-		Uptime: uptime.String(),
-		Pid:    os.Getpid(),
+		Status:  "ok",
+		Uptime:  uptime.String(),
+		Version: config.FormatVersion(),
+		Pid:     os.Getpid(),
 	}
 
 	json.NewEncoder(w).Encode(resp)
@@ -169,26 +183,25 @@ func (s *Server) readyHandler(w http.ResponseWriter, r *http.Request) {
 	maps.Copy(checks, s.checks)
 	s.mu.RUnlock()
 
-	if !ready {
+	probeChecks, notReady := s.checker.Run(r.Context())
+	for name, check := range checks {
+		if check.Status == "fail" {
+			notReady = append(notReady, name)
+		}
+	}
+	sort.Strings(notReady)
+	maps.Copy(checks, probeChecks)
+
+	if !ready || len(notReady) > 0 {
 		w.WriteHeader(http.StatusServiceUnavailable)
 		json.NewEncoder(w).Encode(StatusResponse{
-			Status: "not ready",
-			Checks: checks,
+			Status:   "not ready",
+			Checks:   checks,
+			NotReady: notReady,
 		})
 		return
 	}
 
-	for _, check := range checks {
-		if check.Status == "fail" {
-			w.WriteHeader(http.StatusServiceUnavailable)
-			json.NewEncoder(w).Encode(StatusResponse{
-				Status: "not ready",
-				Checks: checks,
-			})
-			return
-		}
-	}
-
 	w.WriteHeader(http.StatusOK)
 	uptime := time.Since(s.startTime)
 	json.NewEncoder(w).Encode(StatusResponse{
@@ -206,6 +219,18 @@ func (s *Server) RegisterOnMux(mux *http.ServeMux) {
 	mux.HandleFunc("/reload", s.reloadHandler)
 }
 
+// WantsJSON reports whether the client asked for a JSON response via the
+// Accept header.
+func WantsJSON(r *http.Request) bool {
+	for _, part := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, _, _ := strings.Cut(part, ";")
+		if strings.TrimSpace(mediaType) == "application/json" {
+			return true
+		}
+	}
+	return false
+}
+
 func statusString(ok bool) string {
 	if ok {
 		return "ok"
@@ -32,7 +32,8 @@ const (
 
 // HeartbeatHandler is the function type for handling heartbeat.
 // It returns a ToolResult that can indicate async operations.
-// channel and chatID are derived from the last active user channel.
+// channel and chatID are the configured target (see SetTarget), or else
+// the last active user channel.
 type HeartbeatHandler func(prompt, channel, chatID string) *tools.ToolResult
 
 // HeartbeatService manages periodic heartbeat checks
@@ -41,6 +42,7 @@ type HeartbeatService struct {
 	bus       *bus.MessageBus
 	state     *state.Manager
 	handler   HeartbeatHandler
+	target    string // "channel:chatID" set by SetTarget, or empty
 	interval  time.Duration
 	enabled   bool
 	mu        sync.RWMutex
@@ -80,6 +82,20 @@ func (hs *HeartbeatService) SetHandler(handler HeartbeatHandler) {
 	hs.handler = handler
 }
 
+// SetTarget routes heartbeats to a fixed channel and chat instead of the
+// last channel a user was active on. The handler receives this target, so
+// results produced later (e.g. by async subagents) are delivered there too.
+// Empty values restore last-channel routing.
+func (hs *HeartbeatService) SetTarget(channel, chatID string) {
+	hs.mu.Lock()
+	defer hs.mu.Unlock()
+	if channel == "" || chatID == "" {
+		hs.target = ""
+		return
+	}
+	hs.target = channel + ":" + chatID
+}
+
 // Start begins the heartbeat service
 func (hs *HeartbeatService) Start() error {
 	hs.mu.Lock()
@@ -151,6 +167,7 @@ func (hs *HeartbeatService) executeHeartbeat() {
 	hs.mu.RLock()
 	enabled := hs.enabled
 	handler := hs.handler
+	target := hs.target
 	if !hs.enabled || hs.stopChan == nil {
 		hs.mu.RUnlock()
 		return
@@ -174,12 +191,15 @@ func (hs *HeartbeatService) executeHeartbeat() {
 		return
 	}
 
-	// Get last channel info for context
-	lastChannel := hs.state.GetLastChannel()
-	channel, chatID := hs.parseLastChannel(lastChannel)
+	// Route to the configured target, or else the last active channel
+	routing, source := target, "target"
+	if routing == "" {
+		routing, source = hs.state.GetLastChannel(), "lastChannel"
+	}
+	channel, chatID := hs.parseLastChannel(routing)
 
 	// Debug log for channel resolution
-	hs.logInfof("Resolved channel: %s, chatID: %s (from lastChannel: %s)", channel, chatID, lastChannel)
+	hs.logInfof("Resolved channel: %s, chatID: %s (from %s: %s)", channel, chatID, source, routing)
 
 	start := time.Now()
 	result := handler(prompt, channel, chatID)
@@ -217,9 +237,9 @@ func (hs *HeartbeatService) executeHeartbeat() {
 
 	// Send result to user
 	if result.ForUser != "" {
-		hs.sendResponse(result.ForUser)
+		hs.sendResponse(channel, chatID, result.ForUser)
 	} else if result.ForLLM != "" {
-		hs.sendResponse(result.ForLLM)
+		hs.sendResponse(channel, chatID, result.ForLLM)
 	}
 	metrics.DefaultRecorder().RecordHeartbeat("success", hs.workspace, duration)
 
@@ -319,8 +339,9 @@ func heartbeatHasUserTasks(content string) bool {
 	return false
 }
 
-// sendResponse sends the heartbeat response to the last channel
-func (hs *HeartbeatService) sendResponse(response string) {
+// sendResponse sends the heartbeat response to the channel and chat the
+// heartbeat was resolved to. Empty values mean there is nowhere to send it.
+func (hs *HeartbeatService) sendResponse(platform, userID, response string) {
 	hs.mu.RLock()
 	msgBus := hs.bus
 	hs.mu.RUnlock()
@@ -330,17 +351,9 @@ func (hs *HeartbeatService) sendResponse(response string) {
 		return
 	}
 
-	// Get last channel from state
-	lastChannel := hs.state.GetLastChannel()
-	if lastChannel == "" {
-		hs.logInfof("No last channel recorded, heartbeat result not sent")
-		return
-	}
-
-	platform, userID := hs.parseLastChannel(lastChannel)
-
-	// Skip internal channels that can't receive messages
+	// Skip missing or internal channels that can't receive messages
 	if platform == "" || userID == "" {
+		hs.logInfof("No heartbeat target or last channel recorded, heartbeat result not sent")
 		return
 	}
 
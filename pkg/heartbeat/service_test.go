@@ -7,6 +7,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/sipeed/picoclaw/pkg/bus"
 	"github.com/sipeed/picoclaw/pkg/tools"
 )
 
@@ -248,3 +249,53 @@ func TestBuildPrompt_UserTasksAfterMarkerProducePrompt(t *testing.T) {
 		t.Fatalf("prompt = %q, want user task content", prompt)
 	}
 }
+
+func TestExecuteHeartbeat_ConfiguredTarget(t *testing.T) {
+	tmpDir := t.TempDir()
+	os.WriteFile(filepath.Join(tmpDir, "HEARTBEAT.md"), []byte("Test task"), 0o644)
+
+	hs := NewHeartbeatService(tmpDir, 30, true)
+	hs.stopChan = make(chan struct{}) // Enable for testing
+	msgBus := bus.NewMessageBus()
+	defer msgBus.Close()
+	hs.SetBus(msgBus)
+	if err := hs.state.SetLastChannel("discord:777"); err != nil {
+		t.Fatalf("SetLastChannel: %v", err)
+	}
+
+	var gotChannel, gotChatID string
+	hs.SetHandler(func(prompt, channel, chatID string) *tools.ToolResult {
+		gotChannel, gotChatID = channel, chatID
+		return &tools.ToolResult{ForUser: "Reminder: water the plants"}
+	})
+
+	hs.SetTarget("telegram", "42")
+	hs.executeHeartbeat()
+
+	if gotChannel != "telegram" || gotChatID != "42" {
+		t.Errorf("handler got %s:%s, want the configured target telegram:42", gotChannel, gotChatID)
+	}
+	select {
+	case out := <-msgBus.OutboundChan():
+		if out.Channel != "telegram" || out.ChatID != "42" || out.Content != "Reminder: water the plants" {
+			t.Errorf("outbound = %+v, want the result sent to telegram:42", out)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("heartbeat result was not published")
+	}
+
+	// Clearing the target falls back to the last active channel.
+	hs.SetTarget("", "")
+	hs.executeHeartbeat()
+	if gotChannel != "discord" || gotChatID != "777" {
+		t.Errorf("handler got %s:%s, want the last channel discord:777", gotChannel, gotChatID)
+	}
+	select {
+	case out := <-msgBus.OutboundChan():
+		if out.Channel != "discord" || out.ChatID != "777" {
+			t.Errorf("outbound = %+v, want discord:777", out)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("heartbeat result was not published")
+	}
+}
@@ -1,6 +1,9 @@
 package logger
 
 import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -8,6 +11,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/rs/zerolog"
 )
@@ -31,31 +35,72 @@ var (
 		FATAL: "FATAL",
 	}
 
-	currentLevel = INFO
-	logger       zerolog.Logger
-	fileLogger   zerolog.Logger
-	logFile      *os.File
-	once         sync.Once
-	mu           sync.RWMutex
+	currentLevel    = INFO
+	componentLevels = map[string]LogLevel{}
+	logger          zerolog.Logger
+	fileLogger      zerolog.Logger
+	logFile         *os.File
+	once            sync.Once
+	mu              sync.RWMutex
 )
 
+// Format is the console log output format.
+type Format int
+
+const (
+	// FormatText is human-readable, colored console output.
+	FormatText Format = iota
+	// FormatJSON writes one JSON object per line:
+	// {"level":...,"component":...,"fields":{...},"time":...,"caller":...,"msg":...}.
+	FormatJSON
+)
+
+var consoleFormat = FormatText
+
 func init() {
 	once.Do(func() {
 		zerolog.SetGlobalLevel(zerolog.InfoLevel)
 
-		consoleWriter := zerolog.ConsoleWriter{
-			Out:        os.Stdout,
-			TimeFormat: "15:04:05", // TODO: make it configurable???
-
-			// Custom formatter to handle multiline strings and JSON objects
-			FormatFieldValue: formatFieldValue,
-		}
-
-		logger = zerolog.New(consoleWriter).With().Timestamp().Caller().Logger()
+		logger = newConsoleLogger(FormatText)
 		fileLogger = zerolog.Logger{}
 	})
 }
 
+func newConsoleLogger(format Format) zerolog.Logger {
+	if format == FormatJSON {
+		return zerolog.New(os.Stdout).With().Timestamp().Caller().Logger()
+	}
+
+	consoleWriter := zerolog.ConsoleWriter{
+		Out:        os.Stdout,
+		TimeFormat: "15:04:05", // TODO: make it configurable???
+
+		// Custom formatter to handle multiline strings and JSON objects
+		FormatFieldValue: formatFieldValue,
+	}
+	return zerolog.New(consoleWriter).With().Timestamp().Caller().Logger()
+}
+
+// SetFormat switches the console output format. The console level set by
+// SetConsoleLevel is kept; the log file is always JSON and is unaffected.
+func SetFormat(format Format) {
+	mu.Lock()
+	defer mu.Unlock()
+	logger = newConsoleLogger(format).Level(logger.GetLevel())
+	consoleFormat = format
+}
+
+// SetFormatFromString sets the console format from "text" or "json".
+// Empty or unrecognized values keep the current format.
+func SetFormatFromString(s string) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "text":
+		SetFormat(FormatText)
+	case "json":
+		SetFormat(FormatJSON)
+	}
+}
+
 func formatFieldValue(i any) string {
 	var s string
 
@@ -91,7 +136,48 @@ func SetLevel(level LogLevel) {
 	mu.Lock()
 	defer mu.Unlock()
 	currentLevel = level
-	zerolog.SetGlobalLevel(level)
+	applyGlobalLevelLocked()
+}
+
+// SetComponentLevel overrides the level for log lines tagged with
+// component, e.g. to see DEBUG output from "mcp" while everything else
+// stays at INFO. Components without an override use the global level.
+func SetComponentLevel(component string, level LogLevel) {
+	mu.Lock()
+	defer mu.Unlock()
+	componentLevels[component] = level
+	applyGlobalLevelLocked()
+}
+
+// ClearComponentLevel removes the override set by SetComponentLevel.
+func ClearComponentLevel(component string) {
+	mu.Lock()
+	defer mu.Unlock()
+	delete(componentLevels, component)
+	applyGlobalLevelLocked()
+}
+
+// applyGlobalLevelLocked lowers zerolog's global level to the most verbose
+// level in use, so component overrides below the global level are not
+// dropped by zerolog; logMessage does the actual filtering.
+func applyGlobalLevelLocked() {
+	lowest := currentLevel
+	for _, level := range componentLevels {
+		if level < lowest {
+			lowest = level
+		}
+	}
+	zerolog.SetGlobalLevel(lowest)
+}
+
+// levelFor returns the level in effect for component.
+func levelFor(component string) LogLevel {
+	mu.RLock()
+	defer mu.RUnlock()
+	if level, ok := componentLevels[component]; ok && component != "" {
+		return level
+	}
+	return currentLevel
 }
 
 func SetConsoleLevel(level LogLevel) {
@@ -219,20 +305,30 @@ func getEvent(logger zerolog.Logger, level LogLevel) *zerolog.Event {
 }
 
 func logMessage(level LogLevel, component string, message string, fields map[string]any) {
-	if level < currentLevel {
+	if level < levelFor(component) {
 		return
 	}
 
 	skip := getCallerSkip()
 
-	event := getEvent(logger, level)
+	mu.RLock()
+	console, format := logger, consoleFormat
+	mu.RUnlock()
+
+	event := getEvent(console, level)
 
 	if component != "" {
 		event.Str("component", component)
 	}
 
-	appendFields(event, fields)
-	event.CallerSkipFrame(skip).Msg(message)
+	if format == FormatJSON {
+		fieldsDict := zerolog.Dict()
+		appendFields(fieldsDict, fields)
+		event.Dict("fields", fieldsDict).Str("msg", message).CallerSkipFrame(skip).Send()
+	} else {
+		appendFields(event, fields)
+		event.CallerSkipFrame(skip).Msg(message)
+	}
 
 	// Also log to file if enabled
 	if fileLogger.GetLevel() != zerolog.NoLevel {
@@ -274,6 +370,96 @@ func appendFields(event *zerolog.Event, fields map[string]any) {
 	}
 }
 
+type requestIDKey struct{}
+
+// WithRequestID returns a copy of ctx carrying id. The *CtxCF helpers add it
+// to every log line as the "request_id" field, so lines from one user turn
+// can be tied together across the gateway, agent and tools.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFromContext returns the request ID stored by WithRequestID, or
+// "" if there is none.
+func RequestIDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// NewRequestID returns a random 16-character hex ID.
+func NewRequestID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// contextFields returns fields plus the request ID from ctx, without
+// modifying the caller's map.
+func contextFields(ctx context.Context, fields map[string]any) map[string]any {
+	id := RequestIDFromContext(ctx)
+	if id == "" {
+		return fields
+	}
+	merged := make(map[string]any, len(fields)+1)
+	for k, v := range fields {
+		merged[k] = v
+	}
+	merged["request_id"] = id
+	return merged
+}
+
+// sampleWindow is how long a sampling key keeps its count. The first call
+// after the window closes is always logged.
+const sampleWindow = time.Minute
+
+var (
+	samplesMu sync.Mutex
+	samples   = map[string]*sampleState{}
+	sampleNow = time.Now // for testing
+)
+
+type sampleState struct {
+	windowStart time.Time
+	count       int
+}
+
+// sampled reports whether the current call for key should be logged: the
+// first of every n calls within sampleWindow is.
+func sampled(key string, n int) bool {
+	if n <= 1 {
+		return true
+	}
+	samplesMu.Lock()
+	defer samplesMu.Unlock()
+
+	now := sampleNow()
+	st, ok := samples[key]
+	if !ok || now.Sub(st.windowStart) >= sampleWindow {
+		st = &sampleState{windowStart: now}
+		samples[key] = st
+	}
+	st.count++
+	return (st.count-1)%n == 0
+}
+
+func logSampled(level LogLevel, component, key string, n int, message string, fields map[string]any) {
+	if level < levelFor(component) || !sampled(key, n) {
+		return
+	}
+	if n > 1 {
+		merged := make(map[string]any, len(fields)+1)
+		for k, v := range fields {
+			merged[k] = v
+		}
+		merged["sample_rate"] = n
+		fields = merged
+	}
+	logMessage(level, component, message, fields)
+}
+
 func Debug(message string) {
 	logMessage(DEBUG, "", message, nil)
 }
@@ -294,6 +480,18 @@ func DebugCF(component string, message string, fields map[string]any) {
 	logMessage(DEBUG, component, message, fields)
 }
 
+// DebugCtxCF is DebugCF plus the request ID carried by ctx.
+func DebugCtxCF(ctx context.Context, component string, message string, fields map[string]any) {
+	logMessage(DEBUG, component, message, contextFields(ctx, fields))
+}
+
+// DebugSampledCF is DebugCF for hot paths: of the calls sharing key, only
+// the first of every n within a minute is logged. Keys should be fixed
+// strings, such as "heartbeat.tick", not per-message values.
+func DebugSampledCF(component, key string, n int, message string, fields map[string]any) {
+	logSampled(DEBUG, component, key, n, message, fields)
+}
+
 func Info(message string) {
 	logMessage(INFO, "", message, nil)
 }
@@ -314,6 +512,16 @@ func InfoCF(component string, message string, fields map[string]any) {
 	logMessage(INFO, component, message, fields)
 }
 
+// InfoCtxCF is InfoCF plus the request ID carried by ctx.
+func InfoCtxCF(ctx context.Context, component string, message string, fields map[string]any) {
+	logMessage(INFO, component, message, contextFields(ctx, fields))
+}
+
+// InfoSampledCF is the INFO counterpart of DebugSampledCF.
+func InfoSampledCF(component, key string, n int, message string, fields map[string]any) {
+	logSampled(INFO, component, key, n, message, fields)
+}
+
 func Warn(message string) {
 	logMessage(WARN, "", message, nil)
 }
@@ -330,6 +538,11 @@ func WarnCF(component string, message string, fields map[string]any) {
 	logMessage(WARN, component, message, fields)
 }
 
+// WarnCtxCF is WarnCF plus the request ID carried by ctx.
+func WarnCtxCF(ctx context.Context, component string, message string, fields map[string]any) {
+	logMessage(WARN, component, message, contextFields(ctx, fields))
+}
+
 func Error(message string) {
 	logMessage(ERROR, "", message, nil)
 }
@@ -350,6 +563,11 @@ func ErrorCF(component string, message string, fields map[string]any) {
 	logMessage(ERROR, component, message, fields)
 }
 
+// ErrorCtxCF is ErrorCF plus the request ID carried by ctx.
+func ErrorCtxCF(ctx context.Context, component string, message string, fields map[string]any) {
+	logMessage(ERROR, component, message, contextFields(ctx, fields))
+}
+
 func Fatal(message string) {
 	logMessage(FATAL, "", message, nil)
 }
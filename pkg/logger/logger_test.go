@@ -2,9 +2,11 @@ package logger
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/rs/zerolog"
 )
@@ -365,3 +367,260 @@ func TestAppendFields_ErrorUsesErrorString(t *testing.T) {
 		t.Fatalf("error field = %#v, want %q", got["error"], "transcription request failed")
 	}
 }
+
+// captureLogs points the console logger at a buffer for the test and
+// returns it; each log line is one JSON object.
+func captureLogs(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	mu.Lock()
+	prev := logger
+	logger = zerolog.New(&buf)
+	mu.Unlock()
+	t.Cleanup(func() {
+		mu.Lock()
+		logger = prev
+		mu.Unlock()
+	})
+	return &buf
+}
+
+func decodeLogLines(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var lines []map[string]any
+	for _, line := range bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n")) {
+		if len(line) == 0 {
+			continue
+		}
+		var got map[string]any
+		if err := json.Unmarshal(line, &got); err != nil {
+			t.Fatalf("unmarshal log line %q: %v", line, err)
+		}
+		lines = append(lines, got)
+	}
+	return lines
+}
+
+func TestCtxCF_IncludesRequestID(t *testing.T) {
+	buf := captureLogs(t)
+	initialLevel := GetLevel()
+	defer SetLevel(initialLevel)
+	SetLevel(DEBUG)
+
+	ctx := WithRequestID(context.Background(), "req-123")
+	fields := map[string]any{"tool": "search"}
+	DebugCtxCF(ctx, "agent", "debug line", fields)
+	InfoCtxCF(ctx, "agent", "info line", fields)
+	WarnCtxCF(ctx, "agent", "warn line", nil)
+	ErrorCtxCF(ctx, "agent", "error line", nil)
+	InfoCtxCF(context.Background(), "agent", "no id", nil)
+
+	lines := decodeLogLines(t, buf)
+	if len(lines) != 5 {
+		t.Fatalf("got %d log lines, want 5", len(lines))
+	}
+	for _, line := range lines[:4] {
+		if line["request_id"] != "req-123" {
+			t.Errorf("line %v is missing request_id", line)
+		}
+		if line["component"] != "agent" {
+			t.Errorf("line %v is missing component", line)
+		}
+	}
+	if lines[0]["tool"] != "search" {
+		t.Errorf("caller fields were dropped: %v", lines[0])
+	}
+	if _, ok := lines[4]["request_id"]; ok {
+		t.Errorf("line without a request ID in context has one: %v", lines[4])
+	}
+	if _, ok := fields["request_id"]; ok {
+		t.Error("the caller's fields map must not be modified")
+	}
+}
+
+func TestRequestIDFromContext(t *testing.T) {
+	if id := RequestIDFromContext(context.Background()); id != "" {
+		t.Errorf("RequestIDFromContext(empty) = %q, want empty", id)
+	}
+	a, b := NewRequestID(), NewRequestID()
+	if len(a) != 16 || a == b {
+		t.Errorf("NewRequestID returned %q and %q, want distinct 16-character IDs", a, b)
+	}
+	if got := RequestIDFromContext(WithRequestID(context.Background(), a)); got != a {
+		t.Errorf("RequestIDFromContext = %q, want %q", got, a)
+	}
+}
+
+func TestSetComponentLevel(t *testing.T) {
+	buf := captureLogs(t)
+	initialLevel := GetLevel()
+	defer SetLevel(initialLevel)
+	SetLevel(INFO)
+
+	SetComponentLevel("mcp", DEBUG)
+	SetComponentLevel("noisy", ERROR)
+	defer ClearComponentLevel("mcp")
+	defer ClearComponentLevel("noisy")
+
+	DebugCF("mcp", "mcp debug", nil)
+	DebugCF("agent", "agent debug", nil)
+	InfoC("agent", "agent info")
+	WarnC("noisy", "noisy warn")
+	ErrorC("noisy", "noisy error")
+	Debug("plain debug")
+
+	var got []string
+	for _, line := range decodeLogLines(t, buf) {
+		got = append(got, line["message"].(string))
+	}
+	want := []string{"mcp debug", "agent info", "noisy error"}
+	if len(got) != len(want) {
+		t.Fatalf("logged %q, want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("logged %q, want %q", got, want)
+		}
+	}
+
+	if GetLevel() != INFO {
+		t.Errorf("GetLevel() = %v, want the global level to stay INFO", GetLevel())
+	}
+
+	ClearComponentLevel("mcp")
+	buf.Reset()
+	DebugCF("mcp", "mcp debug after clear", nil)
+	if buf.Len() != 0 {
+		t.Errorf("cleared component still logs DEBUG: %s", buf.String())
+	}
+}
+
+func TestDebugSampledCF(t *testing.T) {
+	buf := captureLogs(t)
+	initialLevel := GetLevel()
+	defer SetLevel(initialLevel)
+	SetLevel(DEBUG)
+
+	now := time.Unix(1000, 0)
+	sampleNow = func() time.Time { return now }
+	defer func() { sampleNow = time.Now }()
+
+	for range 100 {
+		DebugSampledCF("heartbeat", "test.tick", 10, "tick", nil)
+	}
+	for range 5 {
+		DebugSampledCF("heartbeat", "test.other", 10, "other", nil)
+	}
+	for range 3 {
+		DebugSampledCF("heartbeat", "test.every", 1, "every", nil)
+	}
+
+	counts := map[string]int{}
+	for _, line := range decodeLogLines(t, buf) {
+		counts[line["message"].(string)]++
+		if line["message"] == "tick" && line["sample_rate"] != float64(10) {
+			t.Errorf("sampled line should record sample_rate: %v", line)
+		}
+	}
+	if counts["tick"] != 10 {
+		t.Errorf("logged %d of 100 calls at 1-in-10, want 10", counts["tick"])
+	}
+	if counts["other"] != 1 {
+		t.Errorf("keys must be sampled independently: logged %d of 5, want 1", counts["other"])
+	}
+	if counts["every"] != 3 {
+		t.Errorf("n=1 logged %d of 3 calls, want all", counts["every"])
+	}
+
+	// A new window starts the count again, so its first call is logged.
+	buf.Reset()
+	DebugSampledCF("heartbeat", "test.tick", 10, "tick", nil) // call 101 starts the next 1-in-10 group
+	DebugSampledCF("heartbeat", "test.tick", 10, "tick", nil)
+	if n := len(decodeLogLines(t, buf)); n != 1 {
+		t.Errorf("calls 101-102 logged %d lines, want 1", n)
+	}
+	buf.Reset()
+	now = now.Add(sampleWindow)
+	DebugSampledCF("heartbeat", "test.tick", 10, "tick", nil)
+	if buf.Len() == 0 {
+		t.Error("first call of a new window should be logged")
+	}
+}
+
+func TestJSONFormat(t *testing.T) {
+	buf := captureLogs(t)
+	mu.Lock()
+	consoleFormat = FormatJSON
+	mu.Unlock()
+	defer func() {
+		mu.Lock()
+		consoleFormat = FormatText
+		mu.Unlock()
+	}()
+
+	WarnCF("gateway", "disk almost full", map[string]any{"free_mb": 12, "path": "/data"})
+	Info("no component")
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2: %s", len(lines), buf.String())
+	}
+	var got struct {
+		Level     string         `json:"level"`
+		Component string         `json:"component"`
+		Msg       string         `json:"msg"`
+		Fields    map[string]any `json:"fields"`
+	}
+	if err := json.Unmarshal(lines[0], &got); err != nil {
+		t.Fatalf("line is not valid JSON: %v\n%s", err, lines[0])
+	}
+	if got.Level != "warn" || got.Component != "gateway" || got.Msg != "disk almost full" {
+		t.Errorf("line = %+v", got)
+	}
+	if got.Fields["free_mb"] != float64(12) || got.Fields["path"] != "/data" {
+		t.Errorf("fields = %v", got.Fields)
+	}
+
+	var plain map[string]any
+	if err := json.Unmarshal(lines[1], &plain); err != nil {
+		t.Fatalf("line is not valid JSON: %v\n%s", err, lines[1])
+	}
+	if plain["msg"] != "no component" {
+		t.Errorf("line = %v", plain)
+	}
+	if _, ok := plain["component"]; ok {
+		t.Errorf("line without a component has one: %v", plain)
+	}
+}
+
+func TestSetFormatFromString(t *testing.T) {
+	mu.RLock()
+	prevLogger := logger
+	mu.RUnlock()
+	defer func() {
+		mu.Lock()
+		logger, consoleFormat = prevLogger, FormatText
+		mu.Unlock()
+	}()
+
+	SetConsoleLevel(WARN)
+	SetFormatFromString("JSON")
+	mu.RLock()
+	format, level := consoleFormat, logger.GetLevel()
+	mu.RUnlock()
+	if format != FormatJSON {
+		t.Errorf("format = %v, want FormatJSON", format)
+	}
+	if level != WARN {
+		t.Errorf("console level = %v, want WARN to survive the format switch", level)
+	}
+
+	SetFormatFromString("yaml")
+	if consoleFormat != FormatJSON {
+		t.Error("an unknown format must keep the current one")
+	}
+	SetFormatFromString("text")
+	if consoleFormat != FormatText {
+		t.Error("SetFormatFromString(text) did not switch back")
+	}
+}
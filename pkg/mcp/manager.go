@@ -9,9 +9,11 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 
@@ -97,26 +99,127 @@ func loadEnvFile(path string) (map[string]string, error) {
 	return envVars, nil
 }
 
+// expandServerEnv resolves $VAR and ${VAR} references in a server's Command,
+// Args and Env values from the host environment, so secrets need not be
+// written into the config file. Undefined variables expand to "" unless
+// cfg.StrictEnv is set, in which case they are reported as an error.
+func expandServerEnv(cfg config.MCPServerConfig) (config.MCPServerConfig, error) {
+	missing := make(map[string]bool)
+	expand := func(s string) string {
+		return os.Expand(s, func(key string) string {
+			value, ok := os.LookupEnv(key)
+			if !ok {
+				missing[key] = true
+			}
+			return value
+		})
+	}
+
+	cfg.Command = expand(cfg.Command)
+	if len(cfg.Args) > 0 {
+		args := make([]string, len(cfg.Args))
+		for i, arg := range cfg.Args {
+			args[i] = expand(arg)
+		}
+		cfg.Args = args
+	}
+	if len(cfg.Env) > 0 {
+		env := make(map[string]string, len(cfg.Env))
+		for k, v := range cfg.Env {
+			env[k] = expand(v)
+		}
+		cfg.Env = env
+	}
+
+	if cfg.StrictEnv && len(missing) > 0 {
+		names := make([]string, 0, len(missing))
+		for name := range missing {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return cfg, fmt.Errorf("undefined environment variable(s): %s", strings.Join(names, ", "))
+	}
+	return cfg, nil
+}
+
+// buildStdioEnv returns the environment for a stdio server subprocess. It
+// starts from the host environment so PATH, HOME and friends are inherited
+// (npx-based servers fail without them), then applies EnvFile entries and
+// finally cfg.Env, each overriding the previous layer.
+func buildStdioEnv(name string, cfg config.MCPServerConfig) ([]string, error) {
+	envMap := make(map[string]string)
+
+	// Start with parent process environment
+	for _, e := range os.Environ() {
+		if idx := strings.Index(e, "="); idx > 0 {
+			envMap[e[:idx]] = e[idx+1:]
+		}
+	}
+
+	// Load environment variables from file if specified
+	if cfg.EnvFile != "" {
+		envVars, err := loadEnvFile(cfg.EnvFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load env file %s: %w", cfg.EnvFile, err)
+		}
+		for k, v := range envVars {
+			envMap[k] = v
+		}
+		logger.DebugCF("mcp", "Loaded environment variables from file",
+			map[string]any{
+				"server":    name,
+				"envFile":   cfg.EnvFile,
+				"var_count": len(envVars),
+			})
+	}
+
+	// Environment variables from config override those from file
+	for k, v := range cfg.Env {
+		envMap[k] = v
+	}
+
+	env := make([]string, 0, len(envMap))
+	for k, v := range envMap {
+		env = append(env, fmt.Sprintf("%s=%s", k, v))
+	}
+	return env, nil
+}
+
 // ServerConnection represents a connection to an MCP server
 type ServerConnection struct {
-	Name    string
-	Client  *mcp.Client
-	Session *mcp.ClientSession
-	Tools   []*mcp.Tool
+	Name        string
+	Description string
+	Client      *mcp.Client
+	Session     *mcp.ClientSession
+	Tools       []*mcp.Tool
+
+	cmd *exec.Cmd // subprocess for stdio servers, nil otherwise
+}
+
+// ServerSummary describes a configured server for status displays.
+type ServerSummary struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Tools       []string `json:"tools"`
+	ToolCount   int      `json:"tool_count"`
+	Connected   bool     `json:"connected"`
+	LastError   string   `json:"last_error,omitempty"`
 }
 
 // Manager manages multiple MCP server connections
 type Manager struct {
-	servers map[string]*ServerConnection
-	mu      sync.RWMutex
-	closed  atomic.Bool    // changed from bool to atomic.Bool to avoid TOCTOU race
-	wg      sync.WaitGroup // tracks in-flight CallTool calls
+	servers    map[string]*ServerConnection
+	lastErrors map[string]string // most recent connection error per server
+	mu         sync.RWMutex
+	closed     atomic.Bool    // changed from bool to atomic.Bool to avoid TOCTOU race
+	wg         sync.WaitGroup // tracks in-flight CallTool calls
 }
 
 // NewManager creates a new MCP manager
 func NewManager() *Manager {
 	return &Manager{
-		servers: make(map[string]*ServerConnection),
+		servers:    make(map[string]*ServerConnection),
+		lastErrors: make(map[string]string),
 	}
 }
 
@@ -236,12 +339,36 @@ func (m *Manager) LoadFromMCPConfig(
 	return nil
 }
 
-// ConnectServer connects to a single MCP server
+// ConnectServer connects to a single MCP server. A failure is remembered
+// and reported by GetServerSummary until the server connects successfully.
 func (m *Manager) ConnectServer(
 	ctx context.Context,
 	name string,
 	cfg config.MCPServerConfig,
 ) error {
+	err := m.connectServer(ctx, name, cfg)
+
+	m.mu.Lock()
+	if err != nil {
+		m.lastErrors[name] = err.Error()
+	} else {
+		delete(m.lastErrors, name)
+	}
+	m.mu.Unlock()
+
+	return err
+}
+
+func (m *Manager) connectServer(
+	ctx context.Context,
+	name string,
+	cfg config.MCPServerConfig,
+) error {
+	cfg, err := expandServerEnv(cfg)
+	if err != nil {
+		return err
+	}
+
 	logger.InfoCF("mcp", "Connecting to MCP server",
 		map[string]any{
 			"server":     name,
@@ -249,15 +376,10 @@ func (m *Manager) ConnectServer(
 			"args_count": len(cfg.Args),
 		})
 
-	// Create client
-	client := mcp.NewClient(&mcp.Implementation{
-		Name:    "picoclaw",
-		Version: "1.0.0",
-	}, nil)
-
 	// Create transport based on configuration
 	// Auto-detect transport type if not explicitly specified
 	var transport mcp.Transport
+	var cmd *exec.Cmd
 	transportType := cfg.Type
 
 	// Auto-detect: if URL is provided, use SSE; if command is provided, use stdio
@@ -313,45 +435,15 @@ func (m *Manager) ConnectServer(
 				"command": cfg.Command,
 			})
 		// Create command with context
-		cmd := exec.CommandContext(ctx, cfg.Command, cfg.Args...)
-
-		// Build environment variables with proper override semantics
-		// Use a map to ensure config variables override file variables
-		envMap := make(map[string]string)
-
-		// Start with parent process environment
-		for _, e := range cmd.Environ() {
-			if idx := strings.Index(e, "="); idx > 0 {
-				envMap[e[:idx]] = e[idx+1:]
-			}
-		}
-
-		// Load environment variables from file if specified
-		if cfg.EnvFile != "" {
-			envVars, err := loadEnvFile(cfg.EnvFile)
-			if err != nil {
-				return fmt.Errorf("failed to load env file %s: %w", cfg.EnvFile, err)
-			}
-			for k, v := range envVars {
-				envMap[k] = v
-			}
-			logger.DebugCF("mcp", "Loaded environment variables from file",
-				map[string]any{
-					"server":    name,
-					"envFile":   cfg.EnvFile,
-					"var_count": len(envVars),
-				})
-		}
-
-		// Environment variables from config override those from file
-		for k, v := range cfg.Env {
-			envMap[k] = v
-		}
-
-		// Convert map to slice
-		env := make([]string, 0, len(envMap))
-		for k, v := range envMap {
-			env = append(env, fmt.Sprintf("%s=%s", k, v))
+		cmd = exec.CommandContext(ctx, cfg.Command, cfg.Args...)
+		// If ctx is canceled, ask the server to stop before the runtime
+		// escalates to a kill after the grace period.
+		cmd.Cancel = func() error { return terminateProcess(cmd.Process) }
+		cmd.WaitDelay = disconnectGracePeriod
+
+		env, err := buildStdioEnv(name, cfg)
+		if err != nil {
+			return err
 		}
 		cmd.Env = env
 
@@ -363,6 +455,34 @@ func (m *Manager) ConnectServer(
 		)
 	}
 
+	return m.connectTransport(ctx, name, transport, cmd)
+}
+
+// connectTransport opens a session over transport, lists its tools and
+// registers the connection under name. cmd is the server subprocess for
+// stdio transports, or nil.
+func (m *Manager) connectTransport(ctx context.Context, name string, transport mcp.Transport, cmd *exec.Cmd) error {
+	client := mcp.NewClient(&mcp.Implementation{
+		Name:    "picoclaw",
+		Version: "1.0.0",
+	}, &mcp.ClientOptions{
+		ToolListChangedHandler: func(context.Context, *mcp.ToolListChangedRequest) {
+			// Reload outside the notification handler so the tools/list
+			// round trip doesn't block the session's message loop.
+			go func() {
+				ctx, cancel := context.WithTimeout(context.Background(), toolReloadTimeout)
+				defer cancel()
+				if err := m.ReloadTools(ctx, name); err != nil {
+					logger.WarnCF("mcp", "Failed to reload tools after list change",
+						map[string]any{
+							"server": name,
+							"error":  err.Error(),
+						})
+				}
+			}()
+		},
+	})
+
 	// Connect to server
 	session, err := client.Connect(ctx, transport, nil)
 	if err != nil {
@@ -379,41 +499,163 @@ func (m *Manager) ConnectServer(
 			"protocol":      initResult.ProtocolVersion,
 		})
 
-	// List available tools if supported
+	tools := listTools(ctx, name, session)
+
+	// Store connection
+	m.mu.Lock()
+	m.servers[name] = &ServerConnection{
+		Name:        name,
+		Description: serverDescription(initResult),
+		Client:      client,
+		Session:     session,
+		Tools:       tools,
+		cmd:         cmd,
+	}
+	m.mu.Unlock()
+
+	return nil
+}
+
+// toolReloadTimeout bounds a tools/list refresh triggered by a server's
+// list_changed notification.
+const toolReloadTimeout = 30 * time.Second
+
+// listTools fetches a session's tools, sorted by name. Servers that don't
+// advertise the tools capability have none.
+func listTools(ctx context.Context, name string, session *mcp.ClientSession) []*mcp.Tool {
+	initResult := session.InitializeResult()
+	if initResult == nil || initResult.Capabilities == nil || initResult.Capabilities.Tools == nil {
+		return nil
+	}
+
 	var tools []*mcp.Tool
-	if initResult.Capabilities.Tools != nil {
-		for tool, err := range session.Tools(ctx, nil) {
-			if err != nil {
-				logger.WarnCF("mcp", "Error listing tool",
-					map[string]any{
-						"server": name,
-						"error":  err.Error(),
-					})
-				continue
-			}
-			tools = append(tools, tool)
+	for tool, err := range session.Tools(ctx, nil) {
+		if err != nil {
+			logger.WarnCF("mcp", "Error listing tool",
+				map[string]any{
+					"server": name,
+					"error":  err.Error(),
+				})
+			continue
 		}
+		tools = append(tools, tool)
+	}
 
-		logger.InfoCF("mcp", "Listed tools from MCP server",
-			map[string]any{
-				"server":    name,
-				"toolCount": len(tools),
-			})
+	logger.InfoCF("mcp", "Listed tools from MCP server",
+		map[string]any{
+			"server":    name,
+			"toolCount": len(tools),
+		})
+
+	sortTools(tools)
+	return tools
+}
+
+// ReloadTools re-issues tools/list for a connected server and replaces its
+// tool list. Use it when a server's tools change at runtime; servers that
+// send notifications/tools/list_changed are reloaded automatically.
+func (m *Manager) ReloadTools(ctx context.Context, serverName string) error {
+	if m.closed.Load() {
+		return fmt.Errorf("manager is closed")
+	}
+
+	conn, ok := m.GetServer(serverName)
+	if !ok {
+		return fmt.Errorf("server %s not found", serverName)
+	}
+
+	tools := listTools(ctx, serverName, conn.Session)
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("failed to reload tools: %w", err)
 	}
 
-	// Store connection
 	m.mu.Lock()
-	m.servers[name] = &ServerConnection{
-		Name:    name,
-		Client:  client,
-		Session: session,
-		Tools:   tools,
+	defer m.mu.Unlock()
+	// Swap in a copy rather than mutating conn, which callers of
+	// GetServers may be reading without the lock.
+	if current, ok := m.servers[serverName]; ok {
+		updated := *current
+		updated.Tools = tools
+		m.servers[serverName] = &updated
 	}
-	m.mu.Unlock()
 
 	return nil
 }
 
+// sortTools orders tools by name so listings are stable regardless of the
+// order a server reports them in.
+func sortTools(tools []*mcp.Tool) {
+	sort.SliceStable(tools, func(i, j int) bool {
+		return tools[i].Name < tools[j].Name
+	})
+}
+
+// serverDescription summarizes the implementation a server reported during
+// initialization, e.g. "filesystem 1.2.0".
+func serverDescription(initResult *mcp.InitializeResult) string {
+	if initResult == nil || initResult.ServerInfo == nil {
+		return ""
+	}
+	info := initResult.ServerInfo
+	name := info.Title
+	if name == "" {
+		name = info.Name
+	}
+	return strings.TrimSpace(name + " " + info.Version)
+}
+
+// GetServerSummary returns one entry per known server, sorted by name:
+// connected servers with their tools, and servers whose last connection
+// attempt failed with the error.
+func (m *Manager) GetServerSummary() []ServerSummary {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	summaries := make([]ServerSummary, 0, len(m.servers)+len(m.lastErrors))
+	for name, conn := range m.servers {
+		tools := make([]string, len(conn.Tools))
+		for i, tool := range conn.Tools {
+			tools[i] = tool.Name
+		}
+		sort.Strings(tools)
+		summaries = append(summaries, ServerSummary{
+			Name:        name,
+			Description: conn.Description,
+			Tools:       tools,
+			ToolCount:   len(tools),
+			Connected:   true,
+		})
+	}
+	for name, lastErr := range m.lastErrors {
+		if _, connected := m.servers[name]; connected {
+			continue
+		}
+		summaries = append(summaries, ServerSummary{
+			Name:      name,
+			Tools:     []string{},
+			LastError: lastErr,
+		})
+	}
+
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].Name < summaries[j].Name
+	})
+	return summaries
+}
+
+// ServerNames returns the names of all connected servers, sorted.
+func (m *Manager) ServerNames() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	names := make([]string, 0, len(m.servers))
+	for name := range m.servers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 // GetServers returns all connected servers
 func (m *Manager) GetServers() map[string]*ServerConnection {
 	m.mu.RLock()
@@ -476,8 +718,52 @@ func (m *Manager) CallTool(
 	return result, nil
 }
 
-// Close closes all server connections
+// disconnectGracePeriod is how long a stdio server has to exit after
+// SIGTERM before it is killed.
+const disconnectGracePeriod = 3 * time.Second
+
+// DisconnectServer closes a single server connection and removes it. Stdio
+// servers are sent SIGTERM and killed only if they are still running after
+// disconnectGracePeriod.
+func (m *Manager) DisconnectServer(name string) error {
+	m.mu.Lock()
+	conn, ok := m.servers[name]
+	delete(m.servers, name)
+	m.mu.Unlock()
+
+	if !ok {
+		return fmt.Errorf("server %s not found", name)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), disconnectGracePeriod)
+	defer cancel()
+	if err := closeConnection(ctx, conn); err != nil {
+		return fmt.Errorf("failed to disconnect server %s: %w", name, err)
+	}
+
+	logger.InfoCF("mcp", "Disconnected MCP server",
+		map[string]any{
+			"server": name,
+		})
+	return nil
+}
+
+// defaultCloseTimeout is how long Close gives server subprocesses to exit
+// after SIGTERM before killing them.
+const defaultCloseTimeout = 5 * time.Second
+
+// Close closes all server connections, allowing defaultCloseTimeout for
+// subprocesses to exit.
 func (m *Manager) Close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultCloseTimeout)
+	defer cancel()
+	return m.CloseAll(ctx)
+}
+
+// CloseAll disconnects every server. Stdio subprocesses are sent SIGTERM
+// and given until ctx is done to exit on their own; any still running then
+// are killed. In-flight CallTool calls are waited for, also bounded by ctx.
+func (m *Manager) CloseAll(ctx context.Context) error {
 	// Use Swap to atomically set closed=true and get the previous value
 	// This prevents TOCTOU race with CallTool's closed check
 	if m.closed.Swap(true) {
@@ -486,29 +772,49 @@ func (m *Manager) Close() error {
 
 	// Wait for all in-flight CallTool calls to finish before closing sessions
 	// After closed=true is set, no new CallTool can start (they check closed first)
-	m.wg.Wait()
+	inFlight := make(chan struct{})
+	go func() {
+		m.wg.Wait()
+		close(inFlight)
+	}()
+	select {
+	case <-inFlight:
+	case <-ctx.Done():
+		logger.WarnCF("mcp", "Timed out waiting for in-flight MCP tool calls", nil)
+	}
 
 	m.mu.Lock()
-	defer m.mu.Unlock()
+	servers := m.servers
+	m.servers = make(map[string]*ServerConnection)
+	m.mu.Unlock()
 
 	logger.InfoCF("mcp", "Closing all MCP server connections",
 		map[string]any{
-			"count": len(m.servers),
+			"count": len(servers),
 		})
 
-	var errs []error
-	for name, conn := range m.servers {
-		if err := conn.Session.Close(); err != nil {
-			logger.ErrorCF("mcp", "Failed to close server connection",
-				map[string]any{
-					"server": name,
-					"error":  err.Error(),
-				})
-			errs = append(errs, fmt.Errorf("server %s: %w", name, err))
-		}
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+	for name, conn := range servers {
+		wg.Add(1)
+		go func(name string, conn *ServerConnection) {
+			defer wg.Done()
+			if err := closeConnection(ctx, conn); err != nil {
+				logger.ErrorCF("mcp", "Failed to close server connection",
+					map[string]any{
+						"server": name,
+						"error":  err.Error(),
+					})
+				mu.Lock()
+				errs = append(errs, fmt.Errorf("server %s: %w", name, err))
+				mu.Unlock()
+			}
+		}(name, conn)
 	}
-
-	m.servers = make(map[string]*ServerConnection)
+	wg.Wait()
 
 	if len(errs) > 0 {
 		return fmt.Errorf("failed to close %d server(s): %w", len(errs), errors.Join(errs...))
@@ -517,7 +823,57 @@ func (m *Manager) Close() error {
 	return nil
 }
 
-// GetAllTools returns all tools from all connected servers
+// closeConnection closes conn's session. For stdio servers it also sends
+// SIGTERM and, if the process is still running when ctx is done, kills it.
+func closeConnection(ctx context.Context, conn *ServerConnection) error {
+	if conn.Session == nil {
+		return nil
+	}
+
+	closed := make(chan error, 1)
+	go func() {
+		// For stdio this closes the server's stdin and waits for it to exit.
+		closed <- conn.Session.Close()
+	}()
+
+	if conn.cmd == nil || conn.cmd.Process == nil {
+		select {
+		case err := <-closed:
+			return err
+		case <-ctx.Done():
+			return fmt.Errorf("timed out closing session: %w", ctx.Err())
+		}
+	}
+
+	// An error here usually means the process has already exited.
+	_ = terminateProcess(conn.cmd.Process)
+
+	select {
+	case err := <-closed:
+		// A non-zero exit status (including death by our SIGTERM) still
+		// means the server is gone, which is all shutdown needs.
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return nil
+		}
+		return err
+	case <-ctx.Done():
+	}
+
+	logger.WarnCF("mcp", "MCP server did not exit after SIGTERM; killing",
+		map[string]any{
+			"server": conn.Name,
+			"pid":    conn.cmd.Process.Pid,
+		})
+	if err := conn.cmd.Process.Kill(); err != nil && !errors.Is(err, os.ErrProcessDone) {
+		return fmt.Errorf("failed to kill server process: %w", err)
+	}
+	<-closed
+	return fmt.Errorf("server process killed after %w", ctx.Err())
+}
+
+// GetAllTools returns all tools from all connected servers, each server's
+// tools sorted by name. Use ServerNames to iterate servers in a stable order.
 func (m *Manager) GetAllTools() map[string][]*mcp.Tool {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
@@ -525,7 +881,9 @@ func (m *Manager) GetAllTools() map[string][]*mcp.Tool {
 	result := make(map[string][]*mcp.Tool)
 	for name, conn := range m.servers {
 		if len(conn.Tools) > 0 {
-			result[name] = conn.Tools
+			tools := append([]*mcp.Tool(nil), conn.Tools...)
+			sortTools(tools)
+			result[name] = tools
 		}
 	}
 	return result
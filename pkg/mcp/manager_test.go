@@ -3,9 +3,14 @@ package mcp
 import (
 	"context"
 	"os"
+	"os/exec"
+	"os/signal"
 	"path/filepath"
+	"runtime"
 	"strings"
+	"syscall"
 	"testing"
+	"time"
 
 	sdkmcp "github.com/modelcontextprotocol/go-sdk/mcp"
 
@@ -306,3 +311,350 @@ func TestClose_IdempotentOnEmptyManager(t *testing.T) {
 		t.Fatalf("second close should be idempotent, got: %v", err)
 	}
 }
+
+func TestGetAllTools_StableOrdering(t *testing.T) {
+	mgr := NewManager()
+	mgr.servers["zeta"] = &ServerConnection{Name: "zeta", Tools: []*sdkmcp.Tool{{Name: "b"}, {Name: "a"}}}
+	mgr.servers["alpha"] = &ServerConnection{Name: "alpha", Tools: []*sdkmcp.Tool{{Name: "y"}, {Name: "x"}}}
+	mgr.servers["mid"] = &ServerConnection{Name: "mid", Tools: []*sdkmcp.Tool{{Name: "only"}}}
+
+	for i := 0; i < 20; i++ {
+		names := mgr.ServerNames()
+		if strings.Join(names, ",") != "alpha,mid,zeta" {
+			t.Fatalf("call %d: ServerNames() = %v, want sorted", i, names)
+		}
+
+		all := mgr.GetAllTools()
+		for server, want := range map[string]string{"alpha": "x,y", "mid": "only", "zeta": "a,b"} {
+			var got []string
+			for _, tool := range all[server] {
+				got = append(got, tool.Name)
+			}
+			if strings.Join(got, ",") != want {
+				t.Fatalf("call %d: tools for %s = %v, want %s", i, server, got, want)
+			}
+		}
+	}
+
+	if mgr.servers["zeta"].Tools[0].Name != "b" {
+		t.Fatal("GetAllTools should not reorder the manager's own slices")
+	}
+}
+
+// newFakeServer starts an in-memory MCP server with the named tools and
+// connects mgr to it as serverName.
+func newFakeServer(t *testing.T, mgr *Manager, serverName string, toolNames ...string) *sdkmcp.Server {
+	t.Helper()
+
+	server := sdkmcp.NewServer(&sdkmcp.Implementation{Name: "fake", Version: "1.0.0"}, nil)
+	for _, name := range toolNames {
+		addFakeTool(server, name)
+	}
+
+	serverTransport, clientTransport := sdkmcp.NewInMemoryTransports()
+	ctx := context.Background()
+	if _, err := server.Connect(ctx, serverTransport, nil); err != nil {
+		t.Fatalf("server connect: %v", err)
+	}
+	if err := mgr.connectTransport(ctx, serverName, clientTransport, nil); err != nil {
+		t.Fatalf("client connect: %v", err)
+	}
+	t.Cleanup(func() { mgr.Close() })
+	return server
+}
+
+func addFakeTool(server *sdkmcp.Server, name string) {
+	server.AddTool(&sdkmcp.Tool{
+		Name:        name,
+		InputSchema: map[string]any{"type": "object"},
+	}, func(context.Context, *sdkmcp.CallToolRequest) (*sdkmcp.CallToolResult, error) {
+		return &sdkmcp.CallToolResult{}, nil
+	})
+}
+
+func toolNames(conn *ServerConnection) string {
+	names := make([]string, len(conn.Tools))
+	for i, tool := range conn.Tools {
+		names[i] = tool.Name
+	}
+	return strings.Join(names, ",")
+}
+
+func TestReloadTools_PicksUpChangedToolList(t *testing.T) {
+	mgr := NewManager()
+	server := newFakeServer(t, mgr, "fake", "alpha")
+
+	conn, _ := mgr.GetServer("fake")
+	if got := toolNames(conn); got != "alpha" {
+		t.Fatalf("initial tools = %q, want alpha", got)
+	}
+
+	server.RemoveTools("alpha")
+	addFakeTool(server, "gamma")
+	addFakeTool(server, "beta")
+
+	if err := mgr.ReloadTools(context.Background(), "fake"); err != nil {
+		t.Fatalf("ReloadTools: %v", err)
+	}
+	conn, _ = mgr.GetServer("fake")
+	if got := toolNames(conn); got != "beta,gamma" {
+		t.Fatalf("reloaded tools = %q, want beta,gamma", got)
+	}
+}
+
+func TestReloadTools_OnListChangedNotification(t *testing.T) {
+	mgr := NewManager()
+	server := newFakeServer(t, mgr, "fake", "alpha")
+
+	addFakeTool(server, "beta")
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		conn, _ := mgr.GetServer("fake")
+		if toolNames(conn) == "alpha,beta" {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("tools not reloaded after list_changed, got %q", toolNames(conn))
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestReloadTools_UnknownServer(t *testing.T) {
+	mgr := NewManager()
+	if err := mgr.ReloadTools(context.Background(), "missing"); err == nil {
+		t.Fatal("expected error for unknown server")
+	}
+}
+
+func TestExpandServerEnv(t *testing.T) {
+	t.Setenv("PICOCLAW_TEST_KEY", "secret")
+	t.Setenv("PICOCLAW_TEST_BIN", "/opt/bin")
+
+	cfg := config.MCPServerConfig{
+		Command: "$PICOCLAW_TEST_BIN/server",
+		Args:    []string{"--token=${PICOCLAW_TEST_KEY}", "plain"},
+		Env:     map[string]string{"API_KEY": "${PICOCLAW_TEST_KEY}", "STATIC": "value"},
+	}
+
+	got, err := expandServerEnv(cfg)
+	if err != nil {
+		t.Fatalf("expandServerEnv: %v", err)
+	}
+	if got.Command != "/opt/bin/server" {
+		t.Errorf("Command = %q", got.Command)
+	}
+	if strings.Join(got.Args, " ") != "--token=secret plain" {
+		t.Errorf("Args = %v", got.Args)
+	}
+	if got.Env["API_KEY"] != "secret" || got.Env["STATIC"] != "value" {
+		t.Errorf("Env = %v", got.Env)
+	}
+	if cfg.Env["API_KEY"] != "${PICOCLAW_TEST_KEY}" || cfg.Args[0] != "--token=${PICOCLAW_TEST_KEY}" {
+		t.Error("expandServerEnv must not modify the caller's config")
+	}
+}
+
+func TestExpandServerEnv_UndefinedVariable(t *testing.T) {
+	cfg := config.MCPServerConfig{
+		Command: "server",
+		Env:     map[string]string{"API_KEY": "${PICOCLAW_TEST_UNDEFINED}"},
+	}
+
+	got, err := expandServerEnv(cfg)
+	if err != nil {
+		t.Fatalf("non-strict expansion should not fail: %v", err)
+	}
+	if got.Env["API_KEY"] != "" {
+		t.Errorf("undefined variable should expand to empty, got %q", got.Env["API_KEY"])
+	}
+
+	cfg.StrictEnv = true
+	_, err = expandServerEnv(cfg)
+	if err == nil || !strings.Contains(err.Error(), "PICOCLAW_TEST_UNDEFINED") {
+		t.Fatalf("expected strict error naming the variable, got %v", err)
+	}
+
+	err = NewManager().ConnectServer(context.Background(), "strict", cfg)
+	if err == nil || !strings.Contains(err.Error(), "PICOCLAW_TEST_UNDEFINED") {
+		t.Fatalf("ConnectServer should surface the strict error, got %v", err)
+	}
+}
+
+func TestBuildStdioEnv_InheritsHostEnvironment(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh to inspect the child environment")
+	}
+	t.Setenv("PICOCLAW_TEST_OVERRIDE", "from_host")
+
+	env, err := buildStdioEnv("test", config.MCPServerConfig{
+		Env: map[string]string{"PICOCLAW_TEST_OVERRIDE": "from_config"},
+	})
+	if err != nil {
+		t.Fatalf("buildStdioEnv: %v", err)
+	}
+
+	cmd := exec.Command("sh", "-c", `printf '%s|%s' "$PATH" "$PICOCLAW_TEST_OVERRIDE"`)
+	cmd.Env = env
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("running child: %v", err)
+	}
+
+	path, override, _ := strings.Cut(string(out), "|")
+	if path == "" || path != os.Getenv("PATH") {
+		t.Errorf("child PATH = %q, want inherited %q", path, os.Getenv("PATH"))
+	}
+	if override != "from_config" {
+		t.Errorf("child PICOCLAW_TEST_OVERRIDE = %q, want from_config", override)
+	}
+}
+
+// TestHelperProcess is not a real test: it is re-executed as a stdio MCP
+// server subprocess by the shutdown tests. With PICOCLAW_MCP_HELPER=term it
+// exits cleanly on SIGTERM after writing PICOCLAW_MCP_MARKER; with
+// PICOCLAW_MCP_HELPER=stubborn it ignores SIGTERM.
+func TestHelperProcess(t *testing.T) {
+	mode := os.Getenv("PICOCLAW_MCP_HELPER")
+	if mode == "" {
+		return
+	}
+
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGTERM)
+
+	server := sdkmcp.NewServer(&sdkmcp.Implementation{Name: "helper", Version: "1.0.0"}, nil)
+	addFakeTool(server, "ping")
+	go server.Run(context.Background(), &sdkmcp.StdioTransport{})
+
+	for range sigs {
+		if mode == "term" {
+			os.WriteFile(os.Getenv("PICOCLAW_MCP_MARKER"), []byte("terminated"), 0o644)
+			os.Exit(0)
+		}
+	}
+}
+
+func connectHelper(t *testing.T, mgr *Manager, name, mode, marker string) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("SIGTERM handling is Unix-only")
+	}
+
+	err := mgr.ConnectServer(context.Background(), name, config.MCPServerConfig{
+		Command: os.Args[0],
+		Args:    []string{"-test.run=^TestHelperProcess$"},
+		Env: map[string]string{
+			"PICOCLAW_MCP_HELPER": mode,
+			"PICOCLAW_MCP_MARKER": marker,
+		},
+	})
+	if err != nil {
+		t.Fatalf("ConnectServer: %v", err)
+	}
+}
+
+func TestCloseAll_ServerExitsOnSIGTERM(t *testing.T) {
+	mgr := NewManager()
+	marker := filepath.Join(t.TempDir(), "terminated")
+	connectHelper(t, mgr, "helper", "term", marker)
+	conn, _ := mgr.GetServer("helper")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	start := time.Now()
+	if err := mgr.CloseAll(ctx); err != nil {
+		t.Fatalf("CloseAll: %v", err)
+	}
+
+	if _, err := os.Stat(marker); err != nil {
+		t.Fatalf("server did not observe SIGTERM: %v", err)
+	}
+	if !conn.cmd.ProcessState.Success() {
+		t.Errorf("expected clean exit, got %v", conn.cmd.ProcessState)
+	}
+	if elapsed := time.Since(start); elapsed > 3*time.Second {
+		t.Errorf("CloseAll took %v; server should exit promptly on SIGTERM", elapsed)
+	}
+	if len(mgr.GetServers()) != 0 {
+		t.Error("expected no servers after CloseAll")
+	}
+}
+
+func TestCloseAll_KillsServerIgnoringSIGTERM(t *testing.T) {
+	mgr := NewManager()
+	marker := filepath.Join(t.TempDir(), "terminated")
+	connectHelper(t, mgr, "stubborn", "stubborn", marker)
+	conn, _ := mgr.GetServer("stubborn")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+	err := mgr.CloseAll(ctx)
+	if err == nil || !strings.Contains(err.Error(), "killed") {
+		t.Fatalf("expected kill error, got %v", err)
+	}
+	if conn.cmd.ProcessState == nil || conn.cmd.ProcessState.Success() {
+		t.Errorf("expected process to be killed, got %v", conn.cmd.ProcessState)
+	}
+}
+
+func TestDisconnectServer_CooperativeServerExitsOnSIGTERM(t *testing.T) {
+	mgr := NewManager()
+	marker := filepath.Join(t.TempDir(), "terminated")
+	connectHelper(t, mgr, "helper", "term", marker)
+	conn, _ := mgr.GetServer("helper")
+
+	start := time.Now()
+	if err := mgr.DisconnectServer("helper"); err != nil {
+		t.Fatalf("DisconnectServer: %v", err)
+	}
+
+	if _, err := os.Stat(marker); err != nil {
+		t.Fatalf("server did not receive SIGTERM: %v", err)
+	}
+	if !conn.cmd.ProcessState.Success() {
+		t.Errorf("expected the server to exit on its own, got %v", conn.cmd.ProcessState)
+	}
+	if elapsed := time.Since(start); elapsed >= disconnectGracePeriod {
+		t.Errorf("DisconnectServer took %v; server should exit before the kill deadline", elapsed)
+	}
+	if _, ok := mgr.GetServer("helper"); ok {
+		t.Error("expected server to be removed")
+	}
+	if err := mgr.DisconnectServer("helper"); err == nil {
+		t.Error("expected error disconnecting an unknown server")
+	}
+}
+
+func TestGetServerSummary_ConnectedAndFailed(t *testing.T) {
+	mgr := NewManager()
+	newFakeServer(t, mgr, "good", "beta", "alpha")
+
+	err := mgr.ConnectServer(context.Background(), "broken", config.MCPServerConfig{Type: "stdio"})
+	if err == nil {
+		t.Fatal("expected ConnectServer to fail without a command")
+	}
+
+	summaries := mgr.GetServerSummary()
+	if len(summaries) != 2 {
+		t.Fatalf("expected 2 summaries, got %+v", summaries)
+	}
+
+	broken, good := summaries[0], summaries[1]
+	if broken.Name != "broken" || broken.Connected || broken.LastError == "" || broken.ToolCount != 0 {
+		t.Errorf("unexpected failed summary: %+v", broken)
+	}
+	if !strings.Contains(broken.LastError, "command is required") {
+		t.Errorf("LastError = %q, want the connection error", broken.LastError)
+	}
+	if good.Name != "good" || !good.Connected || good.LastError != "" {
+		t.Errorf("unexpected connected summary: %+v", good)
+	}
+	if good.ToolCount != 2 || strings.Join(good.Tools, ",") != "alpha,beta" {
+		t.Errorf("unexpected tools: %+v", good)
+	}
+	if good.Description != "fake 1.0.0" {
+		t.Errorf("Description = %q, want %q", good.Description, "fake 1.0.0")
+	}
+}
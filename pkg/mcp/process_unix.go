@@ -0,0 +1,13 @@
+//go:build !windows
+
+package mcp
+
+import (
+	"os"
+	"syscall"
+)
+
+// terminateProcess asks a server subprocess to shut down gracefully.
+func terminateProcess(p *os.Process) error {
+	return p.Signal(syscall.SIGTERM)
+}
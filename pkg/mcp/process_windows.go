@@ -0,0 +1,13 @@
+//go:build windows
+
+package mcp
+
+import (
+	"os"
+)
+
+// terminateProcess asks a server subprocess to shut down. Windows has no
+// SIGTERM equivalent for console processes, so this kills immediately.
+func terminateProcess(p *os.Process) error {
+	return p.Kill()
+}
@@ -0,0 +1,80 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ToolHandler runs one tools/call with the call's arguments decoded into a
+// map. The result may mix text, image and embedded-resource content blocks;
+// they are sent to the client as returned. A returned error becomes a tool result with IsError set, unless it
+// wraps an InvalidParamsError, which is answered with a JSON-RPC Invalid
+// params error instead.
+type ToolHandler func(ctx context.Context, args map[string]any) (*mcp.CallToolResult, error)
+
+// InvalidParamsError reports tool arguments that do not match the tool's
+// input schema.
+type InvalidParamsError struct {
+	Err error
+}
+
+func (e *InvalidParamsError) Error() string { return e.Err.Error() }
+func (e *InvalidParamsError) Unwrap() error { return e.Err }
+
+// InvalidParams marks err as caused by bad tool arguments.
+func InvalidParams(err error) error {
+	return &InvalidParamsError{Err: err}
+}
+
+// NewServer returns an MCP server that identifies itself with name and
+// version. Serve it with Run and an mcp.StdioTransport.
+func NewServer(name, version string) *mcp.Server {
+	return mcp.NewServer(&mcp.Implementation{Name: name, Version: version}, nil)
+}
+
+// AddTool registers tool on s, adapting h to the SDK's raw tool handler.
+// Registering a name again replaces the earlier tool.
+func AddTool(s *mcp.Server, tool *mcp.Tool, h ToolHandler) {
+	s.AddTool(tool, func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args map[string]any
+		if len(req.Params.Arguments) > 0 {
+			if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+				return nil, invalidParamsError(fmt.Errorf("arguments must be a JSON object: %w", err))
+			}
+		}
+
+		result, err := h(ctx, args)
+		if err != nil {
+			var invalid *InvalidParamsError
+			if errors.As(err, &invalid) {
+				return nil, invalidParamsError(invalid.Err)
+			}
+			return errorResult(err.Error()), nil
+		}
+		if result == nil {
+			result = &mcp.CallToolResult{}
+		}
+		return result, nil
+	})
+}
+
+// TextResult is a successful tool result with a single text block.
+func TextResult(text string) *mcp.CallToolResult {
+	return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: text}}}
+}
+
+func errorResult(text string) *mcp.CallToolResult {
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: text}},
+		IsError: true,
+	}
+}
+
+func invalidParamsError(err error) error {
+	return &jsonrpc.Error{Code: jsonrpc.CodeInvalidParams, Message: fmt.Sprintf("Invalid params: %v", err)}
+}
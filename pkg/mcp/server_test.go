@@ -0,0 +1,152 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	sdkmcp "github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// connectEchoServer serves an echo tool over in-memory transports and
+// returns a client session connected to it.
+func connectEchoServer(t *testing.T) *sdkmcp.ClientSession {
+	t.Helper()
+	s := NewServer("test-server", "0.1.0")
+	AddTool(s, &sdkmcp.Tool{
+		Name:        "echo",
+		Description: "Echo the text back.",
+		InputSchema: map[string]any{
+			"type":       "object",
+			"properties": map[string]any{"text": map[string]any{"type": "string"}},
+			"required":   []string{"text"},
+		},
+	}, func(ctx context.Context, args map[string]any) (*sdkmcp.CallToolResult, error) {
+		text, ok := args["text"].(string)
+		if !ok {
+			return nil, InvalidParams(errors.New(`argument "text" must be a string`))
+		}
+		if text == "fail" {
+			return nil, errors.New("echo failed")
+		}
+		return TextResult(text), nil
+	})
+	AddTool(s, &sdkmcp.Tool{Name: "noop", InputSchema: map[string]any{"type": "object"}},
+		func(ctx context.Context, args map[string]any) (*sdkmcp.CallToolResult, error) {
+			return nil, nil
+		})
+	return connectServer(t, s)
+}
+
+// connectServer connects a client session to s over in-memory transports.
+func connectServer(t *testing.T, s *sdkmcp.Server) *sdkmcp.ClientSession {
+	t.Helper()
+	ctx := context.Background()
+	serverTransport, clientTransport := sdkmcp.NewInMemoryTransports()
+	if _, err := s.Connect(ctx, serverTransport, nil); err != nil {
+		t.Fatalf("server connect: %v", err)
+	}
+	client := sdkmcp.NewClient(&sdkmcp.Implementation{Name: "test-client", Version: "0.1.0"}, nil)
+	session, err := client.Connect(ctx, clientTransport, nil)
+	if err != nil {
+		t.Fatalf("client connect: %v", err)
+	}
+	t.Cleanup(func() { session.Close() })
+	return session
+}
+
+func TestServer_InitializeListCall(t *testing.T) {
+	session := connectEchoServer(t)
+	ctx := context.Background()
+
+	if info := session.InitializeResult().ServerInfo; info.Name != "test-server" {
+		t.Errorf("server info = %+v, want test-server", info)
+	}
+
+	list, err := session.ListTools(ctx, nil)
+	if err != nil {
+		t.Fatalf("ListTools: %v", err)
+	}
+	if len(list.Tools) != 2 {
+		t.Fatalf("tools = %+v, want echo and noop", list.Tools)
+	}
+
+	result, err := session.CallTool(ctx, &sdkmcp.CallToolParams{Name: "echo", Arguments: map[string]any{"text": "hello"}})
+	if err != nil {
+		t.Fatalf("CallTool: %v", err)
+	}
+	if text, ok := result.Content[0].(*sdkmcp.TextContent); result.IsError || !ok || text.Text != "hello" {
+		t.Errorf("tools/call result = %+v", result)
+	}
+}
+
+func TestServer_ToolErrors(t *testing.T) {
+	session := connectEchoServer(t)
+
+	tests := []struct {
+		name      string
+		tool      string
+		args      any
+		wantCode  int64 // 0 for a tool result
+		wantError bool
+	}{
+		{"handler error", "echo", map[string]any{"text": "fail"}, 0, true},
+		{"invalid params", "echo", map[string]any{"text": 7}, jsonrpc.CodeInvalidParams, false},
+		{"arguments not an object", "echo", "oops", jsonrpc.CodeInvalidParams, false},
+		{"unknown tool", "missing", nil, jsonrpc.CodeInvalidParams, false},
+		{"empty result", "noop", nil, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := session.CallTool(context.Background(), &sdkmcp.CallToolParams{Name: tt.tool, Arguments: tt.args})
+			if tt.wantCode != 0 {
+				var wire *jsonrpc.Error
+				if !errors.As(err, &wire) || wire.Code != tt.wantCode {
+					t.Fatalf("error = %v, want code %d", err, tt.wantCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected JSON-RPC error %v", err)
+			}
+			if result.IsError != tt.wantError {
+				t.Errorf("IsError = %v, want %v (%+v)", result.IsError, tt.wantError, result)
+			}
+		})
+	}
+}
+
+func TestServer_MixedContentResult(t *testing.T) {
+	png := []byte{0x89, 'P', 'N', 'G'}
+	s := NewServer("test-server", "0.1.0")
+	AddTool(s, &sdkmcp.Tool{Name: "snapshot", InputSchema: map[string]any{"type": "object"}},
+		func(ctx context.Context, args map[string]any) (*sdkmcp.CallToolResult, error) {
+			return &sdkmcp.CallToolResult{Content: []sdkmcp.Content{
+				&sdkmcp.TextContent{Text: "the dashboard"},
+				&sdkmcp.ImageContent{Data: png, MIMEType: "image/png"},
+				&sdkmcp.EmbeddedResource{Resource: &sdkmcp.ResourceContents{
+					URI: "file:///tmp/report.md", MIMEType: "text/markdown", Text: "# Report",
+				}},
+			}}, nil
+		})
+	session := connectServer(t, s)
+
+	result, err := session.CallTool(context.Background(), &sdkmcp.CallToolParams{Name: "snapshot"})
+	if err != nil {
+		t.Fatalf("CallTool: %v", err)
+	}
+	if result.IsError || len(result.Content) != 3 {
+		t.Fatalf("result = %+v, want three content blocks", result)
+	}
+	if text, ok := result.Content[0].(*sdkmcp.TextContent); !ok || text.Text != "the dashboard" {
+		t.Errorf("block 0 = %#v, want the text block", result.Content[0])
+	}
+	if img, ok := result.Content[1].(*sdkmcp.ImageContent); !ok || img.MIMEType != "image/png" || string(img.Data) != string(png) {
+		t.Errorf("block 1 = %#v, want the PNG image", result.Content[1])
+	}
+	res, ok := result.Content[2].(*sdkmcp.EmbeddedResource)
+	if !ok || res.Resource == nil || res.Resource.URI != "file:///tmp/report.md" || res.Resource.Text != "# Report" {
+		t.Errorf("block 2 = %#v, want the embedded report", result.Content[2])
+	}
+}
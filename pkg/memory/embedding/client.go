@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"math"
 	"net/http"
 	"strings"
 	"time"
@@ -22,6 +23,7 @@ type Client struct {
 	chunkSize int
 	keepAlive string
 	numCtx    int
+	normalize bool
 }
 
 func NewClient(cfg config.EmbeddingConfig) *Client {
@@ -51,17 +53,44 @@ func NewClient(cfg config.EmbeddingConfig) *Client {
 		chunkSize: cfg.ChunkSize,
 		keepAlive: cfg.KeepAlive,
 		numCtx:    cfg.NumCtx,
+		normalize: cfg.Normalize,
 		client: &http.Client{
 			Timeout: time.Duration(timeout) * time.Second,
 		},
 	}
 }
 
+// useOllamaNative reports whether requests should go to Ollama's native
+// /api/embeddings endpoint rather than its OpenAI-compatible /v1 shim.
+func (c *Client) useOllamaNative() bool {
+	return strings.EqualFold(c.provider, "ollama") && !strings.HasSuffix(c.apiBase, "/v1")
+}
+
+// Embed returns the embedding of text, scaled to unit length if the client
+// was configured with Normalize.
 func (c *Client) Embed(ctx context.Context, text string) ([]float32, error) {
+	vec, err := c.embed(ctx, text)
+	if err != nil || !c.normalize {
+		return vec, err
+	}
+	return normalize(vec), nil
+}
+
+func (c *Client) embed(ctx context.Context, text string) ([]float32, error) {
+	native := c.useOllamaNative()
+
+	endpoint := c.apiBase + "/embeddings"
 	reqBody := map[string]interface{}{
 		"model": c.model,
 		"input": text,
 	}
+	if native {
+		endpoint = c.apiBase + "/api/embeddings"
+		reqBody = map[string]interface{}{
+			"model":  c.model,
+			"prompt": text,
+		}
+	}
 
 	// Add Ollama-specific options if configured
 	if strings.Contains(strings.ToLower(c.apiBase), "localhost:11434") || strings.EqualFold(c.provider, "ollama") {
@@ -82,7 +111,7 @@ func (c *Client) Embed(ctx context.Context, text string) ([]float32, error) {
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "POST", c.apiBase+"/embeddings", bytes.NewReader(jsonData))
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(jsonData))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -107,6 +136,19 @@ func (c *Client) Embed(ctx context.Context, text string) ([]float32, error) {
 		return nil, fmt.Errorf("embedding API request failed: status=%d body=%s", resp.StatusCode, string(body))
 	}
 
+	if native {
+		var nativeResp struct {
+			Embedding []float32 `json:"embedding"`
+		}
+		if err := json.Unmarshal(body, &nativeResp); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+		}
+		if len(nativeResp.Embedding) == 0 {
+			return nil, fmt.Errorf("no embedding data returned")
+		}
+		return nativeResp.Embedding, nil
+	}
+
 	var apiResp struct {
 		Data []struct {
 			Embedding []float32 `json:"embedding"`
@@ -124,6 +166,23 @@ func (c *Client) Embed(ctx context.Context, text string) ([]float32, error) {
 	return apiResp.Data[0].Embedding, nil
 }
 
+// normalize scales vec in place to unit length. A zero vector is returned
+// unchanged.
+func normalize(vec []float32) []float32 {
+	var sum float64
+	for _, v := range vec {
+		sum += float64(v) * float64(v)
+	}
+	if sum == 0 {
+		return vec
+	}
+	norm := math.Sqrt(sum)
+	for i, v := range vec {
+		vec[i] = float32(float64(v) / norm)
+	}
+	return vec
+}
+
 func (c *Client) Dimension() int {
 	// Dimension often depends on the model.
 	// For text-embedding-3-small it is 1536.
@@ -0,0 +1,143 @@
+package embedding
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sipeed/picoclaw/pkg/config"
+)
+
+// fakeEmbeddingServer records the last request path and body and replies
+// with the given JSON response.
+func fakeEmbeddingServer(t *testing.T, response string, gotPath *string, gotBody *map[string]interface{}) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*gotPath = r.URL.Path
+		if err := json.NewDecoder(r.Body).Decode(gotBody); err != nil {
+			t.Errorf("failed to decode request body: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(response))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestEmbed_OpenAICompatible(t *testing.T) {
+	var path string
+	var body map[string]interface{}
+	srv := fakeEmbeddingServer(t, `{"data":[{"embedding":[0.1,0.2,0.3]}]}`, &path, &body)
+
+	c := NewClient(config.EmbeddingConfig{Provider: "openai", Model: "text-embedding-3-small", BaseURL: srv.URL})
+	vec, err := c.Embed(context.Background(), "hello")
+	if err != nil {
+		t.Fatalf("Embed: %v", err)
+	}
+
+	if path != "/embeddings" {
+		t.Errorf("expected POST /embeddings, got %s", path)
+	}
+	if body["input"] != "hello" {
+		t.Errorf("expected input field, got body %v", body)
+	}
+	if _, ok := body["prompt"]; ok {
+		t.Errorf("did not expect prompt field in OpenAI request, got %v", body)
+	}
+	if len(vec) != 3 {
+		t.Errorf("expected 3-dim vector, got %d", len(vec))
+	}
+}
+
+func TestEmbed_OllamaV1Shim(t *testing.T) {
+	var path string
+	var body map[string]interface{}
+	srv := fakeEmbeddingServer(t, `{"data":[{"embedding":[0.5,0.5]}]}`, &path, &body)
+
+	c := NewClient(config.EmbeddingConfig{Provider: "ollama", Model: "nomic-embed-text", BaseURL: srv.URL + "/v1"})
+	if _, err := c.Embed(context.Background(), "hello"); err != nil {
+		t.Fatalf("Embed: %v", err)
+	}
+
+	if path != "/v1/embeddings" {
+		t.Errorf("expected POST /v1/embeddings, got %s", path)
+	}
+	if body["input"] != "hello" {
+		t.Errorf("expected input field, got body %v", body)
+	}
+}
+
+func TestEmbed_OllamaNative(t *testing.T) {
+	var path string
+	var body map[string]interface{}
+	srv := fakeEmbeddingServer(t, `{"embedding":[0.4,0.6]}`, &path, &body)
+
+	c := NewClient(config.EmbeddingConfig{
+		Provider:  "ollama",
+		Model:     "nomic-embed-text",
+		BaseURL:   srv.URL,
+		KeepAlive: "5m",
+		NumCtx:    2048,
+	})
+	vec, err := c.Embed(context.Background(), "hello")
+	if err != nil {
+		t.Fatalf("Embed: %v", err)
+	}
+
+	if path != "/api/embeddings" {
+		t.Errorf("expected POST /api/embeddings, got %s", path)
+	}
+	if body["prompt"] != "hello" {
+		t.Errorf("expected prompt field, got body %v", body)
+	}
+	if _, ok := body["input"]; ok {
+		t.Errorf("did not expect input field in native request, got %v", body)
+	}
+	if body["keep_alive"] != "5m" {
+		t.Errorf("expected keep_alive to be forwarded, got %v", body["keep_alive"])
+	}
+	if len(vec) != 2 || vec[0] != 0.4 {
+		t.Errorf("unexpected vector %v", vec)
+	}
+}
+
+func TestEmbed_Normalize(t *testing.T) {
+	var path string
+	var body map[string]interface{}
+	srv := fakeEmbeddingServer(t, `{"data":[{"embedding":[3,4,0]}]}`, &path, &body)
+
+	raw := NewClient(config.EmbeddingConfig{Provider: "openai", BaseURL: srv.URL})
+	vec, err := raw.Embed(context.Background(), "hello")
+	if err != nil {
+		t.Fatalf("Embed: %v", err)
+	}
+	if vec[0] != 3 || vec[1] != 4 {
+		t.Errorf("without Normalize the vector must be unchanged, got %v", vec)
+	}
+
+	c := NewClient(config.EmbeddingConfig{Provider: "openai", BaseURL: srv.URL, Normalize: true})
+	vec, err = c.Embed(context.Background(), "hello")
+	if err != nil {
+		t.Fatalf("Embed: %v", err)
+	}
+	var sum float64
+	for _, v := range vec {
+		sum += float64(v) * float64(v)
+	}
+	if math.Abs(math.Sqrt(sum)-1) > 1e-6 {
+		t.Errorf("normalized magnitude = %v, want 1 (vector %v)", math.Sqrt(sum), vec)
+	}
+	if math.Abs(float64(vec[0])-0.6) > 1e-6 || math.Abs(float64(vec[1])-0.8) > 1e-6 {
+		t.Errorf("normalized vector = %v, want [0.6 0.8 0]", vec)
+	}
+}
+
+func TestNormalize_ZeroVector(t *testing.T) {
+	vec := normalize([]float32{0, 0})
+	if vec[0] != 0 || vec[1] != 0 {
+		t.Errorf("zero vector changed to %v", vec)
+	}
+}
@@ -0,0 +1,229 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// InMemoryVectorDB is a VectorDB that keeps every record in process memory
+// and searches by brute-force cosine similarity. It is meant for tests and
+// small deployments without a vector database server; nothing is persisted.
+type InMemoryVectorDB struct {
+	mu          sync.RWMutex
+	collections map[string]*memCollection
+}
+
+type memCollection struct {
+	dimension int
+	records   map[string]VectorRecord
+}
+
+// NewInMemoryVectorDB returns an empty InMemoryVectorDB.
+func NewInMemoryVectorDB() *InMemoryVectorDB {
+	return &InMemoryVectorDB{collections: make(map[string]*memCollection)}
+}
+
+func (db *InMemoryVectorDB) EnsureCollection(ctx context.Context, name string, dimension int) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if c, ok := db.collections[name]; ok {
+		if c.dimension != dimension {
+			return fmt.Errorf("collection %q has dimension %d, not %d", name, c.dimension, dimension)
+		}
+		return nil
+	}
+	db.collections[name] = &memCollection{dimension: dimension, records: make(map[string]VectorRecord)}
+	return nil
+}
+
+func (db *InMemoryVectorDB) Store(ctx context.Context, collection string, record VectorRecord) error {
+	return db.StoreBatch(ctx, collection, []VectorRecord{record})
+}
+
+func (db *InMemoryVectorDB) StoreBatch(ctx context.Context, collection string, records []VectorRecord) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	c, ok := db.collections[collection]
+	if !ok {
+		return fmt.Errorf("collection %q does not exist", collection)
+	}
+	for _, r := range records {
+		if len(r.Vector) != c.dimension {
+			return fmt.Errorf("record %s has dimension %d, collection %q expects %d",
+				r.ID, len(r.Vector), collection, c.dimension)
+		}
+	}
+	for _, r := range records {
+		c.records[r.ID] = VectorRecord{
+			ID:      r.ID,
+			Vector:  append([]float32(nil), r.Vector...),
+			Payload: copyPayload(r.Payload),
+		}
+	}
+	return nil
+}
+
+func (db *InMemoryVectorDB) Search(
+	ctx context.Context,
+	collection string,
+	vector []float32,
+	limit, offset int,
+	filters map[string]interface{},
+) ([]SearchResult, error) {
+	matches := db.matching(collection, filters)
+
+	k := len(matches)
+	if limit > 0 && offset+limit < k {
+		k = offset + limit
+	}
+	return page(TopK(vector, matches, k), offset, limit), nil
+}
+
+// KeywordSearch matches text case-insensitively against the "content"
+// payload field.
+func (db *InMemoryVectorDB) KeywordSearch(
+	ctx context.Context,
+	collection, text string,
+	limit int,
+	filters map[string]interface{},
+) ([]SearchResult, error) {
+	needle := strings.ToLower(text)
+	var results []SearchResult
+	for _, r := range db.matching(collection, filters) {
+		content, _ := r.Payload["content"].(string)
+		if strings.Contains(strings.ToLower(content), needle) {
+			results = append(results, SearchResult{ID: r.ID, Payload: r.Payload})
+		}
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].ID < results[j].ID })
+	return page(results, 0, limit), nil
+}
+
+func (db *InMemoryVectorDB) Scroll(
+	ctx context.Context,
+	collection, orderBy string,
+	limit int,
+	cursor string,
+	filters map[string]interface{},
+) ([]SearchResult, string, error) {
+	prev, err := ParseScrollCursor(cursor)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var seen map[string]bool
+	if prev != nil {
+		seen = make(map[string]bool, len(prev.Seen))
+		for _, id := range prev.Seen {
+			seen[id] = true
+		}
+	}
+
+	var results []SearchResult
+	for _, r := range db.matching(collection, filters) {
+		if prev != nil && (PayloadInt(r.Payload, orderBy) > prev.Value || seen[r.ID]) {
+			continue
+		}
+		results = append(results, SearchResult{ID: r.ID, Payload: r.Payload})
+	}
+	sort.Slice(results, func(i, j int) bool {
+		a, b := PayloadInt(results[i].Payload, orderBy), PayloadInt(results[j].Payload, orderBy)
+		if a == b {
+			return results[i].ID < results[j].ID
+		}
+		return a > b
+	})
+	results = page(results, 0, limit)
+
+	return results, NextScrollCursor(results, orderBy, limit, prev), nil
+}
+
+func (db *InMemoryVectorDB) Count(ctx context.Context, collection string, filters map[string]interface{}) (int, error) {
+	return len(db.matching(collection, filters)), nil
+}
+
+func (db *InMemoryVectorDB) Stats(
+	ctx context.Context,
+	collection string,
+	filters map[string]interface{},
+) (CollectionStats, error) {
+	var stats CollectionStats
+	for _, r := range db.matching(collection, filters) {
+		ts := PayloadInt(r.Payload, "timestamp")
+		if stats.Count == 0 || ts < stats.Oldest {
+			stats.Oldest = ts
+		}
+		if stats.Count == 0 || ts > stats.Newest {
+			stats.Newest = ts
+		}
+		stats.Count++
+	}
+	return stats, nil
+}
+
+func (db *InMemoryVectorDB) Close() error {
+	return nil
+}
+
+// matching returns the records in collection whose payload satisfies every
+// string filter. Like the Qdrant backend, non-string filter values are
+// ignored. A missing collection has no records.
+func (db *InMemoryVectorDB) matching(collection string, filters map[string]interface{}) []VectorRecord {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	c, ok := db.collections[collection]
+	if !ok {
+		return nil
+	}
+
+	var out []VectorRecord
+	for _, r := range c.records {
+		if matchesFilters(r.Payload, filters) {
+			out = append(out, VectorRecord{ID: r.ID, Vector: r.Vector, Payload: copyPayload(r.Payload)})
+		}
+	}
+	return out
+}
+
+func matchesFilters(payload, filters map[string]interface{}) bool {
+	for k, v := range filters {
+		want, ok := v.(string)
+		if !ok {
+			continue
+		}
+		if got, _ := payload[k].(string); got != want {
+			return false
+		}
+	}
+	return true
+}
+
+func copyPayload(p map[string]interface{}) map[string]interface{} {
+	if p == nil {
+		return nil
+	}
+	out := make(map[string]interface{}, len(p))
+	for k, v := range p {
+		out[k] = v
+	}
+	return out
+}
+
+// page applies offset and limit to results. A non-positive limit returns
+// everything after offset.
+func page(results []SearchResult, offset, limit int) []SearchResult {
+	if offset >= len(results) {
+		return []SearchResult{}
+	}
+	results = results[offset:]
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+	return results
+}
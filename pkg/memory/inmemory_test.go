@@ -0,0 +1,178 @@
+package memory
+
+import (
+	"context"
+	"testing"
+)
+
+func TestInMemoryVectorDB_EnsureCollection(t *testing.T) {
+	ctx := context.Background()
+	db := NewInMemoryVectorDB()
+
+	if err := db.EnsureCollection(ctx, "c", 3); err != nil {
+		t.Fatalf("EnsureCollection: %v", err)
+	}
+	if err := db.EnsureCollection(ctx, "c", 3); err != nil {
+		t.Errorf("EnsureCollection is not idempotent: %v", err)
+	}
+	if err := db.EnsureCollection(ctx, "c", 4); err == nil {
+		t.Error("expected an error when the dimension changes")
+	}
+
+	if err := db.Store(ctx, "missing", VectorRecord{ID: "a", Vector: []float32{1, 0, 0}}); err == nil {
+		t.Error("expected an error storing into a missing collection")
+	}
+	if err := db.Store(ctx, "c", VectorRecord{ID: "a", Vector: []float32{1, 0}}); err == nil {
+		t.Error("expected an error storing a vector of the wrong dimension")
+	}
+}
+
+func TestInMemoryVectorDB_StoreAndSearch(t *testing.T) {
+	ctx := context.Background()
+	db := NewInMemoryVectorDB()
+	if err := db.EnsureCollection(ctx, "c", 2); err != nil {
+		t.Fatalf("EnsureCollection: %v", err)
+	}
+
+	records := []VectorRecord{
+		{ID: "same", Vector: []float32{2, 0}, Payload: map[string]interface{}{"workspace_id": "ws", "content": "same direction"}},
+		{ID: "diag", Vector: []float32{1, 1}, Payload: map[string]interface{}{"workspace_id": "ws", "content": "diagonal"}},
+		{ID: "orth", Vector: []float32{0, 5}, Payload: map[string]interface{}{"workspace_id": "ws", "content": "orthogonal"}},
+		{ID: "other", Vector: []float32{1, 0}, Payload: map[string]interface{}{"workspace_id": "other", "content": "other ws"}},
+	}
+	if err := db.StoreBatch(ctx, "c", records); err != nil {
+		t.Fatalf("StoreBatch: %v", err)
+	}
+
+	results, err := db.Search(ctx, "c", []float32{1, 0}, 10, 0, map[string]interface{}{"workspace_id": "ws"})
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	want := []struct {
+		id    string
+		score float32
+	}{{"same", 1}, {"diag", 0.7071}, {"orth", 0}}
+	if len(results) != len(want) {
+		t.Fatalf("got %d results, want %d: %+v", len(results), len(want), results)
+	}
+	for i, w := range want {
+		if results[i].ID != w.id {
+			t.Errorf("result %d = %s, want %s", i, results[i].ID, w.id)
+		}
+		if d := results[i].Score - w.score; d > 1e-3 || d < -1e-3 {
+			t.Errorf("%s score = %v, want %v", w.id, results[i].Score, w.score)
+		}
+	}
+
+	paged, _ := db.Search(ctx, "c", []float32{1, 0}, 1, 1, map[string]interface{}{"workspace_id": "ws"})
+	if len(paged) != 1 || paged[0].ID != "diag" {
+		t.Errorf("offset page = %+v, want [diag]", paged)
+	}
+
+	// Upserting replaces the record.
+	if err := db.Store(ctx, "c", VectorRecord{ID: "orth", Vector: []float32{1, 0}, Payload: map[string]interface{}{"workspace_id": "ws"}}); err != nil {
+		t.Fatalf("Store: %v", err)
+	}
+	if n, _ := db.Count(ctx, "c", map[string]interface{}{"workspace_id": "ws"}); n != 3 {
+		t.Errorf("Count = %d, want 3", n)
+	}
+
+	hits, _ := db.KeywordSearch(ctx, "c", "DIAG", 10, map[string]interface{}{"workspace_id": "ws"})
+	if len(hits) != 1 || hits[0].ID != "diag" {
+		t.Errorf("KeywordSearch = %+v, want [diag]", hits)
+	}
+
+	if results, err := db.Search(ctx, "missing", []float32{1, 0}, 10, 0, nil); err != nil || len(results) != 0 {
+		t.Errorf("Search on missing collection = %v, %v; want no results", results, err)
+	}
+}
+
+func TestInMemoryVectorDB_Scroll(t *testing.T) {
+	ctx := context.Background()
+	db := NewInMemoryVectorDB()
+	if err := db.EnsureCollection(ctx, "c", 1); err != nil {
+		t.Fatalf("EnsureCollection: %v", err)
+	}
+	for i, ts := range []int64{10, 30, 20, 20, 5} {
+		id := string(rune('a' + i))
+		err := db.Store(ctx, "c", VectorRecord{ID: id, Vector: []float32{1}, Payload: map[string]interface{}{
+			"workspace_id": "ws",
+			"timestamp":    ts,
+		}})
+		if err != nil {
+			t.Fatalf("Store: %v", err)
+		}
+	}
+
+	var got []int64
+	cursor := ""
+	for range 5 {
+		page, next, err := db.Scroll(ctx, "c", "timestamp", 2, cursor, map[string]interface{}{"workspace_id": "ws"})
+		if err != nil {
+			t.Fatalf("Scroll: %v", err)
+		}
+		for _, r := range page {
+			got = append(got, PayloadInt(r.Payload, "timestamp"))
+		}
+		if next == "" {
+			break
+		}
+		cursor = next
+	}
+
+	want := []int64{30, 20, 20, 10, 5}
+	if len(got) != len(want) {
+		t.Fatalf("scrolled %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("scrolled %v, want %v", got, want)
+		}
+	}
+}
+
+func TestInMemoryVectorDB_WithManager(t *testing.T) {
+	ctx := context.Background()
+	db := NewInMemoryVectorDB()
+	m := newTestManager(db, &fakeEmbedder{vectors: map[string][]float32{"lunch": {0, 1}}})
+
+	if err := m.ArchiveSession(ctx, "ws", "s1", testMessages()); err != nil {
+		t.Fatalf("ArchiveSession: %v", err)
+	}
+	results, err := m.Search(ctx, "ws", "anything", 5, 0)
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(results) != 1 || results[0].Payload["session_id"] != "s1" {
+		t.Errorf("Search = %+v, want the archived session", results)
+	}
+}
+
+func TestInMemoryVectorDB_Stats(t *testing.T) {
+	ctx := context.Background()
+	db := NewInMemoryVectorDB()
+	if err := db.EnsureCollection(ctx, "c", 1); err != nil {
+		t.Fatalf("EnsureCollection: %v", err)
+	}
+	for i, ts := range []int64{300, 100, 200} {
+		err := db.Store(ctx, "c", VectorRecord{ID: string(rune('a' + i)), Vector: []float32{1}, Payload: map[string]interface{}{
+			"workspace_id": "ws",
+			"timestamp":    ts,
+		}})
+		if err != nil {
+			t.Fatalf("Store: %v", err)
+		}
+	}
+
+	stats, err := db.Stats(ctx, "c", map[string]interface{}{"workspace_id": "ws"})
+	if err != nil {
+		t.Fatalf("Stats: %v", err)
+	}
+	if stats != (CollectionStats{Count: 3, Oldest: 100, Newest: 300}) {
+		t.Errorf("Stats = %+v", stats)
+	}
+
+	if stats, _ := db.Stats(ctx, "c", map[string]interface{}{"workspace_id": "none"}); stats != (CollectionStats{}) {
+		t.Errorf("Stats for an empty workspace = %+v, want zero", stats)
+	}
+}
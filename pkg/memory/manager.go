@@ -2,20 +2,41 @@ package memory
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"hash/fnv"
+	"sort"
 	"strings"
+	"sync"
 	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
 	"github.com/sipeed/picoclaw/pkg/config"
 	"github.com/sipeed/picoclaw/pkg/logger"
+	"github.com/sipeed/picoclaw/pkg/metrics"
 	"github.com/sipeed/picoclaw/pkg/providers"
+	"github.com/sipeed/picoclaw/pkg/tokens"
 )
 
 type Manager struct {
 	db       VectorDB
 	embedder Embedder
 	config   config.MemoryConfig
+
+	summarizer Summarizer
+
+	// archivedMu guards archived, the number of messages of each
+	// workspace/session already stored by ArchiveIncremental.
+	archivedMu sync.Mutex
+	archived   map[string]int
+
+	// archiveLocks serialize ArchiveIncremental per session, sharded like
+	// JSONLStore's locks, so one session's slow embedding does not hold up
+	// the others.
+	archiveLocks [numLockShards]sync.Mutex
 }
 
 func NewManager(cfg config.MemoryConfig, db VectorDB, embedder Embedder) *Manager {
@@ -23,13 +44,49 @@ func NewManager(cfg config.MemoryConfig, db VectorDB, embedder Embedder) *Manage
 		db:       db,
 		embedder: embedder,
 		config:   cfg,
+		archived: make(map[string]int),
 	}
 }
 
+// SetSummarizer sets the Summarizer used when config.ArchiveSummary asks for
+// sessions to be summarized before archiving. Without one, raw chunks are
+// stored.
+func (m *Manager) SetSummarizer(s Summarizer) {
+	m.summarizer = s
+}
+
 func (m *Manager) IsEnabled() bool {
 	return m.config.Enabled && m.db != nil && m.embedder != nil
 }
 
+// collectionName returns the vector collection holding a workspace's chunks:
+// the configured collection (default "picoclaw"), or "<name>_<workspace>"
+// when config.CollectionPerWorkspace is set.
+func (m *Manager) collectionName(workspaceID string) string {
+	name := "picoclaw"
+	if m.config.Qdrant.CollectionName != "" {
+		name = m.config.Qdrant.CollectionName
+	}
+	if m.config.CollectionPerWorkspace {
+		name += "_" + collectionSuffix(workspaceID)
+	}
+	return name
+}
+
+// collectionSuffix maps a workspace ID onto characters every backend accepts
+// in a collection name.
+func collectionSuffix(workspaceID string) string {
+	if workspaceID == "" {
+		return "default"
+	}
+	return strings.Map(func(r rune) rune {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '-') {
+			return r
+		}
+		return '_'
+	}, workspaceID)
+}
+
 func (m *Manager) Close() error {
 	if m.db != nil {
 		return m.db.Close()
@@ -37,14 +94,173 @@ func (m *Manager) Close() error {
 	return nil
 }
 
-func (m *Manager) ArchiveSession(ctx context.Context, workspaceID, sessionID string, messages []providers.Message) error {
+// Metadata keys that can be attached to archived chunks and used as search filters.
+const (
+	MetadataChannel = "channel"
+	MetadataUserID  = "user_id"
+	MetadataAgentID = "agent_id"
+)
+
+// metadataKeys lists the payload fields callers may set via ArchiveOptions.Metadata.
+// Everything else in the payload is managed by the Manager itself.
+var metadataKeys = map[string]bool{
+	MetadataChannel: true,
+	MetadataUserID:  true,
+	MetadataAgentID: true,
+}
+
+// ArchiveOptions tunes how ArchiveSessionWithOptions stores a session.
+type ArchiveOptions struct {
+	// Force stores every chunk even if an identical chunk was already
+	// archived for the same workspace.
+	Force bool
+
+	// Metadata is stored on every chunk (channel, user_id, agent_id) so
+	// searches can later be narrowed with SearchOptions.Metadata. Unknown
+	// keys are ignored.
+	Metadata map[string]string
+}
+
+// ArchiveSession chunks, embeds and stores a session with default options.
+func (m *Manager) ArchiveSession(
+	ctx context.Context,
+	workspaceID, sessionID string,
+	messages []providers.Message,
+) error {
+	return m.ArchiveSessionWithOptions(ctx, workspaceID, sessionID, messages, ArchiveOptions{})
+}
+
+// ArchiveSessionWithOptions chunks, embeds and stores a session. Unless
+// opts.Force is set, chunks whose content hash already exists in the
+// workspace are skipped so re-archiving a session does not duplicate it.
+//
+// When config.ArchiveSummary is set and a Summarizer is configured, a
+// summary of the session is stored too (tagged kind "summary"), or instead
+// of the raw chunks. If summarizing fails, the raw chunks are stored.
+func (m *Manager) ArchiveSessionWithOptions(
+	ctx context.Context,
+	workspaceID, sessionID string,
+	messages []providers.Message,
+	opts ArchiveOptions,
+) error {
+	if !m.config.Enabled || m.db == nil || m.embedder == nil {
+		return nil
+	}
+
+	mode := m.config.ArchiveSummary
+	if mode == "" || m.summarizer == nil {
+		return m.archiveText(ctx, workspaceID, sessionID, transcript(messages), opts, nil)
+	}
+
+	summary, err := m.summarize(ctx, messages)
+	if err != nil {
+		logger.WarnCF("memory", "Failed to summarize session; archiving raw chunks", map[string]interface{}{
+			"session": sessionID,
+			"error":   err.Error(),
+		})
+		return m.archiveText(ctx, workspaceID, sessionID, transcript(messages), opts, nil)
+	}
+
+	if err := m.archiveText(ctx, workspaceID, sessionID, summary, opts, map[string]interface{}{"kind": KindSummary}); err != nil {
+		return err
+	}
+	if mode == ArchiveSummaryInstead {
+		return nil
+	}
+	return m.archiveText(ctx, workspaceID, sessionID, transcript(messages), opts, nil)
+}
+
+// summarize asks the configured Summarizer for a summary of messages and
+// records how long it took.
+func (m *Manager) summarize(ctx context.Context, messages []providers.Message) (string, error) {
+	start := time.Now()
+	summary, err := m.summarizer.Summarize(ctx, messages)
+	metrics.DefaultRecorder().RecordSummarization(summarizerModel(m.summarizer), time.Since(start))
+	if err != nil {
+		return "", err
+	}
+	if strings.TrimSpace(summary) == "" {
+		return "", fmt.Errorf("summarizer returned an empty summary")
+	}
+	return summary, nil
+}
+
+// MetadataMessageEnd is the payload field recording how many messages of
+// the session had been archived once a chunk was stored by ArchiveIncremental.
+const MetadataMessageEnd = "message_end"
+
+// ArchiveIncremental archives only the part of a growing session that has
+// not been archived yet. messages is the session's full history; the number
+// already archived is remembered per session (and recovered from the
+// message_end payload field after a restart), so earlier messages are not
+// chunked and embedded again.
+func (m *Manager) ArchiveIncremental(
+	ctx context.Context,
+	workspaceID, sessionID string,
+	messages []providers.Message,
+) error {
 	if !m.config.Enabled || m.db == nil || m.embedder == nil {
 		return nil
 	}
 
-	// 1. Prepare text for embedding.
-	// For now, let's just concatenate the last few messages or a summary.
-	// A better approach might be to chunk it, but let's start simple.
+	key := workspaceID + "\x00" + sessionID
+	l := m.archiveLock(key)
+	l.Lock()
+	defer l.Unlock()
+
+	m.archivedMu.Lock()
+	offset, ok := m.archived[key]
+	m.archivedMu.Unlock()
+	if !ok {
+		var err error
+		offset, err = m.archivedOffset(ctx, workspaceID, sessionID)
+		if err != nil {
+			return err
+		}
+	}
+	if offset > len(messages) {
+		// The session was reset or truncated; start over.
+		offset = 0
+	}
+	if offset < len(messages) {
+		extra := map[string]interface{}{MetadataMessageEnd: len(messages)}
+		if err := m.archiveText(ctx, workspaceID, sessionID, transcript(messages[offset:]), ArchiveOptions{}, extra); err != nil {
+			return err
+		}
+	}
+
+	m.archivedMu.Lock()
+	m.archived[key] = len(messages)
+	m.archivedMu.Unlock()
+	return nil
+}
+
+// archiveLock returns the mutex serializing ArchiveIncremental for key.
+func (m *Manager) archiveLock(key string) *sync.Mutex {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return &m.archiveLocks[h.Sum32()%numLockShards]
+}
+
+// archivedOffset returns the highest message_end stored for a session, or 0
+// if ArchiveIncremental has not archived it before.
+func (m *Manager) archivedOffset(ctx context.Context, workspaceID, sessionID string) (int, error) {
+	results, _, err := m.db.Scroll(ctx, m.collectionName(workspaceID), MetadataMessageEnd, 1, "", map[string]interface{}{
+		"workspace_id": workspaceID,
+		"session_id":   sessionID,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to look up archived offset: %w", err)
+	}
+	if len(results) == 0 {
+		return 0, nil
+	}
+	return int(PayloadInt(results[0].Payload, MetadataMessageEnd)), nil
+}
+
+// transcript renders messages as "role: content" lines, skipping system
+// prompts.
+func transcript(messages []providers.Message) string {
 	var sb strings.Builder
 	for _, msg := range messages {
 		if msg.Role == "system" {
@@ -52,8 +268,17 @@ func (m *Manager) ArchiveSession(ctx context.Context, workspaceID, sessionID str
 		}
 		sb.WriteString(fmt.Sprintf("%s: %s\n", msg.Role, msg.Content))
 	}
+	return sb.String()
+}
 
-	text := sb.String()
+// archiveText chunks, embeds and stores text for a session, adding extra to
+// every chunk's payload.
+func (m *Manager) archiveText(
+	ctx context.Context,
+	workspaceID, sessionID, text string,
+	opts ArchiveOptions,
+	extra map[string]interface{},
+) error {
 	if text == "" {
 		return nil
 	}
@@ -63,109 +288,174 @@ func (m *Manager) ArchiveSession(ctx context.Context, workspaceID, sessionID str
 	if chunkSize <= 0 {
 		chunkSize = 4096 // Default
 	}
-	overlap := chunkSize / 10 // 10% overlap
+	if m.config.Embedding.ChunkTokens > 0 {
+		chunkSize = runesForTokens(text, m.config.Embedding.Model, m.config.Embedding.ChunkTokens)
+	}
+	chunks := chunkText(text, chunkSize)
 
-	chunks := []string{}
-	runes := []rune(text)
+	// 3. Process each chunk
+	collection := m.collectionName(workspaceID)
 
-	if len(runes) <= chunkSize {
-		chunks = append(chunks, text)
-	} else {
-		for i := 0; i < len(runes); i += (chunkSize - overlap) {
-			end := i + chunkSize
-			if end > len(runes) {
-				end = len(runes)
-			}
-			chunks = append(chunks, string(runes[i:end]))
-			if end == len(runes) {
-				break
-			}
-		}
+	// We need to know the dimension for EnsureCollection, so embed the
+	// first chunk up front and reuse its vector below.
+	firstVector, err := m.embedder.Embed(ctx, chunks[0])
+	if err != nil {
+		return fmt.Errorf("failed to generate embedding for first chunk: %w", err)
 	}
 
-	// 3. Process each chunk
-	collection := m.config.Qdrant.CollectionName
-	if collection == "" {
-		collection = "picoclaw"
+	err = m.db.EnsureCollection(ctx, collection, len(firstVector))
+	if err != nil {
+		return fmt.Errorf("failed to ensure collection: %w", err)
 	}
 
-	// We need to know the dimension for EnsureCollection.
-	// We'll use the first chunk to determine it if needed.
-	if len(chunks) > 0 {
-		// Generate first embedding to get dimension
-		vector, err := m.embedder.Embed(ctx, chunks[0])
-		if err != nil {
-			return fmt.Errorf("failed to generate embedding for first chunk: %w", err)
+	timestamp := time.Now().UnixNano()
+	seen := make(map[string]bool, len(chunks))
+	records := make([]VectorRecord, 0, len(chunks))
+	for i, chunk := range chunks {
+		hash := contentHash(chunk)
+		if !opts.Force {
+			if seen[hash] {
+				continue
+			}
+			seen[hash] = true
+
+			count, err := m.db.Count(ctx, collection, map[string]interface{}{
+				"workspace_id": workspaceID,
+				"content_hash": hash,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to check for duplicate chunk %d: %w", i, err)
+			}
+			if count > 0 {
+				continue
+			}
 		}
 
-		err = m.db.EnsureCollection(ctx, collection, len(vector))
-		if err != nil {
-			return fmt.Errorf("failed to ensure collection: %w", err)
+		vector := firstVector
+		if i > 0 {
+			vector, err = m.embedder.Embed(ctx, chunk)
+			if err != nil {
+				return fmt.Errorf("failed to generate embedding for chunk %d: %w", i, err)
+			}
 		}
 
-		// Store first chunk
-		timestamp := time.Now().UnixNano()
 		payload := map[string]interface{}{
 			"workspace_id": workspaceID,
 			"session_id":   sessionID,
-			"content":      chunks[0],
+			"content":      chunk,
+			"content_hash": hash,
 			"timestamp":    timestamp / int64(time.Second),
-			"chunk_index":  0,
+			"chunk_index":  i,
 			"total_chunks": len(chunks),
 		}
+		for k, v := range opts.Metadata {
+			if metadataKeys[k] && v != "" {
+				payload[k] = v
+			}
+		}
+		for k, v := range extra {
+			payload[k] = v
+		}
 
 		// Use UUID for point ID. Qdrant requires UUIDs or uint64.
 		// We use MD5 hash of a stable string to generate a deterministic UUID.
-		rawID0 := fmt.Sprintf("%s_%s_%d_%d", workspaceID, sessionID, timestamp, 0)
-		pointID0 := uuid.NewMD5(uuid.NameSpaceURL, []byte(rawID0)).String()
+		rawID := fmt.Sprintf("%s_%s_%d_%d", workspaceID, sessionID, timestamp, i)
+		if kind, ok := extra["kind"].(string); ok {
+			rawID += "_" + kind
+		}
+		pointID := uuid.NewMD5(uuid.NameSpaceURL, []byte(rawID)).String()
 
-		err = m.db.Store(ctx, collection, VectorRecord{
-			ID:      pointID0,
+		records = append(records, VectorRecord{
+			ID:      pointID,
 			Vector:  vector,
 			Payload: payload,
 		})
-		if err != nil {
-			return fmt.Errorf("failed to store chunk 0 in vector db (ID: %s): %w", pointID0, err)
-		}
+	}
 
-		// Store remaining chunks
-		for i := 1; i < len(chunks); i++ {
-			vector, err := m.embedder.Embed(ctx, chunks[i])
-			if err != nil {
-				return fmt.Errorf("failed to generate embedding for chunk %d: %w", i, err)
-			}
+	if err := m.db.StoreBatch(ctx, collection, records); err != nil {
+		return fmt.Errorf("failed to store %d chunks in vector db: %w", len(records), err)
+	}
 
-			payload := map[string]interface{}{
-				"workspace_id": workspaceID,
-				"session_id":   sessionID,
-				"content":      chunks[i],
-				"timestamp":    timestamp / int64(time.Second),
-				"chunk_index":  i,
-				"total_chunks": len(chunks),
-			}
+	logger.DebugCF("memory", "Archived session to vector DB", map[string]interface{}{
+		"session": sessionID,
+		"chunks":  len(chunks),
+		"stored":  len(records),
+	})
 
-			rawIDi := fmt.Sprintf("%s_%s_%d_%d", workspaceID, sessionID, timestamp, i)
-			pointIDi := uuid.NewMD5(uuid.NameSpaceURL, []byte(rawIDi)).String()
+	return nil
+}
 
-			err = m.db.Store(ctx, collection, VectorRecord{
-				ID:      pointIDi,
-				Vector:  vector,
-				Payload: payload,
-			})
-			if err != nil {
-				return fmt.Errorf("failed to store chunk %d in vector db (ID: %s): %w", i, pointIDi, err)
-			}
+// chunkText splits text into chunks of at most chunkSize runes using a
+// sliding window with 10% overlap.
+func chunkText(text string, chunkSize int) []string {
+	overlap := chunkSize / 10 // 10% overlap
+
+	runes := []rune(text)
+	if len(runes) <= chunkSize {
+		return []string{text}
+	}
+
+	var chunks []string
+	for i := 0; i < len(runes); i += (chunkSize - overlap) {
+		end := i + chunkSize
+		if end > len(runes) {
+			end = len(runes)
+		}
+		chunks = append(chunks, string(runes[i:end]))
+		if end == len(runes) {
+			break
 		}
-		logger.DebugCF("memory", "Archived session to vector DB", map[string]interface{}{
-			"session": sessionID,
-			"chunks":  len(chunks),
-		})
 	}
+	return chunks
+}
 
-	return nil
+// runesForTokens converts a token budget into a chunk size in runes using
+// text's own runes-per-token ratio under the embedding model's tokenizer.
+func runesForTokens(text, model string, maxTokens int) int {
+	count := tokens.Count(model, text)
+	if count <= maxTokens {
+		return utf8.RuneCountInString(text)
+	}
+	size := maxTokens * utf8.RuneCountInString(text) / count
+	if size < 1 {
+		size = 1
+	}
+	return size
+}
+
+// contentHash returns a stable hex-encoded SHA-256 of chunk content, used to
+// detect chunks that were already archived.
+func contentHash(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+// SearchOptions tunes SearchWithOptions.
+type SearchOptions struct {
+	// Offset skips this many of the best-scoring results, for paging.
+	Offset int
+
+	// Metadata restricts results to chunks archived with matching metadata
+	// (see MetadataChannel, MetadataUserID, MetadataAgentID). Empty values
+	// and unknown keys are ignored.
+	Metadata map[string]string
+
+	// MinScore drops results whose cosine similarity is below this value.
+	// Zero disables the threshold.
+	MinScore float32
 }
 
 func (m *Manager) Search(ctx context.Context, workspaceID, query string, limit, offset int) ([]SearchResult, error) {
+	return m.SearchWithOptions(ctx, workspaceID, query, limit, SearchOptions{Offset: offset})
+}
+
+// SearchWithOptions is Search with metadata filters and a score threshold.
+func (m *Manager) SearchWithOptions(
+	ctx context.Context,
+	workspaceID, query string,
+	limit int,
+	opts SearchOptions,
+) ([]SearchResult, error) {
 	if !m.config.Enabled || m.db == nil || m.embedder == nil {
 		return nil, nil
 	}
@@ -177,60 +467,263 @@ func (m *Manager) Search(ctx context.Context, workspaceID, query string, limit,
 	}
 
 	// 2. Search in DB
-	collection := m.config.Qdrant.CollectionName
-	if collection == "" {
-		collection = "picoclaw"
-	}
+	collection := m.collectionName(workspaceID)
 
 	// Prepare filters for workspace isolation
+	filters := searchFilters(workspaceID, opts.Metadata)
+
+	results, err := m.db.Search(ctx, collection, vector, limit, opts.Offset, filters)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search in vector db: %w", err)
+	}
+
+	return filterByScore(results, opts.MinScore), nil
+}
+
+// filterByScore drops results scoring below minScore, preserving order.
+func filterByScore(results []SearchResult, minScore float32) []SearchResult {
+	if minScore <= 0 {
+		return results
+	}
+	kept := results[:0]
+	for _, r := range results {
+		if r.Score >= minScore {
+			kept = append(kept, r)
+		}
+	}
+	return kept
+}
+
+// searchFilters builds the payload filters for a workspace-scoped search,
+// adding any recognised metadata filters.
+func searchFilters(workspaceID string, metadata map[string]string) map[string]interface{} {
 	filters := map[string]interface{}{
 		"workspace_id": workspaceID,
 	}
+	for k, v := range metadata {
+		if metadataKeys[k] && v != "" {
+			filters[k] = v
+		}
+	}
+	return filters
+}
+
+// rrfK is the rank constant for reciprocal-rank fusion. 60 is the value
+// from the original RRF paper and dampens the influence of top ranks.
+const rrfK = 60
+
+// HybridSearch combines vector similarity with keyword matching so exact terms
+// (names, IDs, error codes) surface even when their embedding scores poorly.
+// Each query term is matched against chunk content; the vector and keyword
+// result lists are merged with reciprocal-rank fusion and deduplicated by chunk.
+// The returned Score is the fused RRF score, not a cosine similarity.
+func (m *Manager) HybridSearch(ctx context.Context, workspaceID, query string, limit int) ([]SearchResult, error) {
+	return m.HybridSearchWithMetadata(ctx, workspaceID, query, limit, nil)
+}
 
-	results, err := m.db.Search(ctx, collection, vector, limit, offset, filters)
+// HybridSearchWithMetadata is HybridSearch restricted to chunks archived with
+// matching metadata, like SearchOptions.Metadata.
+func (m *Manager) HybridSearchWithMetadata(
+	ctx context.Context,
+	workspaceID, query string,
+	limit int,
+	metadata map[string]string,
+) ([]SearchResult, error) {
+	if !m.config.Enabled || m.db == nil || m.embedder == nil {
+		return nil, nil
+	}
+
+	vector, err := m.embedder.Embed(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate embedding for search: %w", err)
+	}
+
+	collection := m.collectionName(workspaceID)
+	filters := searchFilters(workspaceID, metadata)
+
+	// Fetch more candidates than requested so fusion has something to re-rank.
+	candidates := limit * 2
+
+	vectorResults, err := m.db.Search(ctx, collection, vector, candidates, 0, filters)
 	if err != nil {
 		return nil, fmt.Errorf("failed to search in vector db: %w", err)
 	}
+	lists := [][]SearchResult{vectorResults}
 
-	return results, nil
+	for _, term := range keywordTerms(query) {
+		keywordResults, err := m.db.KeywordSearch(ctx, collection, term, candidates, filters)
+		if err != nil {
+			return nil, fmt.Errorf("failed to keyword search in vector db: %w", err)
+		}
+		lists = append(lists, keywordResults)
+	}
+
+	return fuseResults(lists, limit), nil
+}
+
+// maxKeywordTerms caps the number of keyword lookups issued per hybrid query.
+const maxKeywordTerms = 8
+
+// keywordTerms splits a query into distinct terms worth matching exactly.
+// Very short words are skipped because they match almost every chunk.
+func keywordTerms(query string) []string {
+	seen := make(map[string]bool)
+	var terms []string
+	for _, field := range strings.Fields(query) {
+		term := strings.Trim(field, ".,;:!?\"'()[]{}")
+		if len([]rune(term)) < 3 || seen[strings.ToLower(term)] {
+			continue
+		}
+		seen[strings.ToLower(term)] = true
+		terms = append(terms, term)
+		if len(terms) == maxKeywordTerms {
+			break
+		}
+	}
+	return terms
+}
+
+// fuseResults merges ranked result lists using reciprocal-rank fusion,
+// deduplicating by ID. Ties keep the order in which results were first seen.
+func fuseResults(lists [][]SearchResult, limit int) []SearchResult {
+	scores := make(map[string]float64)
+	byID := make(map[string]SearchResult)
+	var order []string
+
+	for _, list := range lists {
+		for rank, r := range list {
+			if _, ok := byID[r.ID]; !ok {
+				byID[r.ID] = r
+				order = append(order, r.ID)
+			}
+			scores[r.ID] += 1.0 / float64(rrfK+rank+1)
+		}
+	}
+
+	sort.SliceStable(order, func(i, j int) bool {
+		return scores[order[i]] > scores[order[j]]
+	})
+
+	if limit > 0 && len(order) > limit {
+		order = order[:limit]
+	}
+
+	fused := make([]SearchResult, len(order))
+	for i, id := range order {
+		r := byID[id]
+		r.Score = float32(scores[id])
+		fused[i] = r
+	}
+	return fused
 }
 
+// Stats reports how many chunks are stored for a workspace and the range of
+// their archive timestamps.
+func (m *Manager) Stats(ctx context.Context, workspaceID string) (CollectionStats, error) {
+	if !m.config.Enabled || m.db == nil {
+		return CollectionStats{}, nil
+	}
+
+	stats, err := m.db.Stats(ctx, m.collectionName(workspaceID), map[string]interface{}{
+		"workspace_id": workspaceID,
+	})
+	if err != nil {
+		return CollectionStats{}, fmt.Errorf("failed to read memory stats: %w", err)
+	}
+	return stats, nil
+}
+
+// ListSessions returns stored chunks for a workspace, newest first, without
+// a query. cursor is "" for the first page or the token returned by the
+// previous call; the returned token is "" when there is nothing more to list.
+func (m *Manager) ListSessions(
+	ctx context.Context,
+	workspaceID string,
+	limit int,
+	cursor string,
+) ([]SearchResult, string, error) {
+	if !m.config.Enabled || m.db == nil {
+		return nil, "", nil
+	}
+
+	results, next, err := m.db.Scroll(ctx, m.collectionName(workspaceID), "timestamp", limit, cursor, map[string]interface{}{
+		"workspace_id": workspaceID,
+	})
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to list memories: %w", err)
+	}
+
+	return results, next, nil
+}
+
+const (
+	// dateSearchRelevance is the fraction of the best match's score a chunk
+	// must reach to count as related in SearchByDate.
+	dateSearchRelevance = 0.8
+	// dateSearchBatchSize and dateSearchMaxPages bound how much of the
+	// similarity ranking SearchByDate reads: at most 1000 candidates, even
+	// when every chunk scores alike.
+	dateSearchBatchSize = 100
+	dateSearchMaxPages  = 10
+)
+
 // SearchByDate finds semantically relevant chunks for the given query then
-// returns them ordered by timestamp. It fetches a wider candidate set
-// (candidateMultiplier * limit by similarity) and re-sorts client-side,
-// because Qdrant cannot order_by and perform a vector search in the same query.
-func (m *Manager) SearchByDate(ctx context.Context, workspaceID, query string, limit int, order string) ([]SearchResult, error) {
+// returns them ordered by timestamp. Qdrant cannot order_by and perform a
+// vector search in the same query, so it pages through the similarity
+// ranking until scores fall below dateSearchRelevance of the best match,
+// the backend runs out or dateSearchMaxPages pages have been read, and
+// re-sorts the related chunks client-side.
+//
+// offset pages through the date-ordered set. The related set does not depend
+// on limit or offset, so successive pages never overlap; ties on timestamp
+// are broken by ID to keep ordering stable.
+func (m *Manager) SearchByDate(
+	ctx context.Context,
+	workspaceID, query string,
+	limit, offset int,
+	order string,
+) ([]SearchResult, error) {
 	if !m.config.Enabled || m.db == nil || m.embedder == nil {
 		return nil, nil
 	}
 
-	const candidateMultiplier = 10
-	candidates := limit * candidateMultiplier
-	if candidates < 50 {
-		candidates = 50
-	}
-
 	vector, err := m.embedder.Embed(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate embedding: %w", err)
 	}
 
-	collection := m.config.Qdrant.CollectionName
-	if collection == "" {
-		collection = "picoclaw"
-	}
+	collection := m.collectionName(workspaceID)
 
 	filters := map[string]interface{}{
 		"workspace_id": workspaceID,
 	}
 
-	results, err := m.db.Search(ctx, collection, vector, candidates, 0, filters)
-	if err != nil {
-		return nil, fmt.Errorf("failed to search in vector db: %w", err)
+	var (
+		results []SearchResult
+		floor   float32
+	)
+	for page := 0; page < dateSearchMaxPages; page++ {
+		from := page * dateSearchBatchSize
+		batch, err := m.db.Search(ctx, collection, vector, dateSearchBatchSize, from, filters)
+		if err != nil {
+			return nil, fmt.Errorf("failed to search in vector db: %w", err)
+		}
+		if from == 0 && len(batch) > 0 {
+			floor = batch[0].Score * dateSearchRelevance
+		}
+		related := filterByScore(batch, floor)
+		results = append(results, related...)
+		if len(batch) < dateSearchBatchSize || len(related) < len(batch) {
+			break
+		}
 	}
 
 	sortResultsByDate(results, order)
 
+	if offset >= len(results) {
+		return []SearchResult{}, nil
+	}
+	results = results[offset:]
 	if len(results) > limit {
 		results = results[:limit]
 	}
@@ -241,28 +734,17 @@ func (m *Manager) SearchByDate(ctx context.Context, workspaceID, query string, l
 // sortResultsByDate sorts results in-place by the "timestamp" payload field.
 func sortResultsByDate(results []SearchResult, order string) {
 	getTS := func(r SearchResult) int64 {
-		switch v := r.Payload["timestamp"].(type) {
-		case int64:
-			return v
-		case float64:
-			return int64(v)
-		case int:
-			return int64(v)
+		return PayloadInt(r.Payload, "timestamp")
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		a, b := getTS(results[i]), getTS(results[j])
+		if a == b {
+			return results[i].ID < results[j].ID
 		}
-		return 0
-	}
-
-	// Insertion sort — result sets are small.
-	for i := 1; i < len(results); i++ {
-		for j := i; j > 0; j-- {
-			a, b := getTS(results[j-1]), getTS(results[j])
-			if order == "asc" && a > b {
-				results[j-1], results[j] = results[j], results[j-1]
-			} else if order != "asc" && a < b {
-				results[j-1], results[j] = results[j], results[j-1]
-			} else {
-				break
-			}
+		if order == "asc" {
+			return a < b
 		}
-	}
+		return a > b
+	})
 }
@@ -0,0 +1,886 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sipeed/picoclaw/pkg/config"
+	"github.com/sipeed/picoclaw/pkg/providers"
+	"github.com/sipeed/picoclaw/pkg/tokens"
+)
+
+// fakeVectorDB is an in-memory VectorDB that scores by dot product.
+type fakeVectorDB struct {
+	mu         sync.Mutex
+	records    map[string][]VectorRecord
+	batchCalls int
+	storeCalls int
+}
+
+func newFakeVectorDB() *fakeVectorDB {
+	return &fakeVectorDB{records: make(map[string][]VectorRecord)}
+}
+
+func (db *fakeVectorDB) Store(ctx context.Context, collection string, record VectorRecord) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	db.storeCalls++
+	db.upsert(collection, record)
+	return nil
+}
+
+func (db *fakeVectorDB) StoreBatch(ctx context.Context, collection string, records []VectorRecord) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	db.batchCalls++
+	for _, record := range records {
+		db.upsert(collection, record)
+	}
+	return nil
+}
+
+func (db *fakeVectorDB) upsert(collection string, record VectorRecord) {
+	for i, r := range db.records[collection] {
+		if r.ID == record.ID {
+			db.records[collection][i] = record
+			return
+		}
+	}
+	db.records[collection] = append(db.records[collection], record)
+}
+
+func (db *fakeVectorDB) matching(collection string, filters map[string]interface{}) []VectorRecord {
+	var out []VectorRecord
+	for _, r := range db.records[collection] {
+		ok := true
+		for k, v := range filters {
+			if r.Payload[k] != v {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+func (db *fakeVectorDB) Search(
+	ctx context.Context,
+	collection string,
+	vector []float32,
+	limit, offset int,
+	filters map[string]interface{},
+) ([]SearchResult, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	var results []SearchResult
+	for _, r := range db.matching(collection, filters) {
+		var score float32
+		for i := range vector {
+			if i < len(r.Vector) {
+				score += vector[i] * r.Vector[i]
+			}
+		}
+		results = append(results, SearchResult{ID: r.ID, Score: score, Payload: r.Payload})
+	}
+	sort.SliceStable(results, func(i, j int) bool { return results[i].Score > results[j].Score })
+
+	if offset >= len(results) {
+		return nil, nil
+	}
+	results = results[offset:]
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	return results, nil
+}
+
+func (db *fakeVectorDB) Scroll(
+	ctx context.Context,
+	collection, orderBy string,
+	limit int,
+	cursor string,
+	filters map[string]interface{},
+) ([]SearchResult, string, error) {
+	prev, err := ParseScrollCursor(cursor)
+	if err != nil {
+		return nil, "", err
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	seen := make(map[string]bool)
+	if prev != nil {
+		for _, id := range prev.Seen {
+			seen[id] = true
+		}
+	}
+
+	var results []SearchResult
+	for _, r := range db.matching(collection, filters) {
+		v := PayloadInt(r.Payload, orderBy)
+		if prev != nil && (v > prev.Value || seen[r.ID]) {
+			continue
+		}
+		results = append(results, SearchResult{ID: r.ID, Payload: r.Payload})
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		return PayloadInt(results[i].Payload, orderBy) > PayloadInt(results[j].Payload, orderBy)
+	})
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	return results, NextScrollCursor(results, orderBy, limit, prev), nil
+}
+
+func (db *fakeVectorDB) KeywordSearch(
+	ctx context.Context,
+	collection, text string,
+	limit int,
+	filters map[string]interface{},
+) ([]SearchResult, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	var results []SearchResult
+	for _, r := range db.matching(collection, filters) {
+		content, _ := r.Payload["content"].(string)
+		if strings.Contains(strings.ToLower(content), strings.ToLower(text)) {
+			results = append(results, SearchResult{ID: r.ID, Payload: r.Payload})
+		}
+		if len(results) == limit {
+			break
+		}
+	}
+	return results, nil
+}
+
+func (db *fakeVectorDB) Count(ctx context.Context, collection string, filters map[string]interface{}) (int, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	return len(db.matching(collection, filters)), nil
+}
+
+func (db *fakeVectorDB) Stats(
+	ctx context.Context,
+	collection string,
+	filters map[string]interface{},
+) (CollectionStats, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	var stats CollectionStats
+	for _, r := range db.matching(collection, filters) {
+		ts := PayloadInt(r.Payload, "timestamp")
+		if stats.Count == 0 || ts < stats.Oldest {
+			stats.Oldest = ts
+		}
+		if stats.Count == 0 || ts > stats.Newest {
+			stats.Newest = ts
+		}
+		stats.Count++
+	}
+	return stats, nil
+}
+
+func (db *fakeVectorDB) EnsureCollection(ctx context.Context, name string, dimension int) error {
+	return nil
+}
+
+func (db *fakeVectorDB) Close() error {
+	return nil
+}
+
+func (db *fakeVectorDB) count(collection string) int {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	return len(db.records[collection])
+}
+
+// fakeEmbedder maps known texts to fixed vectors and everything else to a default.
+type fakeEmbedder struct {
+	vectors map[string][]float32
+}
+
+func (e *fakeEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	if v, ok := e.vectors[text]; ok {
+		return v, nil
+	}
+	return []float32{1, 0}, nil
+}
+
+func (e *fakeEmbedder) Dimension() int {
+	return 2
+}
+
+func newTestManager(db VectorDB, embedder Embedder) *Manager {
+	return NewManager(config.MemoryConfig{Enabled: true}, db, embedder)
+}
+
+func storeChunk(t *testing.T, db *fakeVectorDB, id, content string, vector []float32) {
+	t.Helper()
+	err := db.Store(context.Background(), "picoclaw", VectorRecord{
+		ID:     id,
+		Vector: vector,
+		Payload: map[string]interface{}{
+			"workspace_id": "ws",
+			"session_id":   "s1",
+			"content":      content,
+		},
+	})
+	if err != nil {
+		t.Fatalf("Store: %v", err)
+	}
+}
+
+func TestHybridSearch_SurfacesExactToken(t *testing.T) {
+	db := newFakeVectorDB()
+	storeChunk(t, db, "a", "we talked about the weather", []float32{1, 0})
+	storeChunk(t, db, "b", "discussed lunch plans", []float32{0.9, 0.1})
+	storeChunk(t, db, "c", "the build failed with error XK-42", []float32{0, 1})
+
+	m := newTestManager(db, &fakeEmbedder{})
+	ctx := context.Background()
+
+	semantic, err := m.Search(ctx, "ws", "what caused XK-42", 2, 0)
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	for _, r := range semantic {
+		if r.ID == "c" {
+			t.Fatalf("expected vector-only search to miss chunk c")
+		}
+	}
+
+	hybrid, err := m.HybridSearch(ctx, "ws", "what caused XK-42", 2)
+	if err != nil {
+		t.Fatalf("HybridSearch: %v", err)
+	}
+	if len(hybrid) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(hybrid))
+	}
+	if hybrid[0].ID != "c" {
+		t.Errorf("expected chunk c ranked first, got %s", hybrid[0].ID)
+	}
+}
+
+func TestHybridSearch_DedupesByChunk(t *testing.T) {
+	db := newFakeVectorDB()
+	storeChunk(t, db, "a", "deploy token ABC123 rotated", []float32{1, 0})
+	storeChunk(t, db, "b", "unrelated chatter", []float32{0.5, 0.5})
+
+	m := newTestManager(db, &fakeEmbedder{})
+
+	results, err := m.HybridSearch(context.Background(), "ws", "ABC123 rotated", 10)
+	if err != nil {
+		t.Fatalf("HybridSearch: %v", err)
+	}
+	seen := make(map[string]bool)
+	for _, r := range results {
+		if seen[r.ID] {
+			t.Errorf("duplicate result %s", r.ID)
+		}
+		seen[r.ID] = true
+	}
+	if len(results) != 2 {
+		t.Errorf("expected 2 unique results, got %d", len(results))
+	}
+}
+
+func TestKeywordTerms(t *testing.T) {
+	got := keywordTerms("Is XK-42 an error? xk-42, ok")
+	want := []string{"XK-42", "error"}
+	if len(got) != len(want) {
+		t.Fatalf("keywordTerms = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("keywordTerms[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func testMessages() []providers.Message {
+	return []providers.Message{
+		{Role: "user", Content: "remind me what we decided about the garden"},
+		{Role: "assistant", Content: "we decided to plant tomatoes in spring"},
+	}
+}
+
+func TestArchiveSession_SkipsDuplicateChunks(t *testing.T) {
+	db := newFakeVectorDB()
+	m := newTestManager(db, &fakeEmbedder{})
+	ctx := context.Background()
+
+	if err := m.ArchiveSession(ctx, "ws", "s1", testMessages()); err != nil {
+		t.Fatalf("ArchiveSession: %v", err)
+	}
+	first := db.count("picoclaw")
+	if first == 0 {
+		t.Fatal("expected chunks to be stored")
+	}
+
+	if err := m.ArchiveSession(ctx, "ws", "s1", testMessages()); err != nil {
+		t.Fatalf("ArchiveSession (again): %v", err)
+	}
+	if got := db.count("picoclaw"); got != first {
+		t.Errorf("expected %d points after re-archive, got %d", first, got)
+	}
+}
+
+func TestArchiveSession_StoresChunksInOneBatch(t *testing.T) {
+	db := newFakeVectorDB()
+	m := NewManager(config.MemoryConfig{
+		Enabled:   true,
+		Embedding: config.EmbeddingConfig{ChunkSize: 40},
+	}, db, &fakeEmbedder{})
+
+	if err := m.ArchiveSession(context.Background(), "ws", "s1", testMessages()); err != nil {
+		t.Fatalf("ArchiveSession: %v", err)
+	}
+	if got := db.count("picoclaw"); got < 2 {
+		t.Fatalf("expected multiple chunks, got %d", got)
+	}
+	if db.batchCalls != 1 || db.storeCalls != 0 {
+		t.Errorf("expected 1 batch and 0 single stores, got %d batches and %d stores", db.batchCalls, db.storeCalls)
+	}
+}
+
+func TestArchiveSession_ForceStoresDuplicates(t *testing.T) {
+	db := newFakeVectorDB()
+	m := newTestManager(db, &fakeEmbedder{})
+	ctx := context.Background()
+
+	if err := m.ArchiveSession(ctx, "ws", "s1", testMessages()); err != nil {
+		t.Fatalf("ArchiveSession: %v", err)
+	}
+	first := db.count("picoclaw")
+
+	opts := ArchiveOptions{Force: true}
+	if err := m.ArchiveSessionWithOptions(ctx, "ws", "s1", testMessages(), opts); err != nil {
+		t.Fatalf("ArchiveSessionWithOptions: %v", err)
+	}
+	if got := db.count("picoclaw"); got != 2*first {
+		t.Errorf("expected %d points with force, got %d", 2*first, got)
+	}
+}
+
+func TestArchiveSession_DedupIsPerWorkspace(t *testing.T) {
+	db := newFakeVectorDB()
+	m := newTestManager(db, &fakeEmbedder{})
+	ctx := context.Background()
+
+	if err := m.ArchiveSession(ctx, "ws", "s1", testMessages()); err != nil {
+		t.Fatalf("ArchiveSession: %v", err)
+	}
+	first := db.count("picoclaw")
+
+	if err := m.ArchiveSession(ctx, "other", "s1", testMessages()); err != nil {
+		t.Fatalf("ArchiveSession (other workspace): %v", err)
+	}
+	if got := db.count("picoclaw"); got != 2*first {
+		t.Errorf("expected other workspace to store its own copy, got %d points", got)
+	}
+}
+
+func TestArchiveSession_StoresMetadataAndFiltersSearch(t *testing.T) {
+	db := newFakeVectorDB()
+	m := newTestManager(db, &fakeEmbedder{})
+	ctx := context.Background()
+
+	discord := ArchiveOptions{Metadata: map[string]string{
+		MetadataChannel: "discord",
+		MetadataUserID:  "alice",
+		"workspace_id":  "spoofed",
+	}}
+	err := m.ArchiveSessionWithOptions(ctx, "ws", "s1", []providers.Message{
+		{Role: "user", Content: "discord chat about raids"},
+	}, discord)
+	if err != nil {
+		t.Fatalf("ArchiveSessionWithOptions: %v", err)
+	}
+
+	telegram := ArchiveOptions{Metadata: map[string]string{MetadataChannel: "telegram"}}
+	err = m.ArchiveSessionWithOptions(ctx, "ws", "s2", []providers.Message{
+		{Role: "user", Content: "telegram chat about groceries"},
+	}, telegram)
+	if err != nil {
+		t.Fatalf("ArchiveSessionWithOptions: %v", err)
+	}
+
+	all, err := m.Search(ctx, "ws", "chat", 10, 0)
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(all) != 2 {
+		t.Fatalf("expected 2 results without filter, got %d", len(all))
+	}
+
+	opts := SearchOptions{Metadata: map[string]string{MetadataChannel: "discord"}}
+	filtered, err := m.SearchWithOptions(ctx, "ws", "chat", 10, opts)
+	if err != nil {
+		t.Fatalf("SearchWithOptions: %v", err)
+	}
+	if len(filtered) != 1 {
+		t.Fatalf("expected 1 discord result, got %d", len(filtered))
+	}
+	if got := filtered[0].Payload[MetadataUserID]; got != "alice" {
+		t.Errorf("expected user_id alice, got %v", got)
+	}
+	if got := filtered[0].Payload["workspace_id"]; got != "ws" {
+		t.Errorf("expected reserved workspace_id to be kept, got %v", got)
+	}
+}
+
+func storeDatedChunk(t *testing.T, db *fakeVectorDB, id string, timestamp int64, vector []float32) {
+	t.Helper()
+	err := db.Store(context.Background(), "picoclaw", VectorRecord{
+		ID:     id,
+		Vector: vector,
+		Payload: map[string]interface{}{
+			"workspace_id": "ws",
+			"session_id":   id,
+			"content":      "chunk " + id,
+			"timestamp":    timestamp,
+		},
+	})
+	if err != nil {
+		t.Fatalf("Store: %v", err)
+	}
+}
+
+func TestSearch_PagesAreDistinct(t *testing.T) {
+	db := newFakeVectorDB()
+	for i, id := range []string{"a", "b", "c", "d", "e", "f"} {
+		storeDatedChunk(t, db, id, int64(i), []float32{1 - float32(i)/10, 0})
+	}
+	m := newTestManager(db, &fakeEmbedder{})
+	ctx := context.Background()
+
+	page1, err := m.Search(ctx, "ws", "anything", 3, 0)
+	if err != nil {
+		t.Fatalf("Search page 1: %v", err)
+	}
+	page2, err := m.Search(ctx, "ws", "anything", 3, 3)
+	if err != nil {
+		t.Fatalf("Search page 2: %v", err)
+	}
+	assertDistinctPages(t, page1, page2, 3)
+}
+
+func TestSearchByDate_PagesAreDistinctAndOrdered(t *testing.T) {
+	db := newFakeVectorDB()
+	// Two chunks share a timestamp to exercise the ID tie-break.
+	timestamps := map[string]int64{"a": 100, "b": 300, "c": 200, "d": 300, "e": 50, "f": 400}
+	for id, ts := range timestamps {
+		storeDatedChunk(t, db, id, ts, []float32{1, 0})
+	}
+	m := newTestManager(db, &fakeEmbedder{})
+	ctx := context.Background()
+
+	page1, err := m.SearchByDate(ctx, "ws", "anything", 3, 0, "desc")
+	if err != nil {
+		t.Fatalf("SearchByDate page 1: %v", err)
+	}
+	page2, err := m.SearchByDate(ctx, "ws", "anything", 3, 3, "desc")
+	if err != nil {
+		t.Fatalf("SearchByDate page 2: %v", err)
+	}
+	assertDistinctPages(t, page1, page2, 3)
+
+	var got []string
+	for _, r := range append(page1, page2...) {
+		got = append(got, r.ID)
+	}
+	want := []string{"f", "b", "d", "c", "a", "e"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("date order = %v, want %v", got, want)
+		}
+	}
+
+	page3, err := m.SearchByDate(ctx, "ws", "anything", 3, 6, "desc")
+	if err != nil {
+		t.Fatalf("SearchByDate page 3: %v", err)
+	}
+	if len(page3) != 0 {
+		t.Errorf("expected empty page past the end, got %d results", len(page3))
+	}
+}
+
+func TestSearchByDate_PagesPastFirstBatch(t *testing.T) {
+	db := newFakeVectorDB()
+	for i := 0; i < 250; i++ {
+		storeDatedChunk(t, db, fmt.Sprintf("related-%03d", i), int64(i), []float32{1, 0})
+	}
+	// Unrelated chunks are the newest but must never be returned.
+	for i := 0; i < 5; i++ {
+		storeDatedChunk(t, db, fmt.Sprintf("unrelated-%d", i), int64(1000+i), []float32{0, 1})
+	}
+	m := newTestManager(db, &fakeEmbedder{})
+
+	page, err := m.SearchByDate(context.Background(), "ws", "anything", 5, 240, "desc")
+	if err != nil {
+		t.Fatalf("SearchByDate: %v", err)
+	}
+	var got []string
+	for _, r := range page {
+		got = append(got, r.ID)
+	}
+	want := []string{"related-009", "related-008", "related-007", "related-006", "related-005"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("page at offset 240 = %v, want %v", got, want)
+	}
+}
+
+// searchCountingDB counts Search calls on a fakeVectorDB.
+type searchCountingDB struct {
+	*fakeVectorDB
+	searches int
+}
+
+func (db *searchCountingDB) Search(
+	ctx context.Context,
+	collection string,
+	vector []float32,
+	limit, offset int,
+	filters map[string]interface{},
+) ([]SearchResult, error) {
+	db.searches++
+	return db.fakeVectorDB.Search(ctx, collection, vector, limit, offset, filters)
+}
+
+func TestSearchByDate_CapsPagesRead(t *testing.T) {
+	db := &searchCountingDB{fakeVectorDB: newFakeVectorDB()}
+	// Every chunk scores 0, so none falls below the relevance floor.
+	total := dateSearchBatchSize*dateSearchMaxPages + 50
+	for i := 0; i < total; i++ {
+		storeDatedChunk(t, db.fakeVectorDB, fmt.Sprintf("chunk-%04d", i), int64(i), []float32{0, 1})
+	}
+	m := newTestManager(db, &fakeEmbedder{})
+
+	limit := dateSearchBatchSize * dateSearchMaxPages
+	results, err := m.SearchByDate(context.Background(), "ws", "anything", limit+10, 0, "desc")
+	if err != nil {
+		t.Fatalf("SearchByDate: %v", err)
+	}
+	if db.searches != dateSearchMaxPages {
+		t.Errorf("Search calls = %d, want %d", db.searches, dateSearchMaxPages)
+	}
+	if len(results) != limit {
+		t.Errorf("got %d results, want the %d candidates read", len(results), limit)
+	}
+}
+
+func assertDistinctPages(t *testing.T, page1, page2 []SearchResult, size int) {
+	t.Helper()
+	if len(page1) != size || len(page2) != size {
+		t.Fatalf("expected two pages of %d, got %d and %d", size, len(page1), len(page2))
+	}
+	seen := make(map[string]bool)
+	for _, r := range page1 {
+		seen[r.ID] = true
+	}
+	for _, r := range page2 {
+		if seen[r.ID] {
+			t.Errorf("result %s appears on both pages", r.ID)
+		}
+	}
+}
+
+func TestSearchWithOptions_MinScore(t *testing.T) {
+	db := newFakeVectorDB()
+	storeChunk(t, db, "high", "very relevant", []float32{0.9, 0})
+	storeChunk(t, db, "mid", "somewhat relevant", []float32{0.5, 0})
+	storeChunk(t, db, "low", "barely relevant", []float32{0.1, 0})
+	m := newTestManager(db, &fakeEmbedder{})
+	ctx := context.Background()
+
+	results, err := m.SearchWithOptions(ctx, "ws", "query", 10, SearchOptions{MinScore: 0.4})
+	if err != nil {
+		t.Fatalf("SearchWithOptions: %v", err)
+	}
+	if len(results) != 2 || results[0].ID != "high" || results[1].ID != "mid" {
+		t.Fatalf("expected [high mid] above threshold, got %v", results)
+	}
+
+	results, err = m.SearchWithOptions(ctx, "ws", "query", 10, SearchOptions{MinScore: 0.95})
+	if err != nil {
+		t.Fatalf("SearchWithOptions: %v", err)
+	}
+	if len(results) != 0 {
+		t.Errorf("expected everything filtered out, got %d results", len(results))
+	}
+
+	results, err = m.Search(ctx, "ws", "query", 10, 0)
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(results) != 3 {
+		t.Errorf("expected plain Search to apply no threshold, got %d results", len(results))
+	}
+}
+
+func TestListSessions_PagesThroughAllRecords(t *testing.T) {
+	db := newFakeVectorDB()
+	// Chunks from one archive share a timestamp, so include ties that
+	// straddle page boundaries.
+	timestamps := map[string]int64{"a": 100, "b": 200, "c": 200, "d": 200, "e": 300, "f": 50}
+	for id, ts := range timestamps {
+		storeDatedChunk(t, db, id, ts, []float32{1, 0})
+	}
+	db.Store(context.Background(), "picoclaw", VectorRecord{
+		ID:      "other-ws",
+		Payload: map[string]interface{}{"workspace_id": "other", "timestamp": int64(400)},
+	})
+
+	m := newTestManager(db, &fakeEmbedder{})
+	ctx := context.Background()
+
+	var listed []SearchResult
+	cursor := ""
+	for pages := 0; ; pages++ {
+		if pages > len(timestamps) {
+			t.Fatal("paging did not terminate")
+		}
+		page, next, err := m.ListSessions(ctx, "ws", 2, cursor)
+		if err != nil {
+			t.Fatalf("ListSessions: %v", err)
+		}
+		listed = append(listed, page...)
+		if next == "" {
+			break
+		}
+		cursor = next
+	}
+
+	if len(listed) != len(timestamps) {
+		t.Fatalf("expected %d records, got %d", len(timestamps), len(listed))
+	}
+	seen := make(map[string]bool)
+	for i, r := range listed {
+		if seen[r.ID] {
+			t.Errorf("record %s listed twice", r.ID)
+		}
+		seen[r.ID] = true
+		if i > 0 && PayloadInt(r.Payload, "timestamp") > PayloadInt(listed[i-1].Payload, "timestamp") {
+			t.Errorf("records not newest-first at %d: %v", i, listed)
+		}
+	}
+}
+
+func TestParseScrollCursor_RoundTrip(t *testing.T) {
+	c, err := ParseScrollCursor((&ScrollCursor{Value: 42, Seen: []string{"a", "b"}}).String())
+	if err != nil {
+		t.Fatalf("ParseScrollCursor: %v", err)
+	}
+	if c.Value != 42 || strings.Join(c.Seen, ",") != "a,b" {
+		t.Errorf("unexpected cursor: %+v", c)
+	}
+
+	if c, err := ParseScrollCursor(""); c != nil || err != nil {
+		t.Errorf("empty cursor = %+v, %v; want nil, nil", c, err)
+	}
+	if _, err := ParseScrollCursor("garbage"); err == nil {
+		t.Error("expected error for malformed cursor")
+	}
+}
+
+// recordingEmbedder remembers every text it embeds.
+type recordingEmbedder struct {
+	fakeEmbedder
+	mu    sync.Mutex
+	texts []string
+}
+
+func (e *recordingEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	e.mu.Lock()
+	e.texts = append(e.texts, text)
+	e.mu.Unlock()
+	return e.fakeEmbedder.Embed(ctx, text)
+}
+
+func (e *recordingEmbedder) take() string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	all := strings.Join(e.texts, "\n")
+	e.texts = nil
+	return all
+}
+
+func TestArchiveIncremental_EmbedsOnlyNewMessages(t *testing.T) {
+	ctx := context.Background()
+	db := NewInMemoryVectorDB()
+	embedder := &recordingEmbedder{}
+	m := newTestManager(db, embedder)
+
+	session := []providers.Message{
+		{Role: "system", Content: "you are helpful"},
+		{Role: "user", Content: "first question"},
+		{Role: "assistant", Content: "first answer"},
+	}
+	if err := m.ArchiveIncremental(ctx, "ws", "s1", session); err != nil {
+		t.Fatalf("ArchiveIncremental: %v", err)
+	}
+	if got := embedder.take(); !strings.Contains(got, "first question") || !strings.Contains(got, "first answer") {
+		t.Errorf("first archive embedded %q", got)
+	}
+
+	session = append(session,
+		providers.Message{Role: "user", Content: "second question"},
+		providers.Message{Role: "assistant", Content: "second answer"},
+	)
+	if err := m.ArchiveIncremental(ctx, "ws", "s1", session); err != nil {
+		t.Fatalf("ArchiveIncremental (grown): %v", err)
+	}
+	got := embedder.take()
+	if !strings.Contains(got, "second question") || !strings.Contains(got, "second answer") {
+		t.Errorf("second archive did not embed the new messages: %q", got)
+	}
+	if strings.Contains(got, "first") {
+		t.Errorf("second archive re-embedded old messages: %q", got)
+	}
+
+	// Nothing new: nothing embedded.
+	if err := m.ArchiveIncremental(ctx, "ws", "s1", session); err != nil {
+		t.Fatalf("ArchiveIncremental (unchanged): %v", err)
+	}
+	if got := embedder.take(); got != "" {
+		t.Errorf("unchanged session embedded %q", got)
+	}
+
+	// A fresh Manager recovers the offset from the stored payloads.
+	restarted := newTestManager(db, embedder)
+	session = append(session, providers.Message{Role: "user", Content: "third question"})
+	if err := restarted.ArchiveIncremental(ctx, "ws", "s1", session); err != nil {
+		t.Fatalf("ArchiveIncremental (restarted): %v", err)
+	}
+	if got := embedder.take(); got != "user: third question\n" {
+		t.Errorf("restarted archive embedded %q, want only the third question", got)
+	}
+
+	if n, _ := db.Count(ctx, "picoclaw", map[string]interface{}{"session_id": "s1"}); n != 3 {
+		t.Errorf("stored %d chunks, want 3", n)
+	}
+}
+
+// blockingEmbedder blocks embedding text containing "slow" until released.
+type blockingEmbedder struct {
+	fakeEmbedder
+	started chan struct{}
+	release chan struct{}
+}
+
+func (e *blockingEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	if strings.Contains(text, "slow") {
+		close(e.started)
+		<-e.release
+	}
+	return e.fakeEmbedder.Embed(ctx, text)
+}
+
+func TestArchiveIncremental_SessionsDoNotBlockEachOther(t *testing.T) {
+	ctx := context.Background()
+	embedder := &blockingEmbedder{started: make(chan struct{}), release: make(chan struct{})}
+	m := newTestManager(NewInMemoryVectorDB(), embedder)
+	if m.archiveLock("ws\x00s1") == m.archiveLock("ws\x00s2") {
+		t.Fatal("test sessions share a lock shard; pick other session IDs")
+	}
+
+	slowDone := make(chan error, 1)
+	go func() {
+		slowDone <- m.ArchiveIncremental(ctx, "ws", "s1", []providers.Message{{Role: "user", Content: "slow"}})
+	}()
+	<-embedder.started
+
+	fastDone := make(chan error, 1)
+	go func() {
+		fastDone <- m.ArchiveIncremental(ctx, "ws", "s2", []providers.Message{{Role: "user", Content: "fast"}})
+	}()
+	select {
+	case err := <-fastDone:
+		if err != nil {
+			t.Fatalf("ArchiveIncremental(s2): %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("archiving one session waited for another session's embedding")
+	}
+
+	close(embedder.release)
+	if err := <-slowDone; err != nil {
+		t.Fatalf("ArchiveIncremental(s1): %v", err)
+	}
+}
+
+func TestCollectionPerWorkspace(t *testing.T) {
+	ctx := context.Background()
+	db := NewInMemoryVectorDB()
+	m := NewManager(config.MemoryConfig{
+		Enabled:                true,
+		Qdrant:                 config.QdrantConfig{CollectionName: "mem"},
+		CollectionPerWorkspace: true,
+	}, db, &fakeEmbedder{})
+
+	for _, ws := range []string{"home", "work/team"} {
+		if err := m.ArchiveSession(ctx, ws, "s1", testMessages()); err != nil {
+			t.Fatalf("ArchiveSession(%s): %v", ws, err)
+		}
+	}
+
+	for _, collection := range []string{"mem_home", "mem_work_team"} {
+		if n, _ := db.Count(ctx, collection, nil); n != 1 {
+			t.Errorf("collection %s has %d chunks, want 1", collection, n)
+		}
+	}
+	if n, _ := db.Count(ctx, "mem", nil); n != 0 {
+		t.Errorf("shared collection has %d chunks, want 0", n)
+	}
+
+	results, err := m.Search(ctx, "home", "garden", 10, 0)
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(results) != 1 || results[0].Payload["workspace_id"] != "home" {
+		t.Errorf("Search(home) = %+v, want the home chunk only", results)
+	}
+	if stats, _ := m.Stats(ctx, "work/team"); stats.Count != 1 {
+		t.Errorf("Stats(work/team).Count = %d, want 1", stats.Count)
+	}
+
+	// Without the option both workspaces share one collection.
+	shared := NewManager(config.MemoryConfig{Enabled: true, Qdrant: config.QdrantConfig{CollectionName: "mem"}}, db, &fakeEmbedder{})
+	if shared.collectionName("home") != shared.collectionName("work/team") {
+		t.Error("workspaces must share a collection when the option is off")
+	}
+}
+
+func TestArchiveSession_ChunksByTokens(t *testing.T) {
+	db := newFakeVectorDB()
+	m := NewManager(config.MemoryConfig{
+		Enabled:   true,
+		Embedding: config.EmbeddingConfig{Model: "text-embedding-3-small", ChunkTokens: 50},
+	}, db, &fakeEmbedder{})
+
+	messages := []providers.Message{{Role: "user", Content: strings.Repeat("we planted tomatoes and basil in spring. ", 30)}}
+	if err := m.ArchiveSession(context.Background(), "ws", "s1", messages); err != nil {
+		t.Fatalf("ArchiveSession: %v", err)
+	}
+
+	if got := db.count("picoclaw"); got < 5 {
+		t.Fatalf("expected the session to be split into several chunks, got %d", got)
+	}
+	for _, r := range db.records["picoclaw"] {
+		content, _ := r.Payload["content"].(string)
+		if n := tokens.Count("text-embedding-3-small", content); n > 55 {
+			t.Errorf("chunk has %d tokens, want about 50 or fewer", n)
+		}
+	}
+}
@@ -5,29 +5,171 @@ import (
 	"fmt"
 	"net/url"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/qdrant/go-client/qdrant"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
 	"github.com/sipeed/picoclaw/pkg/memory"
 )
 
+// pointsClient is the subset of *qdrant.Client used by Client, so tests can
+// substitute a fake.
+type pointsClient interface {
+	Upsert(ctx context.Context, request *qdrant.UpsertPoints) (*qdrant.UpdateResult, error)
+	Query(ctx context.Context, request *qdrant.QueryPoints) ([]*qdrant.ScoredPoint, error)
+	Scroll(ctx context.Context, request *qdrant.ScrollPoints) ([]*qdrant.RetrievedPoint, error)
+	Count(ctx context.Context, request *qdrant.CountPoints) (uint64, error)
+	ListCollections(ctx context.Context) ([]string, error)
+	CreateCollection(ctx context.Context, request *qdrant.CreateCollection) error
+	CreateFieldIndex(ctx context.Context, request *qdrant.CreateFieldIndexCollection) (*qdrant.UpdateResult, error)
+	HealthCheck(ctx context.Context) (*qdrant.HealthCheckReply, error)
+	Close() error
+}
+
 type Client struct {
-	client *qdrant.Client
+	mu     sync.RWMutex
+	client pointsClient
+	dial   func() (pointsClient, error)
 }
 
+// Options controls how the client verifies and re-establishes its
+// connection.
+type Options struct {
+	// ConnectRetries is the number of additional health checks made at
+	// construction after the first one fails.
+	ConnectRetries int
+	// ConnectBackoff is the delay before the first retry; it doubles after
+	// each failed attempt.
+	ConnectBackoff time.Duration
+	// PingTimeout bounds each individual health check.
+	PingTimeout time.Duration
+}
+
+// DefaultOptions returns the options used by NewClient.
+func DefaultOptions() Options {
+	return Options{
+		ConnectRetries: 3,
+		ConnectBackoff: 500 * time.Millisecond,
+		PingTimeout:    5 * time.Second,
+	}
+}
+
+// NewClient connects to Qdrant using DefaultOptions.
 func NewClient(rawURL, apiKey string) (*Client, error) {
+	return NewClientWithOptions(rawURL, apiKey, DefaultOptions())
+}
+
+// NewClientWithOptions connects to Qdrant and health-checks the connection,
+// retrying with exponential backoff so an unreachable server is reported at
+// startup rather than on the first archive.
+func NewClientWithOptions(rawURL, apiKey string, opts Options) (*Client, error) {
 	host, port, useTLS := ParseAddress(rawURL)
 
-	client, err := qdrant.NewClient(&qdrant.Config{
-		Host:   host,
-		Port:   port,
-		UseTLS: useTLS,
-		APIKey: apiKey,
-	})
+	dial := func() (pointsClient, error) {
+		client, err := qdrant.NewClient(&qdrant.Config{
+			Host:   host,
+			Port:   port,
+			UseTLS: useTLS,
+			APIKey: apiKey,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create qdrant client: %w", err)
+		}
+		return client, nil
+	}
+
+	c, err := newClient(dial, opts)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create qdrant client: %w", err)
+		return nil, fmt.Errorf("qdrant at %s is unreachable: %w", rawURL, err)
 	}
+	return c, nil
+}
 
-	return &Client{client: client}, nil
+func newClient(dial func() (pointsClient, error), opts Options) (*Client, error) {
+	client, err := dial()
+	if err != nil {
+		return nil, err
+	}
+	c := &Client{client: client, dial: dial}
+
+	backoff := opts.ConnectBackoff
+	for attempt := 0; ; attempt++ {
+		ctx, cancel := pingContext(opts.PingTimeout)
+		err = c.Ping(ctx)
+		cancel()
+		if err == nil {
+			return c, nil
+		}
+		if attempt >= opts.ConnectRetries {
+			client.Close()
+			return nil, fmt.Errorf("health check failed after %d attempts: %w", attempt+1, err)
+		}
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+func pingContext(timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(context.Background())
+	}
+	return context.WithTimeout(context.Background(), timeout)
+}
+
+// Ping checks that Qdrant is reachable.
+func (c *Client) Ping(ctx context.Context) error {
+	if _, err := c.current().HealthCheck(ctx); err != nil {
+		return fmt.Errorf("failed to ping qdrant: %w", err)
+	}
+	return nil
+}
+
+func (c *Client) current() pointsClient {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.client
+}
+
+// withReconnect runs fn and, if it fails because the connection is gone,
+// redials once and retries.
+func (c *Client) withReconnect(fn func(pointsClient) error) error {
+	client := c.current()
+	err := fn(client)
+	if err == nil || !isConnectionError(err) || c.dial == nil {
+		return err
+	}
+
+	client, rerr := c.reconnect(client)
+	if rerr != nil {
+		return fmt.Errorf("%w (reconnect failed: %v)", err, rerr)
+	}
+	return fn(client)
+}
+
+// reconnect replaces stale with a freshly dialed client unless another
+// caller has already done so.
+func (c *Client) reconnect(stale pointsClient) (pointsClient, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.client != stale {
+		return c.client, nil
+	}
+
+	client, err := c.dial()
+	if err != nil {
+		return nil, err
+	}
+	stale.Close()
+	c.client = client
+	return client, nil
+}
+
+func isConnectionError(err error) bool {
+	return status.Code(err) == codes.Unavailable
 }
 
 func ParseAddress(rawURL string) (string, int, bool) {
@@ -70,16 +212,13 @@ func ParseAddress(rawURL string) (string, int, bool) {
 func (c *Client) Store(ctx context.Context, collection string, record memory.VectorRecord) error {
 	upsertPoints := &qdrant.UpsertPoints{
 		CollectionName: collection,
-		Points: []*qdrant.PointStruct{
-			{
-				Id:      qdrant.NewID(record.ID),
-				Vectors: qdrant.NewVectors(record.Vector...),
-				Payload: qdrant.NewValueMap(record.Payload),
-			},
-		},
+		Points:         []*qdrant.PointStruct{newPoint(record)},
 	}
 
-	_, err := c.client.Upsert(ctx, upsertPoints)
+	err := c.withReconnect(func(client pointsClient) error {
+		_, err := client.Upsert(ctx, upsertPoints)
+		return err
+	})
 	if err != nil {
 		return fmt.Errorf("failed to upsert point: %w", err)
 	}
@@ -87,6 +226,39 @@ func (c *Client) Store(ctx context.Context, collection string, record memory.Vec
 	return nil
 }
 
+// StoreBatch upserts all records in a single request.
+func (c *Client) StoreBatch(ctx context.Context, collection string, records []memory.VectorRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	points := make([]*qdrant.PointStruct, len(records))
+	for i, record := range records {
+		points[i] = newPoint(record)
+	}
+
+	err := c.withReconnect(func(client pointsClient) error {
+		_, err := client.Upsert(ctx, &qdrant.UpsertPoints{
+			CollectionName: collection,
+			Points:         points,
+		})
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to upsert %d points: %w", len(points), err)
+	}
+
+	return nil
+}
+
+func newPoint(record memory.VectorRecord) *qdrant.PointStruct {
+	return &qdrant.PointStruct{
+		Id:      qdrant.NewID(record.ID),
+		Vectors: qdrant.NewVectors(record.Vector...),
+		Payload: qdrant.NewValueMap(record.Payload),
+	}
+}
+
 func (c *Client) Search(ctx context.Context, collection string, vector []float32, limit, offset int, filters map[string]interface{}) ([]memory.SearchResult, error) {
 	queryPoints := &qdrant.QueryPoints{
 		CollectionName: collection,
@@ -96,24 +268,21 @@ func (c *Client) Search(ctx context.Context, collection string, vector []float32
 	}
 
 	// 1. Handle Filters
-	if len(filters) > 0 {
-		var must []*qdrant.Condition
-		for k, v := range filters {
-			if s, ok := v.(string); ok {
-				must = append(must, qdrant.NewMatch(k, s))
-			}
-		}
-		if len(must) > 0 {
-			queryPoints.Filter = &qdrant.Filter{
-				Must: must,
-			}
+	if must := buildConditions(filters); len(must) > 0 {
+		queryPoints.Filter = &qdrant.Filter{
+			Must: must,
 		}
 	}
 
 	// 2. Vector search
 	queryPoints.Query = qdrant.NewQueryNearest(qdrant.NewVectorInput(vector...))
 
-	resp, err := c.client.Query(ctx, queryPoints)
+	var resp []*qdrant.ScoredPoint
+	err := c.withReconnect(func(client pointsClient) error {
+		var err error
+		resp, err = client.Query(ctx, queryPoints)
+		return err
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to query points: %w", err)
 	}
@@ -130,32 +299,224 @@ func (c *Client) Search(ctx context.Context, collection string, vector []float32
 	return results, nil
 }
 
+func (c *Client) KeywordSearch(ctx context.Context, collection, text string, limit int, filters map[string]interface{}) ([]memory.SearchResult, error) {
+	must := append(buildConditions(filters), qdrant.NewMatchText("content", text))
+
+	var resp []*qdrant.RetrievedPoint
+	err := c.withReconnect(func(client pointsClient) error {
+		var err error
+		resp, err = client.Scroll(ctx, &qdrant.ScrollPoints{
+			CollectionName: collection,
+			Filter:         &qdrant.Filter{Must: must},
+			Limit:          qdrant.PtrOf(uint32(limit)),
+			WithPayload:    qdrant.NewWithPayload(true),
+		})
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scroll points: %w", err)
+	}
+
+	results := make([]memory.SearchResult, len(resp))
+	for i, r := range resp {
+		results[i] = memory.SearchResult{
+			ID:      r.Id.String(),
+			Payload: convertPayload(r.Payload),
+		}
+	}
+
+	return results, nil
+}
+
+func (c *Client) Scroll(
+	ctx context.Context,
+	collection, orderBy string,
+	limit int,
+	cursor string,
+	filters map[string]interface{},
+) ([]memory.SearchResult, string, error) {
+	prev, err := memory.ParseScrollCursor(cursor)
+	if err != nil {
+		return nil, "", err
+	}
+
+	filter := &qdrant.Filter{Must: buildConditions(filters)}
+	if prev != nil {
+		// Ordered scrolls don't return a next-page offset, so resume from
+		// the last value and exclude the points already returned at it.
+		filter.Must = append(filter.Must, qdrant.NewRange(orderBy, &qdrant.Range{
+			Lte: qdrant.PtrOf(float64(prev.Value)),
+		}))
+		if len(prev.Seen) > 0 {
+			ids := make([]*qdrant.PointId, len(prev.Seen))
+			for i, id := range prev.Seen {
+				ids[i] = qdrant.NewID(id)
+			}
+			filter.MustNot = []*qdrant.Condition{qdrant.NewHasID(ids...)}
+		}
+	}
+
+	desc := qdrant.Direction_Desc
+	var resp []*qdrant.RetrievedPoint
+	err = c.withReconnect(func(client pointsClient) error {
+		var err error
+		resp, err = client.Scroll(ctx, &qdrant.ScrollPoints{
+			CollectionName: collection,
+			Filter:         filter,
+			Limit:          qdrant.PtrOf(uint32(limit)),
+			OrderBy:        &qdrant.OrderBy{Key: orderBy, Direction: &desc},
+			WithPayload:    qdrant.NewWithPayload(true),
+		})
+		return err
+	})
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to scroll points: %w", err)
+	}
+
+	results := make([]memory.SearchResult, len(resp))
+	for i, r := range resp {
+		results[i] = memory.SearchResult{
+			ID:      pointIDString(r.Id),
+			Payload: convertPayload(r.Payload),
+		}
+	}
+
+	return results, memory.NextScrollCursor(results, orderBy, limit, prev), nil
+}
+
+// pointIDString returns the UUID or numeric form of a point ID, suitable for
+// passing back to qdrant.NewID.
+func pointIDString(id *qdrant.PointId) string {
+	if uuid := id.GetUuid(); uuid != "" {
+		return uuid
+	}
+	return fmt.Sprintf("%d", id.GetNum())
+}
+
+func (c *Client) Count(ctx context.Context, collection string, filters map[string]interface{}) (int, error) {
+	countPoints := &qdrant.CountPoints{
+		CollectionName: collection,
+		Exact:          qdrant.PtrOf(true),
+	}
+	if must := buildConditions(filters); len(must) > 0 {
+		countPoints.Filter = &qdrant.Filter{Must: must}
+	}
+
+	var count uint64
+	err := c.withReconnect(func(client pointsClient) error {
+		var err error
+		count, err = client.Count(ctx, countPoints)
+		return err
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to count points: %w", err)
+	}
+
+	return int(count), nil
+}
+
+// Stats counts the matching points and reads the oldest and newest
+// timestamps with two single-point ordered scrolls.
+func (c *Client) Stats(
+	ctx context.Context,
+	collection string,
+	filters map[string]interface{},
+) (memory.CollectionStats, error) {
+	count, err := c.Count(ctx, collection, filters)
+	if err != nil || count == 0 {
+		return memory.CollectionStats{}, err
+	}
+
+	stats := memory.CollectionStats{Count: count}
+	for _, dir := range []qdrant.Direction{qdrant.Direction_Asc, qdrant.Direction_Desc} {
+		req := &qdrant.ScrollPoints{
+			CollectionName: collection,
+			Limit:          qdrant.PtrOf(uint32(1)),
+			OrderBy:        &qdrant.OrderBy{Key: "timestamp", Direction: &dir},
+			WithPayload:    qdrant.NewWithPayload(true),
+		}
+		if must := buildConditions(filters); len(must) > 0 {
+			req.Filter = &qdrant.Filter{Must: must}
+		}
+		var points []*qdrant.RetrievedPoint
+		err := c.withReconnect(func(client pointsClient) error {
+			var err error
+			points, err = client.Scroll(ctx, req)
+			return err
+		})
+		if err != nil {
+			return memory.CollectionStats{}, fmt.Errorf("failed to scroll points: %w", err)
+		}
+		if len(points) == 0 {
+			continue
+		}
+		ts := memory.PayloadInt(convertPayload(points[0].Payload), "timestamp")
+		if dir == qdrant.Direction_Asc {
+			stats.Oldest = ts
+		} else {
+			stats.Newest = ts
+		}
+	}
+	return stats, nil
+}
+
+// buildConditions converts exact-match filters into Qdrant conditions.
+func buildConditions(filters map[string]interface{}) []*qdrant.Condition {
+	var must []*qdrant.Condition
+	for k, v := range filters {
+		if s, ok := v.(string); ok {
+			must = append(must, qdrant.NewMatch(k, s))
+		}
+	}
+	return must
+}
+
 func convertPayload(p map[string]*qdrant.Value) map[string]interface{} {
 	if p == nil {
 		return nil
 	}
 	res := make(map[string]interface{})
 	for k, v := range p {
-		switch kind := v.GetKind().(type) {
-		case *qdrant.Value_StringValue:
-			res[k] = kind.StringValue
-		case *qdrant.Value_DoubleValue:
-			res[k] = kind.DoubleValue
-		case *qdrant.Value_IntegerValue:
-			res[k] = kind.IntegerValue
-		case *qdrant.Value_BoolValue:
-			res[k] = kind.BoolValue
-		case *qdrant.Value_NullValue:
-			res[k] = nil
-		default:
-			res[k] = v.String()
-		}
+		res[k] = convertValue(v)
 	}
 	return res
 }
 
+// convertValue converts a Qdrant value into its plain Go equivalent,
+// recursing into lists and structs.
+func convertValue(v *qdrant.Value) interface{} {
+	switch kind := v.GetKind().(type) {
+	case *qdrant.Value_StringValue:
+		return kind.StringValue
+	case *qdrant.Value_DoubleValue:
+		return kind.DoubleValue
+	case *qdrant.Value_IntegerValue:
+		return kind.IntegerValue
+	case *qdrant.Value_BoolValue:
+		return kind.BoolValue
+	case *qdrant.Value_NullValue:
+		return nil
+	case *qdrant.Value_ListValue:
+		values := kind.ListValue.GetValues()
+		list := make([]interface{}, len(values))
+		for i, item := range values {
+			list[i] = convertValue(item)
+		}
+		return list
+	case *qdrant.Value_StructValue:
+		return convertPayload(kind.StructValue.GetFields())
+	default:
+		return v.String()
+	}
+}
+
 func (c *Client) EnsureCollection(ctx context.Context, name string, dimension int) error {
-	collections, err := c.client.ListCollections(ctx)
+	var collections []string
+	err := c.withReconnect(func(client pointsClient) error {
+		var err error
+		collections, err = client.ListCollections(ctx)
+		return err
+	})
 	if err != nil {
 		return fmt.Errorf("failed to list collections: %w", err)
 	}
@@ -169,12 +530,14 @@ func (c *Client) EnsureCollection(ctx context.Context, name string, dimension in
 	}
 
 	if !exists {
-		err = c.client.CreateCollection(ctx, &qdrant.CreateCollection{
-			CollectionName: name,
-			VectorsConfig: qdrant.NewVectorsConfig(&qdrant.VectorParams{
-				Size:     uint64(dimension),
-				Distance: qdrant.Distance_Cosine,
-			}),
+		err = c.withReconnect(func(client pointsClient) error {
+			return client.CreateCollection(ctx, &qdrant.CreateCollection{
+				CollectionName: name,
+				VectorsConfig: qdrant.NewVectorsConfig(&qdrant.VectorParams{
+					Size:     uint64(dimension),
+					Distance: qdrant.Distance_Cosine,
+				}),
+			})
 		})
 		if err != nil {
 			return fmt.Errorf("failed to create collection: %w", err)
@@ -184,7 +547,7 @@ func (c *Client) EnsureCollection(ctx context.Context, name string, dimension in
 	// Ensure a range payload index exists on `timestamp` so that order_by queries work.
 	// This is idempotent — Qdrant silently succeeds if the index already exists.
 	ftInt := qdrant.FieldType_FieldTypeInteger
-	_, err = c.client.CreateFieldIndex(ctx, &qdrant.CreateFieldIndexCollection{
+	err = c.createFieldIndex(ctx, &qdrant.CreateFieldIndexCollection{
 		CollectionName: name,
 		FieldName:      "timestamp",
 		FieldType:      &ftInt,
@@ -193,9 +556,48 @@ func (c *Client) EnsureCollection(ctx context.Context, name string, dimension in
 		return fmt.Errorf("failed to create timestamp index: %w", err)
 	}
 
+	// ArchiveIncremental orders by `message_end` to find where it left off.
+	err = c.createFieldIndex(ctx, &qdrant.CreateFieldIndexCollection{
+		CollectionName: name,
+		FieldName:      memory.MetadataMessageEnd,
+		FieldType:      &ftInt,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create message_end index: %w", err)
+	}
+
+	// Keyword index on `content_hash` keeps duplicate-chunk lookups cheap.
+	ftKeyword := qdrant.FieldType_FieldTypeKeyword
+	err = c.createFieldIndex(ctx, &qdrant.CreateFieldIndexCollection{
+		CollectionName: name,
+		FieldName:      "content_hash",
+		FieldType:      &ftKeyword,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create content_hash index: %w", err)
+	}
+
+	// Full-text index on `content` backs KeywordSearch.
+	ftText := qdrant.FieldType_FieldTypeText
+	err = c.createFieldIndex(ctx, &qdrant.CreateFieldIndexCollection{
+		CollectionName: name,
+		FieldName:      "content",
+		FieldType:      &ftText,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create content index: %w", err)
+	}
+
 	return nil
 }
 
+func (c *Client) createFieldIndex(ctx context.Context, req *qdrant.CreateFieldIndexCollection) error {
+	return c.withReconnect(func(client pointsClient) error {
+		_, err := client.CreateFieldIndex(ctx, req)
+		return err
+	})
+}
+
 func (c *Client) Close() error {
-	return c.client.Close()
+	return c.current().Close()
 }
@@ -1,9 +1,16 @@
 package qdrant
 
 import (
+	"context"
 	"testing"
+	"time"
 
+	"github.com/qdrant/go-client/qdrant"
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/sipeed/picoclaw/pkg/memory"
 )
 
 func TestParseQdrantAddress(t *testing.T) {
@@ -60,3 +67,217 @@ func TestParseQdrantAddress(t *testing.T) {
 		})
 	}
 }
+
+// fakePointsClient records upserts, health checks and created collections.
+// readErr, when set, fails Scroll, Count and ListCollections.
+type fakePointsClient struct {
+	pointsClient
+	upserts   []*qdrant.UpsertPoints
+	upsertErr error
+	readErr   error
+	pingErr   error
+	pings     int
+	closed    bool
+	created   []*qdrant.CreateCollection
+}
+
+func (f *fakePointsClient) Upsert(ctx context.Context, request *qdrant.UpsertPoints) (*qdrant.UpdateResult, error) {
+	if f.upsertErr != nil {
+		return nil, f.upsertErr
+	}
+	f.upserts = append(f.upserts, request)
+	return &qdrant.UpdateResult{}, nil
+}
+
+func (f *fakePointsClient) HealthCheck(ctx context.Context) (*qdrant.HealthCheckReply, error) {
+	f.pings++
+	if f.pingErr != nil {
+		return nil, f.pingErr
+	}
+	return &qdrant.HealthCheckReply{}, nil
+}
+
+func (f *fakePointsClient) Scroll(ctx context.Context, request *qdrant.ScrollPoints) ([]*qdrant.RetrievedPoint, error) {
+	return nil, f.readErr
+}
+
+func (f *fakePointsClient) Count(ctx context.Context, request *qdrant.CountPoints) (uint64, error) {
+	if f.readErr != nil {
+		return 0, f.readErr
+	}
+	return 1, nil
+}
+
+func (f *fakePointsClient) ListCollections(ctx context.Context) ([]string, error) {
+	if f.readErr != nil {
+		return nil, f.readErr
+	}
+	names := make([]string, len(f.created))
+	for i, c := range f.created {
+		names[i] = c.CollectionName
+	}
+	return names, nil
+}
+
+func (f *fakePointsClient) CreateCollection(ctx context.Context, request *qdrant.CreateCollection) error {
+	f.created = append(f.created, request)
+	return nil
+}
+
+func (f *fakePointsClient) CreateFieldIndex(
+	ctx context.Context,
+	request *qdrant.CreateFieldIndexCollection,
+) (*qdrant.UpdateResult, error) {
+	return &qdrant.UpdateResult{}, nil
+}
+
+func (f *fakePointsClient) Close() error {
+	f.closed = true
+	return nil
+}
+
+func TestStoreBatch_SingleUpsert(t *testing.T) {
+	fake := &fakePointsClient{}
+	c := &Client{client: fake}
+
+	records := []memory.VectorRecord{
+		{ID: "00000000-0000-0000-0000-000000000001", Vector: []float32{1, 0}, Payload: map[string]interface{}{"n": 1}},
+		{ID: "00000000-0000-0000-0000-000000000002", Vector: []float32{0, 1}, Payload: map[string]interface{}{"n": 2}},
+		{ID: "00000000-0000-0000-0000-000000000003", Vector: []float32{1, 1}, Payload: map[string]interface{}{"n": 3}},
+	}
+	err := c.StoreBatch(context.Background(), "picoclaw", records)
+	assert.NoError(t, err)
+
+	if assert.Len(t, fake.upserts, 1) {
+		assert.Equal(t, "picoclaw", fake.upserts[0].CollectionName)
+		assert.Len(t, fake.upserts[0].Points, 3)
+		assert.Equal(t, records[2].ID, fake.upserts[0].Points[2].Id.GetUuid())
+	}
+
+	assert.NoError(t, c.StoreBatch(context.Background(), "picoclaw", nil))
+	assert.Len(t, fake.upserts, 1, "empty batch should not upsert")
+}
+
+func TestNewClient_RetriesThenFails(t *testing.T) {
+	fake := &fakePointsClient{pingErr: status.Error(codes.Unavailable, "connection refused")}
+	dials := 0
+	dial := func() (pointsClient, error) {
+		dials++
+		return fake, nil
+	}
+
+	_, err := newClient(dial, Options{ConnectRetries: 2, ConnectBackoff: time.Millisecond})
+	assert.ErrorContains(t, err, "after 3 attempts")
+	assert.Equal(t, 3, fake.pings)
+	assert.Equal(t, 1, dials)
+	assert.True(t, fake.closed, "client should be closed when the health check gives up")
+}
+
+func TestNewClient_DeadAddress(t *testing.T) {
+	start := time.Now()
+	_, err := NewClientWithOptions("127.0.0.1:1", "", Options{
+		ConnectRetries: 1,
+		ConnectBackoff: 10 * time.Millisecond,
+		PingTimeout:    time.Second,
+	})
+	assert.ErrorContains(t, err, "unreachable")
+	assert.ErrorContains(t, err, "after 2 attempts")
+	assert.GreaterOrEqual(t, time.Since(start), 10*time.Millisecond)
+}
+
+func TestStore_ReconnectsOnUnavailable(t *testing.T) {
+	stale := &fakePointsClient{upsertErr: status.Error(codes.Unavailable, "transport is closing")}
+	fresh := &fakePointsClient{}
+	c := &Client{client: stale, dial: func() (pointsClient, error) { return fresh, nil }}
+
+	err := c.Store(context.Background(), "picoclaw", memory.VectorRecord{
+		ID:     "00000000-0000-0000-0000-000000000001",
+		Vector: []float32{1},
+	})
+	assert.NoError(t, err)
+	assert.True(t, stale.closed)
+	assert.Len(t, fresh.upserts, 1)
+}
+
+func TestReadCalls_ReconnectOnUnavailable(t *testing.T) {
+	ctx := context.Background()
+	calls := map[string]func(c *Client) error{
+		"KeywordSearch": func(c *Client) error {
+			_, err := c.KeywordSearch(ctx, "picoclaw", "tomatoes", 5, nil)
+			return err
+		},
+		"Scroll": func(c *Client) error {
+			_, _, err := c.Scroll(ctx, "picoclaw", "timestamp", 5, "", nil)
+			return err
+		},
+		"Count": func(c *Client) error {
+			_, err := c.Count(ctx, "picoclaw", nil)
+			return err
+		},
+		"Stats": func(c *Client) error {
+			_, err := c.Stats(ctx, "picoclaw", nil)
+			return err
+		},
+		"EnsureCollection": func(c *Client) error {
+			return c.EnsureCollection(ctx, "picoclaw", 3)
+		},
+	}
+	for name, call := range calls {
+		t.Run(name, func(t *testing.T) {
+			stale := &fakePointsClient{readErr: status.Error(codes.Unavailable, "transport is closing")}
+			fresh := &fakePointsClient{}
+			c := &Client{client: stale, dial: func() (pointsClient, error) { return fresh, nil }}
+
+			assert.NoError(t, call(c))
+			assert.True(t, stale.closed)
+			assert.Same(t, fresh, c.current())
+		})
+	}
+}
+
+func TestStore_DoesNotReconnectOnOtherErrors(t *testing.T) {
+	failing := &fakePointsClient{upsertErr: status.Error(codes.InvalidArgument, "bad vector")}
+	dialed := false
+	c := &Client{client: failing, dial: func() (pointsClient, error) {
+		dialed = true
+		return &fakePointsClient{}, nil
+	}}
+
+	err := c.Store(context.Background(), "picoclaw", memory.VectorRecord{ID: "00000000-0000-0000-0000-000000000001"})
+	assert.Error(t, err)
+	assert.False(t, dialed)
+}
+
+func TestConvertPayload_NestedValues(t *testing.T) {
+	payload := qdrant.NewValueMap(map[string]any{
+		"tags":   []any{"garden", "spring", int64(3)},
+		"source": map[string]any{"channel": "discord", "ids": []any{"a", "b"}},
+		"empty":  []any{},
+	})
+
+	got := convertPayload(payload)
+
+	assert.Equal(t, []interface{}{"garden", "spring", int64(3)}, got["tags"])
+	assert.Equal(t, map[string]interface{}{
+		"channel": "discord",
+		"ids":     []interface{}{"a", "b"},
+	}, got["source"])
+	assert.Equal(t, []interface{}{}, got["empty"])
+}
+
+func TestEnsureCollection_UsesCosineDistance(t *testing.T) {
+	fake := &fakePointsClient{}
+	c := &Client{client: fake}
+
+	if err := c.EnsureCollection(context.Background(), "picoclaw", 3); err != nil {
+		t.Fatalf("EnsureCollection: %v", err)
+	}
+	if err := c.EnsureCollection(context.Background(), "picoclaw", 3); err != nil {
+		t.Fatalf("EnsureCollection (existing): %v", err)
+	}
+
+	assert.Len(t, fake.created, 1)
+	params := fake.created[0].GetVectorsConfig().GetParams()
+	assert.Equal(t, qdrant.Distance_Cosine, params.GetDistance())
+	assert.Equal(t, uint64(3), params.GetSize())
+}
@@ -0,0 +1,78 @@
+package memory
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ScrollCursor marks a position in a listing ordered (descending) by an
+// integer payload field. Records sharing the boundary value are remembered by
+// ID so that ties are neither repeated nor skipped on the next page.
+type ScrollCursor struct {
+	Value int64
+	Seen  []string
+}
+
+// String encodes the cursor as an opaque token.
+func (c *ScrollCursor) String() string {
+	if c == nil {
+		return ""
+	}
+	return fmt.Sprintf("%d:%s", c.Value, strings.Join(c.Seen, ","))
+}
+
+// ParseScrollCursor decodes a token produced by ScrollCursor.String. An
+// empty token yields a nil cursor, meaning "start from the beginning".
+func ParseScrollCursor(token string) (*ScrollCursor, error) {
+	if token == "" {
+		return nil, nil
+	}
+	value, seen, ok := strings.Cut(token, ":")
+	if !ok {
+		return nil, fmt.Errorf("invalid cursor %q", token)
+	}
+	v, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor %q: %w", token, err)
+	}
+	c := &ScrollCursor{Value: v}
+	if seen != "" {
+		c.Seen = strings.Split(seen, ",")
+	}
+	return c, nil
+}
+
+// NextScrollCursor returns the token for the page after results, or "" when
+// results is shorter than limit and the listing is exhausted.
+func NextScrollCursor(results []SearchResult, orderBy string, limit int, prev *ScrollCursor) string {
+	if len(results) == 0 || len(results) < limit {
+		return ""
+	}
+
+	last := PayloadInt(results[len(results)-1].Payload, orderBy)
+	next := &ScrollCursor{Value: last}
+	if prev != nil && prev.Value == last {
+		next.Seen = append(next.Seen, prev.Seen...)
+	}
+	for _, r := range results {
+		if PayloadInt(r.Payload, orderBy) == last {
+			next.Seen = append(next.Seen, r.ID)
+		}
+	}
+	return next.String()
+}
+
+// PayloadInt reads an integer payload field regardless of whether the
+// backend decoded it as int, int64 or float64.
+func PayloadInt(payload map[string]interface{}, key string) int64 {
+	switch v := payload[key].(type) {
+	case int64:
+		return v
+	case float64:
+		return int64(v)
+	case int:
+		return int64(v)
+	}
+	return 0
+}
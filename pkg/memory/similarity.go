@@ -0,0 +1,57 @@
+package memory
+
+import (
+	"math"
+	"sort"
+)
+
+// CosineSimilarity returns the cosine of the angle between a and b, in
+// [-1, 1]. It returns 0 when either vector is all zeros or the dimensions
+// differ, since neither case has a meaningful direction to compare.
+func CosineSimilarity(a, b []float32) float32 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+	var dot, na, nb float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		na += float64(a[i]) * float64(a[i])
+		nb += float64(b[i]) * float64(b[i])
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return float32(dot / (math.Sqrt(na) * math.Sqrt(nb)))
+}
+
+// TopK scores records by cosine similarity to query and returns the k best,
+// highest first, with ties broken by ID. Records whose dimension differs from
+// the query are skipped. k <= 0 returns no results.
+func TopK(query []float32, records []VectorRecord, k int) []SearchResult {
+	if k <= 0 {
+		return []SearchResult{}
+	}
+
+	results := make([]SearchResult, 0, len(records))
+	for _, r := range records {
+		if len(r.Vector) != len(query) {
+			continue
+		}
+		results = append(results, SearchResult{
+			ID:      r.ID,
+			Score:   CosineSimilarity(query, r.Vector),
+			Payload: r.Payload,
+		})
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score == results[j].Score {
+			return results[i].ID < results[j].ID
+		}
+		return results[i].Score > results[j].Score
+	})
+
+	if len(results) > k {
+		results = results[:k]
+	}
+	return results
+}
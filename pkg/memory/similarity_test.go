@@ -0,0 +1,74 @@
+package memory
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCosineSimilarity(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b []float32
+		want float32
+	}{
+		{"identical", []float32{1, 2, 3}, []float32{1, 2, 3}, 1},
+		{"same direction, different length", []float32{1, 1}, []float32{3, 3}, 1},
+		{"orthogonal", []float32{1, 0}, []float32{0, 1}, 0},
+		{"opposite", []float32{1, 2}, []float32{-1, -2}, -1},
+		{"45 degrees", []float32{1, 0}, []float32{1, 1}, float32(1 / math.Sqrt2)},
+		{"zero vector", []float32{0, 0}, []float32{1, 1}, 0},
+		{"both zero", []float32{0, 0}, []float32{0, 0}, 0},
+		{"mismatched dimensions", []float32{1, 0}, []float32{1, 0, 0}, 0},
+		{"empty", nil, nil, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CosineSimilarity(tt.a, tt.b)
+			if math.Abs(float64(got-tt.want)) > 1e-6 {
+				t.Errorf("CosineSimilarity(%v, %v) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTopK(t *testing.T) {
+	records := []VectorRecord{
+		{ID: "orth", Vector: []float32{0, 1}},
+		{ID: "diag", Vector: []float32{1, 1}},
+		{ID: "same", Vector: []float32{4, 0}},
+		{ID: "zero", Vector: []float32{0, 0}},
+		{ID: "wide", Vector: []float32{1, 0, 0}},
+		{ID: "opp", Vector: []float32{-1, 0}},
+		{ID: "same2", Vector: []float32{1, 0}},
+	}
+	query := []float32{1, 0}
+
+	tests := []struct {
+		name string
+		k    int
+		want []string
+	}{
+		{"top one", 1, []string{"same"}},
+		{"ties break by ID", 2, []string{"same", "same2"}},
+		{"all comparable records", 10, []string{"same", "same2", "diag", "orth", "zero", "opp"}},
+		{"zero k", 0, nil},
+		{"negative k", -1, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := TopK(query, records, tt.k)
+			if len(got) != len(tt.want) {
+				t.Fatalf("TopK(k=%d) returned %d results %+v, want %v", tt.k, len(got), got, tt.want)
+			}
+			for i, id := range tt.want {
+				if got[i].ID != id {
+					t.Errorf("result %d = %s, want %s", i, got[i].ID, id)
+				}
+			}
+		})
+	}
+
+	if got := TopK([]float32{1, 0, 0, 0}, records, 3); len(got) != 0 {
+		t.Errorf("TopK with an unmatched dimension = %+v, want none", got)
+	}
+}
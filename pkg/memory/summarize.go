@@ -0,0 +1,71 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/sipeed/picoclaw/pkg/providers"
+)
+
+// Archive summary modes for MemoryConfig.ArchiveSummary.
+const (
+	// ArchiveSummaryAlongside stores a summary chunk in addition to the raw chunks.
+	ArchiveSummaryAlongside = "alongside"
+	// ArchiveSummaryInstead stores only the summary chunk.
+	ArchiveSummaryInstead = "instead"
+)
+
+// KindSummary is the "kind" payload value of chunks holding a session summary.
+const KindSummary = "summary"
+
+// Summarizer condenses a conversation before it is archived.
+type Summarizer interface {
+	Summarize(ctx context.Context, messages []providers.Message) (string, error)
+}
+
+// ProviderSummarizer is a Summarizer backed by an LLM provider.
+type ProviderSummarizer struct {
+	provider providers.LLMProvider
+	model    string
+}
+
+// NewProviderSummarizer returns a Summarizer that asks provider to summarize
+// with model, or with the provider's default model if model is empty.
+func NewProviderSummarizer(provider providers.LLMProvider, model string) *ProviderSummarizer {
+	if model == "" {
+		model = provider.GetDefaultModel()
+	}
+	return &ProviderSummarizer{provider: provider, model: model}
+}
+
+// Model returns the model used for summaries.
+func (s *ProviderSummarizer) Model() string {
+	return s.model
+}
+
+func (s *ProviderSummarizer) Summarize(ctx context.Context, messages []providers.Message) (string, error) {
+	prompt := "Summarize this conversation for long-term memory. Keep names, decisions, " +
+		"dates and open questions; drop small talk.\n\nCONVERSATION:\n" + transcript(messages)
+
+	resp, err := s.provider.Chat(ctx, []providers.Message{{Role: "user", Content: prompt}}, nil, s.model,
+		map[string]any{"temperature": 0.3})
+	if err != nil {
+		return "", fmt.Errorf("summarization failed: %w", err)
+	}
+	summary := strings.TrimSpace(resp.Content)
+	if summary == "" {
+		return "", errors.New("summarization returned no content")
+	}
+	return summary, nil
+}
+
+// summarizerModel returns the model label used when recording how long
+// summarization took.
+func summarizerModel(s Summarizer) string {
+	if m, ok := s.(interface{ Model() string }); ok {
+		return m.Model()
+	}
+	return "unknown"
+}
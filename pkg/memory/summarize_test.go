@@ -0,0 +1,173 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/sipeed/picoclaw/pkg/config"
+	"github.com/sipeed/picoclaw/pkg/providers"
+)
+
+type mockSummarizer struct {
+	summary string
+	err     error
+	calls   int
+}
+
+func (s *mockSummarizer) Summarize(ctx context.Context, messages []providers.Message) (string, error) {
+	s.calls++
+	return s.summary, s.err
+}
+
+func (s *mockSummarizer) Model() string { return "mock-summary-model" }
+
+// archivedKinds archives testMessages with the given mode and summarizer and
+// returns the stored chunks' contents grouped by their "kind" payload ("" for
+// raw chunks).
+func archivedKinds(t *testing.T, mode string, summarizer Summarizer) map[string][]string {
+	t.Helper()
+	ctx := context.Background()
+	db := NewInMemoryVectorDB()
+	m := NewManager(config.MemoryConfig{Enabled: true, ArchiveSummary: mode}, db, &fakeEmbedder{})
+	if summarizer != nil {
+		m.SetSummarizer(summarizer)
+	}
+
+	if err := m.ArchiveSession(ctx, "ws", "s1", testMessages()); err != nil {
+		t.Fatalf("ArchiveSession: %v", err)
+	}
+
+	results, _, err := db.Scroll(ctx, "picoclaw", "timestamp", 100, "", nil)
+	if err != nil {
+		t.Fatalf("Scroll: %v", err)
+	}
+	kinds := make(map[string][]string)
+	for _, r := range results {
+		kind, _ := r.Payload["kind"].(string)
+		content, _ := r.Payload["content"].(string)
+		kinds[kind] = append(kinds[kind], content)
+	}
+	return kinds
+}
+
+func TestArchiveSession_SummaryAlongside(t *testing.T) {
+	s := &mockSummarizer{summary: "Decided to plant tomatoes in spring."}
+	kinds := archivedKinds(t, ArchiveSummaryAlongside, s)
+
+	if s.calls != 1 {
+		t.Errorf("summarizer called %d times, want 1", s.calls)
+	}
+	if got := kinds[KindSummary]; len(got) != 1 || got[0] != s.summary {
+		t.Errorf("summary chunks = %q, want [%q]", got, s.summary)
+	}
+	if len(kinds[""]) == 0 {
+		t.Error("alongside mode must also store the raw chunks")
+	}
+}
+
+func TestArchiveSession_SummaryInstead(t *testing.T) {
+	kinds := archivedKinds(t, ArchiveSummaryInstead, &mockSummarizer{summary: "Tomatoes in spring."})
+
+	if len(kinds[KindSummary]) != 1 {
+		t.Errorf("summary chunks = %q, want one", kinds[KindSummary])
+	}
+	if len(kinds[""]) != 0 {
+		t.Errorf("instead mode stored raw chunks %q", kinds[""])
+	}
+}
+
+func TestArchiveSession_SummaryFallsBackToRawChunks(t *testing.T) {
+	tests := []struct {
+		name       string
+		summarizer Summarizer
+	}{
+		{"no summarizer", nil},
+		{"summarizer error", &mockSummarizer{err: errors.New("provider down")}},
+		{"empty summary", &mockSummarizer{summary: "  "}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kinds := archivedKinds(t, ArchiveSummaryInstead, tt.summarizer)
+			if len(kinds[KindSummary]) != 0 {
+				t.Errorf("stored summary chunks %q", kinds[KindSummary])
+			}
+			if len(kinds[""]) == 0 || !strings.Contains(kinds[""][0], "tomatoes") {
+				t.Errorf("raw chunks = %q, want the transcript", kinds[""])
+			}
+		})
+	}
+}
+
+func TestArchiveSession_RecordsSummarizationDuration(t *testing.T) {
+	before := summarizationSamples(t, "mock-summary-model")
+	archivedKinds(t, ArchiveSummaryAlongside, &mockSummarizer{summary: "ok"})
+	if got := summarizationSamples(t, "mock-summary-model"); got != before+1 {
+		t.Errorf("summarization samples = %d, want %d", got, before+1)
+	}
+}
+
+func summarizationSamples(t *testing.T, model string) uint64 {
+	t.Helper()
+	families, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatalf("Gather: %v", err)
+	}
+	for _, mf := range families {
+		if mf.GetName() != "picoclaw_summarization_duration_seconds" {
+			continue
+		}
+		for _, m := range mf.GetMetric() {
+			for _, l := range m.GetLabel() {
+				if l.GetName() == "model" && l.GetValue() == model {
+					return m.GetHistogram().GetSampleCount()
+				}
+			}
+		}
+	}
+	return 0
+}
+
+type summaryProvider struct {
+	prompt string
+	model  string
+}
+
+func (p *summaryProvider) Chat(
+	ctx context.Context,
+	messages []providers.Message,
+	tools []providers.ToolDefinition,
+	model string,
+	options map[string]any,
+) (*providers.LLMResponse, error) {
+	p.prompt = messages[len(messages)-1].Content
+	p.model = model
+	return &providers.LLMResponse{Content: "  a short summary \n"}, nil
+}
+
+func (p *summaryProvider) GetDefaultModel() string { return "default-model" }
+
+func TestProviderSummarizer(t *testing.T) {
+	p := &summaryProvider{}
+	s := NewProviderSummarizer(p, "")
+	if s.Model() != "default-model" {
+		t.Errorf("Model() = %q, want the provider default", s.Model())
+	}
+
+	summary, err := s.Summarize(context.Background(), testMessages())
+	if err != nil {
+		t.Fatalf("Summarize: %v", err)
+	}
+	if summary != "a short summary" {
+		t.Errorf("summary = %q", summary)
+	}
+	if !strings.Contains(p.prompt, "user: remind me what we decided about the garden") {
+		t.Errorf("prompt does not include the transcript: %q", p.prompt)
+	}
+	if p.model != "default-model" {
+		t.Errorf("Chat model = %q", p.model)
+	}
+}
@@ -18,14 +18,43 @@ type SearchResult struct {
 	Payload map[string]interface{} `json:"payload"`
 }
 
+// CollectionStats summarizes the records matching a filter. Oldest and
+// Newest are the range of the "timestamp" payload field in Unix seconds, and
+// are zero when Count is zero.
+type CollectionStats struct {
+	Count  int   `json:"count"`
+	Oldest int64 `json:"oldest"`
+	Newest int64 `json:"newest"`
+}
+
 // VectorDB defines the interface for interacting with vector databases.
 type VectorDB interface {
 	// Store inserts or updates a vector record in the specified collection.
 	Store(ctx context.Context, collection string, record VectorRecord) error
 
+	// StoreBatch inserts or updates several vector records in one round trip.
+	StoreBatch(ctx context.Context, collection string, records []VectorRecord) error
+
 	// Search finds the nearest neighbors and applies filters in the specified collection.
 	Search(ctx context.Context, collection string, vector []float32, limit, offset int, filters map[string]interface{}) ([]SearchResult, error)
 
+	// KeywordSearch finds records whose "content" payload contains the given text
+	// and applies filters in the specified collection. Result scores are not meaningful.
+	KeywordSearch(ctx context.Context, collection, text string, limit int, filters map[string]interface{}) ([]SearchResult, error)
+
+	// Scroll lists records matching filters without a query vector, ordered
+	// by the integer payload field orderBy from highest to lowest. cursor is
+	// the token returned by the previous page ("" for the first page); the
+	// returned token is "" once there are no more records.
+	Scroll(ctx context.Context, collection, orderBy string, limit int, cursor string, filters map[string]interface{}) ([]SearchResult, string, error)
+
+	// Count returns the number of records matching the filters in the specified collection.
+	Count(ctx context.Context, collection string, filters map[string]interface{}) (int, error)
+
+	// Stats returns the number of records matching the filters in the specified
+	// collection and the range of their timestamps.
+	Stats(ctx context.Context, collection string, filters map[string]interface{}) (CollectionStats, error)
+
 	// EnsureCollection ensures that the specified collection exists with the correct dimension.
 	EnsureCollection(ctx context.Context, name string, dimension int) error
 
@@ -0,0 +1,48 @@
+// Package vectordb selects a memory.VectorDB implementation from config.
+package vectordb
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/sipeed/picoclaw/pkg/config"
+	"github.com/sipeed/picoclaw/pkg/memory"
+	"github.com/sipeed/picoclaw/pkg/memory/qdrant"
+	"github.com/sipeed/picoclaw/pkg/memory/weaviate"
+)
+
+const (
+	BackendQdrant   = "qdrant"
+	BackendWeaviate = "weaviate"
+	BackendMemory   = "memory"
+)
+
+// New returns the vector store configured by cfg.Backend. An empty backend
+// selects Qdrant for compatibility with configs written before the field
+// existed. Qdrant without an address falls back to the in-memory store so
+// memory works out of the box.
+func New(cfg config.MemoryConfig) (memory.VectorDB, error) {
+	switch cfg.Backend {
+	case "", BackendQdrant:
+		if cfg.Qdrant.Address == "" {
+			return memory.NewInMemoryVectorDB(), nil
+		}
+		opts := qdrant.DefaultOptions()
+		if cfg.Qdrant.ConnectRetries > 0 {
+			opts.ConnectRetries = cfg.Qdrant.ConnectRetries
+		}
+		if cfg.Qdrant.ConnectBackoffMS > 0 {
+			opts.ConnectBackoff = time.Duration(cfg.Qdrant.ConnectBackoffMS) * time.Millisecond
+		}
+		return qdrant.NewClientWithOptions(cfg.Qdrant.Address, cfg.Qdrant.APIKey, opts)
+	case BackendWeaviate:
+		if cfg.Weaviate.Address == "" {
+			return nil, fmt.Errorf("memory.weaviate.address is required for the weaviate backend")
+		}
+		return weaviate.NewClient(cfg.Weaviate.Address, cfg.Weaviate.APIKey), nil
+	case BackendMemory:
+		return memory.NewInMemoryVectorDB(), nil
+	default:
+		return nil, fmt.Errorf("unknown memory backend %q", cfg.Backend)
+	}
+}
@@ -0,0 +1,59 @@
+package vectordb
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sipeed/picoclaw/pkg/config"
+	"github.com/sipeed/picoclaw/pkg/memory"
+	"github.com/sipeed/picoclaw/pkg/memory/weaviate"
+)
+
+func TestNew_SelectsBackend(t *testing.T) {
+	db, err := New(config.MemoryConfig{
+		Backend:  BackendWeaviate,
+		Weaviate: config.WeaviateConfig{Address: "http://localhost:8080"},
+	})
+	if err != nil {
+		t.Fatalf("New() weaviate error: %v", err)
+	}
+	if _, ok := db.(*weaviate.Client); !ok {
+		t.Errorf("weaviate backend = %T, want *weaviate.Client", db)
+	}
+}
+
+func TestNew_RejectsUnknownBackend(t *testing.T) {
+	if _, err := New(config.MemoryConfig{Backend: "pinecone"}); err == nil {
+		t.Error("expected error for unknown backend")
+	}
+	if _, err := New(config.MemoryConfig{Backend: BackendWeaviate}); err == nil {
+		t.Error("expected error for weaviate without address")
+	}
+}
+
+func TestNew_QdrantUnreachable(t *testing.T) {
+	_, err := New(config.MemoryConfig{Qdrant: config.QdrantConfig{
+		Address:          "127.0.0.1:1",
+		ConnectRetries:   1,
+		ConnectBackoffMS: 1,
+	}})
+	if err == nil || !strings.Contains(err.Error(), "unreachable") {
+		t.Errorf("expected unreachable error, got %v", err)
+	}
+}
+
+func TestNew_InMemoryWithoutQdrantAddress(t *testing.T) {
+	for _, cfg := range []config.MemoryConfig{
+		{},
+		{Backend: BackendQdrant},
+		{Backend: BackendMemory, Qdrant: config.QdrantConfig{Address: "http://localhost:6334"}},
+	} {
+		db, err := New(cfg)
+		if err != nil {
+			t.Fatalf("New(%+v) error: %v", cfg, err)
+		}
+		if _, ok := db.(*memory.InMemoryVectorDB); !ok {
+			t.Errorf("New(%+v) = %T, want *memory.InMemoryVectorDB", cfg, db)
+		}
+	}
+}
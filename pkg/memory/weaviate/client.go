@@ -0,0 +1,520 @@
+package weaviate
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/sipeed/picoclaw/pkg/memory"
+)
+
+// Client implements memory.VectorDB on top of Weaviate's REST and GraphQL APIs.
+// Collections map to Weaviate classes; vectors are supplied by the caller
+// (the class is created with vectorizer "none").
+type Client struct {
+	baseURL string
+	apiKey  string
+	client  *http.Client
+
+	mu         sync.Mutex
+	properties map[string][]string // class -> primitive property names
+}
+
+// keywordProperties are stored with "field" tokenization so exact-match
+// filters compare whole values instead of individual words.
+var keywordProperties = []string{
+	"workspace_id",
+	"session_id",
+	"content_hash",
+	memory.MetadataChannel,
+	memory.MetadataUserID,
+	memory.MetadataAgentID,
+}
+
+// intProperties are stored as integers so they round-trip as numbers.
+var intProperties = []string{"timestamp", "chunk_index", "total_chunks", memory.MetadataMessageEnd}
+
+func NewClient(rawURL, apiKey string) *Client {
+	baseURL := strings.TrimRight(rawURL, "/")
+	if !strings.Contains(baseURL, "://") {
+		baseURL = "http://" + baseURL
+	}
+
+	return &Client{
+		baseURL:    baseURL,
+		apiKey:     apiKey,
+		client:     &http.Client{Timeout: 30 * time.Second},
+		properties: make(map[string][]string),
+	}
+}
+
+// className converts a collection name into a valid Weaviate class name,
+// which must start with an upper-case letter and contain only [A-Za-z0-9_].
+func className(collection string) string {
+	var sb strings.Builder
+	for i, r := range collection {
+		switch {
+		case i == 0 && unicode.IsLetter(r):
+			sb.WriteRune(unicode.ToUpper(r))
+		case i == 0:
+			sb.WriteString("C_")
+			if unicode.IsDigit(r) {
+				sb.WriteRune(r)
+			}
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			sb.WriteRune(r)
+		default:
+			sb.WriteRune('_')
+		}
+	}
+	if sb.Len() == 0 {
+		return "Picoclaw"
+	}
+	return sb.String()
+}
+
+func (c *Client) do(ctx context.Context, method, path string, body interface{}, out interface{}) (int, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return 0, fmt.Errorf("failed to marshal request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return resp.StatusCode, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("weaviate %s %s failed: status=%d body=%s",
+			method, path, resp.StatusCode, string(respBody))
+	}
+
+	if out != nil && len(respBody) > 0 {
+		if err := json.Unmarshal(respBody, out); err != nil {
+			return resp.StatusCode, fmt.Errorf("failed to unmarshal response: %w", err)
+		}
+	}
+
+	return resp.StatusCode, nil
+}
+
+func (c *Client) EnsureCollection(ctx context.Context, name string, dimension int) error {
+	class := className(name)
+
+	status, err := c.do(ctx, http.MethodGet, "/v1/schema/"+class, nil, nil)
+	if err == nil {
+		return nil
+	}
+	if status != http.StatusNotFound {
+		return fmt.Errorf("failed to get class: %w", err)
+	}
+
+	properties := []map[string]interface{}{
+		{"name": "content", "dataType": []string{"text"}, "tokenization": "word"},
+	}
+	for _, p := range keywordProperties {
+		properties = append(properties, map[string]interface{}{
+			"name": p, "dataType": []string{"text"}, "tokenization": "field",
+		})
+	}
+	for _, p := range intProperties {
+		properties = append(properties, map[string]interface{}{
+			"name": p, "dataType": []string{"int"},
+		})
+	}
+
+	_, err = c.do(ctx, http.MethodPost, "/v1/schema", map[string]interface{}{
+		"class":      class,
+		"vectorizer": "none",
+		"vectorIndexConfig": map[string]interface{}{
+			"distance": "cosine",
+		},
+		"properties": properties,
+	}, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create class: %w", err)
+	}
+
+	c.mu.Lock()
+	delete(c.properties, class)
+	c.mu.Unlock()
+
+	return nil
+}
+
+func (c *Client) Store(ctx context.Context, collection string, record memory.VectorRecord) error {
+	return c.StoreBatch(ctx, collection, []memory.VectorRecord{record})
+}
+
+// StoreBatch upserts all records through a single batch request.
+func (c *Client) StoreBatch(ctx context.Context, collection string, records []memory.VectorRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	class := className(collection)
+
+	objects := make([]map[string]interface{}, len(records))
+	for i, record := range records {
+		objects[i] = map[string]interface{}{
+			"class":      class,
+			"id":         record.ID,
+			"properties": record.Payload,
+			"vector":     record.Vector,
+		}
+	}
+
+	var results []struct {
+		ID     string `json:"id"`
+		Result struct {
+			Errors *struct {
+				Error []struct {
+					Message string `json:"message"`
+				} `json:"error"`
+			} `json:"errors"`
+		} `json:"result"`
+	}
+	_, err := c.do(ctx, http.MethodPost, "/v1/batch/objects", map[string]interface{}{
+		"objects": objects,
+	}, &results)
+	if err != nil {
+		return fmt.Errorf("failed to upsert objects: %w", err)
+	}
+
+	for _, r := range results {
+		if r.Result.Errors != nil && len(r.Result.Errors.Error) > 0 {
+			return fmt.Errorf("failed to upsert object %s: %s", r.ID, r.Result.Errors.Error[0].Message)
+		}
+	}
+
+	// Auto-schema may have added properties; refresh on next read.
+	c.mu.Lock()
+	delete(c.properties, class)
+	c.mu.Unlock()
+
+	return nil
+}
+
+func (c *Client) Search(
+	ctx context.Context,
+	collection string,
+	vector []float32,
+	limit, offset int,
+	filters map[string]interface{},
+) ([]memory.SearchResult, error) {
+	vec, err := json.Marshal(vector)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal vector: %w", err)
+	}
+
+	args := []string{
+		fmt.Sprintf("nearVector: {vector: %s}", vec),
+		fmt.Sprintf("limit: %d", limit),
+	}
+	if offset > 0 {
+		args = append(args, fmt.Sprintf("offset: %d", offset))
+	}
+
+	results, err := c.get(ctx, collection, args, filters)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query objects: %w", err)
+	}
+	return results, nil
+}
+
+func (c *Client) KeywordSearch(
+	ctx context.Context,
+	collection, text string,
+	limit int,
+	filters map[string]interface{},
+) ([]memory.SearchResult, error) {
+	args := []string{
+		fmt.Sprintf("bm25: {query: %s, properties: [\"content\"]}", graphQLString(text)),
+		fmt.Sprintf("limit: %d", limit),
+	}
+
+	results, err := c.get(ctx, collection, args, filters)
+	if err != nil {
+		return nil, fmt.Errorf("failed to keyword search objects: %w", err)
+	}
+	for i := range results {
+		results[i].Score = 0
+	}
+	return results, nil
+}
+
+func (c *Client) Scroll(
+	ctx context.Context,
+	collection, orderBy string,
+	limit int,
+	cursor string,
+	filters map[string]interface{},
+) ([]memory.SearchResult, string, error) {
+	prev, err := memory.ParseScrollCursor(cursor)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var extra []string
+	if prev != nil {
+		extra = append(extra, fmt.Sprintf("{path: [%s], operator: LessThanEqual, valueInt: %d}",
+			graphQLString(orderBy), prev.Value))
+		for _, id := range prev.Seen {
+			extra = append(extra, fmt.Sprintf("{path: [\"id\"], operator: NotEqual, valueText: %s}",
+				graphQLString(id)))
+		}
+	}
+
+	args := []string{
+		fmt.Sprintf("sort: [{path: [%s], order: desc}]", graphQLString(orderBy)),
+		fmt.Sprintf("limit: %d", limit),
+	}
+	if where := whereClause(filters, extra...); where != "" {
+		args = append(args, "where: "+where)
+	}
+
+	results, err := c.get(ctx, collection, args, nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to scroll objects: %w", err)
+	}
+	for i := range results {
+		results[i].Score = 0
+	}
+
+	return results, memory.NextScrollCursor(results, orderBy, limit, prev), nil
+}
+
+func (c *Client) Count(ctx context.Context, collection string, filters map[string]interface{}) (int, error) {
+	stats, err := c.aggregate(ctx, collection, filters, "")
+	if err != nil {
+		return 0, fmt.Errorf("failed to count objects: %w", err)
+	}
+	return stats.Count, nil
+}
+
+// Stats aggregates the object count and the timestamp range in one query.
+func (c *Client) Stats(
+	ctx context.Context,
+	collection string,
+	filters map[string]interface{},
+) (memory.CollectionStats, error) {
+	stats, err := c.aggregate(ctx, collection, filters, " timestamp { minimum maximum }")
+	if err != nil {
+		return memory.CollectionStats{}, fmt.Errorf("failed to aggregate objects: %w", err)
+	}
+	return stats, nil
+}
+
+// aggregate runs an Aggregate query for the class behind collection. fields
+// is appended to the meta count selection.
+func (c *Client) aggregate(
+	ctx context.Context,
+	collection string,
+	filters map[string]interface{},
+	fields string,
+) (memory.CollectionStats, error) {
+	class := className(collection)
+
+	args := ""
+	if where := whereClause(filters); where != "" {
+		args = "(where: " + where + ")"
+	}
+	query := fmt.Sprintf("{ Aggregate { %s%s { meta { count }%s } } }", class, args, fields)
+
+	var resp struct {
+		Data struct {
+			Aggregate map[string][]struct {
+				Meta struct {
+					Count int `json:"count"`
+				} `json:"meta"`
+				Timestamp struct {
+					Minimum float64 `json:"minimum"`
+					Maximum float64 `json:"maximum"`
+				} `json:"timestamp"`
+			} `json:"Aggregate"`
+		} `json:"data"`
+		Errors []graphQLError `json:"errors"`
+	}
+	if _, err := c.do(ctx, http.MethodPost, "/v1/graphql", map[string]string{"query": query}, &resp); err != nil {
+		return memory.CollectionStats{}, err
+	}
+	if len(resp.Errors) > 0 {
+		return memory.CollectionStats{}, fmt.Errorf("%s", resp.Errors[0].Message)
+	}
+
+	groups := resp.Data.Aggregate[class]
+	if len(groups) == 0 || groups[0].Meta.Count == 0 {
+		return memory.CollectionStats{}, nil
+	}
+	return memory.CollectionStats{
+		Count:  groups[0].Meta.Count,
+		Oldest: int64(groups[0].Timestamp.Minimum),
+		Newest: int64(groups[0].Timestamp.Maximum),
+	}, nil
+}
+
+func (c *Client) Close() error {
+	c.client.CloseIdleConnections()
+	return nil
+}
+
+type graphQLError struct {
+	Message string `json:"message"`
+}
+
+// get runs a GraphQL Get query for the class behind collection and converts
+// the returned objects into search results.
+func (c *Client) get(
+	ctx context.Context,
+	collection string,
+	args []string,
+	filters map[string]interface{},
+) ([]memory.SearchResult, error) {
+	class := className(collection)
+
+	props, err := c.classProperties(ctx, class)
+	if err != nil {
+		return nil, err
+	}
+
+	if where := whereClause(filters); where != "" {
+		args = append(args, "where: "+where)
+	}
+
+	query := fmt.Sprintf("{ Get { %s(%s) { %s _additional { id distance } } } }",
+		class, strings.Join(args, ", "), strings.Join(props, " "))
+
+	var resp struct {
+		Data struct {
+			Get map[string][]map[string]interface{} `json:"Get"`
+		} `json:"data"`
+		Errors []graphQLError `json:"errors"`
+	}
+	if _, err := c.do(ctx, http.MethodPost, "/v1/graphql", map[string]string{"query": query}, &resp); err != nil {
+		return nil, err
+	}
+	if len(resp.Errors) > 0 {
+		return nil, fmt.Errorf("graphql error: %s", resp.Errors[0].Message)
+	}
+
+	objects := resp.Data.Get[class]
+	results := make([]memory.SearchResult, 0, len(objects))
+	for _, obj := range objects {
+		var r memory.SearchResult
+		if additional, ok := obj["_additional"].(map[string]interface{}); ok {
+			r.ID, _ = additional["id"].(string)
+			if distance, ok := additional["distance"].(float64); ok {
+				// Cosine distance is 1 - cosine similarity.
+				r.Score = float32(1 - distance)
+			}
+		}
+		delete(obj, "_additional")
+		r.Payload = obj
+		results = append(results, r)
+	}
+	return results, nil
+}
+
+// classProperties returns the primitive property names of a class, which
+// GraphQL requires to be listed explicitly in Get queries.
+func (c *Client) classProperties(ctx context.Context, class string) ([]string, error) {
+	c.mu.Lock()
+	props, ok := c.properties[class]
+	c.mu.Unlock()
+	if ok {
+		return props, nil
+	}
+
+	var schema struct {
+		Properties []struct {
+			Name     string   `json:"name"`
+			DataType []string `json:"dataType"`
+		} `json:"properties"`
+	}
+	if _, err := c.do(ctx, http.MethodGet, "/v1/schema/"+class, nil, &schema); err != nil {
+		return nil, fmt.Errorf("failed to get class schema: %w", err)
+	}
+
+	props = make([]string, 0, len(schema.Properties))
+	for _, p := range schema.Properties {
+		if len(p.DataType) == 1 && isPrimitiveType(p.DataType[0]) {
+			props = append(props, p.Name)
+		}
+	}
+	sort.Strings(props)
+
+	c.mu.Lock()
+	c.properties[class] = props
+	c.mu.Unlock()
+
+	return props, nil
+}
+
+func isPrimitiveType(dataType string) bool {
+	switch strings.TrimSuffix(dataType, "[]") {
+	case "text", "string", "int", "number", "boolean", "date", "uuid":
+		return true
+	}
+	return false
+}
+
+// whereClause converts exact-match string filters, plus any extra operands,
+// into a GraphQL where argument. Keys are sorted so the generated query is
+// deterministic.
+func whereClause(filters map[string]interface{}, extra ...string) string {
+	keys := make([]string, 0, len(filters))
+	for k, v := range filters {
+		if _, ok := v.(string); ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	operands := make([]string, 0, len(keys)+len(extra))
+	for _, k := range keys {
+		operands = append(operands, fmt.Sprintf("{path: [%s], operator: Equal, valueText: %s}",
+			graphQLString(k), graphQLString(filters[k].(string))))
+	}
+	operands = append(operands, extra...)
+	if len(operands) == 0 {
+		return ""
+	}
+	if len(operands) == 1 {
+		return operands[0]
+	}
+	return fmt.Sprintf("{operator: And, operands: [%s]}", strings.Join(operands, ", "))
+}
+
+// graphQLString quotes s as a GraphQL string literal. JSON string escaping is
+// a valid subset of GraphQL string escaping.
+func graphQLString(s string) string {
+	b, _ := json.Marshal(s)
+	return string(b)
+}
@@ -0,0 +1,180 @@
+package weaviate
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/sipeed/picoclaw/pkg/memory"
+)
+
+func TestClassName(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"picoclaw", "Picoclaw"},
+		{"agent-memory", "Agent_memory"},
+		{"9lives", "C_9lives"},
+		{"", "Picoclaw"},
+	}
+	for _, tt := range tests {
+		if got := className(tt.in); got != tt.want {
+			t.Errorf("className(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestWhereClause(t *testing.T) {
+	if got := whereClause(nil); got != "" {
+		t.Errorf("whereClause(nil) = %q, want empty", got)
+	}
+
+	got := whereClause(map[string]interface{}{"workspace_id": "ws"})
+	want := `{path: ["workspace_id"], operator: Equal, valueText: "ws"}`
+	if got != want {
+		t.Errorf("single filter = %q, want %q", got, want)
+	}
+
+	got = whereClause(map[string]interface{}{"workspace_id": "ws", "channel": `a"b`})
+	want = `{operator: And, operands: [` +
+		`{path: ["channel"], operator: Equal, valueText: "a\"b"}, ` +
+		`{path: ["workspace_id"], operator: Equal, valueText: "ws"}]}`
+	if got != want {
+		t.Errorf("multiple filters = %q, want %q", got, want)
+	}
+}
+
+func TestSearch_ParsesGraphQLResponse(t *testing.T) {
+	var query string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			t.Errorf("missing bearer token, got %q", r.Header.Get("Authorization"))
+		}
+		switch r.URL.Path {
+		case "/v1/schema/Picoclaw":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"properties": []map[string]interface{}{
+					{"name": "content", "dataType": []string{"text"}},
+					{"name": "timestamp", "dataType": []string{"int"}},
+				},
+			})
+		case "/v1/graphql":
+			var body map[string]string
+			json.NewDecoder(r.Body).Decode(&body)
+			query = body["query"]
+			w.Write([]byte(`{"data":{"Get":{"Picoclaw":[` +
+				`{"content":"hello","timestamp":100,"_additional":{"id":"abc","distance":0.25}}]}}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL, "secret")
+	results, err := c.Search(context.Background(), "picoclaw", []float32{0.5, 1}, 3, 2,
+		map[string]interface{}{"workspace_id": "ws"})
+	if err != nil {
+		t.Fatalf("Search() error: %v", err)
+	}
+
+	for _, part := range []string{
+		"nearVector: {vector: [0.5,1]}",
+		"limit: 3",
+		"offset: 2",
+		"content timestamp",
+		`valueText: "ws"`,
+	} {
+		if !strings.Contains(query, part) {
+			t.Errorf("query %q missing %q", query, part)
+		}
+	}
+
+	if len(results) != 1 {
+		t.Fatalf("expected 1 result, got %d", len(results))
+	}
+	r := results[0]
+	if r.ID != "abc" || r.Score != 0.75 || r.Payload["content"] != "hello" {
+		t.Errorf("unexpected result: %+v", r)
+	}
+	if _, ok := r.Payload["_additional"]; ok {
+		t.Error("_additional should not be exposed in payload")
+	}
+}
+
+// TestContract runs the VectorDB contract against a live Weaviate instance.
+// Set WEAVIATE_URL (e.g. http://localhost:8080) to enable it.
+func TestContract(t *testing.T) {
+	addr := os.Getenv("WEAVIATE_URL")
+	if addr == "" {
+		t.Skip("WEAVIATE_URL not set")
+	}
+
+	ctx := context.Background()
+	var db memory.VectorDB = NewClient(addr, os.Getenv("WEAVIATE_API_KEY"))
+	defer db.Close()
+
+	collection := "picoclaw_test_" + strings.ReplaceAll(uuid.NewString()[:8], "-", "")
+	if err := db.EnsureCollection(ctx, collection, 2); err != nil {
+		t.Fatalf("EnsureCollection() error: %v", err)
+	}
+	if err := db.EnsureCollection(ctx, collection, 2); err != nil {
+		t.Fatalf("EnsureCollection() should be idempotent: %v", err)
+	}
+
+	records := []memory.VectorRecord{
+		{ID: uuid.NewString(), Vector: []float32{1, 0}, Payload: map[string]interface{}{
+			"workspace_id": "ws", "content": "the quick brown fox", "content_hash": "h1",
+		}},
+		{ID: uuid.NewString(), Vector: []float32{0, 1}, Payload: map[string]interface{}{
+			"workspace_id": "ws", "content": "lazy dogs sleep", "content_hash": "h2",
+		}},
+		{ID: uuid.NewString(), Vector: []float32{1, 0}, Payload: map[string]interface{}{
+			"workspace_id": "other", "content": "the quick brown fox", "content_hash": "h1",
+		}},
+	}
+	for _, r := range records {
+		if err := db.Store(ctx, collection, r); err != nil {
+			t.Fatalf("Store() error: %v", err)
+		}
+	}
+
+	filter := map[string]interface{}{"workspace_id": "ws"}
+
+	results, err := db.Search(ctx, collection, []float32{1, 0}, 10, 0, filter)
+	if err != nil {
+		t.Fatalf("Search() error: %v", err)
+	}
+	if len(results) != 2 || results[0].ID != records[0].ID {
+		t.Errorf("Search() = %+v, want 2 results led by %s", results, records[0].ID)
+	}
+
+	page, err := db.Search(ctx, collection, []float32{1, 0}, 1, 1, filter)
+	if err != nil {
+		t.Fatalf("Search() with offset error: %v", err)
+	}
+	if len(page) != 1 || page[0].ID != records[1].ID {
+		t.Errorf("Search() offset=1 = %+v, want %s", page, records[1].ID)
+	}
+
+	keyword, err := db.KeywordSearch(ctx, collection, "dogs", 10, filter)
+	if err != nil {
+		t.Fatalf("KeywordSearch() error: %v", err)
+	}
+	if len(keyword) != 1 || keyword[0].ID != records[1].ID {
+		t.Errorf("KeywordSearch() = %+v, want %s", keyword, records[1].ID)
+	}
+
+	n, err := db.Count(ctx, collection, map[string]interface{}{"workspace_id": "ws", "content_hash": "h1"})
+	if err != nil {
+		t.Fatalf("Count() error: %v", err)
+	}
+	if n != 1 {
+		t.Errorf("Count() = %d, want 1", n)
+	}
+}
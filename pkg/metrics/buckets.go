@@ -0,0 +1,81 @@
+package metrics
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// DefaultLLMDurationBuckets are the picoclaw_llm_request_duration_seconds
+// buckets used unless overridden by configuration.
+var DefaultLLMDurationBuckets = []float64{0.1, 0.5, 1, 2, 5, 10, 20, 30, 60}
+
+var (
+	llmRequestDuration atomic.Pointer[prometheus.HistogramVec]
+
+	// llmDurationMu serialises SetLLMDurationBuckets; llmDurationBuckets is
+	// the bucket layout currently registered.
+	llmDurationMu      sync.Mutex
+	llmDurationBuckets []float64
+)
+
+func init() {
+	h := NewLLMRequestDuration(nil)
+	prometheus.MustRegister(h)
+	llmRequestDuration.Store(h)
+	llmDurationBuckets = DefaultLLMDurationBuckets
+}
+
+// NewLLMRequestDuration builds the LLM request duration histogram with the
+// given buckets, or DefaultLLMDurationBuckets when buckets is empty. The
+// result is not registered.
+func NewLLMRequestDuration(buckets []float64) *prometheus.HistogramVec {
+	if len(buckets) == 0 {
+		buckets = DefaultLLMDurationBuckets
+	}
+	return prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "picoclaw_llm_request_duration_seconds",
+		Help:    "Duration of LLM requests.",
+		Buckets: buckets,
+	}, []string{"model", "provider", "api_base", "agent_type", "status"})
+}
+
+// SetLLMDurationBuckets replaces the registered LLM request duration
+// histogram with one using buckets; empty buckets restore the defaults.
+// Observations recorded under the previous layout are dropped, so this is
+// a no-op when the layout is unchanged.
+func SetLLMDurationBuckets(buckets []float64) error {
+	if len(buckets) == 0 {
+		buckets = DefaultLLMDurationBuckets
+	}
+	for i := 1; i < len(buckets); i++ {
+		if buckets[i] <= buckets[i-1] {
+			return fmt.Errorf("LLM duration buckets must be strictly increasing, got %v", buckets)
+		}
+	}
+
+	llmDurationMu.Lock()
+	defer llmDurationMu.Unlock()
+	if slices.Equal(buckets, llmDurationBuckets) {
+		return nil
+	}
+
+	// Both histograms share a name, so the new one can only be registered
+	// once the old one is gone. Put the old one back if that fails so the
+	// metric keeps being exported.
+	old := llmRequestDuration.Load()
+	h := NewLLMRequestDuration(buckets)
+	prometheus.Unregister(old)
+	if err := prometheus.Register(h); err != nil {
+		if rerr := prometheus.Register(old); rerr != nil {
+			return fmt.Errorf("failed to register LLM duration histogram: %w (restoring the previous one: %v)", err, rerr)
+		}
+		return fmt.Errorf("failed to register LLM duration histogram: %w", err)
+	}
+	llmRequestDuration.Store(h)
+	llmDurationBuckets = slices.Clone(buckets)
+	return nil
+}
@@ -0,0 +1,68 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// bucketCounts returns the cumulative count per upper bound.
+func bucketCounts(h *dto.Histogram) map[float64]uint64 {
+	out := make(map[float64]uint64)
+	for _, b := range h.GetBucket() {
+		out[b.GetUpperBound()] = b.GetCumulativeCount()
+	}
+	return out
+}
+
+func TestNewLLMRequestDuration_CustomBuckets(t *testing.T) {
+	h := NewLLMRequestDuration([]float64{0.01, 0.05, 0.25})
+	obs := h.WithLabelValues("local", "ollama", "", "main", "success")
+	obs.Observe(0.03)
+	obs.Observe(0.2)
+	obs.Observe(3)
+
+	var m dto.Metric
+	if err := h.WithLabelValues("local", "ollama", "", "main", "success").(prometheus.Metric).Write(&m); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	got := bucketCounts(m.GetHistogram())
+	want := map[float64]uint64{0.01: 0, 0.05: 1, 0.25: 2}
+	if len(got) != len(want) {
+		t.Fatalf("buckets = %v, want %v", got, want)
+	}
+	for le, n := range want {
+		if got[le] != n {
+			t.Errorf("bucket le=%v count = %d, want %d", le, got[le], n)
+		}
+	}
+	if m.GetHistogram().GetSampleCount() != 3 {
+		t.Errorf("sample count = %d, want 3", m.GetHistogram().GetSampleCount())
+	}
+}
+
+func TestSetLLMDurationBuckets(t *testing.T) {
+	t.Cleanup(func() { _ = SetLLMDurationBuckets(nil) })
+
+	if err := SetLLMDurationBuckets([]float64{1, 1, 2}); err == nil {
+		t.Error("expected an error for non-increasing buckets")
+	}
+	if err := SetLLMDurationBuckets([]float64{30, 120, 600}); err != nil {
+		t.Fatalf("SetLLMDurationBuckets: %v", err)
+	}
+
+	r := &Recorder{startTime: time.Now()}
+	r.RecordLLMCall("slow-model", "local", "", "main", "success", 90*time.Second, nil, 0)
+
+	var m dto.Metric
+	h := llmRequestDuration.Load().WithLabelValues("slow-model", "local", "", "main", "success")
+	if err := h.(prometheus.Metric).Write(&m); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	got := bucketCounts(m.GetHistogram())
+	if got[30] != 0 || got[120] != 1 || got[600] != 1 {
+		t.Errorf("buckets = %v, want the 90s observation in le=120", got)
+	}
+}
@@ -0,0 +1,48 @@
+package metrics
+
+import "sync"
+
+const (
+	// DefaultMaxUserIDs is the default number of distinct user_id label
+	// values tracked before new users are recorded as OtherLabel.
+	DefaultMaxUserIDs = 1000
+
+	// OtherLabel replaces label values beyond a cardinality cap.
+	OtherLabel = "other"
+)
+
+// boundedLabelSet caps the number of distinct values a label may take.
+// Values seen before the cap was reached keep their own series.
+type boundedLabelSet struct {
+	mu   sync.Mutex
+	max  int
+	seen map[string]struct{}
+}
+
+func newBoundedLabelSet(max int) *boundedLabelSet {
+	return &boundedLabelSet{max: max, seen: make(map[string]struct{})}
+}
+
+// value returns v if it is already tracked or there is room to track it,
+// and OtherLabel otherwise. A nil set does not cap anything.
+func (s *boundedLabelSet) value(v string) string {
+	if s == nil {
+		return v
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.seen[v]; ok {
+		return v
+	}
+	if len(s.seen) >= s.max {
+		return OtherLabel
+	}
+	s.seen[v] = struct{}{}
+	return v
+}
+
+func (s *boundedLabelSet) setMax(max int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.max = max
+}
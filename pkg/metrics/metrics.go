@@ -7,11 +7,7 @@ import (
 
 var (
 	// --- LLM Performance Metrics ---
-	llmRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
-		Name:    "picoclaw_llm_request_duration_seconds",
-		Help:    "Duration of LLM requests.",
-		Buckets: []float64{0.1, 0.5, 1, 2, 5, 10, 20, 30, 60},
-	}, []string{"model", "provider", "api_base", "agent_type", "status"})
+	// llmRequestDuration has configurable buckets; see buckets.go.
 
 	llmTokensPrompt = promauto.NewCounterVec(prometheus.CounterOpts{
 		Name: "picoclaw_llm_tokens_prompt_total",
@@ -45,6 +41,11 @@ var (
 		Help: "Total LLM requests attempted.",
 	}, []string{"model", "provider", "agent_type"})
 
+	llmCacheLookups = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "picoclaw_llm_cache_lookups_total",
+		Help: "Total LLM response cache lookups by result (hit or miss).",
+	}, []string{"model", "result"})
+
 	// --- Tool Usage Metrics ---
 	toolCalls = promauto.NewCounterVec(prometheus.CounterOpts{
 		Name: "picoclaw_tool_calls_total",
@@ -98,6 +99,12 @@ var (
 		Buckets: []float64{0.5, 1, 2, 5, 10, 20, 60},
 	}, []string{"model"})
 
+	agentTimeToFirstToken = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "picoclaw_llm_time_to_first_token_seconds",
+		Help:    "Time from a streaming LLM request to its first chunk.",
+		Buckets: []float64{0.1, 0.25, 0.5, 1, 2, 5, 10, 30},
+	}, []string{"model"})
+
 	// --- Subagent Metrics ---
 	subagentSpawns = promauto.NewCounterVec(prometheus.CounterOpts{
 		Name: "picoclaw_subagent_spawns_total",
@@ -2,8 +2,11 @@ package metrics
 
 import (
 	"context"
+	"math"
 	"testing"
 	"time"
+
+	dto "github.com/prometheus/client_model/go"
 )
 
 func TestRecorder_NoPanic(t *testing.T) {
@@ -29,6 +32,10 @@ func TestRecorder_NoPanic(t *testing.T) {
 	t.Run("RecordAgentTurn", func(t *testing.T) {
 		r.RecordAgentTurn("gpt-4", "discord", "default", "main", 1*time.Second, 3, 2)
 	})
+
+	t.Run("RecordTimeToFirstTool", func(t *testing.T) {
+		r.RecordTimeToFirstTool("gpt-4", 750*time.Millisecond)
+	})
 }
 
 func TestWithAgentType(t *testing.T) {
@@ -48,3 +55,98 @@ func TestAgentTypeFromContext_Default(t *testing.T) {
 		t.Errorf("expected default agent type %s, got %s", AgentTypeMain, val)
 	}
 }
+
+func TestRecordLLMCost(t *testing.T) {
+	SetModelPrice("test-cost-model", ModelPrice{InputPer1K: 0.003, OutputPer1K: 0.015})
+	usage := &LLMUsageInfo{PromptTokens: 2000, CompletionTokens: 500, TotalTokens: 2500}
+
+	cost, ok := EstimateLLMCost("test-cost-model", usage)
+	if !ok {
+		t.Fatal("expected price for configured model")
+	}
+	// 2 * 0.003 + 0.5 * 0.015
+	if want := 0.0135; math.Abs(cost-want) > 1e-9 {
+		t.Errorf("cost = %v, want %v", cost, want)
+	}
+
+	r := &Recorder{startTime: time.Now()}
+	r.RecordLLMCost("test-cost-model", usage)
+	r.RecordLLMCost("test-cost-model", usage)
+
+	var m dto.Metric
+	if err := llmCost.WithLabelValues("test-cost-model").Write(&m); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if got := m.GetCounter().GetValue(); math.Abs(got-0.027) > 1e-9 {
+		t.Errorf("counter = %v, want 0.027", got)
+	}
+
+	if _, ok := EstimateLLMCost("unpriced-model", usage); ok {
+		t.Error("expected no price for unknown model")
+	}
+}
+
+type fakeFirstToolRecorder struct {
+	models    []string
+	durations []time.Duration
+}
+
+func (f *fakeFirstToolRecorder) RecordTimeToFirstTool(model string, d time.Duration) {
+	f.models = append(f.models, model)
+	f.durations = append(f.durations, d)
+}
+
+func TestFirstToolTimer_RecordsOnce(t *testing.T) {
+	rec := &fakeFirstToolRecorder{}
+	timer := NewFirstToolTimer(rec, "gpt-4", time.Now().Add(-2*time.Second))
+
+	timer.ToolDispatched()
+	timer.ToolDispatched()
+
+	if len(rec.models) != 1 {
+		t.Fatalf("expected exactly one observation, got %d", len(rec.models))
+	}
+	if rec.models[0] != "gpt-4" {
+		t.Errorf("model = %q, want gpt-4", rec.models[0])
+	}
+	if rec.durations[0] < 2*time.Second {
+		t.Errorf("duration = %v, want >= 2s", rec.durations[0])
+	}
+
+	var nilTimer *FirstToolTimer
+	nilTimer.ToolDispatched() // must not panic
+}
+
+func TestRecordUserRequest_CapsUserIDs(t *testing.T) {
+	const ws, agentID = "cap-test-workspace", "cap-test-agent"
+	r := &Recorder{startTime: time.Now(), userIDs: newBoundedLabelSet(2)}
+
+	for _, id := range []string{"alice", "bob", "carol", "alice", "dave"} {
+		r.RecordUserRequest(id, "telegram", ws, agentID)
+	}
+
+	count := func(userID string) float64 {
+		var m dto.Metric
+		if err := userRequests.WithLabelValues(userID, "telegram", ws, agentID).Write(&m); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+		return m.GetCounter().GetValue()
+	}
+	if got := count("alice"); got != 2 {
+		t.Errorf("alice = %v, want 2", got)
+	}
+	if got := count("bob"); got != 1 {
+		t.Errorf("bob = %v, want 1", got)
+	}
+	if got := count(OtherLabel); got != 2 {
+		t.Errorf("%s = %v, want 2 (carol and dave)", OtherLabel, got)
+	}
+
+	var m dto.Metric
+	if err := workspaceRequests.WithLabelValues(ws, agentID).Write(&m); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if got := m.GetCounter().GetValue(); got != 5 {
+		t.Errorf("workspace requests = %v, want 5", got)
+	}
+}
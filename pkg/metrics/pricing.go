@@ -0,0 +1,73 @@
+package metrics
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// ModelPrice is the USD price per 1K tokens for a model.
+type ModelPrice struct {
+	InputPer1K  float64
+	OutputPer1K float64
+}
+
+// defaultPricing holds list prices for common models. Entries can be
+// overridden or extended at runtime with SetModelPrice.
+var defaultPricing = map[string]ModelPrice{
+	"gpt-4o":            {InputPer1K: 0.0025, OutputPer1K: 0.01},
+	"gpt-4o-mini":       {InputPer1K: 0.00015, OutputPer1K: 0.0006},
+	"gpt-4":             {InputPer1K: 0.03, OutputPer1K: 0.06},
+	"claude-sonnet-4.6": {InputPer1K: 0.003, OutputPer1K: 0.015},
+	"claude-haiku-4.5":  {InputPer1K: 0.001, OutputPer1K: 0.005},
+}
+
+var (
+	pricingMu sync.RWMutex
+	pricing   = copyPricing(defaultPricing)
+
+	llmCost = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "picoclaw_llm_cost_usd_total",
+		Help: "Estimated LLM spend in USD, based on the configured per-model pricing.",
+	}, []string{"model"})
+)
+
+func copyPricing(src map[string]ModelPrice) map[string]ModelPrice {
+	dst := make(map[string]ModelPrice, len(src))
+	for k, v := range src {
+		dst[k] = v
+	}
+	return dst
+}
+
+// SetModelPrice sets or replaces the price used for model.
+func SetModelPrice(model string, price ModelPrice) {
+	pricingMu.Lock()
+	defer pricingMu.Unlock()
+	pricing[model] = price
+}
+
+// EstimateLLMCost returns the USD cost of usage for model. ok is false when
+// the model has no known price.
+func EstimateLLMCost(model string, usage *LLMUsageInfo) (cost float64, ok bool) {
+	if usage == nil {
+		return 0, false
+	}
+	pricingMu.RLock()
+	price, ok := pricing[model]
+	pricingMu.RUnlock()
+	if !ok {
+		return 0, false
+	}
+	return float64(usage.PromptTokens)/1000*price.InputPer1K +
+		float64(usage.CompletionTokens)/1000*price.OutputPer1K, true
+}
+
+// RecordLLMCost adds the estimated cost of an LLM call to the cost counter.
+// Calls for models without a known price are ignored.
+func (r *Recorder) RecordLLMCost(model string, usage *LLMUsageInfo) {
+	if cost, ok := EstimateLLMCost(model, usage); ok && cost > 0 {
+		llmCost.WithLabelValues(model).Add(cost)
+	}
+}
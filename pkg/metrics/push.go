@@ -0,0 +1,25 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/push"
+)
+
+// PushMetrics pushes every metric in the default registry to the Prometheus
+// Pushgateway at pushgatewayURL under jobName. Short-lived processes such as
+// one-shot agent runs call it on shutdown so their metrics outlive them.
+func PushMetrics(ctx context.Context, pushgatewayURL, jobName string) error {
+	if pushgatewayURL == "" {
+		return fmt.Errorf("pushgateway URL is empty")
+	}
+	if jobName == "" {
+		jobName = "picoclaw"
+	}
+	if err := push.New(pushgatewayURL, jobName).Gatherer(prometheus.DefaultGatherer).PushContext(ctx); err != nil {
+		return fmt.Errorf("failed to push metrics to %s: %w", pushgatewayURL, err)
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package metrics
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestPushMetrics(t *testing.T) {
+	var gotMethod, gotPath, gotBody string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		gotMethod, gotPath, gotBody = r.Method, r.URL.Path, string(body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	DefaultRecorder().RecordBusDrop("push-test")
+	if err := PushMetrics(context.Background(), srv.URL, "picoclaw-cron"); err != nil {
+		t.Fatalf("PushMetrics: %v", err)
+	}
+
+	if gotMethod != http.MethodPut {
+		t.Errorf("method = %s, want PUT", gotMethod)
+	}
+	if gotPath != "/metrics/job/picoclaw-cron" {
+		t.Errorf("path = %s, want /metrics/job/picoclaw-cron", gotPath)
+	}
+	if !strings.Contains(gotBody, "picoclaw_bus_drops_total") {
+		t.Error("pushed payload does not contain picoclaw_bus_drops_total")
+	}
+}
+
+func TestPushMetrics_ServerError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	if err := PushMetrics(context.Background(), srv.URL, "picoclaw"); err == nil {
+		t.Error("expected an error when the pushgateway rejects the push")
+	}
+}
+
+func TestPushMetrics_EmptyURL(t *testing.T) {
+	if err := PushMetrics(context.Background(), "", "picoclaw"); err == nil {
+		t.Error("expected an error for an empty pushgateway URL")
+	}
+}
@@ -2,6 +2,7 @@ package metrics
 
 import (
 	"context"
+	"sync/atomic"
 	"time"
 )
 
@@ -35,9 +36,13 @@ func AgentTypeFromContext(ctx context.Context) string {
 // Recorder provides high-level methods for recording metrics.
 type Recorder struct {
 	startTime time.Time
+	userIDs   *boundedLabelSet
 }
 
-var defaultRecorder = &Recorder{startTime: time.Now()}
+var defaultRecorder = &Recorder{
+	startTime: time.Now(),
+	userIDs:   newBoundedLabelSet(DefaultMaxUserIDs),
+}
 
 // DefaultRecorder returns the singleton recorder instance.
 func DefaultRecorder() *Recorder {
@@ -54,7 +59,7 @@ type LLMUsageInfo struct {
 // RecordLLMCall records duration, tokens, and errors for an LLM request.
 func (r *Recorder) RecordLLMCall(model, provider, apiBase, agentType, status string, duration time.Duration, usage *LLMUsageInfo, contextSize int) {
 	llmRequests.WithLabelValues(model, provider, agentType).Inc()
-	llmRequestDuration.WithLabelValues(model, provider, apiBase, agentType, status).Observe(duration.Seconds())
+	llmRequestDuration.Load().WithLabelValues(model, provider, apiBase, agentType, status).Observe(duration.Seconds())
 
 	if usage != nil {
 		llmTokensPrompt.WithLabelValues(model, provider, apiBase, agentType).Add(float64(usage.PromptTokens))
@@ -90,6 +95,11 @@ func (r *Recorder) RecordToolCall(name, agentType, status string, duration time.
 	}
 }
 
+// RecordToolResultSize records the size of a tool result in bytes.
+func (r *Recorder) RecordToolResultSize(name string, size int) {
+	toolResultSize.WithLabelValues(name).Observe(float64(size))
+}
+
 // RecordToolError records a tool execution error.
 func (r *Recorder) RecordToolError(name, errorType string) {
 	toolErrors.WithLabelValues(name, errorType).Inc()
@@ -105,6 +115,24 @@ func (r *Recorder) RecordBusDrop(direction string) {
 	busDrops.WithLabelValues(direction).Inc()
 }
 
+// RecordUserRequest records a request from a user to an agent. Once the
+// user_id cap is reached, requests from users not seen before are recorded
+// under OtherLabel.
+func (r *Recorder) RecordUserRequest(userID, channel, workspace, agentID string) {
+	userRequests.WithLabelValues(r.userIDs.value(userID), channel, workspace, agentID).Inc()
+	workspaceRequests.WithLabelValues(workspace, agentID).Inc()
+}
+
+// SetMaxUserIDs sets the number of distinct user_id label values tracked.
+// Non-positive values are ignored. Lowering the cap does not forget users
+// already tracked.
+func (r *Recorder) SetMaxUserIDs(n int) {
+	if n <= 0 || r.userIDs == nil {
+		return
+	}
+	r.userIDs.setMax(n)
+}
+
 // RecordAgentTurn records end-to-end turn metrics.
 func (r *Recorder) RecordAgentTurn(model, channel, workspace, agentType string, duration time.Duration, iterations, tools int) {
 	agentTurns.WithLabelValues(model, channel, workspace, agentType).Inc()
@@ -113,6 +141,60 @@ func (r *Recorder) RecordAgentTurn(model, channel, workspace, agentType string,
 	agentToolsPerTurn.WithLabelValues(model, agentType).Observe(float64(tools))
 }
 
+// RecordLLMCacheLookup records a response cache hit or miss for model.
+func (r *Recorder) RecordLLMCacheLookup(model string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	llmCacheLookups.WithLabelValues(model, result).Inc()
+}
+
+// RecordTimeToFirstToken records how long a streaming LLM call took to
+// produce its first chunk.
+func (r *Recorder) RecordTimeToFirstToken(model string, d time.Duration) {
+	agentTimeToFirstToken.WithLabelValues(model).Observe(d.Seconds())
+}
+
+// RecordTimeToFirstTool records the time from receiving a user message to
+// dispatching the turn's first tool call. The agent loop reports this through
+// a FirstToolTimer started with the turn.
+func (r *Recorder) RecordTimeToFirstTool(model string, d time.Duration) {
+	agentTimeToFirstTool.WithLabelValues(model).Observe(d.Seconds())
+}
+
+// TimeToFirstToolRecorder is the part of Recorder used by FirstToolTimer.
+type TimeToFirstToolRecorder interface {
+	RecordTimeToFirstTool(model string, d time.Duration)
+}
+
+// FirstToolTimer measures time-to-first-tool for a single agent turn.
+type FirstToolTimer struct {
+	rec   TimeToFirstToolRecorder
+	model string
+	start time.Time
+	done  atomic.Bool
+}
+
+// NewFirstToolTimer starts timing a turn that began at start.
+func NewFirstToolTimer(rec TimeToFirstToolRecorder, model string, start time.Time) *FirstToolTimer {
+	return &FirstToolTimer{rec: rec, model: model, start: start}
+}
+
+// ToolDispatched records the elapsed time on the first call and is a no-op
+// afterwards.
+func (t *FirstToolTimer) ToolDispatched() {
+	if t == nil || !t.done.CompareAndSwap(false, true) {
+		return
+	}
+	t.rec.RecordTimeToFirstTool(t.model, time.Since(t.start))
+}
+
+// RecordSummarization records how long summarizing a conversation took.
+func (r *Recorder) RecordSummarization(model string, duration time.Duration) {
+	summarizationDuration.WithLabelValues(model).Observe(duration.Seconds())
+}
+
 // RecordSubagentDuration records subagent execution duration.
 func (r *Recorder) RecordSubagentDuration(model, role, subType, status string, duration time.Duration) {
 	subagentDuration.WithLabelValues(model, role, subType, status).Observe(duration.Seconds())
@@ -130,6 +212,11 @@ func (r *Recorder) RecordCronExecution(jobName, status, payloadKind string, dura
 	cronDuration.WithLabelValues(jobName).Observe(duration.Seconds())
 }
 
+// RecordCronMissed records n scheduled cron runs that did not happen.
+func (r *Recorder) RecordCronMissed(n int) {
+	cronMissed.Add(float64(n))
+}
+
 // UpdateUptime updates the application uptime metric.
 func (r *Recorder) UpdateUptime() {
 	uptimeGauge.Set(time.Since(r.startTime).Seconds())
@@ -33,6 +33,25 @@ type FamilyStore struct {
 	mu     sync.RWMutex
 	chores map[string]*Chore
 	lists  map[string]*List
+	path   string // JSON file backing the store; empty means in-memory only
+
+	notifier Notifier
+}
+
+// Notifier receives chore lifecycle events, e.g. to drop a mailbox message
+// for the affected family member. Either callback may be nil. Callbacks run
+// after the store lock is released and receive a copy of the chore.
+type Notifier struct {
+	OnChoreAssigned func(ctx context.Context, chore Chore)
+	OnChoreVerified func(ctx context.Context, chore Chore, approved bool)
+}
+
+// SetNotifier installs the callbacks fired when chores are assigned,
+// reassigned or verified.
+func (s *FamilyStore) SetNotifier(n Notifier) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.notifier = n
 }
 
 func NewFamilyStore() *FamilyStore {
@@ -43,6 +62,17 @@ func NewFamilyStore() *FamilyStore {
 }
 
 func (s *FamilyStore) AssignChore(ctx context.Context, assigner, assignee, title, description string) (string, error) {
+	assigned, n, err := s.assignChore(assigner, assignee, title, description)
+	if err != nil {
+		return "", err
+	}
+	if n.OnChoreAssigned != nil {
+		n.OnChoreAssigned(ctx, assigned)
+	}
+	return assigned.ID, nil
+}
+
+func (s *FamilyStore) assignChore(assigner, assignee, title, description string) (Chore, Notifier, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
@@ -57,7 +87,10 @@ func (s *FamilyStore) AssignChore(ctx context.Context, assigner, assignee, title
 		CreatedAt:   time.Now(),
 	}
 	s.chores[id] = c
-	return id, nil
+	if err := s.saveLocked(); err != nil {
+		return Chore{}, Notifier{}, err
+	}
+	return *c, s.notifier, nil
 }
 
 func (s *FamilyStore) ListChores(ctx context.Context, user string) ([]Chore, error) {
@@ -74,6 +107,25 @@ func (s *FamilyStore) ListChores(ctx context.Context, user string) ([]Chore, err
 	return result, nil
 }
 
+// GetChore returns a copy of a single chore visible to user (its assignee or
+// assigner).
+func (s *FamilyStore) GetChore(ctx context.Context, user, choreID string) (*Chore, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	chore, ok := s.chores[choreID]
+	if !ok {
+		return nil, fmt.Errorf("chore not found")
+	}
+
+	if chore.Assignee != user && chore.Assigner != user {
+		return nil, fmt.Errorf("unauthorized to view this chore")
+	}
+
+	c := *chore
+	return &c, nil
+}
+
 func (s *FamilyStore) CompleteChore(ctx context.Context, user, choreID string) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -95,24 +147,35 @@ func (s *FamilyStore) CompleteChore(ctx context.Context, user, choreID string) e
 	now := time.Now()
 	chore.CompletedAt = &now
 
-	return nil
+	return s.saveLocked()
 }
 
 func (s *FamilyStore) VerifyChore(ctx context.Context, user, choreID string, approved bool) error {
+	verified, n, err := s.verifyChore(user, choreID, approved)
+	if err != nil {
+		return err
+	}
+	if n.OnChoreVerified != nil {
+		n.OnChoreVerified(ctx, verified, approved)
+	}
+	return nil
+}
+
+func (s *FamilyStore) verifyChore(user, choreID string, approved bool) (Chore, Notifier, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
 	chore, ok := s.chores[choreID]
 	if !ok {
-		return fmt.Errorf("chore not found")
+		return Chore{}, Notifier{}, fmt.Errorf("chore not found")
 	}
 
 	if chore.Assigner != user {
-		return fmt.Errorf("unauthorized to verify this chore")
+		return Chore{}, Notifier{}, fmt.Errorf("unauthorized to verify this chore")
 	}
 
 	if chore.Status != StatusCompleted {
-		return fmt.Errorf("chore is not completed yet")
+		return Chore{}, Notifier{}, fmt.Errorf("chore is not completed yet")
 	}
 
 	if approved {
@@ -124,5 +187,45 @@ func (s *FamilyStore) VerifyChore(ctx context.Context, user, choreID string, app
 		chore.CompletedAt = nil
 	}
 
+	if err := s.saveLocked(); err != nil {
+		return Chore{}, Notifier{}, err
+	}
+	return *chore, s.notifier, nil
+}
+
+// ReassignChore moves a chore to a different assignee. Only the original
+// assigner may reassign; the chore goes back to pending.
+func (s *FamilyStore) ReassignChore(ctx context.Context, assigner, choreID, newAssignee string) error {
+	reassigned, n, err := s.reassignChore(assigner, choreID, newAssignee)
+	if err != nil {
+		return err
+	}
+	if n.OnChoreAssigned != nil {
+		n.OnChoreAssigned(ctx, reassigned)
+	}
 	return nil
 }
+
+func (s *FamilyStore) reassignChore(assigner, choreID, newAssignee string) (Chore, Notifier, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	chore, ok := s.chores[choreID]
+	if !ok {
+		return Chore{}, Notifier{}, fmt.Errorf("chore not found")
+	}
+
+	if chore.Assigner != assigner {
+		return Chore{}, Notifier{}, fmt.Errorf("unauthorized to reassign this chore")
+	}
+
+	chore.Assignee = newAssignee
+	chore.Status = StatusPending
+	chore.CompletedAt = nil
+	chore.VerifiedAt = nil
+
+	if err := s.saveLocked(); err != nil {
+		return Chore{}, Notifier{}, err
+	}
+	return *chore, s.notifier, nil
+}
@@ -69,4 +69,101 @@ func TestChoresStore(t *testing.T) {
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "unauthorized")
 	})
+	t.Run("Reassign Chore", func(t *testing.T) {
+		choreID, _ := store.AssignChore(ctx, "dad", "kid", "Walk the dog", "")
+		require.NoError(t, store.CompleteChore(ctx, "kid", choreID))
+
+		err := store.ReassignChore(ctx, "dad", choreID, "sibling")
+		require.NoError(t, err)
+
+		chores, _ := store.ListChores(ctx, "sibling")
+		require.Len(t, chores, 1)
+		assert.Equal(t, choreID, chores[0].ID)
+		assert.Equal(t, "sibling", chores[0].Assignee)
+		assert.Equal(t, StatusPending, chores[0].Status)
+		assert.Nil(t, chores[0].CompletedAt)
+		assert.Nil(t, chores[0].VerifiedAt)
+
+		chores, _ = store.ListChores(ctx, "kid")
+		for _, c := range chores {
+			assert.NotEqual(t, choreID, c.ID)
+		}
+	})
+
+	t.Run("Unauthorized Reassignment", func(t *testing.T) {
+		choreID, _ := store.AssignChore(ctx, "dad", "kid", "Set the table", "")
+
+		// Assignee tries to hand it off to a sibling
+		err := store.ReassignChore(ctx, "kid", choreID, "sibling")
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "unauthorized")
+
+		err = store.ReassignChore(ctx, "dad", "missing", "sibling")
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "not found")
+	})
+	t.Run("Get Chore", func(t *testing.T) {
+		choreID, _ := store.AssignChore(ctx, "dad", "kid", "Water plants", "Front and back yard")
+
+		chore, err := store.GetChore(ctx, "kid", choreID)
+		require.NoError(t, err)
+		assert.Equal(t, "Water plants", chore.Title)
+
+		chore, err = store.GetChore(ctx, "dad", choreID)
+		require.NoError(t, err)
+		assert.Equal(t, "kid", chore.Assignee)
+
+		_, err = store.GetChore(ctx, "sibling", choreID)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "unauthorized")
+
+		_, err = store.GetChore(ctx, "kid", "missing")
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "not found")
+	})
+}
+
+func TestChoreNotifier(t *testing.T) {
+	store := NewFamilyStore()
+	ctx := context.Background()
+
+	var assigned []Chore
+	var verified []Chore
+	var approvals []bool
+	store.SetNotifier(Notifier{
+		OnChoreAssigned: func(ctx context.Context, chore Chore) {
+			assigned = append(assigned, chore)
+		},
+		OnChoreVerified: func(ctx context.Context, chore Chore, approved bool) {
+			verified = append(verified, chore)
+			approvals = append(approvals, approved)
+		},
+	})
+
+	choreID, err := store.AssignChore(ctx, "dad", "kid", "Mow the lawn", "")
+	require.NoError(t, err)
+	require.Len(t, assigned, 1)
+	assert.Equal(t, choreID, assigned[0].ID)
+	assert.Equal(t, "dad", assigned[0].Assigner)
+	assert.Equal(t, "kid", assigned[0].Assignee)
+	assert.Equal(t, "Mow the lawn", assigned[0].Title)
+
+	require.NoError(t, store.ReassignChore(ctx, "dad", choreID, "sibling"))
+	require.Len(t, assigned, 2)
+	assert.Equal(t, "sibling", assigned[1].Assignee)
+
+	require.NoError(t, store.CompleteChore(ctx, "sibling", choreID))
+	require.NoError(t, store.VerifyChore(ctx, "dad", choreID, false))
+	require.NoError(t, store.CompleteChore(ctx, "sibling", choreID))
+	require.NoError(t, store.VerifyChore(ctx, "dad", choreID, true))
+	require.Len(t, verified, 2)
+	assert.Equal(t, []bool{false, true}, approvals)
+	assert.Equal(t, StatusPending, verified[0].Status)
+	assert.Equal(t, StatusVerified, verified[1].Status)
+
+	// Failed operations must not notify
+	assert.Error(t, store.VerifyChore(ctx, "kid", choreID, true))
+	assert.Error(t, store.ReassignChore(ctx, "kid", choreID, "kid"))
+	assert.Len(t, assigned, 2)
+	assert.Len(t, verified, 2)
 }
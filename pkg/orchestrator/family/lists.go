@@ -12,6 +12,7 @@ type ListItem struct {
 	ID          string     `json:"id"`
 	Content     string     `json:"content"`
 	AddedBy     string     `json:"added_by"`
+	AssignedTo  string     `json:"assigned_to,omitempty"`
 	Completed   bool       `json:"completed"`
 	CompletedBy string     `json:"completed_by,omitempty"`
 	CompletedAt *time.Time `json:"completed_at,omitempty"`
@@ -54,6 +55,9 @@ func (s *FamilyStore) CreateList(ctx context.Context, user, name string) (string
 		s.lists = make(map[string]*List)
 	}
 	s.lists[id] = l
+	if err := s.saveLocked(); err != nil {
+		return "", err
+	}
 	return id, nil
 }
 
@@ -68,6 +72,22 @@ func (s *FamilyStore) GetLists(ctx context.Context, user string) ([]List, error)
 	return result, nil
 }
 
+// GetList returns a copy of a single list. Lists are visible to everyone in
+// the family.
+func (s *FamilyStore) GetList(ctx context.Context, user, listID string) (*List, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	l, ok := s.lists[listID]
+	if !ok {
+		return nil, fmt.Errorf("list not found")
+	}
+
+	result := *l
+	result.Items = append([]ListItem(nil), l.Items...)
+	return &result, nil
+}
+
 func (s *FamilyStore) AddListItem(ctx context.Context, user, listID, content string) (string, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -87,6 +107,9 @@ func (s *FamilyStore) AddListItem(ctx context.Context, user, listID, content str
 	}
 
 	l.Items = append(l.Items, item)
+	if err := s.saveLocked(); err != nil {
+		return "", err
+	}
 	return itemID, nil
 }
 
@@ -110,12 +133,51 @@ func (s *FamilyStore) UpdateListItem(ctx context.Context, user, listID, itemID s
 				l.Items[i].CompletedAt = nil
 				l.Items[i].CompletedBy = ""
 			}
-			return nil
+			return s.saveLocked()
+		}
+	}
+	return fmt.Errorf("item not found")
+}
+
+// AssignListItem makes assignee responsible for an item. An empty assignee
+// clears the assignment.
+func (s *FamilyStore) AssignListItem(ctx context.Context, user, listID, itemID, assignee string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	l, ok := s.lists[listID]
+	if !ok {
+		return fmt.Errorf("list not found")
+	}
+
+	for i, item := range l.Items {
+		if item.ID == itemID {
+			l.Items[i].AssignedTo = assignee
+			return s.saveLocked()
 		}
 	}
 	return fmt.Errorf("item not found")
 }
 
+// GetListItemsFor returns the items on a list assigned to assignee.
+func (s *FamilyStore) GetListItemsFor(ctx context.Context, listID, assignee string) ([]ListItem, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	l, ok := s.lists[listID]
+	if !ok {
+		return nil, fmt.Errorf("list not found")
+	}
+
+	var result []ListItem
+	for _, item := range l.Items {
+		if item.AssignedTo == assignee {
+			result = append(result, item)
+		}
+	}
+	return result, nil
+}
+
 func (s *FamilyStore) DeleteList(ctx context.Context, user, listID string) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -131,5 +193,5 @@ func (s *FamilyStore) DeleteList(ctx context.Context, user, listID string) error
 	}
 
 	delete(s.lists, listID)
-	return nil
+	return s.saveLocked()
 }
@@ -70,4 +70,52 @@ func TestListsStore(t *testing.T) {
 			assert.NotEqual(t, listID, l.ID)
 		}
 	})
+	t.Run("Get List", func(t *testing.T) {
+		listID, _ := store.CreateList(ctx, "mom", "Hardware Store")
+		_, _ = store.AddListItem(ctx, "dad", listID, "Nails")
+
+		// Anyone in the family can fetch a list
+		list, err := store.GetList(ctx, "kid", listID)
+		require.NoError(t, err)
+		assert.Equal(t, "Hardware Store", list.Name)
+		require.Len(t, list.Items, 1)
+
+		// Mutating the copy must not affect the store
+		list.Items[0].Content = "Screws"
+		list, _ = store.GetList(ctx, "mom", listID)
+		assert.Equal(t, "Nails", list.Items[0].Content)
+
+		_, err = store.GetList(ctx, "kid", "missing")
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "not found")
+	})
+	t.Run("Assign Items", func(t *testing.T) {
+		listID, _ := store.CreateList(ctx, "mom", "Camping Trip")
+		tent, _ := store.AddListItem(ctx, "mom", listID, "Tent")
+		stove, _ := store.AddListItem(ctx, "mom", listID, "Stove")
+		snacks, _ := store.AddListItem(ctx, "kid", listID, "Snacks")
+
+		require.NoError(t, store.AssignListItem(ctx, "mom", listID, tent, "dad"))
+		require.NoError(t, store.AssignListItem(ctx, "mom", listID, stove, "dad"))
+		require.NoError(t, store.AssignListItem(ctx, "mom", listID, snacks, "kid"))
+
+		dadItems, err := store.GetListItemsFor(ctx, listID, "dad")
+		require.NoError(t, err)
+		require.Len(t, dadItems, 2)
+		assert.Equal(t, "Tent", dadItems[0].Content)
+		assert.Equal(t, "Stove", dadItems[1].Content)
+
+		kidItems, err := store.GetListItemsFor(ctx, listID, "kid")
+		require.NoError(t, err)
+		require.Len(t, kidItems, 1)
+		assert.Equal(t, "Snacks", kidItems[0].Content)
+		assert.Equal(t, "kid", kidItems[0].AssignedTo)
+
+		err = store.AssignListItem(ctx, "mom", listID, "missing", "dad")
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "item not found")
+
+		_, err = store.GetListItemsFor(ctx, "missing", "dad")
+		assert.Error(t, err)
+	})
 }
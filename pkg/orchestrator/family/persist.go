@@ -0,0 +1,60 @@
+package family
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/sipeed/picoclaw/pkg/fileutil"
+)
+
+// familySnapshot is the on-disk JSON layout of a FamilyStore.
+type familySnapshot struct {
+	Chores map[string]*Chore `json:"chores"`
+	Lists  map[string]*List  `json:"lists"`
+}
+
+// NewFamilyStoreWithPath returns a FamilyStore persisted to the JSON file at
+// path. Existing data is loaded, and every mutation rewrites the file
+// atomically.
+func NewFamilyStoreWithPath(path string) (*FamilyStore, error) {
+	s := NewFamilyStore()
+	s.path = path
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read family store: %w", err)
+	}
+
+	var snap familySnapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return nil, fmt.Errorf("failed to parse family store %s: %w", path, err)
+	}
+	if snap.Chores != nil {
+		s.chores = snap.Chores
+	}
+	if snap.Lists != nil {
+		s.lists = snap.Lists
+	}
+	return s, nil
+}
+
+// saveLocked writes the store to disk. Callers must hold s.mu.
+func (s *FamilyStore) saveLocked() error {
+	if s.path == "" {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(familySnapshot{Chores: s.chores, Lists: s.lists}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode family store: %w", err)
+	}
+	if err := fileutil.WriteFileAtomic(s.path, data, 0o600); err != nil {
+		return fmt.Errorf("failed to save family store: %w", err)
+	}
+	return nil
+}
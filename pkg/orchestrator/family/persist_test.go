@@ -0,0 +1,61 @@
+package family
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFamilyStorePersistence(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "family.json")
+
+	store, err := NewFamilyStoreWithPath(path)
+	require.NoError(t, err)
+
+	choreID, err := store.AssignChore(ctx, "dad", "kid", "Feed the cat", "")
+	require.NoError(t, err)
+	require.NoError(t, store.CompleteChore(ctx, "kid", choreID))
+
+	listID, err := store.CreateList(ctx, "mom", "Groceries")
+	require.NoError(t, err)
+	itemID, err := store.AddListItem(ctx, "kid", listID, "Milk")
+	require.NoError(t, err)
+	require.NoError(t, store.UpdateListItem(ctx, "mom", listID, itemID, true))
+
+	reopened, err := NewFamilyStoreWithPath(path)
+	require.NoError(t, err)
+
+	chores, err := reopened.ListChores(ctx, "kid")
+	require.NoError(t, err)
+	require.Len(t, chores, 1)
+	assert.Equal(t, "Feed the cat", chores[0].Title)
+	assert.Equal(t, StatusCompleted, chores[0].Status)
+	assert.NotNil(t, chores[0].CompletedAt)
+
+	lists, err := reopened.GetLists(ctx, "dad")
+	require.NoError(t, err)
+	require.Len(t, lists, 1)
+	assert.Equal(t, "Groceries", lists[0].Name)
+	require.Len(t, lists[0].Items, 1)
+	assert.Equal(t, "Milk", lists[0].Items[0].Content)
+	assert.True(t, lists[0].Items[0].Completed)
+
+	require.NoError(t, reopened.DeleteList(ctx, "mom", listID))
+	reopened, err = NewFamilyStoreWithPath(path)
+	require.NoError(t, err)
+	lists, _ = reopened.GetLists(ctx, "dad")
+	assert.Empty(t, lists)
+}
+
+func TestFamilyStorePersistence_CorruptFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "family.json")
+	require.NoError(t, os.WriteFile(path, []byte("{not json"), 0o600))
+
+	_, err := NewFamilyStoreWithPath(path)
+	assert.Error(t, err)
+}
@@ -2,7 +2,12 @@ package mailbox
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -17,12 +22,55 @@ type Message struct {
 	Content   string    `json:"content"`
 	Read      bool      `json:"read"`
 	Timestamp time.Time `json:"timestamp"`
+	// GroupID links the per-recipient copies of a broadcast message. It is
+	// empty for direct messages.
+	GroupID string `json:"group_id,omitempty"`
+	// ExpiresAt is when the message stops being listed. Nil means never.
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	// Attachments reference files that go with the message, such as a
+	// photo of a finished chore.
+	Attachments []Attachment `json:"attachments,omitempty"`
+}
+
+// Attachment points at a file by URL or by a path relative to the
+// workspace. The file itself is not stored in the mailbox.
+type Attachment struct {
+	Name     string `json:"name"`
+	MimeType string `json:"mime_type,omitempty"`
+	URL      string `json:"url,omitempty"`
+	Path     string `json:"path,omitempty"`
+}
+
+// validate checks that the attachment names a file and points at it in
+// exactly one way, with paths kept inside the workspace.
+func (a Attachment) validate() error {
+	if a.Name == "" {
+		return errors.New("attachment needs a name")
+	}
+	switch {
+	case a.URL == "" && a.Path == "":
+		return fmt.Errorf("attachment %q needs a url or a path", a.Name)
+	case a.URL != "" && a.Path != "":
+		return fmt.Errorf("attachment %q must have a url or a path, not both", a.Name)
+	case a.Path != "":
+		clean := path.Clean(filepath.ToSlash(a.Path))
+		if path.IsAbs(clean) || filepath.IsAbs(a.Path) || clean == ".." || strings.HasPrefix(clean, "../") {
+			return fmt.Errorf("attachment %q path must be relative to the workspace", a.Name)
+		}
+	}
+	return nil
+}
+
+// expired reports whether the message has expired as of now.
+func (m *Message) expired(now time.Time) bool {
+	return m.ExpiresAt != nil && !now.Before(*m.ExpiresAt)
 }
 
 // MemoryStore is an in-memory implementation of the mailbox store.
 type MemoryStore struct {
 	mu       sync.RWMutex
 	messages map[string]*Message
+	ttl      time.Duration
 }
 
 // NewMemoryStore creates a new in-memory mailbox.
@@ -32,55 +80,292 @@ func NewMemoryStore() *MemoryStore {
 	}
 }
 
+// SetTTL makes messages sent from now on expire after ttl. Zero, the
+// default, keeps them forever.
+func (s *MemoryStore) SetTTL(ttl time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ttl = ttl
+}
+
+// expiryLocked returns the ExpiresAt for a message sent at now. Callers must
+// hold s.mu.
+func (s *MemoryStore) expiryLocked(now time.Time) *time.Time {
+	if s.ttl <= 0 {
+		return nil
+	}
+	at := now.Add(s.ttl)
+	return &at
+}
+
+// PurgeExpired deletes every message that has expired as of now and returns
+// how many were removed.
+func (s *MemoryStore) PurgeExpired(ctx context.Context, now time.Time) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	purged := 0
+	for id, msg := range s.messages {
+		if msg.expired(now) {
+			delete(s.messages, id)
+			purged++
+		}
+	}
+	return purged
+}
+
+// StartPurger runs PurgeExpired every interval until ctx is cancelled.
+func (s *MemoryStore) StartPurger(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				s.PurgeExpired(ctx, now)
+			}
+		}
+	}()
+}
+
 // SendMessage sends a message from one user to another.
 func (s *MemoryStore) SendMessage(ctx context.Context, from, to, content string) (string, error) {
+	return s.SendMessageWithAttachments(ctx, from, to, content, nil)
+}
+
+// SendMessageWithAttachments sends a message that references files. Every
+// attachment must pass validation or nothing is sent.
+func (s *MemoryStore) SendMessageWithAttachments(
+	ctx context.Context,
+	from, to, content string,
+	attachments []Attachment,
+) (string, error) {
+	for _, a := range attachments {
+		if err := a.validate(); err != nil {
+			return "", err
+		}
+	}
+
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
 	id := uuid.New().String()
+	now := time.Now()
 	msg := &Message{
 		ID:        id,
 		From:      from,
 		To:        to,
 		Content:   content,
 		Read:      false,
-		Timestamp: time.Now(),
+		Timestamp: now,
+		ExpiresAt: s.expiryLocked(now),
+	}
+	if len(attachments) > 0 {
+		msg.Attachments = append([]Attachment(nil), attachments...)
 	}
 	s.messages[id] = msg
 	return id, nil
 }
 
-// ListMessages returns all messages for a user (either sent or received).
-// According to test logic, this returns messages directed to the user,
-// or sent by the user (if we want sent messages?). But let's just do received messages for now 
-// or maybe both, wait, let's check what test does: Kid receives, lists kid - sees 1 msg.
-// ReadMessage test: Kid sends to Dad, Dad lists - sees 1 msg.
+// BroadcastMessage sends content from one user to each of the recipients.
+// Every recipient gets their own copy, so read state is tracked per person,
+// and the copies share a group ID, which is returned.
+func (s *MemoryStore) BroadcastMessage(ctx context.Context, from string, to []string, content string) (string, error) {
+	if len(to) == 0 {
+		return "", errors.New("broadcast needs at least one recipient")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	groupID := uuid.New().String()
+	now := time.Now()
+	expiresAt := s.expiryLocked(now)
+	seen := make(map[string]bool, len(to))
+	for _, recipient := range to {
+		if seen[recipient] {
+			continue
+		}
+		seen[recipient] = true
+
+		id := uuid.New().String()
+		s.messages[id] = &Message{
+			ID:        id,
+			From:      from,
+			To:        recipient,
+			Content:   content,
+			Timestamp: now,
+			GroupID:   groupID,
+			ExpiresAt: expiresAt,
+		}
+	}
+	return groupID, nil
+}
+
+// ListGroupMessages returns every recipient's copy of a broadcast, ordered
+// by recipient.
+func (s *MemoryStore) ListGroupMessages(ctx context.Context, groupID string) ([]Message, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	now := time.Now()
+	var result []Message
+	for _, msg := range s.messages {
+		if groupID != "" && msg.GroupID == groupID && !msg.expired(now) {
+			result = append(result, *msg)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].To < result[j].To })
+	return result, nil
+}
+
+// ListMessages returns every unexpired message addressed to user, newest
+// first.
 func (s *MemoryStore) ListMessages(ctx context.Context, user string) ([]Message, error) {
+	return s.ListMessagesPage(ctx, user, 0, 0)
+}
+
+// ListMessagesPage returns one page of the messages addressed to user,
+// newest first: at most limit messages after skipping offset. A limit of 0
+// returns every message from offset on. Expired messages are skipped even if
+// no purge has run yet.
+func (s *MemoryStore) ListMessagesPage(ctx context.Context, user string, limit, offset int) ([]Message, error) {
+	if limit < 0 || offset < 0 {
+		return nil, errors.New("limit and offset must not be negative")
+	}
+
+	s.mu.RLock()
+	now := time.Now()
+	var result []Message
+	for _, msg := range s.messages {
+		if msg.To == user && !msg.expired(now) {
+			result = append(result, *msg)
+		}
+	}
+	s.mu.RUnlock()
+
+	sortNewestFirst(result)
+	if offset >= len(result) {
+		return nil, nil
+	}
+	result = result[offset:]
+	if limit > 0 && limit < len(result) {
+		result = result[:limit]
+	}
+	return result, nil
+}
+
+// ListUnread returns the unread messages addressed to user, oldest first.
+// Expired messages are skipped.
+func (s *MemoryStore) ListUnread(ctx context.Context, user string) ([]Message, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
+	now := time.Now()
 	var result []Message
 	for _, msg := range s.messages {
-		if msg.To == user || msg.From == user {
-			// Actually the test only lists mailbox. Let's return received messages only for inbox?
-			// The second test sends from kid to dad, then dad lists and expects 1. Yes, To == user.
-			// Let's just return To == user for a strict inbox, but what if they want to see sent? Let's just do To == user.
-			if msg.To == user {
-				// return a copy
-				result = append(result, *msg)
-			}
+		if msg.To == user && !msg.Read && !msg.expired(now) {
+			result = append(result, *msg)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Timestamp.Before(result[j].Timestamp) })
+	return result, nil
+}
+
+// UnreadCount returns how many unread, unexpired messages are waiting for user.
+func (s *MemoryStore) UnreadCount(ctx context.Context, user string) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	now := time.Now()
+	count := 0
+	for _, msg := range s.messages {
+		if msg.To == user && !msg.Read && !msg.expired(now) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// SearchMessages returns the unexpired messages user sent or received whose
+// content contains query, ignoring case, newest first.
+func (s *MemoryStore) SearchMessages(ctx context.Context, user, query string) ([]Message, error) {
+	if query == "" {
+		return nil, errors.New("search query must not be empty")
+	}
+	needle := strings.ToLower(query)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	now := time.Now()
+	var result []Message
+	for _, msg := range s.messages {
+		if msg.To != user && msg.From != user {
+			continue
+		}
+		if msg.expired(now) || !strings.Contains(strings.ToLower(msg.Content), needle) {
+			continue
 		}
+		result = append(result, *msg)
 	}
+	sortNewestFirst(result)
 	return result, nil
 }
 
+// sortNewestFirst orders messages by timestamp, newest first, breaking ties
+// by ID so the order is stable.
+func sortNewestFirst(msgs []Message) {
+	sort.Slice(msgs, func(i, j int) bool {
+		if !msgs[i].Timestamp.Equal(msgs[j].Timestamp) {
+			return msgs[i].Timestamp.After(msgs[j].Timestamp)
+		}
+		return msgs[i].ID < msgs[j].ID
+	})
+}
+
+// MarkAllRead marks every unexpired message addressed to user as read and
+// returns how many changed.
+func (s *MemoryStore) MarkAllRead(ctx context.Context, user string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	marked := 0
+	for _, msg := range s.messages {
+		if msg.To == user && !msg.Read && !msg.expired(now) {
+			msg.Read = true
+			marked++
+		}
+	}
+	return marked, nil
+}
+
+// PurgeRead deletes the read messages addressed to user and returns how many
+// were removed. Unread messages and messages user sent are kept.
+func (s *MemoryStore) PurgeRead(ctx context.Context, user string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	purged := 0
+	for id, msg := range s.messages {
+		if msg.To == user && msg.Read {
+			delete(s.messages, id)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
 // ReadMessage reads a specific message, marking it as read, provided the user is authorized.
 func (s *MemoryStore) ReadMessage(ctx context.Context, user, msgID string) (*Message, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
 	msg, ok := s.messages[msgID]
-	if !ok {
+	if !ok || msg.expired(time.Now()) {
 		return nil, fmt.Errorf("message not found")
 	}
 
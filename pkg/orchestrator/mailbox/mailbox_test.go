@@ -2,6 +2,8 @@ package mailbox
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"testing"
 	"time"
 
@@ -55,3 +57,269 @@ func TestMailboxStore(t *testing.T) {
 		assert.Contains(t, err.Error(), "unauthorized")
 	})
 }
+
+func TestBroadcastMessage(t *testing.T) {
+	store := NewMemoryStore()
+	ctx := context.Background()
+
+	groupID, err := store.BroadcastMessage(ctx, "mom", []string{"dad", "kid", "kid"}, "Dinner is ready")
+	require.NoError(t, err)
+	require.NotEmpty(t, groupID)
+
+	for _, user := range []string{"dad", "kid"} {
+		messages, err := store.ListMessages(ctx, user)
+		require.NoError(t, err)
+		require.Len(t, messages, 1, "duplicate recipients get a single copy")
+		assert.Equal(t, "mom", messages[0].From)
+		assert.Equal(t, "Dinner is ready", messages[0].Content)
+		assert.Equal(t, groupID, messages[0].GroupID)
+	}
+
+	group, err := store.ListGroupMessages(ctx, groupID)
+	require.NoError(t, err)
+	require.Len(t, group, 2)
+	assert.Equal(t, "dad", group[0].To)
+	assert.Equal(t, "kid", group[1].To)
+	assert.NotEqual(t, group[0].ID, group[1].ID)
+
+	// Read state is per recipient.
+	_, err = store.ReadMessage(ctx, "kid", group[1].ID)
+	require.NoError(t, err)
+	dadMessages, _ := store.ListMessages(ctx, "dad")
+	assert.False(t, dadMessages[0].Read)
+
+	_, err = store.BroadcastMessage(ctx, "mom", nil, "nobody")
+	assert.Error(t, err)
+
+	direct, _ := store.SendMessage(ctx, "dad", "kid", "Hi")
+	empty, err := store.ListGroupMessages(ctx, "")
+	require.NoError(t, err)
+	assert.Empty(t, empty, "direct message %s must not match an empty group ID", direct)
+}
+
+func TestMessageExpiry(t *testing.T) {
+	store := NewMemoryStore()
+	ctx := context.Background()
+
+	keptID, err := store.SendMessage(ctx, "mom", "kid", "Forever")
+	require.NoError(t, err)
+
+	store.SetTTL(time.Hour)
+	expiringID, err := store.SendMessage(ctx, "mom", "kid", "Soon gone")
+	require.NoError(t, err)
+
+	messages, _ := store.ListMessages(ctx, "kid")
+	require.Len(t, messages, 2)
+	for _, m := range messages {
+		if m.ID == keptID {
+			assert.Nil(t, m.ExpiresAt)
+		} else {
+			require.NotNil(t, m.ExpiresAt)
+			assert.WithinDuration(t, time.Now().Add(time.Hour), *m.ExpiresAt, 5*time.Second)
+		}
+	}
+
+	// Nothing has expired an hour early.
+	assert.Equal(t, 0, store.PurgeExpired(ctx, time.Now()))
+
+	// Backdate the expiry: the message is hidden before any purge runs.
+	past := time.Now().Add(-time.Minute)
+	store.messages[expiringID].ExpiresAt = &past
+
+	messages, _ = store.ListMessages(ctx, "kid")
+	require.Len(t, messages, 1)
+	assert.Equal(t, keptID, messages[0].ID)
+
+	_, err = store.ReadMessage(ctx, "kid", expiringID)
+	assert.Error(t, err)
+
+	assert.Equal(t, 1, store.PurgeExpired(ctx, time.Now()))
+	assert.NotContains(t, store.messages, expiringID)
+	assert.Contains(t, store.messages, keptID)
+}
+
+func TestStartPurger(t *testing.T) {
+	store := NewMemoryStore()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	store.SetTTL(time.Millisecond)
+	_, err := store.BroadcastMessage(ctx, "mom", []string{"dad", "kid"}, "Quick!")
+	require.NoError(t, err)
+
+	store.StartPurger(ctx, 5*time.Millisecond)
+	assert.Eventually(t, func() bool {
+		store.mu.RLock()
+		defer store.mu.RUnlock()
+		return len(store.messages) == 0
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestUnreadMessages(t *testing.T) {
+	store := NewMemoryStore()
+	ctx := context.Background()
+
+	firstID, _ := store.SendMessage(ctx, "mom", "kid", "first")
+	store.SendMessage(ctx, "dad", "kid", "second")
+	store.SendMessage(ctx, "kid", "dad", "not mine")
+
+	count, err := store.UnreadCount(ctx, "kid")
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	_, err = store.ReadMessage(ctx, "kid", firstID)
+	require.NoError(t, err)
+
+	unread, err := store.ListUnread(ctx, "kid")
+	require.NoError(t, err)
+	require.Len(t, unread, 1)
+	assert.Equal(t, "second", unread[0].Content)
+
+	count, _ = store.UnreadCount(ctx, "kid")
+	assert.Equal(t, 1, count)
+}
+
+func TestMarkAllReadAndPurgeRead(t *testing.T) {
+	store := NewMemoryStore()
+	ctx := context.Background()
+
+	readID, _ := store.SendMessage(ctx, "mom", "kid", "already read")
+	store.SendMessage(ctx, "dad", "kid", "still unread")
+	store.SendMessage(ctx, "kid", "mom", "sent by kid")
+	_, err := store.ReadMessage(ctx, "kid", readID)
+	require.NoError(t, err)
+
+	purged, err := store.PurgeRead(ctx, "kid")
+	require.NoError(t, err)
+	assert.Equal(t, 1, purged)
+
+	inbox, _ := store.ListMessages(ctx, "kid")
+	require.Len(t, inbox, 1)
+	assert.Equal(t, "still unread", inbox[0].Content)
+	sent, _ := store.ListMessages(ctx, "mom")
+	require.Len(t, sent, 1, "the kid's sent message stays in mom's inbox")
+
+	marked, err := store.MarkAllRead(ctx, "kid")
+	require.NoError(t, err)
+	assert.Equal(t, 1, marked)
+	inbox, _ = store.ListMessages(ctx, "kid")
+	assert.True(t, inbox[0].Read)
+	sent, _ = store.ListMessages(ctx, "mom")
+	assert.False(t, sent[0].Read, "marking the kid's inbox must not touch other inboxes")
+
+	purged, _ = store.PurgeRead(ctx, "kid")
+	assert.Equal(t, 1, purged)
+	inbox, _ = store.ListMessages(ctx, "kid")
+	assert.Empty(t, inbox)
+}
+
+func TestSearchMessages(t *testing.T) {
+	store := NewMemoryStore()
+	ctx := context.Background()
+
+	store.SendMessage(ctx, "mom", "kid", "Pack your soccer bag")
+	time.Sleep(time.Millisecond)
+	store.SendMessage(ctx, "kid", "dad", "Soccer practice ran late")
+	time.Sleep(time.Millisecond)
+	store.SendMessage(ctx, "mom", "dad", "Soccer snacks are on me")
+	store.SendMessage(ctx, "dad", "kid", "Dinner at six")
+
+	results, err := store.SearchMessages(ctx, "kid", "SOCCER")
+	require.NoError(t, err)
+	require.Len(t, results, 2, "kid sees what they sent and received, not mom's note to dad")
+	assert.Equal(t, "Soccer practice ran late", results[0].Content, "newest first")
+	assert.Equal(t, "Pack your soccer bag", results[1].Content)
+
+	results, err = store.SearchMessages(ctx, "grandma", "soccer")
+	require.NoError(t, err)
+	assert.Empty(t, results)
+
+	_, err = store.SearchMessages(ctx, "kid", "")
+	assert.Error(t, err)
+}
+
+func TestListMessagesPage(t *testing.T) {
+	store := NewMemoryStore()
+	ctx := context.Background()
+
+	for i := 1; i <= 5; i++ {
+		store.SendMessage(ctx, "mom", "kid", fmt.Sprintf("message %d", i))
+		time.Sleep(time.Millisecond)
+	}
+
+	contents := func(msgs []Message) []string {
+		out := make([]string, len(msgs))
+		for i, m := range msgs {
+			out[i] = m.Content
+		}
+		return out
+	}
+
+	all, err := store.ListMessages(ctx, "kid")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"message 5", "message 4", "message 3", "message 2", "message 1"}, contents(all))
+
+	tests := []struct {
+		name          string
+		limit, offset int
+		want          []string
+	}{
+		{"first page", 2, 0, []string{"message 5", "message 4"}},
+		{"second page", 2, 2, []string{"message 3", "message 2"}},
+		{"short last page", 2, 4, []string{"message 1"}},
+		{"past the end", 2, 5, []string{}},
+		{"offset without limit", 0, 3, []string{"message 2", "message 1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := store.ListMessagesPage(ctx, "kid", tt.limit, tt.offset)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, contents(page))
+		})
+	}
+
+	_, err = store.ListMessagesPage(ctx, "kid", -1, 0)
+	assert.Error(t, err)
+}
+
+func TestSendMessageWithAttachments(t *testing.T) {
+	store := NewMemoryStore()
+	ctx := context.Background()
+
+	attachments := []Attachment{
+		{Name: "bedroom.jpg", MimeType: "image/jpeg", Path: "chores/bedroom.jpg"},
+		{Name: "checklist", URL: "https://example.com/checklist.pdf"},
+	}
+	_, err := store.SendMessageWithAttachments(ctx, "kid", "mom", "Room is clean!", attachments)
+	require.NoError(t, err)
+	attachments[0].Name = "changed after sending"
+
+	messages, err := store.ListMessages(ctx, "mom")
+	require.NoError(t, err)
+	require.Len(t, messages, 1)
+	assert.Equal(t, []Attachment{
+		{Name: "bedroom.jpg", MimeType: "image/jpeg", Path: "chores/bedroom.jpg"},
+		{Name: "checklist", URL: "https://example.com/checklist.pdf"},
+	}, messages[0].Attachments)
+
+	// Attachments survive a JSON round trip, as they would over MCP.
+	raw, err := json.Marshal(messages[0])
+	require.NoError(t, err)
+	var decoded Message
+	require.NoError(t, json.Unmarshal(raw, &decoded))
+	assert.Equal(t, messages[0].Attachments, decoded.Attachments)
+
+	invalid := []Attachment{
+		{Path: "a.jpg"},
+		{Name: "nowhere"},
+		{Name: "both", URL: "https://example.com/a", Path: "a.jpg"},
+		{Name: "escape", Path: "../secrets.txt"},
+		{Name: "absolute", Path: "/etc/passwd"},
+	}
+	for _, a := range invalid {
+		_, err := store.SendMessageWithAttachments(ctx, "kid", "mom", "bad", []Attachment{a})
+		assert.Error(t, err, "attachment %+v", a)
+	}
+	messages, _ = store.ListMessages(ctx, "mom")
+	assert.Len(t, messages, 1, "rejected messages are not stored")
+}
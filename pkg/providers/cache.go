@@ -0,0 +1,164 @@
+package providers
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/sipeed/picoclaw/pkg/metrics"
+)
+
+// NoCacheOption is the Chat option that makes CachingProvider skip the cache
+// for one call. It is removed before the call reaches the wrapped provider.
+const NoCacheOption = "no_cache"
+
+const defaultCacheSize = 256
+
+// CachingProvider serves repeated identical prompts from an in-memory LRU
+// cache for a fixed TTL. Calls offering tools, calls with the no_cache
+// option, failures and responses with tool calls are never cached.
+type CachingProvider struct {
+	providerWrapper
+	ttl  time.Duration
+	size int
+	now  func() time.Time
+
+	mu      sync.Mutex
+	order   *list.List // front is most recently used
+	entries map[string]*list.Element
+}
+
+type cacheEntry struct {
+	key     string
+	resp    *LLMResponse
+	expires time.Time
+}
+
+// WrapWithCache caches p's responses for ttl, keeping at most size entries
+// (256 if size is not positive). The result implements StreamingProvider
+// only if p does.
+func WrapWithCache(p LLMProvider, ttl time.Duration, size int) LLMProvider {
+	if size <= 0 {
+		size = defaultCacheSize
+	}
+	return decorate(&CachingProvider{
+		providerWrapper: providerWrapper{p},
+		ttl:             ttl,
+		size:            size,
+		now:             time.Now,
+		order:           list.New(),
+		entries:         make(map[string]*list.Element),
+	}, p)
+}
+
+func (c *CachingProvider) Chat(
+	ctx context.Context,
+	messages []Message,
+	tools []ToolDefinition,
+	model string,
+	options map[string]any,
+) (*LLMResponse, error) {
+	key, options, ok := c.lookupKey(model, messages, tools, options)
+	if !ok {
+		return c.LLMProvider.Chat(ctx, messages, tools, model, options)
+	}
+	if resp, hit := c.get(key, model); hit {
+		return resp, nil
+	}
+	resp, err := c.LLMProvider.Chat(ctx, messages, tools, model, options)
+	c.put(key, resp, err)
+	return resp, err
+}
+
+// chatStream delivers a cached response as a single chunk; misses stream
+// from the wrapped provider and are cached on success.
+func (c *CachingProvider) chatStream(
+	inner StreamingProvider,
+	ctx context.Context,
+	messages []Message,
+	tools []ToolDefinition,
+	model string,
+	options map[string]any,
+	onChunk func(accumulated string),
+) (*LLMResponse, error) {
+	key, options, ok := c.lookupKey(model, messages, tools, options)
+	if !ok {
+		return inner.ChatStream(ctx, messages, tools, model, options, onChunk)
+	}
+	if resp, hit := c.get(key, model); hit {
+		if onChunk != nil && resp.Content != "" {
+			onChunk(resp.Content)
+		}
+		return resp, nil
+	}
+	resp, err := inner.ChatStream(ctx, messages, tools, model, options, onChunk)
+	c.put(key, resp, err)
+	return resp, err
+}
+
+// lookupKey returns the cache key for a call and its options with
+// NoCacheOption removed. ok is false if the call bypasses the cache.
+func (c *CachingProvider) lookupKey(
+	model string,
+	messages []Message,
+	tools []ToolDefinition,
+	options map[string]any,
+) (key string, opts map[string]any, ok bool) {
+	opts = options
+	noCache := false
+	if v, present := options[NoCacheOption]; present {
+		noCache, _ = v.(bool)
+		opts = make(map[string]any, len(options)-1)
+		for k, v := range options {
+			if k != NoCacheOption {
+				opts[k] = v
+			}
+		}
+	}
+	if noCache || len(tools) > 0 || c.ttl <= 0 {
+		return "", opts, false
+	}
+	key, ok = requestKey(model, messages, nil, opts)
+	return key, opts, ok
+}
+
+func (c *CachingProvider) get(key, model string) (*LLMResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[key]
+	if ok && c.now().After(el.Value.(*cacheEntry).expires) {
+		c.order.Remove(el)
+		delete(c.entries, key)
+		ok = false
+	}
+	metrics.DefaultRecorder().RecordLLMCacheLookup(model, ok)
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(el)
+	return cloneResponse(el.Value.(*cacheEntry).resp), true
+}
+
+func (c *CachingProvider) put(key string, resp *LLMResponse, err error) {
+	if err != nil || resp == nil || len(resp.ToolCalls) > 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry := &cacheEntry{key: key, resp: cloneResponse(resp), expires: c.now().Add(c.ttl)}
+	if el, ok := c.entries[key]; ok {
+		el.Value = entry
+		c.order.MoveToFront(el)
+		return
+	}
+	c.entries[key] = c.order.PushFront(entry)
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
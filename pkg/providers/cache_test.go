@@ -0,0 +1,211 @@
+package providers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/sipeed/picoclaw/pkg/providers/protocoltypes"
+)
+
+// countingProvider answers with the call number so cached replies are
+// distinguishable from fresh ones.
+type countingProvider struct {
+	plainMockProvider
+	calls       int
+	lastOptions map[string]any
+	toolCalls   []ToolCall
+}
+
+func (p *countingProvider) Chat(
+	ctx context.Context,
+	messages []Message,
+	tools []ToolDefinition,
+	model string,
+	options map[string]any,
+) (*LLMResponse, error) {
+	p.calls++
+	p.lastOptions = options
+	return &LLMResponse{Content: string(rune('0' + p.calls)), ToolCalls: p.toolCalls}, nil
+}
+
+// cacheLookups returns the hit and miss counts recorded for model.
+func cacheLookups(t *testing.T, model string) (hits, misses float64) {
+	t.Helper()
+	families, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatalf("Gather: %v", err)
+	}
+	for _, mf := range families {
+		if mf.GetName() != "picoclaw_llm_cache_lookups_total" {
+			continue
+		}
+		for _, m := range mf.GetMetric() {
+			labels := map[string]string{}
+			for _, l := range m.GetLabel() {
+				labels[l.GetName()] = l.GetValue()
+			}
+			if labels["model"] != model {
+				continue
+			}
+			if labels["result"] == "hit" {
+				hits = m.GetCounter().GetValue()
+			} else {
+				misses = m.GetCounter().GetValue()
+			}
+		}
+	}
+	return hits, misses
+}
+
+func newTestCache(inner LLMProvider, ttl time.Duration, size int) (*CachingProvider, *time.Time) {
+	c := WrapWithCache(inner, ttl, size).(*CachingProvider)
+	now := time.Unix(1_700_000_000, 0)
+	c.now = func() time.Time { return now }
+	return c, &now
+}
+
+func TestCachingProvider_HitAndMiss(t *testing.T) {
+	const model = "cache-hit-model"
+	inner := &countingProvider{}
+	c, _ := newTestCache(inner, time.Minute, 0)
+	faq := []Message{{Role: "user", Content: "when is trash day?"}}
+	hitsBefore, missesBefore := cacheLookups(t, model)
+
+	first, _ := c.Chat(context.Background(), faq, nil, model, nil)
+	second, _ := c.Chat(context.Background(), faq, nil, model, nil)
+	other, _ := c.Chat(context.Background(), []Message{{Role: "user", Content: "something else"}}, nil, model, nil)
+
+	if inner.calls != 2 {
+		t.Errorf("upstream calls = %d, want 2", inner.calls)
+	}
+	if first.Content != "1" || second.Content != "1" || other.Content != "2" {
+		t.Errorf("responses = %q, %q, %q; want 1, 1 (cached), 2", first.Content, second.Content, other.Content)
+	}
+	if first == second {
+		t.Error("cache hit returned the stored response itself; callers should get a copy")
+	}
+	hits, misses := cacheLookups(t, model)
+	if hits-hitsBefore != 1 || misses-missesBefore != 2 {
+		t.Errorf("recorded hits = %v, misses = %v; want 1 and 2", hits-hitsBefore, misses-missesBefore)
+	}
+}
+
+func TestCachingProvider_TTLExpiry(t *testing.T) {
+	inner := &countingProvider{}
+	c, now := newTestCache(inner, time.Minute, 0)
+	faq := []Message{{Role: "user", Content: "when is trash day?"}}
+
+	c.Chat(context.Background(), faq, nil, "cache-ttl-model", nil)
+	*now = now.Add(59 * time.Second)
+	c.Chat(context.Background(), faq, nil, "cache-ttl-model", nil)
+	if inner.calls != 1 {
+		t.Fatalf("upstream calls before expiry = %d, want 1", inner.calls)
+	}
+
+	*now = now.Add(2 * time.Second)
+	resp, _ := c.Chat(context.Background(), faq, nil, "cache-ttl-model", nil)
+	if inner.calls != 2 || resp.Content != "2" {
+		t.Errorf("after expiry got %q with %d upstream calls, want a fresh reply", resp.Content, inner.calls)
+	}
+}
+
+func TestCachingProvider_Bypass(t *testing.T) {
+	faq := []Message{{Role: "user", Content: "list my chores"}}
+
+	t.Run("tools offered", func(t *testing.T) {
+		inner := &countingProvider{}
+		c, _ := newTestCache(inner, time.Minute, 0)
+		tools := []ToolDefinition{{Type: "function", Function: ToolFunctionDefinition{Name: "list_chores"}}}
+		c.Chat(context.Background(), faq, tools, "m", nil)
+		c.Chat(context.Background(), faq, tools, "m", nil)
+		if inner.calls != 2 {
+			t.Errorf("upstream calls = %d, want 2", inner.calls)
+		}
+	})
+
+	t.Run("no_cache option", func(t *testing.T) {
+		inner := &countingProvider{}
+		c, _ := newTestCache(inner, time.Minute, 0)
+		opts := map[string]any{NoCacheOption: true, "temperature": 0.5}
+		c.Chat(context.Background(), faq, nil, "m", opts)
+		c.Chat(context.Background(), faq, nil, "m", opts)
+		if inner.calls != 2 {
+			t.Errorf("upstream calls = %d, want 2", inner.calls)
+		}
+		if _, ok := inner.lastOptions[NoCacheOption]; ok || inner.lastOptions["temperature"] != 0.5 {
+			t.Errorf("options passed upstream = %v, want no_cache stripped", inner.lastOptions)
+		}
+	})
+
+	t.Run("tool call responses", func(t *testing.T) {
+		inner := &countingProvider{toolCalls: []ToolCall{{ID: "1", Name: "list_chores"}}}
+		c, _ := newTestCache(inner, time.Minute, 0)
+		c.Chat(context.Background(), faq, nil, "m", nil)
+		c.Chat(context.Background(), faq, nil, "m", nil)
+		if inner.calls != 2 {
+			t.Errorf("upstream calls = %d, want 2", inner.calls)
+		}
+	})
+}
+
+func TestCachingProvider_EvictsLeastRecentlyUsed(t *testing.T) {
+	inner := &countingProvider{}
+	c, _ := newTestCache(inner, time.Minute, 2)
+	ask := func(q string) string {
+		resp, _ := c.Chat(context.Background(), []Message{{Role: "user", Content: q}}, nil, "m", nil)
+		return resp.Content
+	}
+
+	ask("a")
+	ask("b")
+	ask("a") // a is now most recently used
+	ask("c") // evicts b
+	if got := ask("a"); got != "1" {
+		t.Errorf("a = %q, want the cached 1", got)
+	}
+	if got := ask("b"); got != "4" {
+		t.Errorf("b = %q, want a fresh 4 after eviction", got)
+	}
+}
+
+// fixedProvider always answers with the same response value.
+type fixedProvider struct {
+	plainMockProvider
+	resp *LLMResponse
+}
+
+func (p *fixedProvider) Chat(
+	ctx context.Context,
+	messages []Message,
+	tools []ToolDefinition,
+	model string,
+	options map[string]any,
+) (*LLMResponse, error) {
+	return p.resp, nil
+}
+
+func TestCachingProvider_HitsDoNotShareState(t *testing.T) {
+	inner := &fixedProvider{resp: &LLMResponse{
+		Content:          "cached",
+		Usage:            &UsageInfo{PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15},
+		ReasoningDetails: []protocoltypes.ReasoningDetail{{Type: "reasoning.text", Text: "because"}},
+	}}
+	c, _ := newTestCache(inner, time.Minute, 0)
+	faq := []Message{{Role: "user", Content: "when is trash day?"}}
+
+	miss, _ := c.Chat(context.Background(), faq, nil, "cache-copy-model", nil)
+	miss.Usage.TotalTokens = 0
+	miss.ReasoningDetails[0].Text = "changed by the first caller"
+
+	hit, _ := c.Chat(context.Background(), faq, nil, "cache-copy-model", nil)
+	hit.Usage.PromptTokens = 0
+	hit.ReasoningDetails[0].Text = "changed by the second caller"
+
+	again, _ := c.Chat(context.Background(), faq, nil, "cache-copy-model", nil)
+	if again.Usage.PromptTokens != 10 || again.Usage.TotalTokens != 15 || again.ReasoningDetails[0].Text != "because" {
+		t.Errorf("cache hit = %+v (usage %+v), want the response as first stored", again, again.Usage)
+	}
+}
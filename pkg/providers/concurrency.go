@@ -0,0 +1,160 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/sipeed/picoclaw/pkg/metrics"
+)
+
+// ErrConcurrencyLimit is returned by ConcurrencyLimiter.Acquire when both the
+// provider's slots and its queue are full.
+var ErrConcurrencyLimit = errors.New("provider concurrency limit reached")
+
+// ConcurrencyLimiter bounds the number of in-flight calls per provider.
+// Callers beyond maxActive wait in a queue of up to maxQueue; callers beyond
+// that are rejected. It drives the picoclaw_concurrency_* metrics.
+type ConcurrencyLimiter struct {
+	maxActive int
+	maxQueue  int
+
+	mu    sync.Mutex
+	slots map[string]*providerSlots
+}
+
+type providerSlots struct {
+	sem    chan struct{}
+	active int
+	queued int
+}
+
+// NewConcurrencyLimiter returns a limiter allowing maxActive concurrent calls
+// per provider (at least 1) with up to maxQueue waiting callers.
+func NewConcurrencyLimiter(maxActive, maxQueue int) *ConcurrencyLimiter {
+	if maxActive < 1 {
+		maxActive = 1
+	}
+	if maxQueue < 0 {
+		maxQueue = 0
+	}
+	return &ConcurrencyLimiter{
+		maxActive: maxActive,
+		maxQueue:  maxQueue,
+		slots:     make(map[string]*providerSlots),
+	}
+}
+
+// Acquire takes a slot for providerID, waiting in the queue if all slots are
+// busy. It returns ErrConcurrencyLimit when the queue is full, or the
+// context's error if ctx ends while waiting. The returned release function
+// frees the slot; calling it more than once is harmless.
+func (l *ConcurrencyLimiter) Acquire(ctx context.Context, providerID string) (release func(), err error) {
+	l.mu.Lock()
+	s, ok := l.slots[providerID]
+	if !ok {
+		s = &providerSlots{sem: make(chan struct{}, l.maxActive)}
+		l.slots[providerID] = s
+	}
+
+	start := time.Now()
+	select {
+	case s.sem <- struct{}{}:
+		s.active++
+		l.reportLocked(providerID, s)
+		l.mu.Unlock()
+		metrics.DefaultRecorder().RecordConcurrencyWait(providerID, 0)
+		return l.releaser(providerID, s), nil
+	default:
+	}
+
+	if s.queued >= l.maxQueue {
+		l.mu.Unlock()
+		metrics.DefaultRecorder().RecordConcurrencyRejection(providerID)
+		return nil, ErrConcurrencyLimit
+	}
+	s.queued++
+	l.reportLocked(providerID, s)
+	l.mu.Unlock()
+
+	select {
+	case s.sem <- struct{}{}:
+		l.mu.Lock()
+		s.queued--
+		s.active++
+		l.reportLocked(providerID, s)
+		l.mu.Unlock()
+		metrics.DefaultRecorder().RecordConcurrencyWait(providerID, time.Since(start))
+		return l.releaser(providerID, s), nil
+	case <-ctx.Done():
+		l.mu.Lock()
+		s.queued--
+		l.reportLocked(providerID, s)
+		l.mu.Unlock()
+		return nil, ctx.Err()
+	}
+}
+
+func (l *ConcurrencyLimiter) releaser(providerID string, s *providerSlots) func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			l.mu.Lock()
+			s.active--
+			l.reportLocked(providerID, s)
+			l.mu.Unlock()
+			<-s.sem
+		})
+	}
+}
+
+func (l *ConcurrencyLimiter) reportLocked(providerID string, s *providerSlots) {
+	metrics.DefaultRecorder().SetConcurrency(providerID, s.active, s.queued)
+}
+
+// ConcurrencyWrapper decorates an LLMProvider so every Chat call holds a
+// ConcurrencyLimiter slot for its provider ID.
+type ConcurrencyWrapper struct {
+	providerWrapper
+	limiter    *ConcurrencyLimiter
+	providerID string
+}
+
+// WrapWithConcurrency limits p's calls through limiter under providerID. The
+// result implements StreamingProvider only if p does.
+func WrapWithConcurrency(p LLMProvider, limiter *ConcurrencyLimiter, providerID string) LLMProvider {
+	return decorate(&ConcurrencyWrapper{providerWrapper: providerWrapper{p}, limiter: limiter, providerID: providerID}, p)
+}
+
+func (w *ConcurrencyWrapper) Chat(
+	ctx context.Context,
+	messages []Message,
+	tools []ToolDefinition,
+	model string,
+	options map[string]any,
+) (*LLMResponse, error) {
+	release, err := w.limiter.Acquire(ctx, w.providerID)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return w.LLMProvider.Chat(ctx, messages, tools, model, options)
+}
+
+func (w *ConcurrencyWrapper) chatStream(
+	inner StreamingProvider,
+	ctx context.Context,
+	messages []Message,
+	tools []ToolDefinition,
+	model string,
+	options map[string]any,
+	onChunk func(accumulated string),
+) (*LLMResponse, error) {
+	release, err := w.limiter.Acquire(ctx, w.providerID)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return inner.ChatStream(ctx, messages, tools, model, options, onChunk)
+}
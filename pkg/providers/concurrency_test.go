@@ -0,0 +1,197 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// gatherProviderMetric returns the named metric for the given provider_id.
+func gatherProviderMetric(t *testing.T, name, providerID string) *dto.Metric {
+	t.Helper()
+	families, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatalf("Gather: %v", err)
+	}
+	for _, mf := range families {
+		if mf.GetName() != name {
+			continue
+		}
+		for _, m := range mf.GetMetric() {
+			for _, l := range m.GetLabel() {
+				if l.GetName() == "provider_id" && l.GetValue() == providerID {
+					return m
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func TestConcurrencyLimiter_AcquireQueueReject(t *testing.T) {
+	const id = "limiter-test-provider"
+	l := NewConcurrencyLimiter(1, 1)
+	ctx := context.Background()
+	rejectedBefore := gatherProviderMetric(t, "picoclaw_concurrency_rejections_total", id).GetCounter().GetValue()
+
+	release, err := l.Acquire(ctx, id)
+	if err != nil {
+		t.Fatalf("first Acquire: %v", err)
+	}
+	if g := gatherProviderMetric(t, "picoclaw_concurrency_active", id); g.GetGauge().GetValue() != 1 {
+		t.Errorf("active gauge = %v, want 1", g.GetGauge().GetValue())
+	}
+
+	queued := make(chan error, 1)
+	go func() {
+		r, err := l.Acquire(ctx, id)
+		if err == nil {
+			defer r()
+		}
+		queued <- err
+	}()
+	waitFor(t, func() bool {
+		return gatherProviderMetric(t, "picoclaw_concurrency_queue_depth", id).GetGauge().GetValue() == 1
+	})
+
+	if _, err := l.Acquire(ctx, id); !errors.Is(err, ErrConcurrencyLimit) {
+		t.Fatalf("Acquire with a full queue error = %v, want ErrConcurrencyLimit", err)
+	}
+	rejected := gatherProviderMetric(t, "picoclaw_concurrency_rejections_total", id).GetCounter().GetValue()
+	if rejected-rejectedBefore != 1 {
+		t.Errorf("rejections recorded = %v, want 1", rejected-rejectedBefore)
+	}
+
+	if _, err := l.Acquire(ctx, "other-provider"); err != nil {
+		t.Errorf("another provider's slots were affected: %v", err)
+	}
+
+	release()
+	release() // a second release must not free another caller's slot
+	select {
+	case err := <-queued:
+		if err != nil {
+			t.Fatalf("queued Acquire: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("queued caller never got the released slot")
+	}
+}
+
+func TestConcurrencyLimiter_RecordsWaitTime(t *testing.T) {
+	const id = "limiter-wait-provider"
+	const hold = 50 * time.Millisecond
+	l := NewConcurrencyLimiter(1, 1)
+	before := gatherProviderMetric(t, "picoclaw_concurrency_wait_seconds", id).GetHistogram()
+
+	release, err := l.Acquire(context.Background(), id)
+	if err != nil {
+		t.Fatalf("Acquire: %v", err)
+	}
+	time.AfterFunc(hold, release)
+
+	start := time.Now()
+	r, err := l.Acquire(context.Background(), id)
+	if err != nil {
+		t.Fatalf("queued Acquire: %v", err)
+	}
+	r()
+	if waited := time.Since(start); waited < hold {
+		t.Errorf("queued Acquire returned after %v, want at least %v", waited, hold)
+	}
+
+	h := gatherProviderMetric(t, "picoclaw_concurrency_wait_seconds", id).GetHistogram()
+	count := h.GetSampleCount() - before.GetSampleCount()
+	sum := h.GetSampleSum() - before.GetSampleSum()
+	if count != 2 || sum < hold.Seconds() {
+		t.Errorf("wait histogram recorded count=%d sum=%v, want 2 samples summing to at least %v",
+			count, sum, hold.Seconds())
+	}
+}
+
+func TestConcurrencyLimiter_QueuedCallerHonorsContext(t *testing.T) {
+	l := NewConcurrencyLimiter(1, 1)
+	if _, err := l.Acquire(context.Background(), "ctx-provider"); err != nil {
+		t.Fatalf("Acquire: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := l.Acquire(ctx, "ctx-provider"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("error = %v, want context.DeadlineExceeded", err)
+	}
+	// The abandoned wait must leave room in the queue.
+	if g := gatherProviderMetric(t, "picoclaw_concurrency_queue_depth", "ctx-provider"); g.GetGauge().GetValue() != 0 {
+		t.Errorf("queue depth = %v, want 0", g.GetGauge().GetValue())
+	}
+}
+
+type blockingProvider struct {
+	plainMockProvider
+	mu       sync.Mutex
+	inFlight int
+	peak     int
+	gate     chan struct{}
+}
+
+func (p *blockingProvider) Chat(
+	ctx context.Context,
+	messages []Message,
+	tools []ToolDefinition,
+	model string,
+	options map[string]any,
+) (*LLMResponse, error) {
+	p.mu.Lock()
+	p.inFlight++
+	p.peak = max(p.peak, p.inFlight)
+	p.mu.Unlock()
+	<-p.gate
+	p.mu.Lock()
+	p.inFlight--
+	p.mu.Unlock()
+	return &LLMResponse{Content: "ok"}, nil
+}
+
+func TestWrapWithConcurrency_LimitsChat(t *testing.T) {
+	inner := &blockingProvider{gate: make(chan struct{})}
+	p := WrapWithConcurrency(inner, NewConcurrencyLimiter(2, 10), "wrapper-provider")
+	if _, ok := p.(StreamingProvider); ok {
+		t.Error("wrapped non-streaming provider must not report streaming support")
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := p.Chat(context.Background(), nil, nil, "m", nil); err != nil {
+				t.Errorf("Chat: %v", err)
+			}
+		}()
+	}
+	waitFor(t, func() bool {
+		return gatherProviderMetric(t, "picoclaw_concurrency_queue_depth", "wrapper-provider").GetGauge().GetValue() == 3
+	})
+	close(inner.gate)
+	wg.Wait()
+
+	if inner.peak != 2 {
+		t.Errorf("peak concurrent calls = %d, want 2", inner.peak)
+	}
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met within 1s")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,167 @@
+package providers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"maps"
+	"slices"
+	"sync"
+)
+
+// DedupeProvider coalesces concurrent identical Chat calls into one upstream
+// request and hands every caller a copy of its result. Calls are identical
+// when model, messages, tools and options all match.
+type DedupeProvider struct {
+	providerWrapper
+
+	mu    sync.Mutex
+	calls map[string]*dedupeCall
+}
+
+// dedupeCall is one upstream request shared by waiters callers.
+type dedupeCall struct {
+	done    chan struct{}
+	resp    *LLMResponse
+	err     error
+	waiters int
+	cancel  context.CancelFunc
+}
+
+// WrapWithDedupe coalesces p's concurrent identical Chat calls. The result
+// implements StreamingProvider only if p does.
+func WrapWithDedupe(p LLMProvider) LLMProvider {
+	return decorate(&DedupeProvider{providerWrapper: providerWrapper{p}, calls: make(map[string]*dedupeCall)}, p)
+}
+
+// Chat joins an in-flight identical call if there is one. A caller whose
+// context ends stops waiting without affecting the others; the shared call
+// itself is cancelled once every caller has stopped waiting.
+func (d *DedupeProvider) Chat(
+	ctx context.Context,
+	messages []Message,
+	tools []ToolDefinition,
+	model string,
+	options map[string]any,
+) (*LLMResponse, error) {
+	key, ok := requestKey(model, messages, tools, options)
+	if !ok {
+		return d.LLMProvider.Chat(ctx, messages, tools, model, options)
+	}
+
+	d.mu.Lock()
+	c, inFlight := d.calls[key]
+	if !inFlight {
+		upstream, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		c = &dedupeCall{done: make(chan struct{}), cancel: cancel}
+		d.calls[key] = c
+		go d.run(upstream, key, c, messages, tools, model, options)
+	}
+	c.waiters++
+	d.mu.Unlock()
+
+	select {
+	case <-c.done:
+		return cloneResponse(c.resp), c.err
+	case <-ctx.Done():
+		d.mu.Lock()
+		c.waiters--
+		if c.waiters == 0 {
+			c.cancel()
+			d.forget(key, c)
+		}
+		d.mu.Unlock()
+		return nil, ctx.Err()
+	}
+}
+
+// run makes the upstream request for c and wakes its waiters.
+func (d *DedupeProvider) run(
+	ctx context.Context,
+	key string,
+	c *dedupeCall,
+	messages []Message,
+	tools []ToolDefinition,
+	model string,
+	options map[string]any,
+) {
+	defer c.cancel()
+	c.resp, c.err = d.LLMProvider.Chat(ctx, messages, tools, model, options)
+
+	d.mu.Lock()
+	d.forget(key, c)
+	d.mu.Unlock()
+	close(c.done)
+}
+
+// forget removes c from the in-flight calls unless a newer call has already
+// replaced it. d.mu must be held.
+func (d *DedupeProvider) forget(key string, c *dedupeCall) {
+	if d.calls[key] == c {
+		delete(d.calls, key)
+	}
+}
+
+// cloneResponse copies resp deeply enough that callers sharing one upstream
+// response cannot observe each other's changes to it.
+func cloneResponse(resp *LLMResponse) *LLMResponse {
+	if resp == nil {
+		return nil
+	}
+	cp := *resp
+	if resp.ToolCalls != nil {
+		cp.ToolCalls = make([]ToolCall, len(resp.ToolCalls))
+		for i, tc := range resp.ToolCalls {
+			if tc.Function != nil {
+				fn := *tc.Function
+				tc.Function = &fn
+			}
+			if tc.ExtraContent != nil {
+				extra := *tc.ExtraContent
+				if extra.Google != nil {
+					google := *extra.Google
+					extra.Google = &google
+				}
+				tc.ExtraContent = &extra
+			}
+			tc.Arguments = maps.Clone(tc.Arguments)
+			cp.ToolCalls[i] = tc
+		}
+	}
+	if resp.Usage != nil {
+		usage := *resp.Usage
+		cp.Usage = &usage
+	}
+	cp.ReasoningDetails = slices.Clone(resp.ReasoningDetails)
+	return &cp
+}
+
+// chatStream passes streams through; they are not coalesced.
+func (d *DedupeProvider) chatStream(
+	inner StreamingProvider,
+	ctx context.Context,
+	messages []Message,
+	tools []ToolDefinition,
+	model string,
+	options map[string]any,
+	onChunk func(accumulated string),
+) (*LLMResponse, error) {
+	return inner.ChatStream(ctx, messages, tools, model, options, onChunk)
+}
+
+// requestKey hashes everything that determines a response. ok is false if
+// the request cannot be encoded, in which case it is not deduplicated.
+func requestKey(model string, messages []Message, tools []ToolDefinition, options map[string]any) (string, bool) {
+	b, err := json.Marshal(struct {
+		Model    string           `json:"model"`
+		Messages []Message        `json:"messages"`
+		Tools    []ToolDefinition `json:"tools,omitempty"`
+		Options  map[string]any   `json:"options,omitempty"`
+	}{model, messages, tools, options})
+	if err != nil {
+		return "", false
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), true
+}
@@ -0,0 +1,240 @@
+package providers
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// gatedProvider counts calls and blocks each one until gate is closed.
+type gatedProvider struct {
+	plainMockProvider
+	calls   atomic.Int32
+	started chan struct{}
+	gate    chan struct{}
+
+	toolCalls []ToolCall
+	usage     *UsageInfo
+}
+
+func (p *gatedProvider) Chat(
+	ctx context.Context,
+	messages []Message,
+	tools []ToolDefinition,
+	model string,
+	options map[string]any,
+) (*LLMResponse, error) {
+	p.calls.Add(1)
+	p.started <- struct{}{}
+	<-p.gate
+	return &LLMResponse{
+		Content:   "answer to " + messages[len(messages)-1].Content,
+		ToolCalls: p.toolCalls,
+		Usage:     p.usage,
+	}, nil
+}
+
+// cancelAwareProvider blocks until its context ends and reports the error.
+type cancelAwareProvider struct {
+	plainMockProvider
+	started   chan struct{}
+	cancelled chan error
+}
+
+func (p *cancelAwareProvider) Chat(
+	ctx context.Context,
+	messages []Message,
+	tools []ToolDefinition,
+	model string,
+	options map[string]any,
+) (*LLMResponse, error) {
+	p.started <- struct{}{}
+	<-ctx.Done()
+	p.cancelled <- ctx.Err()
+	return nil, ctx.Err()
+}
+
+func newGatedProvider() *gatedProvider {
+	return &gatedProvider{started: make(chan struct{}, 10), gate: make(chan struct{})}
+}
+
+func TestDedupeProvider_CoalescesIdenticalCalls(t *testing.T) {
+	inner := newGatedProvider()
+	p := WrapWithDedupe(inner)
+	msgs := []Message{{Role: "user", Content: "what's for dinner?"}}
+	opts := map[string]any{"temperature": 0.2}
+
+	var wg sync.WaitGroup
+	responses := make([]*LLMResponse, 2)
+	for i := range responses {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := p.Chat(context.Background(), msgs, nil, "m", opts)
+			if err != nil {
+				t.Errorf("Chat: %v", err)
+			}
+			responses[i] = resp
+		}()
+	}
+
+	<-inner.started
+	// Give the second caller time to join the in-flight call.
+	time.Sleep(20 * time.Millisecond)
+	close(inner.gate)
+	wg.Wait()
+
+	if n := inner.calls.Load(); n != 1 {
+		t.Fatalf("upstream calls = %d, want 1", n)
+	}
+	for i, resp := range responses {
+		if resp == nil || resp.Content != "answer to what's for dinner?" {
+			t.Errorf("response %d = %+v", i, resp)
+		}
+	}
+	if responses[0] == responses[1] {
+		t.Error("callers share one response value; each should get its own copy")
+	}
+}
+
+func TestDedupeProvider_DistinctRequestsNotCoalesced(t *testing.T) {
+	inner := newGatedProvider()
+	p := WrapWithDedupe(inner)
+
+	var wg sync.WaitGroup
+	for _, tc := range []struct {
+		model string
+		text  string
+		opts  map[string]any
+	}{
+		{"m", "hello", nil},
+		{"m", "goodbye", nil},
+		{"other", "hello", nil},
+		{"m", "hello", map[string]any{"temperature": 1.0}},
+	} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := p.Chat(context.Background(), []Message{{Role: "user", Content: tc.text}}, nil, tc.model, tc.opts); err != nil {
+				t.Errorf("Chat: %v", err)
+			}
+		}()
+	}
+	for i := 0; i < 4; i++ {
+		<-inner.started
+	}
+	close(inner.gate)
+	wg.Wait()
+
+	if n := inner.calls.Load(); n != 4 {
+		t.Errorf("upstream calls = %d, want 4", n)
+	}
+}
+
+func TestDedupeProvider_CallerCancellationDoesNotAbortOthers(t *testing.T) {
+	inner := newGatedProvider()
+	p := WrapWithDedupe(inner)
+	msgs := []Message{{Role: "user", Content: "hi"}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan error, 1)
+	go func() {
+		_, err := p.Chat(ctx, msgs, nil, "m", nil)
+		first <- err
+	}()
+	<-inner.started
+
+	second := make(chan *LLMResponse, 1)
+	go func() {
+		resp, _ := p.Chat(context.Background(), msgs, nil, "m", nil)
+		second <- resp
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	cancel()
+	if err := <-first; err != context.Canceled {
+		t.Fatalf("cancelled caller error = %v, want context.Canceled", err)
+	}
+	close(inner.gate)
+	if resp := <-second; resp == nil || resp.Content != "answer to hi" {
+		t.Errorf("remaining caller response = %+v", resp)
+	}
+}
+
+func TestDedupeProvider_CancelsUpstreamWhenAllCallersLeave(t *testing.T) {
+	inner := &cancelAwareProvider{started: make(chan struct{}, 1), cancelled: make(chan error, 1)}
+	p := WrapWithDedupe(inner)
+	msgs := []Message{{Role: "user", Content: "hi"}}
+
+	ctx1, cancel1 := context.WithCancel(context.Background())
+	ctx2, cancel2 := context.WithTimeout(context.Background(), time.Hour)
+	defer cancel2()
+	errs := make(chan error, 2)
+	go func() {
+		_, err := p.Chat(ctx1, msgs, nil, "m", nil)
+		errs <- err
+	}()
+	<-inner.started
+	go func() {
+		_, err := p.Chat(ctx2, msgs, nil, "m", nil)
+		errs <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	cancel1()
+	<-errs
+	select {
+	case err := <-inner.cancelled:
+		t.Fatalf("upstream cancelled (%v) while a caller was still waiting", err)
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	cancel2()
+	<-errs
+	select {
+	case <-inner.cancelled:
+	case <-time.After(2 * time.Second):
+		t.Fatal("upstream call was not cancelled after every caller left")
+	}
+}
+
+func TestDedupeProvider_CallersGetIndependentCopies(t *testing.T) {
+	inner := newGatedProvider()
+	inner.toolCalls = []ToolCall{{
+		ID:        "call_1",
+		Name:      "list_chores",
+		Arguments: map[string]any{"who": "sam"},
+		Function:  &FunctionCall{Name: "list_chores", Arguments: `{"who":"sam"}`},
+	}}
+	inner.usage = &UsageInfo{TotalTokens: 10}
+	p := WrapWithDedupe(inner)
+	msgs := []Message{{Role: "user", Content: "chores?"}}
+
+	var wg sync.WaitGroup
+	responses := make([]*LLMResponse, 2)
+	for i := range responses {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			responses[i], _ = p.Chat(context.Background(), msgs, nil, "m", nil)
+		}()
+	}
+	<-inner.started
+	time.Sleep(20 * time.Millisecond)
+	close(inner.gate)
+	wg.Wait()
+
+	a, b := responses[0], responses[1]
+	a.ToolCalls[0].ID = "changed"
+	a.ToolCalls[0].Arguments["who"] = "alex"
+	a.ToolCalls[0].Function.Name = "changed"
+	a.Usage.TotalTokens = 99
+	if got := b.ToolCalls[0]; got.ID != "call_1" || got.Arguments["who"] != "sam" || got.Function.Name != "list_chores" {
+		t.Errorf("second caller's tool call changed with the first's: %+v", got)
+	}
+	if b.Usage.TotalTokens != 10 {
+		t.Errorf("second caller's usage = %d, want 10", b.Usage.TotalTokens)
+	}
+}
@@ -0,0 +1,46 @@
+package providers
+
+import "context"
+
+// EchoProvider is an offline LLMProvider for tests and demos. It replies with
+// a fixed script if one is set, otherwise with the last user message, and
+// never calls tools.
+type EchoProvider struct {
+	reply string
+}
+
+// NewEchoProvider returns an EchoProvider. An empty reply echoes the last
+// user message back.
+func NewEchoProvider(reply string) *EchoProvider {
+	return &EchoProvider{reply: reply}
+}
+
+func (p *EchoProvider) Chat(
+	ctx context.Context,
+	messages []Message,
+	tools []ToolDefinition,
+	model string,
+	options map[string]any,
+) (*LLMResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	content := p.reply
+	if content == "" {
+		content = lastUserMessage(messages)
+	}
+	return &LLMResponse{Content: content, FinishReason: "stop"}, nil
+}
+
+func (p *EchoProvider) GetDefaultModel() string { return "echo" }
+
+func (p *EchoProvider) GetID() string { return "echo" }
+
+func lastUserMessage(messages []Message) string {
+	for i := len(messages) - 1; i >= 0; i-- {
+		if messages[i].Role == "user" {
+			return messages[i].Content
+		}
+	}
+	return ""
+}
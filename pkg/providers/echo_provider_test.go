@@ -0,0 +1,55 @@
+package providers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sipeed/picoclaw/pkg/config"
+)
+
+func TestEchoProvider_EchoesLastUserMessage(t *testing.T) {
+	p := NewEchoProvider("")
+	messages := []Message{
+		{Role: "system", Content: "You are helpful."},
+		{Role: "user", Content: "first"},
+		{Role: "assistant", Content: "ok"},
+		{Role: "user", Content: "second"},
+	}
+	tools := []ToolDefinition{{Type: "function", Function: ToolFunctionDefinition{Name: "exec"}}}
+
+	for i := 0; i < 2; i++ {
+		resp, err := p.Chat(context.Background(), messages, tools, "echo", nil)
+		if err != nil {
+			t.Fatalf("Chat: %v", err)
+		}
+		if resp.Content != "second" || len(resp.ToolCalls) != 0 || resp.FinishReason != "stop" {
+			t.Errorf("call %d response = %+v, want \"second\" with no tool calls", i, resp)
+		}
+	}
+}
+
+func TestEchoProvider_ScriptedReply(t *testing.T) {
+	p := NewEchoProvider("scripted")
+	resp, err := p.Chat(context.Background(), []Message{{Role: "user", Content: "anything"}}, nil, "", nil)
+	if err != nil {
+		t.Fatalf("Chat: %v", err)
+	}
+	if resp.Content != "scripted" {
+		t.Errorf("Content = %q, want scripted", resp.Content)
+	}
+	if p.GetID() != "echo" || p.GetDefaultModel() != "echo" {
+		t.Errorf("GetID = %q, GetDefaultModel = %q, want echo", p.GetID(), p.GetDefaultModel())
+	}
+}
+
+func TestCreateProviderFromConfig_Echo(t *testing.T) {
+	for _, model := range []string{"echo/demo", "mock/demo"} {
+		provider, modelID, err := CreateProviderFromConfig(&config.ModelConfig{ModelName: "offline", Model: model})
+		if err != nil {
+			t.Fatalf("CreateProviderFromConfig(%q) error = %v", model, err)
+		}
+		if _, ok := provider.(*EchoProvider); !ok || modelID != "demo" {
+			t.Errorf("CreateProviderFromConfig(%q) = %T, %q; want *EchoProvider, demo", model, provider, modelID)
+		}
+	}
+}
@@ -260,6 +260,10 @@ func CreateProviderFromConfig(cfg *config.ModelConfig) (LLMProvider, string, err
 	case "antigravity":
 		return NewAntigravityProvider(), modelID, nil
 
+	case "echo", "mock":
+		// Offline provider: needs no credentials and echoes the user.
+		return NewEchoProvider(""), modelID, nil
+
 	case "claude-cli", "claudecli":
 		workspace := cfg.Workspace
 		if workspace == "" {
@@ -23,8 +23,63 @@ func TestCreateProviderReturnsHTTPProviderForOpenRouter(t *testing.T) {
 		t.Fatalf("CreateProvider() error = %v", err)
 	}
 
-	if _, ok := provider.(*HTTPProvider); !ok {
-		t.Fatalf("provider type = %T, want *HTTPProvider", provider)
+	if _, ok := unwrapProvider(provider).(*HTTPProvider); !ok {
+		t.Fatalf("provider type = %T, want *HTTPProvider", unwrapProvider(provider))
+	}
+}
+
+func TestCreateProviderWrapsWithConcurrencyLimit(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Agents.Defaults.ModelName = "test-limited"
+	modelCfg := &config.ModelConfig{
+		ModelName:     "test-limited",
+		Model:         "openrouter/auto",
+		APIBase:       "https://openrouter.ai/api/v1",
+		MaxConcurrent: 2,
+		MaxQueue:      4,
+	}
+	modelCfg.SetAPIKey("sk-or-test")
+	cfg.ModelList = []*config.ModelConfig{modelCfg}
+
+	provider, _, err := CreateProvider(cfg)
+	if err != nil {
+		t.Fatalf("CreateProvider() error = %v", err)
+	}
+
+	sw, ok := provider.(*streamingWrapper)
+	if !ok {
+		t.Fatalf("provider type = %T, want a streaming wrapper", provider)
+	}
+	w, ok := sw.decorator.(*ConcurrencyWrapper)
+	if !ok {
+		t.Fatalf("decorator type = %T, want *ConcurrencyWrapper", sw.decorator)
+	}
+	inner := unwrapProvider(w.LLMProvider)
+	if _, ok := inner.(*HTTPProvider); !ok || w.providerID != "openrouter" {
+		t.Errorf("wrapped provider = %T with ID %q, want *HTTPProvider with ID openrouter", inner, w.providerID)
+	}
+}
+
+// unwrapProvider strips the decorators CreateProvider adds, returning the
+// provider that talks to the model.
+func unwrapProvider(p LLMProvider) LLMProvider {
+	for {
+		switch w := p.(type) {
+		case *streamingWrapper:
+			p = w.decorator
+		case *MetricsWrapper:
+			p = w.LLMProvider
+		case *ConcurrencyWrapper:
+			p = w.LLMProvider
+		case *RetryProvider:
+			p = w.LLMProvider
+		case *DedupeProvider:
+			p = w.LLMProvider
+		case *CachingProvider:
+			p = w.LLMProvider
+		default:
+			return p
+		}
 	}
 }
 
@@ -44,8 +99,8 @@ func TestCreateProviderReturnsCodexCliProviderForCodexCode(t *testing.T) {
 		t.Fatalf("CreateProvider() error = %v", err)
 	}
 
-	if _, ok := provider.(*CodexCliProvider); !ok {
-		t.Fatalf("provider type = %T, want *CodexCliProvider", provider)
+	if _, ok := unwrapProvider(provider).(*CodexCliProvider); !ok {
+		t.Fatalf("provider type = %T, want *CodexCliProvider", unwrapProvider(provider))
 	}
 }
 
@@ -65,8 +120,8 @@ func TestCreateProviderReturnsClaudeCliProviderForClaudeCli(t *testing.T) {
 		t.Fatalf("CreateProvider() error = %v", err)
 	}
 
-	if _, ok := provider.(*ClaudeCliProvider); !ok {
-		t.Fatalf("provider type = %T, want *ClaudeCliProvider", provider)
+	if _, ok := unwrapProvider(provider).(*ClaudeCliProvider); !ok {
+		t.Fatalf("provider type = %T, want *ClaudeCliProvider", unwrapProvider(provider))
 	}
 }
 
@@ -98,8 +153,8 @@ func TestCreateProviderReturnsClaudeProviderForAnthropicOAuth(t *testing.T) {
 		t.Fatalf("CreateProvider() error = %v", err)
 	}
 
-	if _, ok := provider.(*ClaudeProvider); !ok {
-		t.Fatalf("provider type = %T, want *ClaudeProvider", provider)
+	if _, ok := unwrapProvider(provider).(*ClaudeProvider); !ok {
+		t.Fatalf("provider type = %T, want *ClaudeProvider", unwrapProvider(provider))
 	}
 	// TODO: Test custom APIBase when createClaudeAuthProvider supports it
 }
@@ -7,6 +7,7 @@ package providers
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/sipeed/picoclaw/pkg/config"
 )
@@ -34,11 +35,45 @@ func CreateProvider(cfg *config.Config) (LLMProvider, string, error) {
 		modelCfg.Workspace = cfg.WorkspacePath()
 	}
 
-	// Use factory to create provider
-	provider, modelID, err := CreateProviderFromConfig(modelCfg)
+	provider, modelID, err := CreateProviderForModel(modelCfg)
 	if err != nil {
 		return nil, "", fmt.Errorf("failed to create provider for model %q: %w", model, err)
 	}
+	return provider, modelID, nil
+}
+
+// CreateProviderForModel creates the provider for modelCfg, wrapped with
+// metrics and with the concurrency limit, retries, request dedupe and
+// response cache its config asks for. Use it rather than CreateProviderFromConfig wherever the agent
+// talks to the model.
+func CreateProviderForModel(modelCfg *config.ModelConfig) (LLMProvider, string, error) {
+	provider, modelID, err := CreateProviderFromConfig(modelCfg)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Metrics sit innermost so they see every upstream attempt and never a
+	// cache hit.
+	provider = WrapWithMetrics(provider)
+	if modelCfg.MaxConcurrent > 0 {
+		protocol, _ := ExtractProtocol(modelCfg.Model)
+		limiter := NewConcurrencyLimiter(modelCfg.MaxConcurrent, modelCfg.MaxQueue)
+		provider = WrapWithConcurrency(provider, limiter, protocol)
+	}
+	// Retry outside the limiter so backoff does not hold a slot.
+	if modelCfg.MaxRetries > 0 {
+		provider = WrapWithRetry(provider, modelCfg.MaxRetries, 0)
+	}
+	if modelCfg.DedupeRequests {
+		provider = WrapWithDedupe(provider)
+	}
+	if modelCfg.CacheTTL > 0 {
+		provider = WrapWithCache(provider, time.Duration(modelCfg.CacheTTL)*time.Second, modelCfg.CacheSize)
+	}
+	// Validate outermost so a malformed history is never retried or cached.
+	if modelCfg.ValidateMessages {
+		provider = WrapWithValidation(provider)
+	}
 
 	return provider, modelID, nil
 }
@@ -4,31 +4,127 @@ import (
 	"context"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
 	"github.com/sipeed/picoclaw/pkg/metrics"
+	"github.com/sipeed/picoclaw/pkg/tracing"
 )
 
 // MetricsWrapper decorates an LLMProvider to record metrics.
 type MetricsWrapper struct {
-	LLMProvider
+	providerWrapper
 }
 
-// WrapWithMetrics wraps a provider with metrics collection.
+// WrapWithMetrics wraps a provider with metrics collection. The result
+// implements StreamingProvider only if p does.
 func WrapWithMetrics(p LLMProvider) LLMProvider {
-	return &MetricsWrapper{p}
+	return decorate(&MetricsWrapper{providerWrapper{p}}, p)
+}
+
+// contextSizeKey carries a caller-computed context size to the
+// MetricsWrapper through the decorators stacked above it.
+type contextSizeKey struct{}
+
+// WithContextSize returns a context telling the MetricsWrapper that the
+// request holds tokens tokens of context. Callers that already counted the
+// prompt (the agent loop does, for its context budget) set it so the
+// recorded size matches theirs, tool definitions included.
+func WithContextSize(ctx context.Context, tokens int) context.Context {
+	return context.WithValue(ctx, contextSizeKey{}, tokens)
 }
 
 func (w *MetricsWrapper) Chat(ctx context.Context, messages []Message, tools []ToolDefinition, model string, options map[string]interface{}) (*LLMResponse, error) {
+	ctx, span := w.startSpan(ctx, model)
+	defer span.End()
+
 	start := time.Now()
 	resp, err := w.LLMProvider.Chat(ctx, messages, tools, model, options)
-	duration := time.Since(start)
+	w.record(ctx, model, time.Since(start), resp, err, contextSizeOf(ctx, messages))
+	tracing.RecordError(span, err)
+	return resp, err
+}
 
-	// Record metrics
-	agentType := metrics.AgentTypeFromContext(ctx)
+// ChatCounted is Chat for callers that already know the context size in
+// tokens. A contextSize of 0 is estimated from the messages.
+func (w *MetricsWrapper) ChatCounted(
+	ctx context.Context,
+	messages []Message,
+	tools []ToolDefinition,
+	model string,
+	options map[string]any,
+	contextSize int,
+) (*LLMResponse, error) {
+	return w.Chat(WithContextSize(ctx, contextSize), messages, tools, model, options)
+}
 
-	providerID := "unknown"
+// chatStream passes through to the wrapped provider's ChatStream, recording
+// the latency of the first chunk in addition to the usual call metrics.
+func (w *MetricsWrapper) chatStream(
+	inner StreamingProvider,
+	ctx context.Context,
+	messages []Message,
+	tools []ToolDefinition,
+	model string,
+	options map[string]any,
+	onChunk func(accumulated string),
+) (*LLMResponse, error) {
+	ctx, span := w.startSpan(ctx, model)
+	span.SetAttributes(attribute.Bool("llm.streaming", true))
+	defer span.End()
+
+	start := time.Now()
+	firstChunk := true
+	resp, err := inner.ChatStream(ctx, messages, tools, model, options, func(accumulated string) {
+		if firstChunk {
+			firstChunk = false
+			metrics.DefaultRecorder().RecordTimeToFirstToken(model, time.Since(start))
+		}
+		if onChunk != nil {
+			onChunk(accumulated)
+		}
+	})
+	w.record(ctx, model, time.Since(start), resp, err, contextSizeOf(ctx, messages))
+	tracing.RecordError(span, err)
+	return resp, err
+}
+
+// startSpan opens the "llm.chat" span for one provider call.
+func (w *MetricsWrapper) startSpan(ctx context.Context, model string) (context.Context, trace.Span) {
+	return tracing.Start(ctx, "llm.chat",
+		attribute.String("llm.model", model),
+		attribute.String("llm.provider", w.providerID()),
+	)
+}
+
+func (w *MetricsWrapper) providerID() string {
 	if p, ok := w.LLMProvider.(interface{ GetID() string }); ok {
-		providerID = p.GetID()
+		return p.GetID()
+	}
+	return "unknown"
+}
+
+// contextSizeOf returns the size set with WithContextSize, otherwise the
+// estimated token count of messages.
+func contextSizeOf(ctx context.Context, messages []Message) int {
+	if known, _ := ctx.Value(contextSizeKey{}).(int); known > 0 {
+		return known
 	}
+	return CountMessageTokens(messages)
+}
+
+func (w *MetricsWrapper) record(
+	ctx context.Context,
+	model string,
+	duration time.Duration,
+	resp *LLMResponse,
+	err error,
+	contextSize int,
+) {
+	// Record metrics
+	agentType := metrics.AgentTypeFromContext(ctx)
+
+	providerID := w.providerID()
 
 	status := "success"
 	if err != nil {
@@ -50,7 +146,6 @@ func (w *MetricsWrapper) Chat(ctx context.Context, messages []Message, tools []T
 		apiBase = p.GetAPIBase()
 	}
 
-	metrics.DefaultRecorder().RecordLLMCall(model, providerID, apiBase, string(agentType), status, duration, usage, 0)
-
-	return resp, err
+	metrics.DefaultRecorder().RecordLLMCall(model, providerID, apiBase, string(agentType), status, duration, usage, contextSize)
+	metrics.DefaultRecorder().RecordLLMCost(model, usage)
 }
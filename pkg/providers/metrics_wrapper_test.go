@@ -0,0 +1,262 @@
+package providers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace/noop"
+
+	"github.com/sipeed/picoclaw/pkg/config"
+	"github.com/sipeed/picoclaw/pkg/metrics"
+	"github.com/sipeed/picoclaw/pkg/tracing"
+)
+
+type plainMockProvider struct{}
+
+func (plainMockProvider) Chat(
+	ctx context.Context,
+	messages []Message,
+	tools []ToolDefinition,
+	model string,
+	options map[string]any,
+) (*LLMResponse, error) {
+	return &LLMResponse{Content: "hello world", FinishReason: "stop"}, nil
+}
+
+func (plainMockProvider) GetDefaultModel() string { return "mock" }
+
+type streamingMockProvider struct {
+	plainMockProvider
+	chunks []string
+}
+
+func (p streamingMockProvider) ChatStream(
+	ctx context.Context,
+	messages []Message,
+	tools []ToolDefinition,
+	model string,
+	options map[string]any,
+	onChunk func(accumulated string),
+) (*LLMResponse, error) {
+	var acc string
+	for _, c := range p.chunks {
+		acc += c
+		onChunk(acc)
+	}
+	return &LLMResponse{Content: acc, FinishReason: "stop"}, nil
+}
+
+// gatherHistogram returns the named histogram for the given model label.
+func gatherHistogram(t *testing.T, name, model string) *dto.Histogram {
+	t.Helper()
+	families, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatalf("Gather: %v", err)
+	}
+	for _, mf := range families {
+		if mf.GetName() != name {
+			continue
+		}
+		for _, m := range mf.GetMetric() {
+			for _, l := range m.GetLabel() {
+				if l.GetName() == "model" && l.GetValue() == model {
+					return m.GetHistogram()
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func TestWrapWithMetrics_PreservesStreamingDetection(t *testing.T) {
+	if _, ok := WrapWithMetrics(plainMockProvider{}).(StreamingProvider); ok {
+		t.Error("wrapped non-streaming provider must not report streaming support")
+	}
+	if _, ok := WrapWithMetrics(streamingMockProvider{}).(StreamingProvider); !ok {
+		t.Error("wrapped streaming provider must report streaming support")
+	}
+}
+
+func TestMetricsWrapper_ChatStream(t *testing.T) {
+	const model = "stream-metrics-test-model"
+	p := WrapWithMetrics(streamingMockProvider{chunks: []string{"hel", "lo ", "world"}})
+	sp := p.(StreamingProvider)
+
+	var got []string
+	resp, err := sp.ChatStream(context.Background(), nil, nil, model, nil, func(acc string) {
+		got = append(got, acc)
+	})
+	if err != nil {
+		t.Fatalf("ChatStream: %v", err)
+	}
+	if resp.Content != "hello world" {
+		t.Errorf("Content = %q, want %q", resp.Content, "hello world")
+	}
+	want := []string{"hel", "hello ", "hello world"}
+	if len(got) != len(want) {
+		t.Fatalf("chunks = %q, want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("chunk %d = %q, want %q", i, got[i], want[i])
+		}
+	}
+
+	// Only the first chunk is timed.
+	h := gatherHistogram(t, "picoclaw_llm_time_to_first_token_seconds", model)
+	if n := h.GetSampleCount(); n != 1 {
+		t.Errorf("time-to-first-token samples = %d, want 1", n)
+	}
+}
+
+func TestMetricsWrapper_ChatCountedRecordsContextSize(t *testing.T) {
+	const model = "context-size-test-model"
+	w := &MetricsWrapper{providerWrapper{plainMockProvider{}}}
+
+	if _, err := w.ChatCounted(context.Background(), nil, nil, model, nil, 12345); err != nil {
+		t.Fatalf("ChatCounted: %v", err)
+	}
+
+	h := gatherHistogram(t, "picoclaw_llm_context_size_tokens", model)
+	if h.GetSampleCount() != 1 || h.GetSampleSum() != 12345 {
+		t.Errorf("context size histogram count=%d sum=%v, want 1 and 12345", h.GetSampleCount(), h.GetSampleSum())
+	}
+}
+
+func TestMetricsWrapper_ChatSpanNestsUnderTurn(t *testing.T) {
+	rec := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec)))
+	defer otel.SetTracerProvider(noop.NewTracerProvider())
+
+	p := WrapWithMetrics(plainMockProvider{})
+	ctx, turn := tracing.Start(context.Background(), "agent.turn")
+	if _, err := p.Chat(ctx, nil, nil, "span-test-model", nil); err != nil {
+		t.Fatalf("Chat: %v", err)
+	}
+	turn.End()
+
+	spans := rec.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("got %d spans, want 2", len(spans))
+	}
+	llm, parent := spans[0], spans[1]
+	if llm.Name() != "llm.chat" || llm.Parent().SpanID() != parent.SpanContext().SpanID() {
+		t.Errorf("llm span %q is not a child of the turn span", llm.Name())
+	}
+	if llm.Attributes()[0] != attribute.String("llm.model", "span-test-model") {
+		t.Errorf("llm span attributes = %v", llm.Attributes())
+	}
+}
+
+func TestMetricsWrapper_ChatEstimatesContextSize(t *testing.T) {
+	const model = "gpt-context-estimate-test"
+	messages := []Message{
+		{Role: "system", Content: "You are a helpful assistant."},
+		{Role: "user", Content: "What is the capital of France?"},
+	}
+
+	if _, err := WrapWithMetrics(plainMockProvider{}).Chat(context.Background(), messages, nil, model, nil); err != nil {
+		t.Fatalf("Chat: %v", err)
+	}
+
+	h := gatherHistogram(t, "picoclaw_llm_context_size_tokens", model)
+	want := float64(CountMessageTokens(messages))
+	if want == 0 || h.GetSampleCount() != 1 || h.GetSampleSum() != want {
+		t.Errorf("context size histogram count=%d sum=%v, want 1 and %v", h.GetSampleCount(), h.GetSampleSum(), want)
+	}
+}
+
+// llmCost returns picoclaw_llm_cost_usd_total for model.
+func llmCost(t *testing.T, model string) float64 {
+	t.Helper()
+	families, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatalf("Gather: %v", err)
+	}
+	for _, mf := range families {
+		if mf.GetName() != "picoclaw_llm_cost_usd_total" {
+			continue
+		}
+		for _, m := range mf.GetMetric() {
+			for _, l := range m.GetLabel() {
+				if l.GetName() == "model" && l.GetValue() == model {
+					return m.GetCounter().GetValue()
+				}
+			}
+		}
+	}
+	return 0
+}
+
+func TestCreateProviderRecordsLLMCost(t *testing.T) {
+	const model = "cost-test-model"
+	metrics.SetModelPrice(model, metrics.ModelPrice{InputPer1K: 0.01, OutputPer1K: 0.02})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"hi"},"finish_reason":"stop"}],
+			"usage":{"prompt_tokens":1000,"completion_tokens":500,"total_tokens":1500}}`))
+	}))
+	defer server.Close()
+
+	cfg := config.DefaultConfig()
+	cfg.Agents.Defaults.ModelName = "priced"
+	modelCfg := &config.ModelConfig{ModelName: "priced", Model: "openai/" + model, APIBase: server.URL}
+	modelCfg.SetAPIKey("sk-test")
+	cfg.ModelList = []*config.ModelConfig{modelCfg}
+	provider, modelID, err := CreateProvider(cfg)
+	if err != nil {
+		t.Fatalf("CreateProvider() error = %v", err)
+	}
+	before := llmCost(t, model)
+
+	if _, err := provider.Chat(context.Background(), []Message{{Role: "user", Content: "hello"}}, nil, modelID, nil); err != nil {
+		t.Fatalf("Chat: %v", err)
+	}
+	if got := llmCost(t, model) - before; got < 0.019999 || got > 0.020001 {
+		t.Errorf("recorded cost = %v, want 0.02 (1000 prompt + 500 completion tokens)", got)
+	}
+}
+
+func TestCreateProviderForModel_RecordsCallerContextSize(t *testing.T) {
+	const model = "context-size-stack-test-model"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"hi"},"finish_reason":"stop"}]}`))
+	}))
+	defer server.Close()
+
+	// Every decorator sits above the metrics wrapper.
+	modelCfg := &config.ModelConfig{
+		ModelName:        "stacked",
+		Model:            "openai/" + model,
+		APIBase:          server.URL,
+		MaxConcurrent:    2,
+		MaxRetries:       1,
+		DedupeRequests:   true,
+		CacheTTL:         60,
+		ValidateMessages: true,
+	}
+	modelCfg.SetAPIKey("sk-test")
+	provider, modelID, err := CreateProviderForModel(modelCfg)
+	if err != nil {
+		t.Fatalf("CreateProviderForModel() error = %v", err)
+	}
+
+	ctx := WithContextSize(context.Background(), 4321)
+	if _, err := provider.Chat(ctx, []Message{{Role: "user", Content: "hello"}}, nil, modelID, nil); err != nil {
+		t.Fatalf("Chat: %v", err)
+	}
+
+	h := gatherHistogram(t, "picoclaw_llm_context_size_tokens", model)
+	if h.GetSampleCount() != 1 || h.GetSampleSum() != 4321 {
+		t.Errorf("context size histogram count=%d sum=%v, want 1 and 4321", h.GetSampleCount(), h.GetSampleSum())
+	}
+}
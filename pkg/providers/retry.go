@@ -0,0 +1,144 @@
+package providers
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+
+	"github.com/sipeed/picoclaw/pkg/logger"
+)
+
+const (
+	defaultRetryBaseDelay = 500 * time.Millisecond
+	defaultRetryMaxDelay  = 10 * time.Second
+)
+
+// RetryProvider retries transient failures (timeouts, rate limits, 5xx) of
+// the same provider and model with exponential backoff and jitter. Unlike
+// FallbackChain it never switches models.
+type RetryProvider struct {
+	providerWrapper
+	maxRetries int
+	baseDelay  time.Duration
+	maxDelay   time.Duration
+
+	// sleep waits for d or until ctx ends; replaced in tests.
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+// WrapWithRetry retries p's failed calls up to maxRetries times, starting
+// from baseDelay (500ms if zero) and doubling up to 10s. The result
+// implements StreamingProvider only if p does.
+func WrapWithRetry(p LLMProvider, maxRetries int, baseDelay time.Duration) LLMProvider {
+	if baseDelay <= 0 {
+		baseDelay = defaultRetryBaseDelay
+	}
+	return decorate(&RetryProvider{
+		providerWrapper: providerWrapper{p},
+		maxRetries:      maxRetries,
+		baseDelay:       baseDelay,
+		maxDelay:        max(defaultRetryMaxDelay, baseDelay),
+		sleep:           sleepContext,
+	}, p)
+}
+
+func (r *RetryProvider) Chat(
+	ctx context.Context,
+	messages []Message,
+	tools []ToolDefinition,
+	model string,
+	options map[string]any,
+) (*LLMResponse, error) {
+	return r.do(ctx, model, func() (*LLMResponse, error) {
+		return r.LLMProvider.Chat(ctx, messages, tools, model, options)
+	}, nil)
+}
+
+// ChatStream retries only while no chunk has been delivered; once output has
+// reached the caller a retry would repeat it.
+func (r *RetryProvider) chatStream(
+	inner StreamingProvider,
+	ctx context.Context,
+	messages []Message,
+	tools []ToolDefinition,
+	model string,
+	options map[string]any,
+	onChunk func(accumulated string),
+) (*LLMResponse, error) {
+	streamed := false
+	return r.do(ctx, model, func() (*LLMResponse, error) {
+		return inner.ChatStream(ctx, messages, tools, model, options, func(accumulated string) {
+			streamed = true
+			if onChunk != nil {
+				onChunk(accumulated)
+			}
+		})
+	}, func() bool { return streamed })
+}
+
+// do runs call, retrying retriable errors. started, if set, reports whether
+// the failed attempt already produced output and so must not be retried.
+func (r *RetryProvider) do(
+	ctx context.Context,
+	model string,
+	call func() (*LLMResponse, error),
+	started func() bool,
+) (*LLMResponse, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := call()
+		if err == nil || attempt >= r.maxRetries || ctx.Err() != nil || !isTransient(err) {
+			return resp, err
+		}
+		if started != nil && started() {
+			return resp, err
+		}
+
+		delay := r.backoff(attempt)
+		logger.WarnCtxCF(ctx, "providers", "Retrying transient LLM error",
+			map[string]any{
+				"model":   model,
+				"attempt": attempt + 1,
+				"delay":   delay.String(),
+				"error":   err.Error(),
+			})
+		if sleepErr := r.sleep(ctx, delay); sleepErr != nil {
+			return resp, err
+		}
+	}
+}
+
+// backoff returns the delay before retry attempt+1: the exponential delay
+// capped at maxDelay, jittered to between half and all of it.
+func (r *RetryProvider) backoff(attempt int) time.Duration {
+	d := r.baseDelay
+	for i := 0; i < attempt && d < r.maxDelay; i++ {
+		d <<= 1
+	}
+	d = min(d, r.maxDelay)
+	half := d / 2
+	return half + rand.N(d-half+1)
+}
+
+// isTransient reports whether err is worth retrying against the same model.
+func isTransient(err error) bool {
+	fe := ClassifyError(err, "", "")
+	if fe == nil {
+		return false
+	}
+	switch fe.Reason {
+	case FailoverTimeout, FailoverRateLimit, FailoverOverloaded:
+		return true
+	}
+	return false
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,154 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// flakyProvider fails with errs in order, then succeeds.
+type flakyProvider struct {
+	plainMockProvider
+	errs  []error
+	calls int
+}
+
+func (p *flakyProvider) Chat(
+	ctx context.Context,
+	messages []Message,
+	tools []ToolDefinition,
+	model string,
+	options map[string]any,
+) (*LLMResponse, error) {
+	p.calls++
+	if p.calls <= len(p.errs) {
+		return nil, p.errs[p.calls-1]
+	}
+	return &LLMResponse{Content: "ok"}, nil
+}
+
+// newTestRetry wraps p and records the backoff delays instead of sleeping.
+func newTestRetry(p LLMProvider, maxRetries int) (*RetryProvider, *[]time.Duration) {
+	r := WrapWithRetry(p, maxRetries, 100*time.Millisecond).(*RetryProvider)
+	var delays []time.Duration
+	r.sleep = func(ctx context.Context, d time.Duration) error {
+		delays = append(delays, d)
+		return ctx.Err()
+	}
+	return r, &delays
+}
+
+func TestRetryProvider_RecoversFromTransientErrors(t *testing.T) {
+	inner := &flakyProvider{errs: []error{
+		errors.New("API request failed: status 503: service unavailable"),
+		errors.New("API request failed: status 429: too many requests"),
+	}}
+	r, delays := newTestRetry(inner, 3)
+
+	resp, err := r.Chat(context.Background(), nil, nil, "m", nil)
+	if err != nil {
+		t.Fatalf("Chat: %v", err)
+	}
+	if resp.Content != "ok" || inner.calls != 3 {
+		t.Errorf("resp = %+v after %d calls, want ok after 3", resp, inner.calls)
+	}
+
+	// Backoff doubles from the base delay, jittered to half-to-full.
+	if len(*delays) != 2 {
+		t.Fatalf("delays = %v, want 2", *delays)
+	}
+	for i, d := range *delays {
+		full := 100 * time.Millisecond << i
+		if d < full/2 || d > full {
+			t.Errorf("delay %d = %v, want within [%v, %v]", i, d, full/2, full)
+		}
+	}
+}
+
+func TestRetryProvider_ExhaustsRetries(t *testing.T) {
+	last := errors.New("request timed out")
+	inner := &flakyProvider{errs: []error{last, last, last, last}}
+	r, delays := newTestRetry(inner, 2)
+
+	if _, err := r.Chat(context.Background(), nil, nil, "m", nil); !errors.Is(err, last) {
+		t.Fatalf("error = %v, want the last provider error", err)
+	}
+	if inner.calls != 3 || len(*delays) != 2 {
+		t.Errorf("calls = %d with %d sleeps, want 3 calls and 2 sleeps", inner.calls, len(*delays))
+	}
+}
+
+func TestRetryProvider_DoesNotRetryPermanentErrors(t *testing.T) {
+	for _, err := range []error{
+		errors.New("status 401: invalid api key"),
+		errors.New("status 400: invalid request format"),
+		errors.New("something unexpected"),
+	} {
+		inner := &flakyProvider{errs: []error{err}}
+		r, _ := newTestRetry(inner, 3)
+		if _, got := r.Chat(context.Background(), nil, nil, "m", nil); !errors.Is(got, err) || inner.calls != 1 {
+			t.Errorf("%q: error = %v after %d calls, want it returned after 1", err, got, inner.calls)
+		}
+	}
+}
+
+func TestRetryProvider_StopsWhenContextEnds(t *testing.T) {
+	inner := &flakyProvider{errs: []error{errors.New("status 502"), errors.New("status 502")}}
+	r := WrapWithRetry(inner, 5, time.Hour).(*RetryProvider)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, err := r.Chat(ctx, nil, nil, "m", nil); err == nil {
+		t.Fatal("Chat succeeded, want the provider error")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second || inner.calls != 1 {
+		t.Errorf("returned after %v and %d calls, want prompt return after 1", elapsed, inner.calls)
+	}
+}
+
+func TestRetryProvider_StreamNotRetriedAfterOutput(t *testing.T) {
+	inner := &failingStreamProvider{err: errors.New("status 503")}
+	p := WrapWithRetry(inner, 3, time.Millisecond)
+	sp, ok := p.(StreamingProvider)
+	if !ok {
+		t.Fatal("wrapped streaming provider must report streaming support")
+	}
+
+	if _, err := sp.ChatStream(context.Background(), nil, nil, "m", nil, nil); err == nil {
+		t.Fatal("ChatStream succeeded, want the provider error")
+	}
+	if inner.calls != 1 {
+		t.Errorf("calls = %d, want 1 (output was already streamed)", inner.calls)
+	}
+}
+
+// failingStreamProvider streams one chunk and then fails.
+type failingStreamProvider struct {
+	plainMockProvider
+	err   error
+	calls int
+}
+
+func (p *failingStreamProvider) ChatStream(
+	ctx context.Context,
+	messages []Message,
+	tools []ToolDefinition,
+	model string,
+	options map[string]any,
+	onChunk func(accumulated string),
+) (*LLMResponse, error) {
+	p.calls++
+	onChunk("partial")
+	return nil, p.err
+}
+
+func TestRetryProvider_BackoffDoesNotOverflow(t *testing.T) {
+	r := WrapWithRetry(&flakyProvider{}, 50, 2*time.Hour).(*RetryProvider)
+	for attempt := 0; attempt < 50; attempt++ {
+		if d := r.backoff(attempt); d < time.Hour || d > 2*time.Hour {
+			t.Fatalf("backoff(%d) = %v, want between 1h and 2h", attempt, d)
+		}
+	}
+}
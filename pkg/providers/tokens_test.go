@@ -0,0 +1,21 @@
+package providers
+
+import "testing"
+
+func TestCountMessageTokens(t *testing.T) {
+	messages := []Message{
+		{Role: "user", Content: "Hello, world!"},
+		{Role: "assistant", ToolCalls: []ToolCall{{
+			ID:       "call_1",
+			Function: &FunctionCall{Name: "get_weather", Arguments: `{"city":"Paris"}`},
+		}}},
+	}
+
+	want := EstimateMessageTokens(messages[0]) + EstimateMessageTokens(messages[1])
+	if got := CountMessageTokens(messages); got != want {
+		t.Errorf("CountMessageTokens = %d, want %d", got, want)
+	}
+	if got := CountMessageTokens(nil); got != 0 {
+		t.Errorf("CountMessageTokens(nil) = %d, want 0", got)
+	}
+}
@@ -0,0 +1,61 @@
+package providers
+
+// TurnBoundaries returns the starting index of each Turn in the history.
+// A Turn is a complete "user input → LLM iterations → final response" cycle:
+// it begins at a user message and extends through all subsequent
+// assistant/tool messages until the next user message.
+//
+// Cutting at a Turn boundary guarantees that no tool-call sequence
+// (assistant+ToolCalls → tool results) is split across the cut.
+func TurnBoundaries(history []Message) []int {
+	var starts []int
+	for i, msg := range history {
+		if msg.Role == "user" {
+			starts = append(starts, i)
+		}
+	}
+	return starts
+}
+
+// TrimMessages drops the oldest Turns from a history until it fits in
+// maxTokens, as estimated by CountMessageTokens. Whole Turns are dropped so
+// no tool result outlives its call. The Turn holding the latest user message
+// is always kept, as are system messages when keepSystem is set, so the
+// result may still exceed a budget too small for them. A history without a
+// user message has no safe cut and is returned unchanged. The input slice is
+// not modified.
+func TrimMessages(messages []Message, maxTokens int, keepSystem bool) []Message {
+	total := CountMessageTokens(messages)
+	if total <= maxTokens {
+		return messages
+	}
+
+	turns := TurnBoundaries(messages)
+	if len(turns) == 0 {
+		return messages
+	}
+
+	// Advance the cut one Turn at a time, stopping at the latest one.
+	cut, next := 0, 0
+	for _, start := range turns {
+		for ; next < start; next++ {
+			if !keepSystem || messages[next].Role != "system" {
+				total -= EstimateMessageTokens(messages[next])
+			}
+		}
+		cut = start
+		if total <= maxTokens {
+			break
+		}
+	}
+
+	kept := make([]Message, 0, len(messages)-cut+1)
+	if keepSystem {
+		for _, m := range messages[:cut] {
+			if m.Role == "system" {
+				kept = append(kept, m)
+			}
+		}
+	}
+	return append(kept, messages[cut:]...)
+}
@@ -0,0 +1,91 @@
+package providers
+
+import (
+	"strings"
+	"testing"
+)
+
+func roles(messages []Message) string {
+	parts := make([]string, len(messages))
+	for i, m := range messages {
+		parts[i] = m.Role + ":" + m.Content
+	}
+	return strings.Join(parts, " ")
+}
+
+func TestTrimMessages(t *testing.T) {
+	long := strings.Repeat("x", 250) // 100 tokens + overhead
+	history := []Message{
+		{Role: "system", Content: "sys"},
+		{Role: "user", Content: "u1 " + long},
+		{Role: "assistant", Content: "a1 " + long},
+		{Role: "user", Content: "u2 " + long},
+		{Role: "assistant", Content: "a2"},
+		{Role: "user", Content: "u3"},
+	}
+	full := CountMessageTokens(history)
+
+	tests := []struct {
+		name       string
+		maxTokens  int
+		keepSystem bool
+		want       string
+	}{
+		{"under budget", full, true, roles(history)},
+		{"drops the oldest turn", full - 1, true, roles([]Message{history[0], history[3], history[4], history[5]})},
+		{"drops turns until it fits", 150, true, roles([]Message{history[0], history[3], history[4], history[5]})},
+		{"keeps system and latest user", 1, true, roles([]Message{history[0], history[5]})},
+		{"system can go when not kept", 1, false, roles([]Message{history[5]})},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := TrimMessages(history, tt.maxTokens, tt.keepSystem)
+			if roles(got) != tt.want {
+				t.Errorf("TrimMessages(%d) =\n  %s\nwant\n  %s", tt.maxTokens, roles(got), tt.want)
+			}
+			if tt.maxTokens > 100 && CountMessageTokens(got) > tt.maxTokens {
+				t.Errorf("result has %d tokens, over the budget of %d", CountMessageTokens(got), tt.maxTokens)
+			}
+		})
+	}
+
+	if roles(history) != "system:sys user:u1 "+long+" assistant:a1 "+long+" user:u2 "+long+" assistant:a2 user:u3" {
+		t.Error("TrimMessages modified its input")
+	}
+}
+
+func TestTrimMessages_DropsToolResultsWithTheirCall(t *testing.T) {
+	history := []Message{
+		{Role: "system", Content: "sys"},
+		{Role: "user", Content: "look it up"},
+		{Role: "assistant", ToolCalls: []ToolCall{{ID: "c1", Function: &FunctionCall{Name: "search"}}}},
+		{Role: "tool", ToolCallID: "c1", Content: strings.Repeat("r", 400)},
+		{Role: "assistant", Content: "found it"},
+		{Role: "user", Content: "thanks"},
+	}
+
+	got := TrimMessages(history, CountMessageTokens(history)-10, true)
+	if roles(got) != "system:sys user:thanks" {
+		t.Errorf("TrimMessages = %s, want the system prompt and the latest turn", roles(got))
+	}
+}
+
+func TestTrimMessages_KeepsLatestTurnOverBudget(t *testing.T) {
+	history := []Message{
+		{Role: "system", Content: "sys"},
+		{Role: "user", Content: "old"},
+		{Role: "user", Content: "run it"},
+		{Role: "assistant", ToolCalls: []ToolCall{{ID: "c1", Function: &FunctionCall{Name: "exec"}}}},
+		{Role: "tool", ToolCallID: "c1", Content: strings.Repeat("r", 400)},
+	}
+
+	got := TrimMessages(history, 1, true)
+	if roles(got) != roles([]Message{history[0], history[2], history[3], history[4]}) {
+		t.Errorf("TrimMessages = %s, want the latest turn kept whole", roles(got))
+	}
+
+	noUser := []Message{{Role: "system", Content: "sys"}, {Role: "assistant", Content: strings.Repeat("a", 400)}}
+	if got := TrimMessages(noUser, 1, true); len(got) != len(noUser) {
+		t.Errorf("history without a user message was trimmed to %s", roles(got))
+	}
+}
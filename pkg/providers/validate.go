@@ -0,0 +1,116 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// MessageError describes the first malformed message ValidateMessages found.
+type MessageError struct {
+	Index  int
+	Role   string
+	Reason string
+}
+
+func (e *MessageError) Error() string {
+	return fmt.Sprintf("invalid message %d (role %q): %s", e.Index, e.Role, e.Reason)
+}
+
+// ValidateMessages checks a history for problems chat APIs reject, so they
+// surface as a clear error before dispatch instead of a provider error:
+//   - roles must be system, user, assistant or tool;
+//   - there is at most one system message, and it comes first;
+//   - every message has content, media or (for assistants) tool calls,
+//     except tool results, whose output may be empty;
+//   - tool calls have IDs, and each tool result answers an earlier,
+//     still unanswered tool call.
+//
+// It returns a *MessageError for the first violation. WrapWithValidation runs
+// it before every call.
+func ValidateMessages(messages []Message) error {
+	pending := make(map[string]bool)
+	for i, m := range messages {
+		fail := func(format string, args ...any) error {
+			return &MessageError{Index: i, Role: m.Role, Reason: fmt.Sprintf(format, args...)}
+		}
+
+		switch m.Role {
+		case "system":
+			if i != 0 {
+				return fail("system message must be the first message")
+			}
+		case "user", "assistant", "tool":
+		case "":
+			return fail("role is empty")
+		default:
+			return fail("unknown role")
+		}
+
+		hasContent := strings.TrimSpace(m.Content) != "" || len(m.Media) > 0 || len(m.SystemParts) > 0
+		if !hasContent && !(m.Role == "assistant" && len(m.ToolCalls) > 0) && m.Role != "tool" {
+			return fail("content is empty")
+		}
+
+		if len(m.ToolCalls) > 0 && m.Role != "assistant" {
+			return fail("only assistant messages may contain tool calls")
+		}
+		for _, tc := range m.ToolCalls {
+			if tc.ID == "" {
+				return fail("tool call has no ID")
+			}
+			pending[tc.ID] = true
+		}
+
+		if m.Role == "tool" {
+			if m.ToolCallID == "" {
+				return fail("tool result has no tool_call_id")
+			}
+			if !pending[m.ToolCallID] {
+				return fail("tool result %q does not answer an earlier tool call", m.ToolCallID)
+			}
+			delete(pending, m.ToolCallID)
+		}
+	}
+	return nil
+}
+
+// ValidatingProvider rejects malformed histories with ValidateMessages
+// before they reach the provider it wraps.
+type ValidatingProvider struct {
+	providerWrapper
+}
+
+// WrapWithValidation validates the messages of every call to p. The result
+// implements StreamingProvider only if p does.
+func WrapWithValidation(p LLMProvider) LLMProvider {
+	return decorate(&ValidatingProvider{providerWrapper: providerWrapper{p}}, p)
+}
+
+func (v *ValidatingProvider) Chat(
+	ctx context.Context,
+	messages []Message,
+	tools []ToolDefinition,
+	model string,
+	options map[string]any,
+) (*LLMResponse, error) {
+	if err := ValidateMessages(messages); err != nil {
+		return nil, err
+	}
+	return v.LLMProvider.Chat(ctx, messages, tools, model, options)
+}
+
+func (v *ValidatingProvider) chatStream(
+	inner StreamingProvider,
+	ctx context.Context,
+	messages []Message,
+	tools []ToolDefinition,
+	model string,
+	options map[string]any,
+	onChunk func(accumulated string),
+) (*LLMResponse, error) {
+	if err := ValidateMessages(messages); err != nil {
+		return nil, err
+	}
+	return inner.ChatStream(ctx, messages, tools, model, options, onChunk)
+}
@@ -0,0 +1,93 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestValidateMessages(t *testing.T) {
+	toolCall := ToolCall{ID: "c1", Function: &FunctionCall{Name: "search", Arguments: "{}"}}
+
+	tests := []struct {
+		name      string
+		messages  []Message
+		wantIndex int // -1 for valid
+	}{
+		{"valid conversation", []Message{
+			{Role: "system", Content: "sys"},
+			{Role: "user", Content: "hi"},
+			{Role: "assistant", ToolCalls: []ToolCall{toolCall}},
+			{Role: "tool", ToolCallID: "c1", Content: "result"},
+			{Role: "assistant", Content: "done"},
+		}, -1},
+		{"empty tool output", []Message{
+			{Role: "user", Content: "hi"},
+			{Role: "assistant", ToolCalls: []ToolCall{toolCall}},
+			{Role: "tool", ToolCallID: "c1"},
+		}, -1},
+		{"media-only user message", []Message{{Role: "user", Media: []string{"media://1"}}}, -1},
+		{"empty history", nil, -1},
+		{"empty role", []Message{{Role: "user", Content: "hi"}, {Content: "x"}}, 1},
+		{"unknown role", []Message{{Role: "moderator", Content: "x"}}, 0},
+		{"two system messages", []Message{{Role: "system", Content: "a"}, {Role: "system", Content: "b"}}, 1},
+		{"system after user", []Message{{Role: "user", Content: "hi"}, {Role: "system", Content: "sys"}}, 1},
+		{"empty content", []Message{{Role: "user", Content: "  "}}, 0},
+		{"empty assistant without tool calls", []Message{{Role: "user", Content: "hi"}, {Role: "assistant"}}, 1},
+		{"tool calls on a user message", []Message{{Role: "user", Content: "hi", ToolCalls: []ToolCall{toolCall}}}, 0},
+		{"tool call without ID", []Message{
+			{Role: "user", Content: "hi"},
+			{Role: "assistant", ToolCalls: []ToolCall{{Function: &FunctionCall{Name: "search"}}}},
+		}, 1},
+		{"tool result without ID", []Message{
+			{Role: "assistant", ToolCalls: []ToolCall{toolCall}},
+			{Role: "tool", Content: "result"},
+		}, 1},
+		{"orphaned tool result", []Message{
+			{Role: "user", Content: "hi"},
+			{Role: "tool", ToolCallID: "c1", Content: "result"},
+		}, 1},
+		{"tool call answered twice", []Message{
+			{Role: "assistant", ToolCalls: []ToolCall{toolCall}},
+			{Role: "tool", ToolCallID: "c1", Content: "one"},
+			{Role: "tool", ToolCallID: "c1", Content: "two"},
+		}, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateMessages(tt.messages)
+			if tt.wantIndex < 0 {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			var msgErr *MessageError
+			if !errors.As(err, &msgErr) {
+				t.Fatalf("error = %v, want a *MessageError", err)
+			}
+			if msgErr.Index != tt.wantIndex {
+				t.Errorf("error at message %d, want %d: %v", msgErr.Index, tt.wantIndex, err)
+			}
+		})
+	}
+}
+
+func TestValidatingProvider_RejectsBeforeDispatch(t *testing.T) {
+	inner := &flakyProvider{}
+	p := WrapWithValidation(inner)
+
+	_, err := p.Chat(context.Background(), []Message{{Role: "user", Content: "hi"}, {Content: "x"}}, nil, "m", nil)
+	var msgErr *MessageError
+	if !errors.As(err, &msgErr) {
+		t.Fatalf("Chat error = %v, want a *MessageError", err)
+	}
+	if inner.calls != 0 {
+		t.Errorf("malformed history reached the provider %d times", inner.calls)
+	}
+
+	resp, err := p.Chat(context.Background(), []Message{{Role: "user", Content: "hi"}}, nil, "m", nil)
+	if err != nil || resp.Content != "ok" || inner.calls != 1 {
+		t.Errorf("valid history: resp = %+v, err = %v, calls = %d", resp, err, inner.calls)
+	}
+}
@@ -0,0 +1,73 @@
+package providers
+
+import "context"
+
+// providerWrapper is embedded by the decorators in this package. Embedding
+// the LLMProvider interface alone would hide the wrapped provider's optional
+// capabilities, so they are forwarded here.
+type providerWrapper struct {
+	LLMProvider
+}
+
+// Close closes the wrapped provider if it is stateful.
+func (w providerWrapper) Close() {
+	if sp, ok := w.LLMProvider.(StatefulProvider); ok {
+		sp.Close()
+	}
+}
+
+// SupportsThinking reports the wrapped provider's thinking support.
+func (w providerWrapper) SupportsThinking() bool {
+	tc, ok := w.LLMProvider.(ThinkingCapable)
+	return ok && tc.SupportsThinking()
+}
+
+// SupportsNativeSearch reports the wrapped provider's native search support.
+func (w providerWrapper) SupportsNativeSearch() bool {
+	ns, ok := w.LLMProvider.(NativeSearchCapable)
+	return ok && ns.SupportsNativeSearch()
+}
+
+// streamDecorator is a decorator that also knows how to wrap a streaming
+// call to the provider it decorates.
+type streamDecorator interface {
+	LLMProvider
+	chatStream(
+		inner StreamingProvider,
+		ctx context.Context,
+		messages []Message,
+		tools []ToolDefinition,
+		model string,
+		options map[string]any,
+		onChunk func(accumulated string),
+	) (*LLMResponse, error)
+}
+
+// streamingWrapper gives a decorator a ChatStream method when, and only
+// when, the provider it wraps can stream, so callers can keep detecting
+// streaming support with a type assertion.
+type streamingWrapper struct {
+	providerWrapper
+	decorator streamDecorator
+	inner     StreamingProvider
+}
+
+func (s *streamingWrapper) ChatStream(
+	ctx context.Context,
+	messages []Message,
+	tools []ToolDefinition,
+	model string,
+	options map[string]any,
+	onChunk func(accumulated string),
+) (*LLMResponse, error) {
+	return s.decorator.chatStream(s.inner, ctx, messages, tools, model, options, onChunk)
+}
+
+// decorate returns d, adding ChatStream if inner implements
+// StreamingProvider.
+func decorate(d streamDecorator, inner LLMProvider) LLMProvider {
+	if sp, ok := inner.(StreamingProvider); ok {
+		return &streamingWrapper{providerWrapper: providerWrapper{d}, decorator: d, inner: sp}
+	}
+	return d
+}
@@ -0,0 +1,123 @@
+package safety
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Approval states.
+const (
+	ApprovalPending  = "pending"
+	ApprovalApproved = "approved"
+	ApprovalDenied   = "denied"
+)
+
+// ErrApprovalNotFound is returned when resolving an approval that does not
+// exist or was already resolved.
+var ErrApprovalNotFound = errors.New("approval not found")
+
+// Approval is a response held back until a parent reviews it.
+type Approval struct {
+	ID      string
+	UserID  string // the user the response was written for
+	Content string
+	Reason  string
+	// Channel and ChatID say where to deliver the response once approved.
+	// They are empty for approvals queued with Enqueue.
+	Channel    string
+	ChatID     string
+	Status     string
+	CreatedAt  time.Time
+	ResolvedAt time.Time
+}
+
+// ApprovalStore queues flagged responses for parent review. Resolved
+// approvals are dropped from the store.
+type ApprovalStore struct {
+	isReviewer func(parentID string) bool
+
+	mu    sync.Mutex
+	seq   int
+	items map[string]*Approval
+}
+
+// NewApprovalStore returns an empty store. isReviewer decides who may see the
+// queue, typically Filter.IsExempt.
+func NewApprovalStore(isReviewer func(parentID string) bool) *ApprovalStore {
+	return &ApprovalStore{
+		isReviewer: isReviewer,
+		items:      make(map[string]*Approval),
+	}
+}
+
+// Enqueue holds content written for userID until it is resolved.
+func (s *ApprovalStore) Enqueue(content, userID, reason string) Approval {
+	return s.EnqueueFor("", "", content, userID, reason)
+}
+
+// EnqueueFor is Enqueue for a response that should be delivered to chatID on
+// channel once approved.
+func (s *ApprovalStore) EnqueueFor(channel, chatID, content, userID, reason string) Approval {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.seq++
+	a := &Approval{
+		ID:        strconv.Itoa(s.seq),
+		UserID:    userID,
+		Content:   content,
+		Reason:    reason,
+		Channel:   channel,
+		ChatID:    chatID,
+		Status:    ApprovalPending,
+		CreatedAt: time.Now(),
+	}
+	s.items[a.ID] = a
+	return *a
+}
+
+// ListPending returns the approvals waiting for review, oldest first. It
+// returns nil unless parentID is a reviewer.
+func (s *ApprovalStore) ListPending(parentID string) []Approval {
+	if s.isReviewer == nil || !s.isReviewer(parentID) {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pending := make([]Approval, 0, len(s.items))
+	for _, a := range s.items {
+		pending = append(pending, *a)
+	}
+	// IDs are assigned in enqueue order.
+	sort.Slice(pending, func(i, j int) bool {
+		a, _ := strconv.Atoi(pending[i].ID)
+		b, _ := strconv.Atoi(pending[j].ID)
+		return a < b
+	})
+	return pending
+}
+
+// Resolve approves or denies the approval with the given ID and removes it
+// from the queue. The returned copy carries the final status.
+func (s *ApprovalStore) Resolve(id string, approved bool) (Approval, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	a, ok := s.items[id]
+	if !ok {
+		return Approval{}, ErrApprovalNotFound
+	}
+	delete(s.items, id)
+
+	a.Status = ApprovalDenied
+	if approved {
+		a.Status = ApprovalApproved
+	}
+	a.ResolvedAt = time.Now()
+	return *a, nil
+}
@@ -0,0 +1,72 @@
+package safety
+
+import (
+	"errors"
+	"testing"
+)
+
+func newTestApprovalStore() *ApprovalStore {
+	f := NewFilter(LevelHigh, 0)
+	f.SetExemptUsers([]string{"parent"})
+	return NewApprovalStore(f.IsExempt)
+}
+
+func TestApprovalStore_EnqueueAndList(t *testing.T) {
+	s := newTestApprovalStore()
+	first := s.Enqueue("about grief", "kid", "sensitive topic")
+	second := s.EnqueueFor("telegram", "42", "about politics", "kid", "sensitive topic")
+
+	if first.ID == second.ID {
+		t.Fatalf("approvals share ID %q", first.ID)
+	}
+	if first.Status != ApprovalPending || first.CreatedAt.IsZero() {
+		t.Errorf("enqueued approval = %+v, want pending with a creation time", first)
+	}
+
+	pending := s.ListPending("parent")
+	if len(pending) != 2 || pending[0].ID != first.ID || pending[1].ID != second.ID {
+		t.Fatalf("ListPending = %+v, want both approvals oldest first", pending)
+	}
+	if pending[1].Channel != "telegram" || pending[1].ChatID != "42" || pending[1].UserID != "kid" {
+		t.Errorf("second approval = %+v", pending[1])
+	}
+
+	if got := s.ListPending("kid"); got != nil {
+		t.Errorf("ListPending for a non-parent = %+v, want nil", got)
+	}
+	if got := s.ListPending(""); got != nil {
+		t.Errorf("ListPending for an empty ID = %+v, want nil", got)
+	}
+}
+
+func TestApprovalStore_Resolve(t *testing.T) {
+	tests := []struct {
+		name     string
+		approved bool
+		want     string
+	}{
+		{"approve", true, ApprovalApproved},
+		{"deny", false, ApprovalDenied},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestApprovalStore()
+			a := s.Enqueue("about death", "kid", "sensitive topic")
+
+			resolved, err := s.Resolve(a.ID, tt.approved)
+			if err != nil {
+				t.Fatalf("Resolve: %v", err)
+			}
+			if resolved.Status != tt.want || resolved.Content != "about death" || resolved.ResolvedAt.IsZero() {
+				t.Errorf("resolved = %+v, want status %s with content and resolution time", resolved, tt.want)
+			}
+			if pending := s.ListPending("parent"); len(pending) != 0 {
+				t.Errorf("resolved approval still pending: %+v", pending)
+			}
+
+			if _, err := s.Resolve(a.ID, tt.approved); !errors.Is(err, ErrApprovalNotFound) {
+				t.Errorf("second Resolve error = %v, want ErrApprovalNotFound", err)
+			}
+		})
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"strings"
 	"time"
+	"unicode"
+	"unicode/utf8"
 )
 
 const (
@@ -24,18 +26,50 @@ var mediumBlockKeywords = []string{
 	"hack", "steal", "fraud", "scam",
 }
 
+// Default age thresholds: users under DefaultYoungUnder are young children,
+// users under DefaultTeenUnder are teenagers.
+const (
+	DefaultYoungUnder = 13
+	DefaultTeenUnder  = 18
+)
+
 type Filter struct {
 	level     string
 	birthYear int
+	exempt    map[string]struct{}
+
+	quietHours *QuietHours
+
+	youngBlockedMessage   string
+	generalBlockedMessage string
+
+	youngUnder int
+	teenUnder  int
+
+	language string
 }
 
 func NewFilter(level string, birthYear int) *Filter {
+	level = strings.ToLower(strings.TrimSpace(level))
 	if level == "" {
 		level = LevelOff
 	}
 	return &Filter{
-		level:     level,
-		birthYear: birthYear,
+		level:      level,
+		birthYear:  birthYear,
+		youngUnder: DefaultYoungUnder,
+		teenUnder:  DefaultTeenUnder,
+	}
+}
+
+// SetAgeThresholds overrides the ages below which a user counts as young and
+// as a teen. Non-positive values keep the current threshold.
+func (f *Filter) SetAgeThresholds(youngUnder, teenUnder int) {
+	if youngUnder > 0 {
+		f.youngUnder = youngUnder
+	}
+	if teenUnder > 0 {
+		f.teenUnder = teenUnder
 	}
 }
 
@@ -47,12 +81,33 @@ func (f *Filter) BirthYear() int {
 	return f.birthYear
 }
 
+// SetExemptUsers replaces the set of trusted user IDs (e.g. parents) whose
+// conversations bypass the filter entirely.
+func (f *Filter) SetExemptUsers(userIDs []string) {
+	f.exempt = make(map[string]struct{}, len(userIDs))
+	for _, id := range userIDs {
+		id = strings.TrimSpace(id)
+		if id != "" {
+			f.exempt[id] = struct{}{}
+		}
+	}
+}
+
+// IsExempt reports whether userID is a trusted user that bypasses the filter.
+func (f *Filter) IsExempt(userID string) bool {
+	if userID == "" {
+		return false
+	}
+	_, ok := f.exempt[userID]
+	return ok
+}
+
 func (f *Filter) isYoungUser() bool {
 	if f.birthYear == 0 {
 		return false
 	}
 	age := time.Now().Year() - f.birthYear
-	return age < 13
+	return age < f.youngUnder
 }
 
 func (f *Filter) isTeenUser() bool {
@@ -60,53 +115,45 @@ func (f *Filter) isTeenUser() bool {
 		return false
 	}
 	age := time.Now().Year() - f.birthYear
-	return age >= 13 && age < 18
+	return age >= f.youngUnder && age < f.teenUnder
 }
 
 func (f *Filter) shouldBlock() bool {
 	return f.level != LevelOff
 }
 
-func (f *Filter) CheckContent(content string) (blocked bool, reason string) {
-	if !f.shouldBlock() {
+func (f *Filter) CheckContent(userID, content string) (blocked bool, reason string) {
+	if !f.shouldBlock() || f.IsExempt(userID) {
 		return false, ""
 	}
 
 	contentLower := strings.ToLower(content)
 
-	if f.level == LevelLow {
-		for _, kw := range adultKeywords {
-			if strings.Contains(contentLower, kw) {
-				return true, "content blocked by safety filter (low)"
-			}
+	for _, set := range f.keywordSets(content) {
+		if f.level == LevelLow && containsAny(contentLower, set.adult) {
+			return true, "content blocked by safety filter (low)"
 		}
-	}
 
-	if f.level == LevelMedium || f.level == LevelHigh {
-		for _, kw := range adultKeywords {
-			if strings.Contains(contentLower, kw) {
+		if f.level == LevelMedium || f.level == LevelHigh {
+			if containsAny(contentLower, set.adult) || containsAny(contentLower, set.mediumBlock) {
 				return true, "content blocked by safety filter (medium/high)"
 			}
 		}
-		for _, kw := range mediumBlockKeywords {
-			if strings.Contains(contentLower, kw) {
-				return true, "content blocked by safety filter (medium/high)"
-			}
-		}
-	}
 
-	if f.level == LevelHigh && f.isYoungUser() {
-		teenOnlyTopics := []string{"dating", "romance", "sex", "politics", "religion"}
-		for _, kw := range teenOnlyTopics {
-			if strings.Contains(contentLower, kw) {
-				return true, "content requires parent approval (high safety for young user)"
-			}
+		if f.level == LevelHigh && f.isYoungUser() && containsAny(contentLower, set.youngTopics) {
+			return true, "content requires parent approval (high safety for young user)"
 		}
 	}
 
 	return false, ""
 }
 
+// HasExemptUsers reports whether any trusted user is configured, i.e. whether
+// anyone can review held responses.
+func (f *Filter) HasExemptUsers() bool {
+	return len(f.exempt) > 0
+}
+
 func (f *Filter) RequiresApproval() bool {
 	return f.level == LevelHigh && f.isYoungUser()
 }
@@ -122,19 +169,39 @@ type CheckResult struct {
 	BlockedMessage string // message to show user instead of blocked content
 }
 
-func (f *Filter) CheckResponse(response string) *CheckResult {
+func (f *Filter) CheckResponse(userID, response string) *CheckResult {
+	return f.CheckResponseAt(time.Now(), userID, response)
+}
+
+// CheckResponseAt is CheckResponse evaluated at the given time, so quiet
+// hours can be enforced deterministically.
+func (f *Filter) CheckResponseAt(now time.Time, userID, response string) *CheckResult {
 	result := &CheckResult{
 		Original: response,
 		Safe:     true,
 	}
 
+	// Trusted users are never filtered
+	if f.IsExempt(userID) {
+		return result
+	}
+
+	// Quiet hours apply to young users regardless of the filter level
+	if f.isYoungUser() && f.InQuietHours(now) {
+		result.Safe = false
+		result.Blocked = true
+		result.Reason = "quiet hours"
+		result.BlockedMessage = quietHoursMessage
+		return result
+	}
+
 	// If safety is off, pass everything through
 	if f.level == LevelOff {
 		return result
 	}
 
 	// First: keyword-based quick check
-	blocked, reason := f.CheckContent(response)
+	blocked, reason := f.CheckContent(userID, response)
 	if blocked {
 		result.Safe = false
 		result.Blocked = true
@@ -148,7 +215,7 @@ func (f *Filter) CheckResponse(response string) *CheckResult {
 		sensitiveTopics := []string{"dating", "romance", "sex", "politics", "religion", "death", "grief"}
 		contentLower := strings.ToLower(response)
 		for _, topic := range sensitiveTopics {
-			if strings.Contains(contentLower, topic) {
+			if containsWord(contentLower, topic) {
 				result.Safe = true // Still safe but flag for review
 				result.NeedsApproval = true
 				result.Reason = "Sensitive topic for young user - parent review recommended"
@@ -177,18 +244,69 @@ func (f *Filter) needsLLMCheck(response string) bool {
 		"you should ask", "talk to your parents", "consult an adult",
 	}
 	for _, phrase := range ambiguousPhrases {
-		if strings.Contains(contentLower, phrase) {
+		if containsWord(contentLower, phrase) {
 			return true
 		}
 	}
 	return false
 }
 
+const (
+	defaultYoungBlockedMessage   = "I can't share that information with you. Ask a parent or guardian if you'd like to know more about this topic."
+	defaultGeneralBlockedMessage = "This content has been filtered for safety. Please try a different topic."
+)
+
+// SetBlockedMessages overrides the message shown in place of blocked content
+// for young users and for everyone else. Templates may reference {reason}.
+// An empty template keeps the built-in default.
+func (f *Filter) SetBlockedMessages(young, general string) {
+	f.youngBlockedMessage = young
+	f.generalBlockedMessage = general
+}
+
+// BlockedMessage returns the message shown in place of content blocked for
+// the given reason.
+func (f *Filter) BlockedMessage(reason string) string {
+	return f.getBlockedMessage(reason)
+}
+
 func (f *Filter) getBlockedMessage(reason string) string {
+	tmpl := f.generalBlockedMessage
+	if tmpl == "" {
+		tmpl = defaultGeneralBlockedMessage
+	}
 	if f.isYoungUser() {
-		return "I can't share that information with you. Ask a parent or guardian if you'd like to know more about this topic."
+		tmpl = f.youngBlockedMessage
+		if tmpl == "" {
+			tmpl = defaultYoungBlockedMessage
+		}
+	}
+	return strings.ReplaceAll(tmpl, "{reason}", reason)
+}
+
+// containsWord reports whether kw occurs in content as a whole word or
+// phrase, optionally followed by a plural "s", so "kill" matches "kills" but
+// not "skills". Both arguments are expected to be lower case.
+func containsWord(content, kw string) bool {
+	for i := 0; i < len(content); {
+		j := strings.Index(content[i:], kw)
+		if j < 0 {
+			return false
+		}
+		start, end := i+j, i+j+len(kw)
+		before, _ := utf8.DecodeLastRuneInString(content[:start])
+		rest := strings.TrimPrefix(content[end:], "s")
+		after, _ := utf8.DecodeRuneInString(rest)
+		if (start == 0 || !isWordRune(before)) && (rest == "" || !isWordRune(after)) {
+			return true
+		}
+		i = start + 1
 	}
-	return "This content has been filtered for safety. Please try a different topic."
+	return false
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
 }
 
 func (f *Filter) GetSystemPrompt() string {
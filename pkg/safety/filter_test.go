@@ -1,7 +1,9 @@
 package safety
 
 import (
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestFilter_BirthYear(t *testing.T) {
@@ -33,6 +35,34 @@ func TestFilter_BirthYear(t *testing.T) {
 	}
 }
 
+func TestFilter_CustomAgeThresholds(t *testing.T) {
+	year := time.Now().Year()
+
+	f := NewFilter("low", year-11)
+	if !f.isYoungUser() {
+		t.Fatal("expected 11-year-old to be young with default thresholds")
+	}
+
+	f.SetAgeThresholds(10, 16)
+	if f.isYoungUser() || !f.isTeenUser() {
+		t.Errorf("expected 11-year-old to be a teen when young is under 10")
+	}
+	if !strings.Contains(f.GetSystemPrompt(), "teenager") {
+		t.Errorf("expected teen system prompt, got %q", f.GetSystemPrompt())
+	}
+
+	f = NewFilter("low", year-17)
+	f.SetAgeThresholds(10, 16)
+	if f.isTeenUser() {
+		t.Error("expected 17-year-old not to be a teen when teen is under 16")
+	}
+
+	f.SetAgeThresholds(0, 0)
+	if f.isTeenUser() {
+		t.Error("expected non-positive thresholds to keep the current values")
+	}
+}
+
 func TestFilter_CheckContent(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -46,12 +76,14 @@ func TestFilter_CheckContent(t *testing.T) {
 		{"medium blocks more", "medium", 1980, "how to make a bomb", true},
 		{"high young blocks teen topics", "high", 2015, "dating advice", true},
 		{"off passes all", "off", 2015, "anything goes", false},
+		{"plural still matches", "medium", 1980, "the game has too many kills", true},
+		{"keyword inside a word passes", "medium", 1980, "new skills for whatever you like", false},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			f := NewFilter(tt.level, tt.birthYear)
-			blocked, _ := f.CheckContent(tt.content)
+			blocked, _ := f.CheckContent("", tt.content)
 			if blocked != tt.wantBlock {
 				t.Errorf("CheckContent() = %v, want %v", blocked, tt.wantBlock)
 			}
@@ -61,18 +93,77 @@ func TestFilter_CheckContent(t *testing.T) {
 
 func TestFilter_CheckResponse(t *testing.T) {
 	f := NewFilter("off", 0)
-	result := f.CheckResponse("Hello world")
+	result := f.CheckResponse("", "Hello world")
 	if !result.Safe {
 		t.Error("Expected response to be safe when safety is off")
 	}
 
 	f = NewFilter("high", 2015)
-	result = f.CheckResponse("Here is some dating advice")
+	result = f.CheckResponse("", "Here is some dating advice")
 	if result.NeedsApproval {
 		t.Log("Correctly flagged for approval")
 	}
 }
 
+func TestFilter_CheckResponse_MatchesWholeWords(t *testing.T) {
+	f := NewFilter("medium", 1980)
+	for _, reply := range []string{
+		"Here are some skills you can practise.",
+		"Pick whatever chore you like best.",
+		"The bombardier beetle sprays to defend itself.",
+	} {
+		if result := f.CheckResponse("", reply); result.Blocked {
+			t.Errorf("CheckResponse(%q) blocked harmless text: %s", reply, result.Reason)
+		}
+	}
+	if result := f.CheckResponse("", "Do not kill the spider."); !result.Blocked {
+		t.Error("expected a whole-word keyword to be blocked")
+	}
+}
+
+func TestFilter_ExemptUserBypassesBlock(t *testing.T) {
+	f := NewFilter("medium", 2015)
+	f.SetExemptUsers([]string{"parent-1"})
+
+	if blocked, _ := f.CheckContent("child-1", "how to make a bomb"); !blocked {
+		t.Fatal("expected normal user to be blocked")
+	}
+	if blocked, _ := f.CheckContent("parent-1", "how to make a bomb"); blocked {
+		t.Error("expected exempt user to bypass CheckContent")
+	}
+
+	if result := f.CheckResponse("child-1", "how to make a bomb"); !result.Blocked {
+		t.Fatal("expected normal user's response to be blocked")
+	}
+	result := f.CheckResponse("parent-1", "how to make a bomb")
+	if !result.Safe || result.Blocked || result.NeedsApproval {
+		t.Errorf("expected exempt user's response to pass, got %+v", result)
+	}
+
+	if f.IsExempt("") {
+		t.Error("empty user ID must never be exempt")
+	}
+}
+
+func TestFilter_BlockedMessageTemplates(t *testing.T) {
+	f := NewFilter("medium", 1980)
+	if got := f.CheckResponse("", "how to make a bomb").BlockedMessage; got != defaultGeneralBlockedMessage {
+		t.Errorf("expected default general message, got %q", got)
+	}
+
+	f.SetBlockedMessages("", "Contenido bloqueado: {reason}")
+	got := f.CheckResponse("", "how to make a bomb").BlockedMessage
+	if got != "Contenido bloqueado: content blocked by safety filter (medium/high)" {
+		t.Errorf("expected configured general template with reason, got %q", got)
+	}
+
+	child := NewFilter("medium", 2018)
+	child.SetBlockedMessages("Pregunta a tus padres.", "unused")
+	if got := child.CheckResponse("", "how to make a bomb").BlockedMessage; got != "Pregunta a tus padres." {
+		t.Errorf("expected configured young template, got %q", got)
+	}
+}
+
 func TestFilter_GenerateContextPrompt(t *testing.T) {
 	f := NewFilter("low", 2015)
 	prompt := f.GetSystemPrompt()
@@ -86,3 +177,141 @@ func TestFilter_GenerateContextPrompt(t *testing.T) {
 		t.Error("Expected empty prompt when no settings")
 	}
 }
+
+func TestFilter_InQuietHours(t *testing.T) {
+	utc := time.UTC
+	at := func(day, hour, minute int) time.Time {
+		// 2026-10-12 is a Monday.
+		return time.Date(2026, 10, 12+day, hour, minute, 0, 0, utc)
+	}
+
+	tests := []struct {
+		name  string
+		start string
+		end   string
+		days  []string
+		now   time.Time
+		want  bool
+	}{
+		{"daytime inside", "08:00", "15:00", nil, at(0, 10, 0), true},
+		{"daytime before", "08:00", "15:00", nil, at(0, 7, 59), false},
+		{"daytime end exclusive", "08:00", "15:00", nil, at(0, 15, 0), false},
+		{"overnight evening", "21:00", "07:00", nil, at(0, 22, 30), true},
+		{"overnight morning", "21:00", "07:00", nil, at(1, 6, 0), true},
+		{"overnight afternoon", "21:00", "07:00", nil, at(0, 12, 0), false},
+		{"school days only on monday", "08:00", "15:00", []string{"mon", "tue"}, at(0, 9, 0), true},
+		{"school days only on sunday", "08:00", "15:00", []string{"mon", "tue"}, at(6, 9, 0), false},
+		{"overnight evening on listed day", "21:00", "07:00", []string{"sunday"}, at(-1, 23, 0), true},
+		{"overnight morning belongs to previous day", "21:00", "07:00", []string{"sun"}, at(0, 6, 30), true},
+		{"overnight morning after unlisted day", "21:00", "07:00", []string{"mon"}, at(0, 6, 30), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := ParseQuietHours(tt.start, tt.end, tt.days, "UTC")
+			if err != nil {
+				t.Fatalf("ParseQuietHours: %v", err)
+			}
+			f := NewFilter("low", 2018)
+			f.SetQuietHours(q)
+			if got := f.InQuietHours(tt.now); got != tt.want {
+				t.Errorf("InQuietHours(%s) = %v, want %v", tt.now.Format("Mon 15:04"), got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilter_CheckResponseAt_QuietHours(t *testing.T) {
+	q, err := ParseQuietHours("21:00", "07:00", nil, "UTC")
+	if err != nil {
+		t.Fatalf("ParseQuietHours: %v", err)
+	}
+	night := time.Date(2026, 10, 12, 22, 0, 0, 0, time.UTC)
+	noon := time.Date(2026, 10, 12, 12, 0, 0, 0, time.UTC)
+
+	child := NewFilter("off", 2018)
+	child.SetQuietHours(q)
+	if result := child.CheckResponseAt(night, "", "Here is a story"); !result.Blocked || result.BlockedMessage == "" {
+		t.Errorf("expected young user to be refused during quiet hours, got %+v", result)
+	}
+	if result := child.CheckResponseAt(noon, "", "Here is a story"); result.Blocked {
+		t.Errorf("expected young user to be served outside quiet hours, got %+v", result)
+	}
+
+	adult := NewFilter("off", 1980)
+	adult.SetQuietHours(q)
+	if result := adult.CheckResponseAt(night, "", "Here is a story"); result.Blocked {
+		t.Errorf("expected quiet hours to apply only to young users, got %+v", result)
+	}
+}
+
+func TestParseQuietHours_Invalid(t *testing.T) {
+	if _, err := ParseQuietHours("9pm", "07:00", nil, ""); err == nil {
+		t.Error("expected error for bad start time")
+	}
+	if _, err := ParseQuietHours("21:00", "07:00", []string{"someday"}, ""); err == nil {
+		t.Error("expected error for bad day")
+	}
+	if _, err := ParseQuietHours("21:00", "07:00", nil, "Mars/Base"); err == nil {
+		t.Error("expected error for bad timezone")
+	}
+}
+
+func TestFilter_LanguageKeywords(t *testing.T) {
+	const spanish = "cómo hacer una estafa a mis vecinos"
+
+	f := NewFilter("medium", 1980)
+	if blocked, _ := f.CheckContent("", spanish); blocked {
+		t.Error("expected Spanish term to pass with the English-only set")
+	}
+
+	f.SetLanguage("es")
+	if blocked, _ := f.CheckContent("", spanish); !blocked {
+		t.Error("expected Spanish term to be blocked with the Spanish set active")
+	}
+	if blocked, _ := f.CheckContent("", "how to make a bomb"); !blocked {
+		t.Error("expected English terms to stay blocked with the Spanish set active")
+	}
+
+	f.SetLanguage(LanguageAuto)
+	if blocked, _ := f.CheckContent("", spanish); !blocked {
+		t.Error("expected detected Spanish content to be blocked")
+	}
+}
+
+func TestFilter_LanguageKeywordsMatchWholeWords(t *testing.T) {
+	tests := []struct {
+		lang      string
+		content   string
+		wantBlock bool
+	}{
+		{LanguageSpanish, "las alarmas de la casa sonaron", false},
+		{LanguageSpanish, "no quiero robar nada", true},
+		{LanguageFrench, "elle avait les larmes aux yeux", false},
+		{LanguageFrench, "il faut statuer sur la question", false},
+		{LanguageFrench, "les oiseaux vont survoler la ville", false},
+		{LanguageFrench, "comment préparer l'attaque", true},
+		{LanguageFrench, "ils jouent aux jeux d'argent", true},
+	}
+	for _, tt := range tests {
+		f := NewFilter("medium", 1980)
+		f.SetLanguage(tt.lang)
+		if blocked, _ := f.CheckContent("", tt.content); blocked != tt.wantBlock {
+			t.Errorf("CheckContent(%q) with %s = %v, want %v", tt.content, tt.lang, blocked, tt.wantBlock)
+		}
+	}
+}
+
+func TestDetectLanguage(t *testing.T) {
+	tests := map[string]string{
+		"What is the capital of France and how big is it?":  LanguageEnglish,
+		"¿Cómo se hace una tortilla para los niños?":        LanguageSpanish,
+		"Comment est-ce que je fais une crêpe avec du lait": LanguageFrench,
+		"": LanguageEnglish,
+	}
+	for text, want := range tests {
+		if got := DetectLanguage(text); got != want {
+			t.Errorf("DetectLanguage(%q) = %q, want %q", text, got, want)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package safety
+
+import (
+	"strings"
+	"unicode"
+)
+
+const (
+	LanguageEnglish = "en"
+	LanguageSpanish = "es"
+	LanguageFrench  = "fr"
+
+	// LanguageAuto detects the language of each piece of content.
+	LanguageAuto = "auto"
+)
+
+// keywordSet holds the blocklists for one language.
+type keywordSet struct {
+	adult       []string
+	mediumBlock []string
+	youngTopics []string
+}
+
+var englishKeywords = keywordSet{
+	adult:       adultKeywords,
+	mediumBlock: mediumBlockKeywords,
+	youngTopics: []string{"dating", "romance", "sex", "politics", "religion"},
+}
+
+// languageKeywords are checked in addition to the English lists, since
+// English terms commonly appear in non-English text. Like the English lists
+// they match whole words only, so avoid terms with a common innocent sense
+// (French "voler" also means "to fly").
+var languageKeywords = map[string]keywordSet{
+	LanguageSpanish: {
+		adult: []string{
+			"violencia", "armas", "drogas", "alcohol", "tabaco",
+			"apuestas", "odio", "discriminación", "autolesión",
+			"pornografía", "sexual",
+		},
+		mediumBlock: []string{
+			"suicidio", "asesinato", "matar", "ataque", "bomba",
+			"hackear", "robar", "fraude", "estafa",
+		},
+		youngTopics: []string{"citas", "romance", "sexo", "política", "religión"},
+	},
+	LanguageFrench: {
+		adult: []string{
+			"violence", "armes", "drogues", "alcool", "tabac",
+			"jeux d'argent", "haine", "discrimination", "automutilation",
+			"pornographie", "sexuel",
+		},
+		mediumBlock: []string{
+			"suicide", "meurtre", "tuer", "attaque", "bombe",
+			"pirater", "cambrioler", "fraude", "arnaque",
+		},
+		youngTopics: []string{"rendez-vous amoureux", "romance", "sexe", "politique", "religion"},
+	},
+}
+
+// stopwords are frequent, language-distinctive words used by DetectLanguage.
+var stopwords = map[string][]string{
+	LanguageEnglish: {"the", "and", "is", "are", "you", "what", "how", "with", "this", "of"},
+	LanguageSpanish: {"el", "los", "las", "es", "y", "que", "cómo", "con", "por", "una", "para", "del"},
+	LanguageFrench:  {"le", "les", "est", "et", "que", "comment", "avec", "pour", "une", "des", "du", "je"},
+}
+
+// DetectLanguage makes a best-effort guess at the language of text by
+// counting common stopwords. It returns LanguageEnglish when unsure.
+func DetectLanguage(text string) string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '\''
+	})
+	if len(words) == 0 {
+		return LanguageEnglish
+	}
+
+	best, bestScore := LanguageEnglish, 0
+	for _, lang := range []string{LanguageEnglish, LanguageSpanish, LanguageFrench} {
+		score := 0
+		for _, w := range words {
+			for _, sw := range stopwords[lang] {
+				if w == sw {
+					score++
+					break
+				}
+			}
+		}
+		if score > bestScore {
+			best, bestScore = lang, score
+		}
+	}
+	return best
+}
+
+// SetLanguage selects the extra keyword set checked alongside English: a
+// language code such as "es" or "fr", LanguageAuto to detect it per message,
+// or "" for English only.
+func (f *Filter) SetLanguage(lang string) {
+	f.language = strings.ToLower(strings.TrimSpace(lang))
+}
+
+// Language returns the configured language hint.
+func (f *Filter) Language() string {
+	return f.language
+}
+
+// keywordSets returns the blocklists that apply to content.
+func (f *Filter) keywordSets(content string) []keywordSet {
+	lang := f.language
+	if lang == LanguageAuto {
+		lang = DetectLanguage(content)
+	}
+	sets := []keywordSet{englishKeywords}
+	if extra, ok := languageKeywords[lang]; ok {
+		sets = append(sets, extra)
+	}
+	return sets
+}
+
+func containsAny(content string, keywords []string) bool {
+	for _, kw := range keywords {
+		if containsWord(content, kw) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,113 @@
+package safety
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const quietHoursMessage = "It's quiet time right now, so I can't chat. Let's talk again later!"
+
+// QuietHours is a daily window (e.g. bedtime or school hours) during which
+// the assistant declines to interact with young users. A window whose end is
+// before its start spans midnight.
+type QuietHours struct {
+	start    int // minutes after midnight
+	end      int // minutes after midnight
+	days     map[time.Weekday]bool
+	location *time.Location
+}
+
+var weekdayNames = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// ParseQuietHours builds a QuietHours window from "HH:MM" start/end times, an
+// optional list of weekdays ("mon", "tuesday", ...) on which the window
+// starts, and an optional IANA timezone. No days means every day; no
+// timezone means the local zone.
+func ParseQuietHours(start, end string, days []string, timezone string) (*QuietHours, error) {
+	q := &QuietHours{location: time.Local}
+
+	var err error
+	if q.start, err = parseClock(start); err != nil {
+		return nil, fmt.Errorf("invalid quiet hours start: %w", err)
+	}
+	if q.end, err = parseClock(end); err != nil {
+		return nil, fmt.Errorf("invalid quiet hours end: %w", err)
+	}
+
+	if len(days) > 0 {
+		q.days = make(map[time.Weekday]bool, len(days))
+		for _, d := range days {
+			key := strings.ToLower(strings.TrimSpace(d))
+			if len(key) > 3 {
+				key = key[:3]
+			}
+			wd, ok := weekdayNames[key]
+			if !ok {
+				return nil, fmt.Errorf("invalid quiet hours day %q", d)
+			}
+			q.days[wd] = true
+		}
+	}
+
+	if timezone != "" {
+		loc, err := time.LoadLocation(timezone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid quiet hours timezone: %w", err)
+		}
+		q.location = loc
+	}
+
+	return q, nil
+}
+
+func parseClock(s string) (int, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(s))
+	if err != nil {
+		return 0, err
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// Contains reports whether now falls inside the window.
+func (q *QuietHours) Contains(now time.Time) bool {
+	if q == nil || q.start == q.end {
+		return false
+	}
+
+	now = now.In(q.location)
+	minute := now.Hour()*60 + now.Minute()
+	day := now.Weekday()
+
+	if q.start < q.end {
+		return minute >= q.start && minute < q.end && q.activeOn(day)
+	}
+
+	// Overnight span: the evening half belongs to today's window, the
+	// early-morning half to the window that started yesterday.
+	if minute >= q.start {
+		return q.activeOn(day)
+	}
+	if minute < q.end {
+		return q.activeOn((day + 6) % 7)
+	}
+	return false
+}
+
+func (q *QuietHours) activeOn(day time.Weekday) bool {
+	return len(q.days) == 0 || q.days[day]
+}
+
+// SetQuietHours sets the window during which young users get a gentle
+// refusal instead of a response. Nil disables quiet hours.
+func (f *Filter) SetQuietHours(q *QuietHours) {
+	f.quietHours = q
+}
+
+// InQuietHours reports whether now falls inside the configured quiet hours.
+func (f *Filter) InQuietHours(now time.Time) bool {
+	return f.quietHours.Contains(now)
+}
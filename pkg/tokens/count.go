@@ -0,0 +1,159 @@
+package tokens
+
+import (
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+// counters caches the counting function chosen for each model name.
+var counters sync.Map // model string -> func(string) int
+
+// Count returns the number of tokens model's tokenizer is expected to produce
+// for text. OpenAI models are counted by splitting text the way their BPE
+// tokenizers pre-tokenize it and costing each piece; other models fall back
+// to one token per four characters. The choice is cached per model.
+func Count(model, text string) int {
+	if text == "" {
+		return 0
+	}
+	if c, ok := counters.Load(model); ok {
+		return c.(func(string) int)(text)
+	}
+	c := counterFor(model)
+	counters.Store(model, c)
+	return c(text)
+}
+
+// counterFor picks the counting function for a model family.
+func counterFor(model string) func(string) int {
+	if isOpenAIModel(model) {
+		return countBPE
+	}
+	return countQuarterChars
+}
+
+// isOpenAIModel reports whether model, with or without an "openai/" protocol
+// prefix, names an OpenAI model that uses a tiktoken BPE vocabulary.
+func isOpenAIModel(model string) bool {
+	name := strings.ToLower(model)
+	if protocol, rest, ok := strings.Cut(name, "/"); ok {
+		if protocol != "openai" {
+			return false
+		}
+		name = rest
+	}
+	for _, prefix := range []string{"gpt-", "chatgpt-", "o1", "o3", "o4", "text-embedding-"} {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// countQuarterChars is the fallback: one token per four characters,
+// rounded up.
+func countQuarterChars(text string) int {
+	return (utf8.RuneCountInString(text) + 3) / 4
+}
+
+// countBPE approximates a tiktoken count. Text is split into the pieces the
+// cl100k/o200k pre-tokenizer produces (contractions, words with their
+// leading space, runs of up to three digits, punctuation runs, whitespace),
+// and each piece is costed as the BPE merges would typically leave it:
+// common-length words are one token, long words split every six letters,
+// non-Latin letters cost a token each, and punctuation pairs share one.
+func countBPE(text string) int {
+	n := 0
+	rs := []rune(text)
+	for i := 0; i < len(rs); {
+		r := rs[i]
+		switch {
+		case r == '\'' && contractionLen(rs[i+1:]) > 0:
+			n++
+			i += 1 + contractionLen(rs[i+1:])
+
+		case isLetter(r) || (i+1 < len(rs) && isLetter(rs[i+1]) && !isDigit(r) && !isNewline(r)):
+			if !isLetter(r) {
+				i++ // the leading space or symbol joins the word
+			}
+			start := i
+			for i < len(rs) && isLetter(rs[i]) {
+				i++
+			}
+			n += wordCost(rs[start:i])
+
+		case isDigit(r):
+			start := i
+			for i < len(rs) && isDigit(rs[i]) {
+				i++
+			}
+			n += (i - start + 2) / 3
+
+		case r == ' ' && i+1 < len(rs) && isSymbol(rs[i+1]), isSymbol(r):
+			if r == ' ' {
+				i++ // the leading space joins the symbols
+			}
+			start := i
+			for i < len(rs) && isSymbol(rs[i]) {
+				i++
+			}
+			n += max(1, (i-start+1)/2)
+
+		default: // whitespace
+			start := i
+			for i < len(rs) && unicode.IsSpace(rs[i]) {
+				i++
+			}
+			// Before a word or symbol, a trailing space joins that piece.
+			if i < len(rs) && i-start >= 2 && rs[i-1] == ' ' && !isDigit(rs[i]) {
+				i--
+			}
+			n++
+		}
+	}
+	return n
+}
+
+// wordCost is the token cost of a run of letters.
+func wordCost(word []rune) int {
+	latin, other := 0, 0
+	for _, r := range word {
+		if r < utf8.RuneSelf {
+			latin++
+		} else {
+			other++
+		}
+	}
+	cost := other
+	switch {
+	case latin == 0:
+	case latin <= 10:
+		cost++
+	default:
+		cost += (latin + 5) / 6
+	}
+	return cost
+}
+
+var contractions = []string{"s", "t", "re", "ve", "m", "ll", "d"}
+
+// contractionLen returns the length of the contraction suffix at the start
+// of rest, or 0 if there is none.
+func contractionLen(rest []rune) int {
+	for _, c := range contractions {
+		if len(rest) < len(c) || !strings.EqualFold(string(rest[:len(c)]), c) {
+			continue
+		}
+		if len(rest) == len(c) || !isLetter(rest[len(c)]) {
+			return len(c)
+		}
+	}
+	return 0
+}
+
+func isLetter(r rune) bool  { return unicode.IsLetter(r) || unicode.Is(unicode.Mn, r) }
+func isDigit(r rune) bool   { return unicode.IsNumber(r) }
+func isNewline(r rune) bool { return r == '\n' || r == '\r' }
+func isSymbol(r rune) bool  { return !isLetter(r) && !isDigit(r) && !unicode.IsSpace(r) }
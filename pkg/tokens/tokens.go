@@ -0,0 +1,27 @@
+// Package tokens counts and estimates LLM tokens.
+//
+// Count is model-aware: it approximates the BPE tokenizers of OpenAI models
+// and falls back to four characters per token for other families. Memory
+// chunking uses it to size chunks for the embedding model.
+//
+// Estimate is the conservative, model-agnostic estimator shared by the
+// agent's context budget and the LLM context-size metric. It is a
+// heuristic, not a tokenizer: picoclaw does not ship tokenizer vocabularies.
+// Text is costed at 2.5 characters per token, which overestimates typical
+// English and code so that budgets built on it err on the side of
+// compressing too early rather than overflowing the context window.
+package tokens
+
+import "unicode/utf8"
+
+// Estimate returns the estimated number of tokens in text.
+func Estimate(text string) int {
+	return FromChars(utf8.RuneCountInString(text))
+}
+
+// FromChars converts a character count into an estimated token count.
+// Callers that sum several fields should add up the characters first and
+// convert once, so rounding is applied a single time.
+func FromChars(chars int) int {
+	return chars * 2 / 5
+}
@@ -0,0 +1,62 @@
+package tokens
+
+import "testing"
+
+func TestEstimate(t *testing.T) {
+	tests := []struct {
+		text string
+		want int
+	}{
+		{"", 0},
+		{"ab", 0},
+		{"abc", 1},
+		{"Hello, world!", 5},
+		// Multibyte text is costed per character, not per byte.
+		{"你好世界你好世界你好", 4},
+	}
+	for _, tt := range tests {
+		if got := Estimate(tt.text); got != tt.want {
+			t.Errorf("Estimate(%q) = %d, want %d", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestCount_OpenAIKnownStrings(t *testing.T) {
+	// Ranges bracket what the cl100k_base tokenizer produces; the first four
+	// are exact there ("I", " don", "'t", " think", " we", "'ll", ...).
+	tests := []struct {
+		text     string
+		min, max int
+	}{
+		{"hello world", 2, 2},
+		{"The quick brown fox jumps over the lazy dog.", 10, 10},
+		{"I don't think we'll make it.", 9, 9},
+		{"1234567", 3, 3},
+		{"func main() {\n\tfmt.Println(\"hi\")\n}", 9, 16},
+		{"你好，世界", 3, 8},
+		{"Tokenization of internationalization is surprisingly tricky!", 8, 14},
+	}
+	for _, tt := range tests {
+		if got := Count("gpt-4o", tt.text); got < tt.min || got > tt.max {
+			t.Errorf("Count(gpt-4o, %q) = %d, want %d..%d", tt.text, got, tt.min, tt.max)
+		}
+	}
+}
+
+func TestCount_ModelFamilies(t *testing.T) {
+	text := "The quick brown fox jumps over the lazy dog."
+	for _, model := range []string{"gpt-4o", "openai/gpt-4o-mini", "o3-mini", "text-embedding-3-small"} {
+		if got, want := Count(model, text), countBPE(text); got != want {
+			t.Errorf("Count(%q) = %d, want the BPE count %d", model, got, want)
+		}
+	}
+	// Other families fall back to one token per four characters.
+	for _, model := range []string{"claude-sonnet-4", "anthropic/gpt-lookalike", "qwen2.5", ""} {
+		if got := Count(model, text); got != 11 {
+			t.Errorf("Count(%q) = %d, want 11", model, got)
+		}
+	}
+	if got := Count("gpt-4o", ""); got != 0 {
+		t.Errorf("Count of empty text = %d, want 0", got)
+	}
+}
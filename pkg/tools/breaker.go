@@ -0,0 +1,98 @@
+package tools
+
+import (
+	"sync"
+	"time"
+)
+
+// CircuitBreaker stops calling a tool after it fails threshold times in a
+// row. The open circuit rejects calls for the cooldown, then lets a single
+// probe call through (half-open): success closes the circuit, failure opens
+// it for another cooldown.
+type CircuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+
+	mu     sync.Mutex
+	states map[string]*breakerState
+}
+
+type breakerState struct {
+	failures  int
+	openUntil time.Time // zero while closed
+	probing   bool      // a half-open probe call is in flight
+}
+
+// NewCircuitBreaker returns a breaker that opens after threshold consecutive
+// failures (at least 1) and stays open for cooldown.
+func NewCircuitBreaker(threshold int, cooldown time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{
+		threshold: max(threshold, 1),
+		cooldown:  cooldown,
+		now:       time.Now,
+		states:    make(map[string]*breakerState),
+	}
+}
+
+// Allow reports whether a call to the named tool may proceed. When it may
+// not, retryIn is the time left until the next probe is allowed. A nil
+// breaker allows everything.
+func (b *CircuitBreaker) Allow(name string) (ok bool, retryIn time.Duration) {
+	if b == nil {
+		return true, 0
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	s, exists := b.states[name]
+	if !exists || s.openUntil.IsZero() {
+		return true, 0
+	}
+	if wait := s.openUntil.Sub(b.now()); wait > 0 {
+		return false, wait
+	}
+	if s.probing {
+		return false, 0
+	}
+	s.probing = true
+	return true, 0
+}
+
+// Record reports the outcome of a call that Allow let through.
+func (b *CircuitBreaker) Record(name string, failed bool) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	s, exists := b.states[name]
+	if !failed {
+		delete(b.states, name)
+		return
+	}
+	if !exists {
+		s = &breakerState{}
+		b.states[name] = s
+	}
+	s.failures++
+	if s.probing || s.failures >= b.threshold {
+		s.openUntil = b.now().Add(b.cooldown)
+		s.probing = false
+	}
+}
+
+// release gives up a call that Allow let through without recording an
+// outcome, so an abandoned half-open probe does not block later probes.
+func (b *CircuitBreaker) release(name string) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if s, ok := b.states[name]; ok {
+		s.probing = false
+	}
+}
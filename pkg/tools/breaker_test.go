@@ -0,0 +1,147 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func newTestBreaker(threshold int, cooldown time.Duration) (*CircuitBreaker, *time.Time) {
+	b := NewCircuitBreaker(threshold, cooldown)
+	now := time.Unix(1_700_000_000, 0)
+	b.now = func() time.Time { return now }
+	return b, &now
+}
+
+// toolErrorCount returns picoclaw_tool_errors_total for one tool and type.
+func toolErrorCount(t *testing.T, tool, errorType string) float64 {
+	t.Helper()
+	families, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatalf("Gather: %v", err)
+	}
+	for _, mf := range families {
+		if mf.GetName() != "picoclaw_tool_errors_total" {
+			continue
+		}
+		for _, m := range mf.GetMetric() {
+			labels := map[string]string{}
+			for _, l := range m.GetLabel() {
+				labels[l.GetName()] = l.GetValue()
+			}
+			if labels["tool_name"] == tool && labels["error_type"] == errorType {
+				return m.GetCounter().GetValue()
+			}
+		}
+	}
+	return 0
+}
+
+func TestCircuitBreaker_OpensAfterConsecutiveFailures(t *testing.T) {
+	b, _ := newTestBreaker(3, time.Minute)
+
+	b.Record("exec", true)
+	b.Record("exec", true)
+	b.Record("exec", false) // a success resets the count
+	b.Record("exec", true)
+	b.Record("exec", true)
+	if ok, _ := b.Allow("exec"); !ok {
+		t.Fatal("breaker opened before reaching the threshold of consecutive failures")
+	}
+
+	b.Record("exec", true)
+	ok, retryIn := b.Allow("exec")
+	if ok || retryIn != time.Minute {
+		t.Errorf("Allow = %v, %v; want rejected for the full cooldown", ok, retryIn)
+	}
+	if ok, _ := b.Allow("read_file"); !ok {
+		t.Error("other tools should be unaffected")
+	}
+}
+
+func TestCircuitBreaker_HalfOpenRecovery(t *testing.T) {
+	b, now := newTestBreaker(1, time.Minute)
+	b.Record("exec", true)
+
+	*now = now.Add(30 * time.Second)
+	if ok, retryIn := b.Allow("exec"); ok || retryIn != 30*time.Second {
+		t.Fatalf("Allow during cooldown = %v, %v; want rejected with 30s left", ok, retryIn)
+	}
+
+	*now = now.Add(30 * time.Second)
+	if ok, _ := b.Allow("exec"); !ok {
+		t.Fatal("probe after cooldown was rejected")
+	}
+	if ok, _ := b.Allow("exec"); ok {
+		t.Fatal("second call admitted while the probe is in flight")
+	}
+
+	// A failed probe re-opens the breaker for another cooldown.
+	b.Record("exec", true)
+	if ok, retryIn := b.Allow("exec"); ok || retryIn != time.Minute {
+		t.Fatalf("Allow after failed probe = %v, %v; want re-opened", ok, retryIn)
+	}
+
+	// A successful probe closes it.
+	*now = now.Add(time.Minute)
+	if ok, _ := b.Allow("exec"); !ok {
+		t.Fatal("second probe was rejected")
+	}
+	b.Record("exec", false)
+	for i := 0; i < 3; i++ {
+		if ok, _ := b.Allow("exec"); !ok {
+			t.Fatalf("call %d rejected after a successful probe", i)
+		}
+	}
+}
+
+func TestCircuitBreaker_ReleasedProbeAllowsAnother(t *testing.T) {
+	b, now := newTestBreaker(1, time.Minute)
+	b.Record("exec", true)
+	*now = now.Add(time.Minute)
+
+	b.Allow("exec")
+	b.release("exec")
+	if ok, _ := b.Allow("exec"); !ok {
+		t.Error("abandoned probe still blocks the next one")
+	}
+}
+
+func TestToolRegistry_CircuitBreakerShortCircuitsFailingTool(t *testing.T) {
+	const name = "breaker_flaky_tool"
+	r := NewToolRegistry()
+	tool := newMockTool(name, "fails")
+	tool.result = ErrorResult("backend down")
+	r.Register(tool)
+	b, now := newTestBreaker(2, time.Minute)
+	r.SetCircuitBreaker(b)
+	openBefore := toolErrorCount(t, name, "circuit_open")
+	failedBefore := toolErrorCount(t, name, "execution_error")
+
+	r.Execute(context.Background(), name, nil)
+	r.Execute(context.Background(), name, nil)
+
+	// The tool would now succeed, but the breaker is open.
+	tool.result = SilentResult("ok")
+	res := r.Execute(context.Background(), name, nil)
+	if !res.IsError || !strings.Contains(res.ForLLM, "temporarily unavailable") {
+		t.Fatalf("result while open = %+v, want a temporarily unavailable error", res)
+	}
+	if got := toolErrorCount(t, name, "circuit_open") - openBefore; got != 1 {
+		t.Errorf("circuit_open errors recorded = %v, want 1", got)
+	}
+	if got := toolErrorCount(t, name, "execution_error") - failedBefore; got != 2 {
+		t.Errorf("execution_error errors recorded = %v, want 2", got)
+	}
+
+	*now = now.Add(time.Minute)
+	if res := r.Execute(context.Background(), name, nil); res.IsError {
+		t.Fatalf("probe after cooldown = %+v, want the tool's result", res)
+	}
+	if res := r.Execute(context.Background(), name, nil); res.IsError {
+		t.Errorf("call after recovery = %+v, want the tool's result", res)
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/sipeed/picoclaw/pkg/config"
 	"github.com/sipeed/picoclaw/pkg/constants"
 	"github.com/sipeed/picoclaw/pkg/cron"
+	"github.com/sipeed/picoclaw/pkg/metrics"
 	"github.com/sipeed/picoclaw/pkg/utils"
 )
 
@@ -231,6 +232,7 @@ func (t *CronTool) addJob(ctx context.Context, args map[string]any) *ToolResult
 	}
 
 	if command != "" {
+		job.Payload.Kind = cron.PayloadKindShell
 		job.Payload.Command = command
 		// Need to save the updated payload
 		t.cronService.UpdateJob(job)
@@ -297,6 +299,12 @@ func (t *CronTool) enableJob(args map[string]any, enable bool) *ToolResult {
 
 // ExecuteJob executes a cron job through the agent
 func (t *CronTool) ExecuteJob(ctx context.Context, job *cron.CronJob) string {
+	start := time.Now()
+	status := "ok"
+	defer func() {
+		metrics.DefaultRecorder().RecordCronExecution(job.Name, status, job.PayloadKind(), time.Since(start))
+	}()
+
 	// Get channel/chatID from job payload
 	channel := job.Payload.Channel
 	chatID := job.Payload.To
@@ -312,6 +320,7 @@ func (t *CronTool) ExecuteJob(ctx context.Context, job *cron.CronJob) string {
 	// Execute command if present
 	if job.Payload.Command != "" {
 		if !t.execEnabled || t.execTool == nil {
+			status = "error"
 			output := "Error executing scheduled command: command execution is disabled"
 			pubCtx, pubCancel := context.WithTimeout(context.Background(), 5*time.Second)
 			defer pubCancel()
@@ -332,6 +341,7 @@ func (t *CronTool) ExecuteJob(ctx context.Context, job *cron.CronJob) string {
 		result := t.execTool.Execute(ctx, args)
 		var output string
 		if result.IsError {
+			status = "error"
 			output = fmt.Sprintf("Error executing scheduled command: %s", result.ForLLM)
 		} else {
 			output = fmt.Sprintf("Scheduled command '%s' executed:\n%s", job.Payload.Command, result.ForLLM)
@@ -359,7 +369,8 @@ func (t *CronTool) ExecuteJob(ctx context.Context, job *cron.CronJob) string {
 		return "ok"
 	}
 
-	// For deliver=false, process through agent (for complex tasks)
+	// For deliver=false, process through agent (for complex tasks). Subagent
+	// jobs take the same path until the cron tool can spawn subagents.
 	sessionKey := fmt.Sprintf("cron-%s", job.ID)
 
 	// Call agent with job's message
@@ -371,6 +382,7 @@ func (t *CronTool) ExecuteJob(ctx context.Context, job *cron.CronJob) string {
 		chatID,
 	)
 	if err != nil {
+		status = "error"
 		return fmt.Sprintf("Error: %v", err)
 	}
 
@@ -7,6 +7,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+
 	"github.com/sipeed/picoclaw/pkg/bus"
 	"github.com/sipeed/picoclaw/pkg/config"
 	"github.com/sipeed/picoclaw/pkg/cron"
@@ -237,3 +239,64 @@ func TestCronTool_ExecuteJobPublishesErrorWhenExecDisabled(t *testing.T) {
 		t.Fatalf("expected exec disabled message, got: %s", msg.Content)
 	}
 }
+
+type stubJobExecutor struct{}
+
+func (stubJobExecutor) ProcessDirectWithChannel(ctx context.Context, content, sessionKey, channel, chatID string) (string, error) {
+	return "done", nil
+}
+
+// cronExecutionCount returns picoclaw_cron_executions_total for the labels.
+func cronExecutionCount(t *testing.T, jobName, status, payloadKind string) float64 {
+	t.Helper()
+	families, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatalf("Gather: %v", err)
+	}
+	want := map[string]string{"job_name": jobName, "status": status, "payload_kind": payloadKind}
+	for _, mf := range families {
+		if mf.GetName() != "picoclaw_cron_executions_total" {
+			continue
+		}
+		for _, m := range mf.GetMetric() {
+			matched := 0
+			for _, l := range m.GetLabel() {
+				if want[l.GetName()] == l.GetValue() {
+					matched++
+				}
+			}
+			if matched == len(want) {
+				return m.GetCounter().GetValue()
+			}
+		}
+	}
+	return 0
+}
+
+func TestCronTool_ExecuteJobRecordsPayloadKind(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Tools.Exec.Enabled = false
+	tool := newTestCronToolWithConfig(t, cfg)
+	tool.executor = stubJobExecutor{}
+
+	tests := []struct {
+		name       string
+		payload    cron.CronPayload
+		wantStatus string
+		wantKind   string
+	}{
+		{"payload-kind-reminder", cron.CronPayload{Kind: cron.PayloadKindPrompt, Message: "stretch", Deliver: true}, "ok", cron.PayloadKindPrompt},
+		{"payload-kind-legacy", cron.CronPayload{Kind: "agent_turn", Message: "summarize the news"}, "ok", cron.PayloadKindPrompt},
+		{"payload-kind-subagent", cron.CronPayload{Kind: cron.PayloadKindSubagent, Message: "research"}, "ok", cron.PayloadKindSubagent},
+		{"payload-kind-shell", cron.CronPayload{Kind: cron.PayloadKindShell, Command: "df -h"}, "error", cron.PayloadKindShell},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job := &cron.CronJob{Name: tt.name, Payload: tt.payload}
+			tool.ExecuteJob(context.Background(), job)
+			if got := cronExecutionCount(t, tt.name, tt.wantStatus, tt.wantKind); got != 1 {
+				t.Errorf("cron executions{status=%q, payload_kind=%q} = %v, want 1", tt.wantStatus, tt.wantKind, got)
+			}
+		})
+	}
+}
@@ -256,6 +256,7 @@ func extractContentText(content []mcp.Content) string {
 func (t *MCPTool) normalizeResultContent(ctx context.Context, content []mcp.Content) *ToolResult {
 	llmParts := make([]string, 0, len(content))
 	mediaRefs := make([]string, 0, len(content))
+	var images []Image
 
 	for _, c := range content {
 		switch v := c.(type) {
@@ -265,6 +266,12 @@ func (t *MCPTool) normalizeResultContent(ctx context.Context, content []mcp.Cont
 				llmParts = append(llmParts, text)
 			}
 		case *mcp.ImageContent:
+			if len(v.Data) > 0 && annotationsAllowAssistant(v.Annotations) {
+				images = append(images, Image{
+					MIMEType: normalizedMIMEType(v.MIMEType),
+					Data:     v.Data,
+				})
+			}
 			ref, note := t.storeBinaryContent(
 				ctx,
 				"image",
@@ -310,6 +317,7 @@ func (t *MCPTool) normalizeResultContent(ctx context.Context, content []mcp.Cont
 	result := &ToolResult{
 		ForLLM: strings.Join(compactStrings(llmParts), "\n"),
 		Media:  mediaRefs,
+		Images: images,
 	}
 	return result
 }
@@ -485,6 +493,18 @@ func annotationsAllowUser(annotations *mcp.Annotations) bool {
 	return false
 }
 
+func annotationsAllowAssistant(annotations *mcp.Annotations) bool {
+	if annotations == nil || len(annotations.Audience) == 0 {
+		return true
+	}
+	for _, audience := range annotations.Audience {
+		if strings.EqualFold(string(audience), "assistant") {
+			return true
+		}
+	}
+	return false
+}
+
 func normalizedMIMEType(mimeType string) string {
 	if strings.TrimSpace(mimeType) == "" {
 		return "application/octet-stream"
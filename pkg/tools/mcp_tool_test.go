@@ -547,6 +547,44 @@ func TestMCPTool_Execute_ImageContentStoredAsMedia(t *testing.T) {
 	}
 }
 
+func TestMCPTool_Execute_ImageContentInImages(t *testing.T) {
+	manager := &MockMCPManager{
+		callToolFunc: func(ctx context.Context, serverName, toolName string, arguments map[string]any) (*mcp.CallToolResult, error) {
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{
+					&mcp.TextContent{Text: "Screenshot taken"},
+					&mcp.ImageContent{
+						Data:     []byte("fake-image-bytes"),
+						MIMEType: "image/png",
+					},
+					&mcp.ImageContent{
+						Data:        []byte("user-only"),
+						MIMEType:    "image/jpeg",
+						Annotations: &mcp.Annotations{Audience: []mcp.Role{"user"}},
+					},
+				},
+			}, nil
+		},
+	}
+
+	mcpTool := NewMCPTool(manager, "screenshoto", &mcp.Tool{Name: "take_screenshot"})
+	result := mcpTool.Execute(context.Background(), nil)
+
+	if result.IsError {
+		t.Fatalf("expected success, got %q", result.ForLLM)
+	}
+	if len(result.Images) != 1 {
+		t.Fatalf("expected 1 image for the model, got %d", len(result.Images))
+	}
+	img := result.Images[0]
+	if img.MIMEType != "image/png" || string(img.Data) != "fake-image-bytes" {
+		t.Fatalf("unexpected image: %+v", img)
+	}
+	if want := "data:image/png;base64,ZmFrZS1pbWFnZS1ieXRlcw=="; img.DataURL() != want {
+		t.Fatalf("DataURL() = %q, want %q", img.DataURL(), want)
+	}
+}
+
 func TestMCPTool_Execute_EmbeddedResourceBlobStoredAsMedia(t *testing.T) {
 	store := media.NewFileMediaStore()
 	manager := &MockMCPManager{
@@ -47,6 +47,10 @@ func (t *MemoryBrowseTool) Parameters() map[string]interface{} {
 				"type":        "integer",
 				"description": "Maximum number of results to return (default: 5).",
 			},
+			"offset": map[string]interface{}{
+				"type":        "integer",
+				"description": "Number of results to skip, for fetching the next page (default: 0).",
+			},
 		},
 		"required": []string{"query"},
 	}
@@ -72,7 +76,12 @@ func (t *MemoryBrowseTool) Execute(ctx context.Context, input map[string]interfa
 		limit = int(l)
 	}
 
-	results, err := t.manager.SearchByDate(ctx, t.workspaceID, query, limit, order)
+	offset := 0
+	if o, ok := input["offset"].(float64); ok && o > 0 {
+		offset = int(o)
+	}
+
+	results, err := t.manager.SearchByDate(ctx, t.workspaceID, query, limit, offset, order)
 	if err != nil {
 		return ErrorResult(fmt.Sprintf("failed to browse memory: %v", err))
 	}
@@ -92,10 +101,13 @@ func (t *MemoryBrowseTool) Execute(ctx context.Context, input map[string]interfa
 		content, _ := r.Payload["content"].(string)
 		sessionID, _ := r.Payload["session_id"].(string)
 		timestampStr := formatTimestamp(r.Payload["timestamp"])
-		sb.WriteString(fmt.Sprintf("--- Session %d (ID: %s, Date: %s) ---\n", i+1, sessionID, timestampStr))
+		sb.WriteString(fmt.Sprintf("--- Session %d (ID: %s, Date: %s) ---\n", offset+i+1, sessionID, timestampStr))
 		sb.WriteString(content)
 		sb.WriteString("\n\n")
 	}
+	if len(results) == limit {
+		sb.WriteString(fmt.Sprintf("Use offset=%d to see more.\n", offset+limit))
+	}
 
 	return UserResult(sb.String())
 }
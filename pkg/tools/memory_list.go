@@ -0,0 +1,90 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/sipeed/picoclaw/pkg/memory"
+	"github.com/sipeed/picoclaw/pkg/utils"
+)
+
+type MemoryListTool struct {
+	manager     *memory.Manager
+	workspaceID string
+}
+
+func NewMemoryListTool(manager *memory.Manager, workspaceID string) *MemoryListTool {
+	return &MemoryListTool{
+		manager:     manager,
+		workspaceID: workspaceID,
+	}
+}
+
+func (t *MemoryListTool) Name() string {
+	return "memory_list"
+}
+
+func (t *MemoryListTool) Description() string {
+	return `List everything stored in long-term memory, most recent first, without a search query. Use this to audit what has been remembered.
+
+Use memory_search or memory_browse instead when looking for a specific topic.`
+}
+
+func (t *MemoryListTool) Parameters() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"limit": map[string]interface{}{
+				"type":        "integer",
+				"description": "Maximum number of memories to return (default: 10).",
+			},
+			"cursor": map[string]interface{}{
+				"type":        "string",
+				"description": "Cursor from a previous memory_list call, for fetching the next page.",
+			},
+		},
+	}
+}
+
+func (t *MemoryListTool) Execute(ctx context.Context, input map[string]interface{}) *ToolResult {
+	if t.manager == nil {
+		return SilentResult("Long-term memory is not enabled.")
+	}
+
+	limit := 10
+	if l, ok := input["limit"].(float64); ok && l > 0 {
+		limit = int(l)
+	}
+
+	cursor, _ := input["cursor"].(string)
+
+	results, next, err := t.manager.ListSessions(ctx, t.workspaceID, limit, cursor)
+	if err != nil {
+		return ErrorResult(fmt.Sprintf("failed to list memory: %v", err))
+	}
+
+	if len(results) == 0 {
+		return UserResult("No memories stored.")
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Listing %d memories (most recent first):\n\n", len(results)))
+	for _, r := range results {
+		content, _ := r.Payload["content"].(string)
+		sessionID, _ := r.Payload["session_id"].(string)
+		timestampStr := formatTimestamp(r.Payload["timestamp"])
+		sb.WriteString(fmt.Sprintf("--- Session %s, chunk %d/%d (Date: %s) ---\n",
+			sessionID,
+			memory.PayloadInt(r.Payload, "chunk_index")+1,
+			memory.PayloadInt(r.Payload, "total_chunks"),
+			timestampStr))
+		sb.WriteString(utils.Truncate(content, 200))
+		sb.WriteString("\n\n")
+	}
+	if next != "" {
+		sb.WriteString(fmt.Sprintf("Use cursor=%q to see more.\n", next))
+	}
+
+	return UserResult(sb.String())
+}
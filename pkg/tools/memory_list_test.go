@@ -0,0 +1,42 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestMemoryListTool_PagesWithCursor(t *testing.T) {
+	tool := NewMemoryListTool(newStubMemoryManager(
+		scoredMemory("s1", "first memory", 0),
+		scoredMemory("s2", "second memory", 0),
+		scoredMemory("s3", "third memory", 0),
+	), "ws")
+
+	result := tool.Execute(context.Background(), map[string]interface{}{"limit": 2.0})
+	if result.IsError {
+		t.Fatalf("unexpected error: %s", result.ForLLM)
+	}
+	if !strings.Contains(result.ForLLM, "first memory") || !strings.Contains(result.ForLLM, "second memory") {
+		t.Errorf("expected first page, got %q", result.ForLLM)
+	}
+	if !strings.Contains(result.ForLLM, `cursor="2"`) {
+		t.Fatalf("expected next cursor hint, got %q", result.ForLLM)
+	}
+
+	result = tool.Execute(context.Background(), map[string]interface{}{"limit": 2.0, "cursor": "2"})
+	if !strings.Contains(result.ForLLM, "third memory") || strings.Contains(result.ForLLM, "first memory") {
+		t.Errorf("expected only the second page, got %q", result.ForLLM)
+	}
+	if strings.Contains(result.ForLLM, "cursor=") {
+		t.Errorf("expected no cursor on the last page, got %q", result.ForLLM)
+	}
+}
+
+func TestMemoryListTool_Empty(t *testing.T) {
+	tool := NewMemoryListTool(newStubMemoryManager(), "ws")
+	result := tool.Execute(context.Background(), map[string]interface{}{})
+	if result.ForLLM != "No memories stored." {
+		t.Errorf("unexpected output: %q", result.ForLLM)
+	}
+}
@@ -9,6 +9,10 @@ import (
 	"github.com/sipeed/picoclaw/pkg/memory"
 )
 
+// defaultMemoryMinScore drops weak semantic matches that tend to mislead
+// the agent more than they help.
+const defaultMemoryMinScore = 0.3
+
 type MemorySearchTool struct {
 	manager     *memory.Manager
 	workspaceID string
@@ -43,6 +47,31 @@ func (t *MemorySearchTool) Parameters() map[string]interface{} {
 				"type":        "integer",
 				"description": "Maximum number of results to return (default: 5).",
 			},
+			"min_score": map[string]interface{}{
+				"type": "number",
+				"description": fmt.Sprintf(
+					"Minimum similarity score (0-1) for semantic results (default: %.2f).",
+					defaultMemoryMinScore,
+				),
+			},
+			"offset": map[string]interface{}{
+				"type":        "integer",
+				"description": "Number of results to skip, for fetching the next page (default: 0).",
+			},
+			"mode": map[string]interface{}{
+				"type": "string",
+				"description": "'semantic' ranks by meaning only (default). " +
+					"'hybrid' also matches exact terms such as names, IDs or error codes.",
+				"enum": []string{"semantic", "hybrid"},
+			},
+			"channel": map[string]interface{}{
+				"type":        "string",
+				"description": "Only return memories from this channel (e.g. 'discord', 'telegram').",
+			},
+			"user_id": map[string]interface{}{
+				"type":        "string",
+				"description": "Only return memories from conversations with this user ID.",
+			},
 		},
 		"required": []string{"query"},
 	}
@@ -63,7 +92,43 @@ func (t *MemorySearchTool) Execute(ctx context.Context, input map[string]interfa
 		limit = int(l)
 	}
 
-	results, err := t.manager.Search(ctx, t.workspaceID, query, limit, 0)
+	offset := 0
+	if o, ok := input["offset"].(float64); ok && o > 0 {
+		offset = int(o)
+	}
+
+	minScore := float32(defaultMemoryMinScore)
+	if s, ok := input["min_score"].(float64); ok && s >= 0 {
+		minScore = float32(s)
+	}
+
+	mode, _ := input["mode"].(string)
+
+	metadata := map[string]string{}
+	if channel, ok := input["channel"].(string); ok {
+		metadata[memory.MetadataChannel] = channel
+	}
+	if userID, ok := input["user_id"].(string); ok {
+		metadata[memory.MetadataUserID] = userID
+	}
+
+	var results []memory.SearchResult
+	var err error
+	if mode == "hybrid" {
+		// Fusion re-ranks the whole candidate set, so page by slicing.
+		results, err = t.manager.HybridSearchWithMetadata(ctx, t.workspaceID, query, offset+limit, metadata)
+		if offset < len(results) {
+			results = results[offset:]
+		} else {
+			results = nil
+		}
+	} else {
+		results, err = t.manager.SearchWithOptions(ctx, t.workspaceID, query, limit, memory.SearchOptions{
+			Offset:   offset,
+			Metadata: metadata,
+			MinScore: minScore,
+		})
+	}
 	if err != nil {
 		return ErrorResult(fmt.Sprintf("failed to search memory: %v", err))
 	}
@@ -78,10 +143,14 @@ func (t *MemorySearchTool) Execute(ctx context.Context, input map[string]interfa
 		content, _ := r.Payload["content"].(string)
 		sessionID, _ := r.Payload["session_id"].(string)
 		timestampStr := formatTimestamp(r.Payload["timestamp"])
-		sb.WriteString(fmt.Sprintf("--- Memory %d (Session: %s, Score: %.3f, Date: %s) ---\n", i+1, sessionID, r.Score, timestampStr))
+		sb.WriteString(fmt.Sprintf("--- Memory %d (Session: %s, Score: %.3f, Date: %s) ---\n",
+			offset+i+1, sessionID, r.Score, timestampStr))
 		sb.WriteString(content)
 		sb.WriteString("\n\n")
 	}
+	if len(results) == limit {
+		sb.WriteString(fmt.Sprintf("Use offset=%d to see more.\n", offset+limit))
+	}
 
 	return UserResult(sb.String())
 }
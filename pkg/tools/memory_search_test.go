@@ -0,0 +1,165 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/sipeed/picoclaw/pkg/config"
+	"github.com/sipeed/picoclaw/pkg/memory"
+)
+
+// stubVectorDB returns a fixed, pre-scored result set from Search.
+type stubVectorDB struct {
+	results []memory.SearchResult
+}
+
+func (db *stubVectorDB) Store(ctx context.Context, collection string, record memory.VectorRecord) error {
+	return nil
+}
+
+func (db *stubVectorDB) StoreBatch(ctx context.Context, collection string, records []memory.VectorRecord) error {
+	return nil
+}
+
+func (db *stubVectorDB) Search(
+	ctx context.Context,
+	collection string,
+	vector []float32,
+	limit, offset int,
+	filters map[string]interface{},
+) ([]memory.SearchResult, error) {
+	results := append([]memory.SearchResult(nil), db.results...)
+	if offset >= len(results) {
+		return nil, nil
+	}
+	results = results[offset:]
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	return results, nil
+}
+
+func (db *stubVectorDB) KeywordSearch(
+	ctx context.Context,
+	collection, text string,
+	limit int,
+	filters map[string]interface{},
+) ([]memory.SearchResult, error) {
+	return nil, nil
+}
+
+func (db *stubVectorDB) Scroll(
+	ctx context.Context,
+	collection, orderBy string,
+	limit int,
+	cursor string,
+	filters map[string]interface{},
+) ([]memory.SearchResult, string, error) {
+	start := 0
+	if cursor != "" {
+		fmt.Sscanf(cursor, "%d", &start)
+	}
+	if start >= len(db.results) {
+		return nil, "", nil
+	}
+	end := start + limit
+	if end >= len(db.results) {
+		return db.results[start:], "", nil
+	}
+	return db.results[start:end], fmt.Sprintf("%d", end), nil
+}
+
+func (db *stubVectorDB) Count(ctx context.Context, collection string, filters map[string]interface{}) (int, error) {
+	return len(db.results), nil
+}
+
+func (db *stubVectorDB) Stats(
+	ctx context.Context,
+	collection string,
+	filters map[string]interface{},
+) (memory.CollectionStats, error) {
+	stats := memory.CollectionStats{Count: len(db.results)}
+	for i, r := range db.results {
+		ts := memory.PayloadInt(r.Payload, "timestamp")
+		if i == 0 || ts < stats.Oldest {
+			stats.Oldest = ts
+		}
+		if i == 0 || ts > stats.Newest {
+			stats.Newest = ts
+		}
+	}
+	return stats, nil
+}
+
+func (db *stubVectorDB) EnsureCollection(ctx context.Context, name string, dimension int) error {
+	return nil
+}
+
+func (db *stubVectorDB) Close() error {
+	return nil
+}
+
+type stubEmbedder struct{}
+
+func (stubEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	return []float32{1}, nil
+}
+
+func (stubEmbedder) Dimension() int {
+	return 1
+}
+
+func newStubMemoryManager(results ...memory.SearchResult) *memory.Manager {
+	return memory.NewManager(config.MemoryConfig{Enabled: true}, &stubVectorDB{results: results}, stubEmbedder{})
+}
+
+func scoredMemory(id, content string, score float32) memory.SearchResult {
+	return memory.SearchResult{
+		ID:      id,
+		Score:   score,
+		Payload: map[string]interface{}{"content": content, "session_id": id},
+	}
+}
+
+func TestMemorySearchTool_DropsResultsBelowDefaultMinScore(t *testing.T) {
+	tool := NewMemorySearchTool(newStubMemoryManager(
+		scoredMemory("s1", "garden plans", 0.82),
+		scoredMemory("s2", "unrelated noise", 0.12),
+	), "ws")
+
+	result := tool.Execute(context.Background(), map[string]interface{}{"query": "garden"})
+	if result.IsError {
+		t.Fatalf("unexpected error: %s", result.ForLLM)
+	}
+	if !strings.Contains(result.ForLLM, "garden plans") {
+		t.Errorf("expected high-scoring memory in output, got %q", result.ForLLM)
+	}
+	if strings.Contains(result.ForLLM, "unrelated noise") {
+		t.Errorf("expected low-scoring memory to be dropped, got %q", result.ForLLM)
+	}
+}
+
+func TestMemorySearchTool_MinScoreParameter(t *testing.T) {
+	tool := NewMemorySearchTool(newStubMemoryManager(
+		scoredMemory("s1", "garden plans", 0.82),
+		scoredMemory("s2", "unrelated noise", 0.12),
+	), "ws")
+
+	result := tool.Execute(context.Background(), map[string]interface{}{
+		"query":     "garden",
+		"min_score": 0.0,
+	})
+	if !strings.Contains(result.ForLLM, "unrelated noise") {
+		t.Errorf("expected min_score=0 to keep all results, got %q", result.ForLLM)
+	}
+
+	result = tool.Execute(context.Background(), map[string]interface{}{
+		"query":     "garden",
+		"min_score": 0.9,
+	})
+	if result.ForLLM != "No relevant memories found." {
+		t.Errorf("expected no results above 0.9, got %q", result.ForLLM)
+	}
+}
@@ -0,0 +1,57 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sipeed/picoclaw/pkg/memory"
+)
+
+type MemoryStatsTool struct {
+	manager     *memory.Manager
+	workspaceID string
+}
+
+func NewMemoryStatsTool(manager *memory.Manager, workspaceID string) *MemoryStatsTool {
+	return &MemoryStatsTool{
+		manager:     manager,
+		workspaceID: workspaceID,
+	}
+}
+
+func (t *MemoryStatsTool) Name() string {
+	return "memory_stats"
+}
+
+func (t *MemoryStatsTool) Description() string {
+	return "Report how much conversation history is stored in long-term memory: the number of archived chunks and the dates of the oldest and newest."
+}
+
+func (t *MemoryStatsTool) Parameters() map[string]interface{} {
+	return map[string]interface{}{
+		"type":       "object",
+		"properties": map[string]interface{}{},
+	}
+}
+
+func (t *MemoryStatsTool) Execute(ctx context.Context, input map[string]interface{}) *ToolResult {
+	if t.manager == nil {
+		return SilentResult("Long-term memory is not enabled.")
+	}
+
+	stats, err := t.manager.Stats(ctx, t.workspaceID)
+	if err != nil {
+		return ErrorResult(fmt.Sprintf("failed to read memory stats: %v", err))
+	}
+
+	if stats.Count == 0 {
+		return UserResult("Long-term memory is empty.")
+	}
+
+	noun := "chunks"
+	if stats.Count == 1 {
+		noun = "chunk"
+	}
+	return UserResult(fmt.Sprintf("Long-term memory holds %d %s archived between %s and %s.",
+		stats.Count, noun, formatTimestamp(stats.Oldest), formatTimestamp(stats.Newest)))
+}
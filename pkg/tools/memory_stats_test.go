@@ -0,0 +1,57 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sipeed/picoclaw/pkg/config"
+	"github.com/sipeed/picoclaw/pkg/memory"
+)
+
+func TestMemoryStatsTool_ReportsWorkspaceRange(t *testing.T) {
+	ctx := context.Background()
+	db := memory.NewInMemoryVectorDB()
+	if err := db.EnsureCollection(ctx, "picoclaw", 1); err != nil {
+		t.Fatalf("EnsureCollection: %v", err)
+	}
+
+	oldest := time.Date(2024, 3, 1, 12, 0, 0, 0, time.Local).Unix()
+	newest := time.Date(2024, 6, 30, 8, 30, 0, 0, time.Local).Unix()
+	records := []memory.VectorRecord{
+		{ID: "a", Vector: []float32{1}, Payload: map[string]interface{}{"workspace_id": "ws", "timestamp": newest}},
+		{ID: "b", Vector: []float32{1}, Payload: map[string]interface{}{"workspace_id": "ws", "timestamp": oldest}},
+		{ID: "c", Vector: []float32{1}, Payload: map[string]interface{}{"workspace_id": "ws", "timestamp": oldest + 3600}},
+		{ID: "d", Vector: []float32{1}, Payload: map[string]interface{}{"workspace_id": "other", "timestamp": int64(1)}},
+	}
+	if err := db.StoreBatch(ctx, "picoclaw", records); err != nil {
+		t.Fatalf("StoreBatch: %v", err)
+	}
+
+	manager := memory.NewManager(config.MemoryConfig{Enabled: true}, db, stubEmbedder{})
+	result := NewMemoryStatsTool(manager, "ws").Execute(ctx, map[string]interface{}{})
+	if result.IsError {
+		t.Fatalf("unexpected error: %s", result.ForLLM)
+	}
+
+	want := "Long-term memory holds 3 chunks archived between 2024-03-01 12:00:00 and 2024-06-30 08:30:00."
+	if result.ForLLM != want {
+		t.Errorf("output = %q, want %q", result.ForLLM, want)
+	}
+}
+
+func TestMemoryStatsTool_Empty(t *testing.T) {
+	manager := memory.NewManager(config.MemoryConfig{Enabled: true}, memory.NewInMemoryVectorDB(), stubEmbedder{})
+	result := NewMemoryStatsTool(manager, "ws").Execute(context.Background(), map[string]interface{}{})
+	if result.ForLLM != "Long-term memory is empty." {
+		t.Errorf("unexpected output: %q", result.ForLLM)
+	}
+}
+
+func TestMemoryStatsTool_Disabled(t *testing.T) {
+	result := NewMemoryStatsTool(nil, "ws").Execute(context.Background(), map[string]interface{}{})
+	if !strings.Contains(result.ForLLM, "not enabled") {
+		t.Errorf("unexpected output: %q", result.ForLLM)
+	}
+}
@@ -0,0 +1,31 @@
+package tools
+
+import "path"
+
+// ToolPolicy decides which tools a registry accepts. Patterns are tool
+// names or path.Match globs such as "mcp_*". An empty Allow list permits
+// every tool; Deny always wins over Allow.
+type ToolPolicy struct {
+	Allow []string
+	Deny  []string
+}
+
+// Permits reports whether the tool called name may be registered.
+func (p ToolPolicy) Permits(name string) bool {
+	if matchesAny(p.Deny, name) {
+		return false
+	}
+	return len(p.Allow) == 0 || matchesAny(p.Allow, name)
+}
+
+func matchesAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if pattern == name {
+			return true
+		}
+		if ok, err := path.Match(pattern, name); err == nil && ok {
+			return true
+		}
+	}
+	return false
+}
@@ -2,15 +2,20 @@ package tools
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sort"
 	"sync"
 	"sync/atomic"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+
 	"github.com/sipeed/picoclaw/pkg/logger"
 	"github.com/sipeed/picoclaw/pkg/media"
+	"github.com/sipeed/picoclaw/pkg/metrics"
 	"github.com/sipeed/picoclaw/pkg/providers"
+	"github.com/sipeed/picoclaw/pkg/tracing"
 )
 
 type ToolEntry struct {
@@ -24,6 +29,8 @@ type ToolRegistry struct {
 	mu         sync.RWMutex
 	version    atomic.Uint64 // incremented on Register/RegisterHidden for cache invalidation
 	mediaStore media.MediaStore
+	policy     ToolPolicy
+	breaker    *CircuitBreaker
 }
 
 type mediaStoreAware interface {
@@ -40,6 +47,10 @@ func (r *ToolRegistry) Register(tool Tool) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 	name := tool.Name()
+	if !r.policy.Permits(name) {
+		logger.DebugCF("tools", "Tool excluded by policy", map[string]any{"name": name})
+		return
+	}
 	if _, exists := r.tools[name]; exists {
 		logger.WarnCF("tools", "Tool registration overwrites existing tool",
 			map[string]any{"name": name})
@@ -61,6 +72,10 @@ func (r *ToolRegistry) RegisterHidden(tool Tool) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 	name := tool.Name()
+	if !r.policy.Permits(name) {
+		logger.DebugCF("tools", "Tool excluded by policy", map[string]any{"name": name})
+		return
+	}
 	if _, exists := r.tools[name]; exists {
 		logger.WarnCF("tools", "Hidden tool registration overwrites existing tool",
 			map[string]any{"name": name})
@@ -77,6 +92,34 @@ func (r *ToolRegistry) RegisterHidden(tool Tool) {
 	logger.DebugCF("tools", "Registered hidden tool", map[string]any{"name": name})
 }
 
+// SetPolicy restricts the registry to the tools p permits. Registered tools
+// it denies are removed, and later registrations of denied tools are
+// ignored.
+func (r *ToolRegistry) SetPolicy(p ToolPolicy) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.policy = p
+	removed := false
+	for name := range r.tools {
+		if !p.Permits(name) {
+			delete(r.tools, name)
+			removed = true
+		}
+	}
+	if removed {
+		r.version.Add(1)
+	}
+}
+
+// SetCircuitBreaker makes the registry short-circuit calls to tools that
+// keep failing. A nil breaker disables it.
+func (r *ToolRegistry) SetCircuitBreaker(b *CircuitBreaker) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.breaker = b
+}
+
 // SetMediaStore injects a MediaStore into all registered tools that can
 // consume it, and remembers it for future registrations.
 func (r *ToolRegistry) SetMediaStore(store media.MediaStore) {
@@ -197,6 +240,9 @@ func (r *ToolRegistry) ExecuteWithContext(
 			"args": args,
 		})
 
+	ctx, span := tracing.Start(ctx, "tool.call", attribute.String("tool.name", name))
+	defer span.End()
+
 	tool, ok := r.Get(name)
 	if !ok {
 		logger.ErrorCF("tool", "Tool not found",
@@ -214,6 +260,18 @@ func (r *ToolRegistry) ExecuteWithContext(
 			WithError(fmt.Errorf("argument validation failed: %w", err))
 	}
 
+	r.mu.RLock()
+	breaker := r.breaker
+	r.mu.RUnlock()
+	if ok, retryIn := breaker.Allow(name); !ok {
+		logger.WarnCF("tool", "Tool call rejected by open circuit breaker",
+			map[string]any{"tool": name, "retry_in": retryIn.String()})
+		metrics.DefaultRecorder().RecordToolError(name, "circuit_open")
+		return ErrorResult(fmt.Sprintf(
+			"tool %q is temporarily unavailable after repeated failures; try again in %s or use a different approach",
+			name, max(retryIn.Round(time.Second), time.Second))).WithError(fmt.Errorf("circuit open"))
+	}
+
 	// Inject channel/chatID into ctx so tools read them via ToolChannel(ctx)/ToolChatID(ctx).
 	// Always inject — tools validate what they require.
 	ctx = WithToolContext(ctx, channel, chatID)
@@ -268,8 +326,17 @@ func (r *ToolRegistry) ExecuteWithContext(
 
 	duration := time.Since(start)
 
+	// A call cut short by cancellation says nothing about the tool's health.
+	if ctx.Err() != nil {
+		breaker.release(name)
+	} else {
+		breaker.Record(name, result.IsError)
+	}
+
 	// Log based on result type
 	if result.IsError {
+		tracing.RecordError(span, toolSpanError(result))
+		metrics.DefaultRecorder().RecordToolError(name, "execution_error")
 		logger.ErrorCF("tool", "Tool execution failed",
 			map[string]any{
 				"tool":     name,
@@ -294,6 +361,14 @@ func (r *ToolRegistry) ExecuteWithContext(
 	return result
 }
 
+// toolSpanError returns the error recorded on a failed tool's span.
+func toolSpanError(result *ToolResult) error {
+	if result.Err != nil {
+		return result.Err
+	}
+	return errors.New(result.ForLLM)
+}
+
 // sortedToolNames returns tool names in sorted order for deterministic iteration.
 // This is critical for KV cache stability: non-deterministic map iteration would
 // produce different system prompts and tool definitions on each call, invalidating
@@ -384,6 +459,8 @@ func (r *ToolRegistry) Clone() *ToolRegistry {
 	clone := &ToolRegistry{
 		tools:      make(map[string]*ToolEntry, len(r.tools)),
 		mediaStore: r.mediaStore,
+		policy:     r.policy,
+		breaker:    r.breaker,
 	}
 	for name, entry := range r.tools {
 		clone.tools[name] = &ToolEntry{
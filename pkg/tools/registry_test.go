@@ -9,8 +9,16 @@ import (
 	"sync"
 	"testing"
 
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace/noop"
+
 	"github.com/sipeed/picoclaw/pkg/media"
 	"github.com/sipeed/picoclaw/pkg/providers"
+	"github.com/sipeed/picoclaw/pkg/tracing"
 )
 
 // --- mock types ---
@@ -135,6 +143,40 @@ func TestToolRegistry_Execute_Success(t *testing.T) {
 	}
 }
 
+func TestToolRegistry_ExecuteWithContext_TraceSpan(t *testing.T) {
+	rec := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec)))
+	defer otel.SetTracerProvider(noop.NewTracerProvider())
+
+	r := NewToolRegistry()
+	r.Register(&mockRegistryTool{
+		name:   "broken",
+		desc:   "always fails",
+		params: map[string]any{},
+		result: ErrorResult("disk full"),
+	})
+
+	ctx, turn := tracing.Start(context.Background(), "agent.turn")
+	r.ExecuteWithContext(ctx, "broken", nil, "cli", "direct", nil)
+	turn.End()
+
+	spans := rec.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("got %d spans, want 2", len(spans))
+	}
+	tool, parent := spans[0], spans[1]
+	if tool.Name() != "tool.call" || tool.Parent().SpanID() != parent.SpanContext().SpanID() {
+		t.Errorf("tool span %q has parent %s, want tool.call under %s",
+			tool.Name(), tool.Parent().SpanID(), parent.SpanContext().SpanID())
+	}
+	if st := tool.Status(); st.Code != codes.Error || st.Description != "disk full" {
+		t.Errorf("tool span status = %v %q, want error \"disk full\"", st.Code, st.Description)
+	}
+	if attrs := tool.Attributes(); len(attrs) == 0 || attrs[0] != attribute.String("tool.name", "broken") {
+		t.Errorf("tool span attributes = %v", attrs)
+	}
+}
+
 func TestToolRegistry_Execute_NotFound(t *testing.T) {
 	r := NewToolRegistry()
 	result := r.Execute(context.Background(), "missing", nil)
@@ -732,3 +774,52 @@ func TestToolRegistry_ExecuteWithContext_SanitizesInlineMediaWithoutStore(t *tes
 		t.Fatalf("expected inline media omission note, got %q", result.ForLLM)
 	}
 }
+
+func TestToolRegistry_SetPolicy(t *testing.T) {
+	r := NewToolRegistry()
+	r.Register(newMockTool("cron", "scheduler"))
+	r.Register(newMockTool("read_file", "reader"))
+
+	v := r.Version()
+	r.SetPolicy(ToolPolicy{Deny: []string{"cron", "mcp_*"}})
+	if _, ok := r.Get("cron"); ok {
+		t.Error("denied tool should be removed when the policy is set")
+	}
+	if r.Version() == v {
+		t.Error("removing tools should bump the registry version")
+	}
+
+	r.Register(newMockTool("cron", "scheduler"))
+	r.RegisterHidden(newMockTool("mcp_github_search", "search"))
+	r.Register(newMockTool("write_file", "writer"))
+	if got := r.List(); strings.Join(got, ",") != "read_file,write_file" {
+		t.Errorf("List() = %v, want [read_file write_file]", got)
+	}
+
+	clone := r.Clone()
+	clone.Register(newMockTool("cron", "scheduler"))
+	if _, ok := clone.Get("cron"); ok {
+		t.Error("clone should keep the policy")
+	}
+}
+
+func TestToolPolicy_Permits(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy ToolPolicy
+		tool   string
+		want   bool
+	}{
+		{"empty permits all", ToolPolicy{}, "exec", true},
+		{"allow list", ToolPolicy{Allow: []string{"read_file"}}, "exec", false},
+		{"allow glob", ToolPolicy{Allow: []string{"mcp_*"}}, "mcp_github_search", true},
+		{"deny wins", ToolPolicy{Allow: []string{"cron"}, Deny: []string{"cron"}}, "cron", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.Permits(tt.tool); got != tt.want {
+				t.Errorf("Permits(%q) = %v, want %v", tt.tool, got, tt.want)
+			}
+		})
+	}
+}
@@ -1,9 +1,15 @@
 package tools
 
 import (
+	"bytes"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"strings"
+	"unicode/utf8"
 
+	"github.com/sipeed/picoclaw/pkg/metrics"
 	"github.com/sipeed/picoclaw/pkg/providers"
 )
 
@@ -45,6 +51,11 @@ type ToolResult struct {
 	// When non-empty, the agent will publish these as OutboundMediaMessage.
 	Media []string `json:"media,omitempty"`
 
+	// Images holds inline images for the model to look at, such as
+	// screenshots. The agent attaches them to the conversation as media for
+	// the next LLM call; they are not delivered to the user.
+	Images []Image `json:"images,omitempty"`
+
 	// Messages holds the ephemeral session history after execution.
 	// Only populated by SubTurn executions; used by evaluator_optimizer
 	// to carry stateful worker context across evaluation iterations.
@@ -59,6 +70,25 @@ type ToolResult struct {
 	// user's request at the channel/output level, so the agent loop can stop
 	// without a follow-up assistant response.
 	ResponseHandled bool `json:"response_handled,omitempty"`
+
+	// Data is the JSON encoding of a structured result, for callers that
+	// consume tool output programmatically. ForLLM still carries the text
+	// the model sees. Set by JSONResult; read back with DecodeData.
+	Data json.RawMessage `json:"data,omitempty"`
+}
+
+// Image is inline image data produced by a tool.
+type Image struct {
+	// MIMEType is the image content type, e.g. "image/png".
+	MIMEType string `json:"mime_type"`
+	// Data holds the raw (not base64-encoded) image bytes.
+	Data []byte `json:"data"`
+}
+
+// DataURL returns the image as a base64 data URL, the form multimodal
+// providers accept in providers.Message.Media.
+func (img Image) DataURL() string {
+	return "data:" + img.MIMEType + ";base64," + base64.StdEncoding.EncodeToString(img.Data)
 }
 
 // ContentForLLM returns the normalized textual content to append to the
@@ -194,6 +224,59 @@ func MediaResult(forLLM string, mediaRefs []string) *ToolResult {
 	}
 }
 
+// JSONResult creates a silent ToolResult carrying v as structured Data.
+// ForLLM is set to the indented JSON of v; callers that want a friendlier
+// rendering for the model may overwrite it. If v cannot be encoded, an
+// error result is returned instead.
+//
+// Example:
+//
+//	result := JSONResult(map[string]any{"chores": chores, "count": len(chores)})
+func JSONResult(v any) *ToolResult {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return ErrorResult(fmt.Sprintf("failed to encode tool result: %v", err)).WithError(err)
+	}
+	var text bytes.Buffer
+	_ = json.Indent(&text, data, "", "  ")
+	return &ToolResult{
+		ForLLM: text.String(),
+		Silent: true,
+		Data:   data,
+	}
+}
+
+// DecodeData unmarshals the result's structured Data into v. It returns an
+// error if the result carries no Data.
+func (tr *ToolResult) DecodeData(v any) error {
+	if tr == nil || len(tr.Data) == 0 {
+		return errors.New("tool result has no structured data")
+	}
+	return json.Unmarshal(tr.Data, v)
+}
+
+// TruncatedResult creates a ToolResult whose content is trimmed to at most
+// maxBytes, with a "...[truncated N bytes]" marker appended when anything
+// was cut. The untruncated size is recorded to the tool result size metric
+// under toolName first, so large outputs remain visible in metrics.
+//
+// Example:
+//
+//	result := TruncatedResult("read_file", string(data), 64*1024)
+func TruncatedResult(toolName, content string, maxBytes int) *ToolResult {
+	metrics.DefaultRecorder().RecordToolResultSize(toolName, len(content))
+
+	if maxBytes < 0 || len(content) <= maxBytes {
+		return NewToolResult(content)
+	}
+
+	cut := maxBytes
+	for cut > 0 && !utf8.RuneStart(content[cut]) {
+		cut--
+	}
+	return NewToolResult(fmt.Sprintf("%s\n...[truncated %d bytes]", content[:cut], len(content)-cut))
+}
+
 // MarshalJSON implements custom JSON serialization.
 // The Err field is excluded from JSON output via the json:"-" tag.
 func (tr *ToolResult) MarshalJSON() ([]byte, error) {
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"strings"
 	"testing"
+	"unicode/utf8"
 )
 
 func TestNewToolResult(t *testing.T) {
@@ -266,3 +267,74 @@ func TestToolResultContentForLLM_AppendsArtifactPaths(t *testing.T) {
 		t.Fatalf("expected artifact guidance note in ContentForLLM, got %q", content)
 	}
 }
+
+func TestTruncatedResult_UnderBudget(t *testing.T) {
+	result := TruncatedResult("read_file", "short output", 100)
+	if result.ForLLM != "short output" {
+		t.Errorf("Expected content unchanged, got %q", result.ForLLM)
+	}
+}
+
+func TestTruncatedResult_OverBudget(t *testing.T) {
+	content := strings.Repeat("a", 90) + strings.Repeat("é", 10) // 110 bytes
+	result := TruncatedResult("read_file", content, 95)
+
+	if !strings.HasPrefix(result.ForLLM, strings.Repeat("a", 90)+"éé") {
+		t.Errorf("Expected content to keep the first 94 bytes, got %q", result.ForLLM)
+	}
+	// 95 falls inside a 2-byte rune, so the cut backs off to 94 bytes.
+	if !strings.HasSuffix(result.ForLLM, "\n...[truncated 16 bytes]") {
+		t.Errorf("Expected truncation marker, got %q", result.ForLLM)
+	}
+	if !utf8.ValidString(result.ForLLM) {
+		t.Error("Expected truncated content to remain valid UTF-8")
+	}
+}
+
+func TestJSONResult_RoundTripsStructuredData(t *testing.T) {
+	type chore struct {
+		Name     string `json:"name"`
+		Assignee string `json:"assignee"`
+		Done     bool   `json:"done"`
+	}
+	chores := []chore{{"dishes", "sam", true}, {"laundry", "alex", false}}
+
+	result := JSONResult(chores)
+	if result.IsError || !result.Silent {
+		t.Fatalf("JSONResult = %+v, want a silent success", result)
+	}
+	if !strings.Contains(result.ForLLM, `"name": "laundry"`) {
+		t.Errorf("ForLLM = %q, want an indented JSON rendering", result.ForLLM)
+	}
+
+	// The data survives serialization of the whole result.
+	encoded, err := json.Marshal(result)
+	if err != nil {
+		t.Fatalf("Failed to marshal: %v", err)
+	}
+	var decoded ToolResult
+	if err := json.Unmarshal(encoded, &decoded); err != nil {
+		t.Fatalf("Failed to unmarshal: %v", err)
+	}
+	var got []chore
+	if err := decoded.DecodeData(&got); err != nil {
+		t.Fatalf("DecodeData: %v", err)
+	}
+	if len(got) != 2 || got[0] != chores[0] || got[1] != chores[1] {
+		t.Errorf("decoded data = %+v, want %+v", got, chores)
+	}
+}
+
+func TestJSONResult_UnencodableValue(t *testing.T) {
+	result := JSONResult(map[string]any{"ch": make(chan int)})
+	if !result.IsError || result.Err == nil || len(result.Data) != 0 {
+		t.Errorf("JSONResult = %+v, want an error result without data", result)
+	}
+}
+
+func TestToolResultDecodeData_NoData(t *testing.T) {
+	var v any
+	if err := NewToolResult("plain text").DecodeData(&v); err == nil {
+		t.Error("DecodeData on a text-only result should fail")
+	}
+}
@@ -0,0 +1,44 @@
+package tracing
+
+import (
+	"context"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.39.0"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+const otlpTracesPath = "/v1/traces"
+
+// Setup installs a global tracer provider that batches spans and sends them
+// to the OTLP/HTTP collector at endpoint, e.g. "http://localhost:4318". The
+// /v1/traces path is appended if missing. The returned function flushes
+// pending spans and turns tracing off again.
+func Setup(ctx context.Context, endpoint, serviceName string) (func(context.Context) error, error) {
+	url := strings.TrimRight(endpoint, "/")
+	if !strings.HasSuffix(url, otlpTracesPath) {
+		url += otlpTracesPath
+	}
+	if serviceName == "" {
+		serviceName = "picoclaw"
+	}
+
+	exp, err := otlptracehttp.New(ctx, otlptracehttp.WithEndpointURL(url))
+	if err != nil {
+		return nil, err
+	}
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exp),
+		sdktrace.WithResource(resource.NewSchemaless(semconv.ServiceName(serviceName))),
+	)
+	otel.SetTracerProvider(tp)
+
+	return func(ctx context.Context) error {
+		otel.SetTracerProvider(noop.NewTracerProvider())
+		return tp.Shutdown(ctx)
+	}, nil
+}
@@ -0,0 +1,34 @@
+// Package tracing records OpenTelemetry spans for agent turns, LLM calls and
+// tool calls.
+//
+// Spans go to the global OpenTelemetry tracer provider. Until Setup installs
+// an exporting provider the global one is a no-op, so call sites don't need
+// to check whether tracing is enabled.
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/sipeed/picoclaw"
+
+// Start begins a span named name as a child of the span in ctx, if any, and
+// returns a context carrying the new span.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// RecordError records err on span and marks the span as failed. A nil err
+// is ignored.
+func RecordError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
@@ -0,0 +1,103 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+func installRecorder(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	rec := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec)))
+	t.Cleanup(func() { otel.SetTracerProvider(noop.NewTracerProvider()) })
+	return rec
+}
+
+func TestStart_Disabled(t *testing.T) {
+	_, span := Start(context.Background(), "noop")
+	if span.SpanContext().IsValid() || span.IsRecording() {
+		t.Fatal("expected a non-recording span while tracing is off")
+	}
+	span.SetAttributes(attribute.String("k", "v"))
+	RecordError(span, errors.New("boom"))
+	span.End()
+}
+
+func TestStart_Hierarchy(t *testing.T) {
+	rec := installRecorder(t)
+
+	ctx, turn := Start(context.Background(), "agent.turn")
+	llmCtx, llm := Start(ctx, "llm.chat", attribute.String("model", "m"))
+	_, tool := Start(llmCtx, "tool.call", attribute.String("tool", "read_file"))
+	RecordError(tool, errors.New("denied"))
+	tool.End()
+	llm.End()
+	turn.End()
+
+	spans := rec.Ended()
+	if len(spans) != 3 {
+		t.Fatalf("got %d spans, want 3", len(spans))
+	}
+	toolData, llmData, turnData := spans[0], spans[1], spans[2]
+
+	if turnData.Parent().IsValid() {
+		t.Errorf("turn span should be a root, has parent %s", turnData.Parent().SpanID())
+	}
+	if llmData.Parent().SpanID() != turnData.SpanContext().SpanID() {
+		t.Errorf("llm parent = %s, want turn %s", llmData.Parent().SpanID(), turnData.SpanContext().SpanID())
+	}
+	if toolData.Parent().SpanID() != llmData.SpanContext().SpanID() {
+		t.Errorf("tool parent = %s, want llm %s", toolData.Parent().SpanID(), llmData.SpanContext().SpanID())
+	}
+	for _, s := range spans {
+		if s.SpanContext().TraceID() != turnData.SpanContext().TraceID() {
+			t.Errorf("span %s is not in the turn's trace", s.Name())
+		}
+	}
+	if st := toolData.Status(); st.Code != codes.Error || st.Description != "denied" {
+		t.Errorf("tool status = %v %q, want error \"denied\"", st.Code, st.Description)
+	}
+	if attrs := llmData.Attributes(); len(attrs) != 1 || attrs[0] != attribute.String("model", "m") {
+		t.Errorf("llm attributes = %v", attrs)
+	}
+}
+
+func TestSetup_ShutdownFlushes(t *testing.T) {
+	paths := make(chan string, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths <- r.URL.Path
+	}))
+	defer srv.Close()
+
+	shutdown, err := Setup(context.Background(), srv.URL, "picoclaw-test")
+	if err != nil {
+		t.Fatalf("Setup: %v", err)
+	}
+	_, span := Start(context.Background(), "agent.turn")
+	span.End()
+	if err := shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+
+	select {
+	case path := <-paths:
+		if path != "/v1/traces" {
+			t.Errorf("path = %s, want /v1/traces", path)
+		}
+	default:
+		t.Fatal("shutdown did not export the pending span")
+	}
+	if _, span := Start(context.Background(), "after"); span.IsRecording() {
+		t.Error("tracing still enabled after shutdown")
+	}
+}
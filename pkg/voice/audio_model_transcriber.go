@@ -47,10 +47,24 @@ func NewAudioModelTranscriber(modelCfg *config.ModelConfig) *AudioModelTranscrib
 	}
 }
 
-func (t *AudioModelTranscriber) Transcribe(ctx context.Context, audioFilePath string) (*TranscriptionResponse, error) {
+func (t *AudioModelTranscriber) Transcribe(
+	ctx context.Context,
+	audioFilePath string,
+	opts TranscribeOptions,
+) (*TranscriptionResponse, error) {
+	modelID := t.modelID
+	if opts.Model != "" {
+		modelID = opts.Model
+	}
+	prompt := t.prompt
+	if opts.Language != "" {
+		prompt += fmt.Sprintf(" The speech is in the language with ISO-639-1 code %q.", opts.Language)
+	}
+
 	logger.InfoCF("voice", "Starting audio model transcription", map[string]any{
 		"audio_file": audioFilePath,
-		"model":      t.modelID,
+		"model":      modelID,
+		"language":   opts.Language,
 	})
 
 	audioBytes, err := os.ReadFile(audioFilePath)
@@ -68,12 +82,12 @@ func (t *AudioModelTranscriber) Transcribe(ctx context.Context, audioFilePath st
 	resp, err := t.provider.Chat(ctx, []providers.Message{
 		{
 			Role:    "user",
-			Content: t.prompt,
+			Content: prompt,
 			Media: []string{
 				fmt.Sprintf("data:audio/%s;base64,%s", format, base64.StdEncoding.EncodeToString(audioBytes)),
 			},
 		},
-	}, nil, t.modelID, map[string]any{
+	}, nil, modelID, map[string]any{
 		"temperature": 0,
 	})
 	if err != nil {
@@ -132,7 +132,7 @@ func TestAudioModelTranscriberTranscribe(t *testing.T) {
 			prompt:  defaultTranscriptionPrompt,
 		}
 
-		resp, err := tr.Transcribe(context.Background(), audioPath)
+		resp, err := tr.Transcribe(context.Background(), audioPath, TranscribeOptions{})
 		if err != nil {
 			t.Fatalf("Transcribe() error: %v", err)
 		}
@@ -158,7 +158,7 @@ func TestAudioModelTranscriberTranscribe(t *testing.T) {
 			prompt:  defaultTranscriptionPrompt,
 		}
 
-		_, err := tr.Transcribe(context.Background(), audioPath)
+		_, err := tr.Transcribe(context.Background(), audioPath, TranscribeOptions{})
 		if err == nil {
 			t.Fatal("expected error for provider failure, got nil")
 		}
@@ -174,7 +174,7 @@ func TestAudioModelTranscriberTranscribe(t *testing.T) {
 			prompt:   defaultTranscriptionPrompt,
 		}
 
-		_, err := tr.Transcribe(context.Background(), filepath.Join(tmpDir, "nonexistent.ogg"))
+		_, err := tr.Transcribe(context.Background(), filepath.Join(tmpDir, "nonexistent.ogg"), TranscribeOptions{})
 		if err == nil {
 			t.Fatal("expected error for missing file, got nil")
 		}
@@ -192,7 +192,7 @@ func TestAudioModelTranscriberTranscribe(t *testing.T) {
 			prompt:   defaultTranscriptionPrompt,
 		}
 
-		_, err := tr.Transcribe(context.Background(), badPath)
+		_, err := tr.Transcribe(context.Background(), badPath, TranscribeOptions{})
 		if err == nil {
 			t.Fatal("expected error for unsupported audio format, got nil")
 		}
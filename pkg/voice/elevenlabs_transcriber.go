@@ -16,6 +16,9 @@ import (
 	"github.com/sipeed/picoclaw/pkg/utils"
 )
 
+// defaultElevenLabsModel is the Scribe model requested when none is given.
+const defaultElevenLabsModel = "scribe_v1"
+
 // ElevenLabsTranscriber uses the ElevenLabs Scribe API for speech-to-text.
 type ElevenLabsTranscriber struct {
 	apiKey     string
@@ -35,7 +38,11 @@ func NewElevenLabsTranscriber(apiKey string) *ElevenLabsTranscriber {
 	}
 }
 
-func (t *ElevenLabsTranscriber) Transcribe(ctx context.Context, audioFilePath string) (*TranscriptionResponse, error) {
+func (t *ElevenLabsTranscriber) Transcribe(
+	ctx context.Context,
+	audioFilePath string,
+	opts TranscribeOptions,
+) (*TranscriptionResponse, error) {
 	logger.InfoCF("voice", "Starting ElevenLabs transcription", map[string]any{"audio_file": audioFilePath})
 
 	audioFile, err := os.Open(audioFilePath)
@@ -70,10 +77,20 @@ func (t *ElevenLabsTranscriber) Transcribe(ctx context.Context, audioFilePath st
 		return nil, fmt.Errorf("failed to copy file content: %w", err)
 	}
 
-	if err = writer.WriteField("model_id", "scribe_v1"); err != nil {
+	modelID := defaultElevenLabsModel
+	if opts.Model != "" {
+		modelID = opts.Model
+	}
+	if err = writer.WriteField("model_id", modelID); err != nil {
 		return nil, fmt.Errorf("failed to write model_id field: %w", err)
 	}
 
+	if opts.Language != "" {
+		if err = writer.WriteField("language_code", opts.Language); err != nil {
+			return nil, fmt.Errorf("failed to write language_code field: %w", err)
+		}
+	}
+
 	if err = writer.Close(); err != nil {
 		logger.ErrorCF("voice", "Failed to close multipart writer", map[string]any{"error": err})
 		return nil, fmt.Errorf("failed to close multipart writer: %w", err)
@@ -46,7 +46,7 @@ func TestElevenLabsTranscribe(t *testing.T) {
 		tr := NewElevenLabsTranscriber("sk_test")
 		tr.apiBase = srv.URL
 
-		resp, err := tr.Transcribe(context.Background(), audioPath)
+		resp, err := tr.Transcribe(context.Background(), audioPath, TranscribeOptions{})
 		if err != nil {
 			t.Fatalf("Transcribe() error: %v", err)
 		}
@@ -58,6 +58,26 @@ func TestElevenLabsTranscribe(t *testing.T) {
 		}
 	})
 
+	t.Run("language and model options", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if got := r.FormValue("model_id"); got != "scribe_v2" {
+				t.Errorf("model_id = %q, want %q", got, "scribe_v2")
+			}
+			if got := r.FormValue("language_code"); got != "fr" {
+				t.Errorf("language_code = %q, want %q", got, "fr")
+			}
+			_ = json.NewEncoder(w).Encode(TranscriptionResponse{Text: "bonjour"})
+		}))
+		defer srv.Close()
+
+		tr := NewElevenLabsTranscriber("sk_test")
+		tr.apiBase = srv.URL
+
+		if _, err := tr.Transcribe(context.Background(), audioPath, TranscribeOptions{Language: "fr", Model: "scribe_v2"}); err != nil {
+			t.Fatalf("Transcribe() error: %v", err)
+		}
+	})
+
 	t.Run("api error", func(t *testing.T) {
 		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			http.Error(w, `{"error":"invalid_api_key"}`, http.StatusUnauthorized)
@@ -67,7 +87,7 @@ func TestElevenLabsTranscribe(t *testing.T) {
 		tr := NewElevenLabsTranscriber("sk_bad")
 		tr.apiBase = srv.URL
 
-		_, err := tr.Transcribe(context.Background(), audioPath)
+		_, err := tr.Transcribe(context.Background(), audioPath, TranscribeOptions{})
 		if err == nil {
 			t.Fatal("expected error for non-200 response, got nil")
 		}
@@ -75,7 +95,7 @@ func TestElevenLabsTranscribe(t *testing.T) {
 
 	t.Run("missing file", func(t *testing.T) {
 		tr := NewElevenLabsTranscriber("sk_test")
-		_, err := tr.Transcribe(context.Background(), filepath.Join(tmpDir, "nonexistent.ogg"))
+		_, err := tr.Transcribe(context.Background(), filepath.Join(tmpDir, "nonexistent.ogg"), TranscribeOptions{})
 		if err == nil {
 			t.Fatal("expected error for missing file, got nil")
 		}
@@ -47,7 +47,7 @@ func TestGroqTranscribe(t *testing.T) {
 		tr := NewGroqTranscriber("sk-test")
 		tr.apiBase = srv.URL
 
-		resp, err := tr.Transcribe(context.Background(), audioPath)
+		resp, err := tr.Transcribe(context.Background(), audioPath, TranscribeOptions{})
 		if err != nil {
 			t.Fatalf("Transcribe() error: %v", err)
 		}
@@ -68,7 +68,7 @@ func TestGroqTranscribe(t *testing.T) {
 		tr := NewGroqTranscriber("sk-bad")
 		tr.apiBase = srv.URL
 
-		_, err := tr.Transcribe(context.Background(), audioPath)
+		_, err := tr.Transcribe(context.Background(), audioPath, TranscribeOptions{})
 		if err == nil {
 			t.Fatal("expected error for non-200 response, got nil")
 		}
@@ -76,7 +76,7 @@ func TestGroqTranscribe(t *testing.T) {
 
 	t.Run("missing file", func(t *testing.T) {
 		tr := NewGroqTranscriber("sk-test")
-		_, err := tr.Transcribe(context.Background(), filepath.Join(tmpDir, "nonexistent.ogg"))
+		_, err := tr.Transcribe(context.Background(), filepath.Join(tmpDir, "nonexistent.ogg"), TranscribeOptions{})
 		if err == nil {
 			t.Fatal("expected error for missing file, got nil")
 		}
@@ -10,7 +10,16 @@ import (
 
 type Transcriber interface {
 	Name() string
-	Transcribe(ctx context.Context, audioFilePath string) (*TranscriptionResponse, error)
+	Transcribe(ctx context.Context, audioFilePath string, opts TranscribeOptions) (*TranscriptionResponse, error)
+}
+
+// TranscribeOptions tunes a single transcription. Zero values keep the
+// transcriber's own defaults.
+type TranscribeOptions struct {
+	// Language is an ISO-639-1 hint for the spoken language, e.g. "es".
+	Language string
+	// Model overrides the transcriber's configured model.
+	Model string
 }
 
 type TranscriptionResponse struct {
@@ -58,6 +67,10 @@ func DetectTranscriber(cfg *config.Config) Transcriber {
 	if key := strings.TrimSpace(cfg.Voice.ElevenLabsAPIKey); key != "" {
 		return NewElevenLabsTranscriber(key)
 	}
+	// OpenAI-compatible Whisper endpoint, hosted or local.
+	if base := strings.TrimSpace(cfg.Voice.WhisperAPIBase); base != "" {
+		return NewWhisperTranscriber(base, strings.TrimSpace(cfg.Voice.WhisperAPIKey), cfg.Voice.WhisperModel)
+	}
 	// Fall back to any model-list entry that uses the groq/ protocol.
 	for _, mc := range cfg.ModelList {
 		if strings.HasPrefix(mc.Model, "groq/") && mc.APIKey() != "" {
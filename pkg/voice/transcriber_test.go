@@ -168,6 +168,29 @@ func TestDetectTranscriber(t *testing.T) {
 			}),
 			wantName: "elevenlabs",
 		},
+		{
+			name: "whisper api base",
+			cfg: &config.Config{
+				Voice: config.VoiceConfig{WhisperAPIBase: "http://localhost:8080/v1"},
+			},
+			wantName: "whisper",
+		},
+		{
+			name: "whisper takes priority over groq model list",
+			cfg: (&config.Config{
+				Voice: config.VoiceConfig{WhisperAPIBase: "https://api.openai.com/v1", WhisperAPIKey: "sk-openai"},
+				ModelList: []*config.ModelConfig{
+					{ModelName: "groq", Model: "groq/llama-3.3-70b"},
+				},
+			}).WithSecurity(&config.SecurityConfig{
+				ModelList: map[string]config.ModelSecurityEntry{
+					"groq": {
+						APIKeys: []string{"sk-groq-direct"},
+					},
+				},
+			}),
+			wantName: "whisper",
+		},
 		{
 			name: "voice model name takes priority over elevenlabs",
 			cfg: (&config.Config{
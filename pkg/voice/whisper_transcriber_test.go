@@ -0,0 +1,116 @@
+package voice
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var _ Transcriber = (*WhisperTranscriber)(nil)
+
+func TestNewWhisperTranscriberDefaults(t *testing.T) {
+	tr := NewWhisperTranscriber("http://localhost:8000/v1/", "", "")
+	if tr.apiBase != "http://localhost:8000/v1" {
+		t.Errorf("apiBase = %q, want trailing slash trimmed", tr.apiBase)
+	}
+	if tr.model != defaultWhisperModel {
+		t.Errorf("model = %q, want %q", tr.model, defaultWhisperModel)
+	}
+	if got := tr.Name(); got != "whisper" {
+		t.Errorf("Name() = %q, want %q", got, "whisper")
+	}
+}
+
+func TestWhisperTranscribe(t *testing.T) {
+	audioPath := filepath.Join(t.TempDir(), "clip.ogg")
+	if err := os.WriteFile(audioPath, []byte("fake-audio-data"), 0o644); err != nil {
+		t.Fatalf("failed to write fake audio file: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		apiKey   string
+		model    string
+		wantAuth string
+	}{
+		{name: "local server without key", model: "", wantAuth: ""},
+		{name: "hosted with key and model", apiKey: "sk-openai", model: "whisper-large-v3", wantAuth: "Bearer sk-openai"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			wantModel := tc.model
+			if wantModel == "" {
+				wantModel = defaultWhisperModel
+			}
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/v1/audio/transcriptions" {
+					t.Errorf("unexpected path: %s", r.URL.Path)
+				}
+				if got := r.Header.Get("Authorization"); got != tc.wantAuth {
+					t.Errorf("Authorization = %q, want %q", got, tc.wantAuth)
+				}
+				if got := r.FormValue("model"); got != wantModel {
+					t.Errorf("model = %q, want %q", got, wantModel)
+				}
+				if _, _, err := r.FormFile("file"); err != nil {
+					t.Errorf("missing file part: %v", err)
+				}
+				w.Header().Set("Content-Type", "application/json")
+				_ = json.NewEncoder(w).Encode(TranscriptionResponse{Text: "hello from whisper"})
+			}))
+			defer srv.Close()
+
+			tr := NewWhisperTranscriber(srv.URL+"/v1", tc.apiKey, tc.model)
+			resp, err := tr.Transcribe(context.Background(), audioPath, TranscribeOptions{})
+			if err != nil {
+				t.Fatalf("Transcribe() error: %v", err)
+			}
+			if resp.Text != "hello from whisper" {
+				t.Errorf("Text = %q, want %q", resp.Text, "hello from whisper")
+			}
+		})
+	}
+}
+
+func TestWhisperTranscribeOptions(t *testing.T) {
+	audioPath := filepath.Join(t.TempDir(), "clip.ogg")
+	if err := os.WriteFile(audioPath, []byte("fake-audio-data"), 0o644); err != nil {
+		t.Fatalf("failed to write fake audio file: %v", err)
+	}
+
+	var gotModel, gotLanguage string
+	var hasLanguage bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Errorf("ParseMultipartForm: %v", err)
+		}
+		gotModel = r.FormValue("model")
+		_, hasLanguage = r.MultipartForm.Value["language"]
+		gotLanguage = r.FormValue("language")
+		_ = json.NewEncoder(w).Encode(TranscriptionResponse{Text: "hola"})
+	}))
+	defer srv.Close()
+
+	tr := NewWhisperTranscriber(srv.URL, "", "whisper-1")
+
+	if _, err := tr.Transcribe(context.Background(), audioPath, TranscribeOptions{
+		Language: "es",
+		Model:    "whisper-large-v3-turbo",
+	}); err != nil {
+		t.Fatalf("Transcribe() error: %v", err)
+	}
+	if gotModel != "whisper-large-v3-turbo" || gotLanguage != "es" {
+		t.Errorf("model, language = %q, %q; want the options", gotModel, gotLanguage)
+	}
+
+	if _, err := tr.Transcribe(context.Background(), audioPath, TranscribeOptions{}); err != nil {
+		t.Fatalf("Transcribe() error: %v", err)
+	}
+	if gotModel != "whisper-1" || hasLanguage {
+		t.Errorf("model = %q, language sent = %v; want the default model and no language", gotModel, hasLanguage)
+	}
+}